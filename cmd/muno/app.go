@@ -6,8 +6,11 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/taokim/muno/internal/config"
@@ -19,6 +22,7 @@ type App struct {
 	rootCmd *cobra.Command
 	stdout  io.Writer
 	stderr  io.Writer
+	strict  bool // --strict: fail when a config in the tree cannot be loaded
 }
 
 // NewApp creates a new tree-based application
@@ -41,7 +45,12 @@ func (a *App) SetOutput(stdout, stderr io.Writer) {
 
 // Execute runs the application
 func (a *App) Execute() error {
-	return a.rootCmd.Execute()
+	err := a.rootCmd.Execute()
+	// Keep timings of failed commands too
+	if flushErr := manager.FlushMetrics(); flushErr != nil {
+		fmt.Fprintf(a.stderr, "Warning: saving metrics: %v\n", flushErr)
+	}
+	return err
 }
 
 // ExecuteWithArgs runs with specific arguments
@@ -50,6 +59,12 @@ func (a *App) ExecuteWithArgs(args []string) error {
 	return a.Execute()
 }
 
+// loadManager loads the workspace manager for the current directory with
+// the options set by the global flags
+func (a *App) loadManager() (*manager.Manager, error) {
+	return manager.LoadFromCurrentDirWithOptions(manager.LoadOptions{Strict: a.strict})
+}
+
 // Build-time variables (set via ldflags)
 var (
 	// GitHubOwner is the GitHub repository owner (set at build time)
@@ -74,6 +89,10 @@ func getDocumentationURLs() string {
 
 // setupCommands initializes all commands
 func (a *App) setupCommands() {
+	var logLevel string
+	var logFormat string
+	var debug bool
+	
 	a.rootCmd = &cobra.Command{
 		Use:   "muno",
 		Short: "Multi-repository orchestration with tree-based workspaces",
@@ -88,8 +107,21 @@ Features:
 
 %s`, getDocumentationURLs()),
 		Version: formatVersion(),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if debug {
+				logLevel = "debug"
+			}
+			return manager.SetLogOptions(manager.LogOptions{Level: logLevel, Format: logFormat})
+		},
 	}
 	
+	// Logging applies to every command. Logs also go to .muno/logs/muno.log
+	// in the workspace at info level or below.
+	a.rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "Console log level (debug, info, warn, error)")
+	a.rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "logfmt", "Log format (logfmt, json)")
+	a.rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Shorthand for --log-level=debug")
+	a.rootCmd.PersistentFlags().BoolVar(&a.strict, "strict", false, "Fail when a config in the tree cannot be loaded (for CI)")
+	
 	// Core commands
 	a.rootCmd.AddCommand(a.newInitCmd())
 	a.rootCmd.AddCommand(a.newListCmd())
@@ -99,15 +131,39 @@ Features:
 	a.rootCmd.AddCommand(a.newPathCmd())
 	a.rootCmd.AddCommand(a.newShellInitCmd())
 	a.rootCmd.AddCommand(a.newTreeCmd())
+	a.rootCmd.AddCommand(a.newUICmd())
 	
 	// Repository management
 	a.rootCmd.AddCommand(a.newRemoveCmd())
 	a.rootCmd.AddCommand(a.newCloneCmd())
+	a.rootCmd.AddCommand(a.newSyncCmd())
 	
 	// Git operations
 	a.rootCmd.AddCommand(a.newPullCmd())
+	a.rootCmd.AddCommand(a.newFetchCmd())
 	a.rootCmd.AddCommand(a.newCommitCmd())
 	a.rootCmd.AddCommand(a.newPushCmd())
+	a.rootCmd.AddCommand(a.newUndoCmd())
+	a.rootCmd.AddCommand(a.newChangeCmd())
+	
+	// Dependency-aware operations
+	a.rootCmd.AddCommand(a.newGraphCmd())
+	a.rootCmd.AddCommand(a.newExecCmd())
+	
+	// Tooling integration
+	a.rootCmd.AddCommand(a.newGoWorkCmd())
+	a.rootCmd.AddCommand(a.newIDECmd())
+	a.rootCmd.AddCommand(a.newContextCmd())
+	a.rootCmd.AddCommand(a.newAgentCmd())
+	
+	// Configuration
+	a.rootCmd.AddCommand(a.newConfigCmd())
+	a.rootCmd.AddCommand(a.newDoctorCmd())
+	
+	// Background services
+	a.rootCmd.AddCommand(a.newDaemonCmd())
+	a.rootCmd.AddCommand(a.newStatsCmd())
+	a.rootCmd.AddCommand(a.newJournalCmd())
 	
 	// Version
 	a.rootCmd.AddCommand(a.newVersionCmd())
@@ -139,10 +195,10 @@ Creates:
 			projectName := ""
 			projectPath := "."
 			
-			// Check if muno.yaml already exists
-			configPath := filepath.Join(projectPath, "muno.yaml")
+			// Check if muno.yaml (or muno.json, muno.toml) already exists
+			configPath := config.ConfigFilePath(projectPath)
 			if _, err := os.Stat(configPath); err == nil && !force {
-				fmt.Fprintf(cmd.OutOrStdout(), "Project already initialized (muno.yaml exists)\n")
+				fmt.Fprintf(cmd.OutOrStdout(), "Project already initialized (%s exists)\n", filepath.Base(configPath))
 				fmt.Fprintf(cmd.OutOrStdout(), "Use --force to reinitialize\n")
 				return nil
 			}
@@ -214,7 +270,7 @@ Shows:
 - Lazy/cloned state`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mgr, err := manager.LoadFromCurrentDir()
+			mgr, err := a.loadManager()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
@@ -242,6 +298,8 @@ Shows:
 // newStatusCmd creates the status command
 func (a *App) newStatusCmd() *cobra.Command {
 	var recursive bool
+	var refresh bool
+	var cacheStats bool
 	
 	cmd := &cobra.Command{
 		Use:   "status [path]",
@@ -250,12 +308,25 @@ func (a *App) newStatusCmd() *cobra.Command {
 - Tree structure
 - Repository states (clean/dirty)
 - Branch information
-- Uncommitted changes`,
+- Uncommitted changes
+
+Repository states are cached in the workspace state file and git only runs
+in repositories whose HEAD, index, tracked files or ignore rules changed.
+Use --refresh to check every repository again.`,
 		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mgr, err := manager.LoadFromCurrentDir()
-			if err != nil {
-				return fmt.Errorf("loading workspace: %w", err)
+			// A running daemon serves recursive status without loading the
+			// workspace; strict mode and the local cache need the load
+			var mgr *manager.Manager
+			if recursive && !refresh && !cacheStats && !a.strict {
+				mgr, _ = manager.ConnectDaemonFromCurrentDir()
+			}
+			if mgr == nil {
+				var err error
+				if mgr, err = a.loadManager(); err != nil {
+					return fmt.Errorf("loading workspace: %w", err)
+				}
 			}
 			
 			path := ""
@@ -263,11 +334,20 @@ func (a *App) newStatusCmd() *cobra.Command {
 				path = args[0]
 			}
 			
-			return mgr.StatusNode(path, recursive)
+			mgr.SetStatusRefresh(refresh)
+			if err := mgr.StatusNode(path, recursive); err != nil {
+				return err
+			}
+			if cacheStats {
+				return mgr.ShowStatusCacheStats()
+			}
+			return nil
 		},
 	}
 	
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Show status recursively")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore cached repository states")
+	cmd.Flags().BoolVar(&cacheStats, "cache-stats", false, "Show status cache statistics")
 	
 	return cmd
 }
@@ -275,14 +355,16 @@ func (a *App) newStatusCmd() *cobra.Command {
 // newTreeCmd creates the tree command
 func (a *App) newTreeCmd() *cobra.Command {
 	var depth int
+	var refresh bool
 	
 	cmd := &cobra.Command{
 		Use:   "tree [path]",
 		Short: "Display workspace tree structure",
 		Long:  `Display the tree structure of the workspace from current or specified node.`,
 		Args:  cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mgr, err := manager.LoadFromCurrentDir()
+			mgr, err := a.loadManager()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
@@ -292,15 +374,64 @@ func (a *App) newTreeCmd() *cobra.Command {
 				path = args[0]
 			}
 			
+			mgr.SetStatusRefresh(refresh)
 			return mgr.ShowTreeAtPath(path, depth)
 		},
 	}
 	
 	cmd.Flags().IntVarP(&depth, "depth", "d", 0, "Maximum depth to display (0 for unlimited)")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore cached repository states")
 	
 	return cmd
 }
 
+// newUICmd creates the ui command
+func (a *App) newUICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ui [path]",
+		Short: "Browse the workspace tree interactively",
+		Long: `Opens a full-screen browser of the workspace tree with the live git
+status of each cloned repository.
+
+Keys:
+  ↑/↓ or j/k   Move the selection
+  →/l ←/h      Expand or collapse a node
+  space        Toggle a node
+  /            Filter by name (Esc clears the filter)
+  c            Clone the selected repository
+  p            Pull the selected repository
+  s            Refresh the status of the selected repository
+  enter        Print the directory of the selected node and exit
+  q            Exit without selecting
+
+The shell integration from 'muno shell-init' adds a function (mcdu by
+default) that runs muno ui and changes to the selected directory.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+
+			opts := manager.UIOptions{}
+			if len(args) > 0 {
+				opts.Start = args[0]
+			}
+			dir, err := mgr.RunUI(opts)
+			if err != nil {
+				return err
+			}
+			if dir != "" {
+				fmt.Fprintln(a.stdout, dir)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
 // newRemoveCmd creates the remove command
 func (a *App) newRemoveCmd() *cobra.Command {
 	return &cobra.Command{
@@ -308,7 +439,7 @@ func (a *App) newRemoveCmd() *cobra.Command {
 		Short: "Remove a child repository",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mgr, err := manager.LoadFromCurrentDir()
+			mgr, err := a.loadManager()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
@@ -330,7 +461,7 @@ func (a *App) newCloneCmd() *cobra.Command {
 Use --include-lazy to also clone lazy repositories.`,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mgr, err := manager.LoadFromCurrentDir()
+			mgr, err := a.loadManager()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
@@ -345,6 +476,26 @@ Use --include-lazy to also clone lazy repositories.`,
 	return cmd
 }
 
+// newSyncCmd creates the sync command
+func (a *App) newSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Regenerate generate: nodes from their repository sources",
+		Long: `Reads the source of every generate: node again - a listing file, a
+directory of bare repositories or a plugin catalog - and rewrites the
+generated config holding its children. Repositories added to or removed
+from a source show up in the tree afterwards.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.Sync()
+		},
+	}
+}
 
 func (a *App) newPathCmd() *cobra.Command {
 	var ensure bool
@@ -373,13 +524,14 @@ Examples:
   muno path team/backend        # Resolve to physical path
   muno path ../frontend --ensure # Resolve and clone if needed`,
 		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			target := "."
 			if len(args) > 0 {
 				target = args[0]
 			}
 			
-			mgr, err := manager.LoadFromCurrentDir()
+			mgr, err := a.loadManager()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
@@ -600,6 +752,65 @@ Examples:
 }
 
 
+// newFetchCmd creates the fetch command
+func (a *App) newFetchCmd() *cobra.Command {
+	var recursive bool
+	var all bool
+	var prune bool
+	var tags bool
+	var every time.Duration
+	
+	cmd := &cobra.Command{
+		Use:   "fetch [path]",
+		Short: "Fetch remote refs without changing working trees",
+		Long: `Fetch remote refs for cloned repositories and record when each fetch succeeded.
+
+'muno status' uses the recorded times to show how stale remote information is
+and how many upstream commits each repository is behind.
+
+Use --all to fetch every cloned repository in the workspace.
+Use --every to keep fetching on a schedule until interrupted, e.g. from cron
+or a terminal. 'muno daemon' does the same when git.fetch_interval is set.`,
+		Example: `  muno fetch --all --prune
+  muno fetch backend -r
+  muno fetch --all --every 15m`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			defer mgr.Close()
+			
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			if all {
+				path = ""
+				recursive = true
+			}
+			
+			opts := manager.FetchOptions{Recursive: recursive, Prune: prune, Tags: tags}
+			if every > 0 {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return mgr.FetchPeriodically(ctx, path, every, opts)
+			}
+			return mgr.FetchNodes(path, opts)
+		},
+	}
+	
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Fetch recursively in subtree")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Fetch all cloned repositories in workspace")
+	cmd.Flags().BoolVarP(&prune, "prune", "p", false, "Remove remote-tracking refs deleted upstream")
+	cmd.Flags().BoolVar(&tags, "tags", false, "Fetch all tags")
+	cmd.Flags().DurationVar(&every, "every", 0, "Repeat the fetch at this interval until interrupted")
+	
+	return cmd
+}
+
 // newPullCmd creates the pull command
 func (a *App) newPullCmd() *cobra.Command {
 	var recursive bool
@@ -608,6 +819,8 @@ func (a *App) newPullCmd() *cobra.Command {
 	var configOverrides []string
 	var branch string
 	var parallel int
+	var topo bool
+	var dependentsOf string
 	
 	cmd := &cobra.Command{
 		Use:   "pull [path]",
@@ -622,10 +835,13 @@ Target is determined by:
 
 Use --all to pull all cloned repositories in the workspace.
 Use --force to override local changes.
+Use --topo to pull in dependency order, or --dependents-of to pull only a node
+and the nodes that depend on it.
 Note: This command only pulls already cloned repositories. Use 'muno clone' first for new repositories.`,
 		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mgr, err := manager.LoadFromCurrentDir()
+			mgr, err := a.loadManager()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
@@ -675,6 +891,14 @@ Note: This command only pulls already cloned repositories. Use 'muno clone' firs
 				recursive = true
 			}
 			
+			// Dependency-ordered pull covers the whole subtree
+			if topo || dependentsOf != "" {
+				return mgr.PullNodeOrdered(path, force, manager.OrderOptions{
+					Topological:  true,
+					DependentsOf: dependentsOf,
+				})
+			}
+			
 			// Pull command never clones new repositories
 			return mgr.PullNode(path, recursive, force)
 		},
@@ -686,26 +910,52 @@ Note: This command only pulls already cloned repositories. Use 'muno clone' firs
 	cmd.Flags().StringSliceVar(&configOverrides, "config", nil, "Override config values (key=value)")
 	cmd.Flags().StringVar(&branch, "branch", "", "Override default branch for this operation")
 	cmd.Flags().IntVar(&parallel, "parallel", 0, "Max parallel pull operations")
+	cmd.Flags().BoolVar(&topo, "topo", false, "Pull in dependency order (dependencies first)")
+	cmd.Flags().StringVar(&dependentsOf, "dependents-of", "", "Only pull this node and nodes that depend on it")
 	
 	return cmd
 }
 
 // newCommitCmd creates the commit command
 func (a *App) newCommitCmd() *cobra.Command {
-	var message string
-	var recursive bool
+	var opts manager.CommitOptions
 	
 	cmd := &cobra.Command{
-		Use:   "commit [path]",
+		Use:   "commit [path] [-- pathspec...]",
 		Short: "Commit changes at current or specified node",
-		Long: `Commit changes across repositories at the current node.`,
-		Args: cobra.MaximumNArgs(1),
+		Long: `Commit changes across repositories at the current node.
+
+With --recursive only repositories that have something to commit are
+committed, after showing their combined diffstat: staged changes by default,
+modified tracked files with --all, or the files matching the pathspecs given
+after --, which are staged in each repository first.
+
+Examples:
+  muno commit -m "Fix login"                   # Commit staged changes here
+  muno commit -r -a -m "Bump deps"             # Commit all tracked changes in the subtree
+  muno commit -r -m "Docs" -- README.md docs/  # Stage and commit matching files
+  muno commit -r -i -a -m "Fix" --change-id    # Pick repositories and link the commits`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				args = args[:dash]
+			}
+			return cobra.MaximumNArgs(1)(cmd, args)
+		},
+		ValidArgsFunction: completeTreePaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if message == "" {
-				return fmt.Errorf("commit message is required")
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				opts.Paths = args[dash:]
+				args = args[:dash]
+			}
+			if opts.ChangeID == "auto" {
+				id, err := manager.NewChangeID()
+				if err != nil {
+					return err
+				}
+				opts.ChangeID = id
 			}
 			
-			mgr, err := manager.LoadFromCurrentDir()
+			mgr, err := a.loadManager()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
@@ -715,49 +965,836 @@ func (a *App) newCommitCmd() *cobra.Command {
 				path = args[0]
 			}
 			
-			return mgr.CommitNode(path, message, recursive)
+			return mgr.CommitNodeWithOptions(path, opts)
 		},
 	}
 	
-	cmd.Flags().StringVarP(&message, "message", "m", "", "Commit message (required)")
-	cmd.MarkFlagRequired("message")
-	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Commit recursively in subtree")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Commit message (required unless amending)")
+	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", false, "Commit changed repositories in subtree")
+	cmd.Flags().BoolVarP(&opts.All, "all", "a", false, "Stage modified and deleted tracked files")
+	cmd.Flags().BoolVar(&opts.Amend, "amend", false, "Amend the last commit, unless it is already pushed")
+	cmd.Flags().BoolVarP(&opts.NoVerify, "no-verify", "n", false, "Skip commit hooks")
+	cmd.Flags().BoolVarP(&opts.Select, "interactive", "i", false, "Choose which changed repositories to commit")
+	cmd.Flags().StringVar(&opts.ChangeID, "change-id", "", "Add a "+manager.ChangeIDTrailer+" trailer linking the commits (generated when no id is given)")
+	cmd.Flags().Lookup("change-id").NoOptDefVal = "auto"
 	
 	return cmd
 }
 
 // newPushCmd creates the push command
 func (a *App) newPushCmd() *cobra.Command {
-	var recursive bool
+	var opts manager.PushOptions
 	
 	cmd := &cobra.Command{
 		Use:   "push [path]",
 		Short: "Push changes from current or specified node",
-		Long: `Push committed changes from repositories at the current node.`,
+		Long: `Push committed changes from repositories at the current node.
+
+Branches without an upstream are pushed to the default remote with the upstream
+set. Repositories with nothing to push, including branches only behind their
+upstream, or a detached HEAD are skipped. --force-with-lease overwrites the
+remote only where history diverged, unless it changed since the last fetch.
+Pushes to branches listed in git.protected_branches are refused:
+
+  overrides:
+    git:
+      protected_branches: [main, "release/*"]
+
+With -r every repository in the subtree is pushed and a summary table is
+printed; the command fails if any push failed or was refused.`,
 		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			
+			return mgr.PushNodeWithOptions(path, opts)
+		},
+	}
+	
+	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", false, "Push recursively in subtree")
+	cmd.Flags().BoolVar(&opts.ForceWithLease, "force-with-lease", false, "Overwrite remote history unless it changed since the last fetch")
+	
+	return cmd
+}
+
+// newChangeCmd creates the change command
+func (a *App) newChangeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "change",
+		Short: "Work on a change spanning several repositories",
+		Long: `Treat a change spanning several repositories as one unit.
+
+'muno change start <name>' creates the topic branch <name> in every repository
+below the given nodes (or the current node). Commit in each repository as
+usual, check progress with 'muno change status', then publish everything with
+'muno change land'.
+
+Landing is all or none: every remote must accept a dry-run push before
+anything is pushed, and if a push still fails the branches already pushed are
+restored to where they were.
+
+'muno change export' writes the change to a single archive for offline review,
+and 'muno change import' applies such an archive to the same nodes of another
+workspace.`,
+	}
+	
+	cmd.AddCommand(a.newChangeStartCmd())
+	cmd.AddCommand(a.newChangeStatusCmd())
+	cmd.AddCommand(a.newChangeLandCmd())
+	cmd.AddCommand(a.newChangeExportCmd())
+	cmd.AddCommand(a.newChangeImportCmd())
+	
+	return cmd
+}
+
+// newChangeStartCmd creates the change start subcommand
+func (a *App) newChangeStartCmd() *cobra.Command {
+	var opts manager.ChangeStartOptions
+	
+	cmd := &cobra.Command{
+		Use:   "start <name> [path...]",
+		Short: "Create the topic branch of a change in selected repositories",
+		Args:  cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeTreePaths(cmd, nil, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			opts.Paths = args[1:]
+			return mgr.StartChange(args[0], opts)
+		},
+	}
+	
+	cmd.Flags().BoolVarP(&opts.Select, "interactive", "i", false, "Choose the repositories that join the change")
+	
+	return cmd
+}
+
+// newChangeStatusCmd creates the change status subcommand
+func (a *App) newChangeStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [name]",
+		Short: "Show the commits and push state of a change in each repository",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			
+			return mgr.ShowChange(name)
+		},
+	}
+}
+
+// newChangeLandCmd creates the change land subcommand
+func (a *App) newChangeLandCmd() *cobra.Command {
+	var opts manager.ChangeLandOptions
+	
+	cmd := &cobra.Command{
+		Use:   "land [name]",
+		Short: "Push a change to every repository, all or none",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			
+			return mgr.LandChange(name, opts)
+		},
+	}
+	
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Only check that every remote would accept the push")
+	
+	return cmd
+}
+
+// newChangeExportCmd creates the change export subcommand
+func (a *App) newChangeExportCmd() *cobra.Command {
+	var opts manager.ChangeExportOptions
+	
+	cmd := &cobra.Command{
+		Use:   "export [name]",
+		Short: "Write a change to an archive for offline review",
+		Long: `Write the commits of a change in every repository to a single archive.
+
+The archive holds a git bundle (or with --format patch, a format-patch series)
+per repository and a manifest of tree paths, base commits and branches.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			
+			return mgr.ExportChange(name, opts)
+		},
+	}
+	
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Archive to write (default: <name>.muno-change.tar.gz)")
+	cmd.Flags().StringVar(&opts.Format, "format", manager.ChangeFormatBundle, "Archive contents: bundle or patch")
+	
+	return cmd
+}
+
+// newChangeImportCmd creates the change import subcommand
+func (a *App) newChangeImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <archive>",
+		Short: "Apply a change archive to the matching nodes",
+		Long: `Apply a change archive written by 'muno change export'.
+
+Each repository in the manifest is applied to the node at the same tree path in
+this workspace and switched to the change branch. All nodes must be cloned and
+clean; if one fails, the repositories already imported are switched back.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.ImportChange(args[0])
+		},
+	}
+}
+
+// newConfigCmd creates the config command
+func (a *App) newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the workspace configuration file",
+		Long: `Manage the workspace configuration file.
+
+The workspace config may be written as muno.yaml, muno.json or muno.toml; all
+three share the same schema, and file: nodes may point to sub-configs in any of
+them. Files are looked up in the order of files.config_names, and muno writes
+changes back in the format a file was loaded from.`,
+	}
+	
+	cmd.AddCommand(a.newConfigConvertCmd())
+	
+	return cmd
+}
+
+// newConfigConvertCmd creates the config convert subcommand
+func (a *App) newConfigConvertCmd() *cobra.Command {
+	var format string
+	
+	cmd := &cobra.Command{
+		Use:   "convert --to <yaml|json|toml>",
+		Short: "Rewrite the workspace config in another format",
+		Long: `Rewrite the workspace config in another format and remove the original,
+e.g. muno.yaml becomes muno.json. Comments in YAML files are not carried over.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.ConvertConfig(format)
+		},
+	}
+	
+	cmd.Flags().StringVar(&format, "to", "", "Target format: yaml, json or toml")
+	
+	return cmd
+}
+
+// newDoctorCmd creates the doctor command
+func (a *App) newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Report configs in the tree that cannot be loaded",
+		Long: `Walks the whole tree and lists every node whose config could not be
+loaded - missing or unparsable config files, auto-discovered muno.yaml files
+in cloned repositories, remote configs that cannot be fetched and generated
+configs that were never written - with the file and the error.
+
+muno tree and muno status show these nodes as error nodes; --strict turns
+them into a failure of every command. doctor exits non-zero when it finds any.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Report the problems instead of failing on them while loading
 			mgr, err := manager.LoadFromCurrentDir()
 			if err != nil {
 				return fmt.Errorf("loading workspace: %w", err)
 			}
 			
+			return mgr.Doctor()
+		},
+	}
+}
+
+// newUndoCmd creates the undo command
+func (a *App) newUndoCmd() *cobra.Command {
+	var opts manager.UndoOptions
+	
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Revert the last pull, commit or change start across repositories",
+		Long: `Revert the last pull, commit, change start or change import recorded in the
+operation journal.
+
+Every repository the operation moved is reset to the commit it was at before.
+Undoing a commit keeps its changes staged; undoing a pull stashes local changes
+and restores them afterwards. Undoing a change start or import switches each
+repository back to the branch it was on, deletes the change branch and forgets
+the change. Undo refuses to run when any of those repositories has moved on
+since, so later work is never lost, and when a commit to undo has already been
+pushed. Run it again to undo the operation before.`,
+		Example: `  muno undo --dry-run
+  muno undo
+  muno undo --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			return mgr.Undo(opts)
+		},
+	}
+	
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be reverted")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Do not ask for confirmation")
+	
+	return cmd
+}
+
+
+// newGraphCmd creates the graph command
+func (a *App) newGraphCmd() *cobra.Command {
+	var format string
+	
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show the cross-repository dependency graph",
+		Long: `Show dependencies between repositories in the workspace.
+
+Dependencies are detected from:
+- depends_on entries in muno.yaml node definitions
+- go.mod require/replace directives
+- package.json dependencies
+- pom.xml parent and dependency coordinates
+
+Output formats: dot (Graphviz) and mermaid.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.ShowGraph(format)
+		},
+	}
+	
+	cmd.Flags().StringVar(&format, "format", manager.GraphFormatDOT, "Output format (dot, mermaid)")
+	
+	return cmd
+}
+
+// newExecCmd creates the exec command
+func (a *App) newExecCmd() *cobra.Command {
+	var path string
+	var topo bool
+	var dependentsOf string
+	var keepGoing bool
+	
+	cmd := &cobra.Command{
+		Use:   "exec -- <command> [args...]",
+		Short: "Run a command in each cloned repository",
+		Long: `Run a shell command in every cloned repository under the current or specified node.
+
+A single argument is run as a shell command line, so pipes and && work
+("muno exec -- 'make && make test'"). Several arguments are quoted one by one
+and keep their boundaries ("muno exec -- grep -r 'foo bar' .").
+
+Error output of the command goes to stderr with each line prefixed by the
+node it ran in.
+
+Use --topo to run in dependency order (dependencies first), or --dependents-of
+to run only on a node and the nodes that depend on it.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.ExecNode(path, manager.ShellCommand(args), manager.ExecOptions{
+				OrderOptions: manager.OrderOptions{
+					Topological:  topo || dependentsOf != "",
+					DependentsOf: dependentsOf,
+				},
+				KeepGoing: keepGoing,
+			})
+		},
+	}
+	
+	cmd.Flags().StringVar(&path, "path", "", "Tree path to run under (default: current node)")
+	cmd.Flags().BoolVar(&topo, "topo", false, "Run in dependency order (dependencies first)")
+	cmd.Flags().StringVar(&dependentsOf, "dependents-of", "", "Only run on this node and nodes that depend on it")
+	cmd.Flags().BoolVarP(&keepGoing, "keep-going", "k", false, "Continue after a repository fails")
+	
+	return cmd
+}
+
+// newGoWorkCmd creates the gowork command
+func (a *App) newGoWorkCmd() *cobra.Command {
+	var recursive bool
+	
+	cmd := &cobra.Command{
+		Use:   "gowork [path]",
+		Short: "Generate go.work from Go modules in cloned nodes",
+		Long: `Generate or update a go.work file that uses every Go module found in cloned nodes.
+
+The go.work file is written at the workspace root, or in the directory of the
+specified node. Existing go, toolchain, godebug and replace directives are preserved.
+Generated go.work files are kept in sync when nodes are added, removed or cloned,
+and go.work is added to .gitignore.`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
 			path := ""
 			if len(args) > 0 {
 				path = args[0]
 			}
 			
-			return mgr.PushNode(path, recursive)
+			return mgr.GenerateGoWork(path, manager.GoWorkOptions{Recursive: recursive})
 		},
 	}
 	
-	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Push recursively in subtree")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Include modules from the whole subtree")
 	
 	return cmd
 }
 
+// newIDECmd creates the ide command
+func (a *App) newIDECmd() *cobra.Command {
+	var includeLazy bool
+	
+	cmd := &cobra.Command{
+		Use:   "ide <vscode|jetbrains> [path]",
+		Short: "Generate an IDE multi-root workspace from the tree",
+		Long: `Generate an IDE workspace listing every cloned node under the current or specified node.
 
-// newVersionCmd creates the version command
+vscode:    writes <name>.code-workspace with one folder per node, named by tree path
+jetbrains: writes .idea/modules.xml with one module per node
+
+Use --include-lazy to clone lazy nodes first so they are included.
+Generated workspaces are regenerated when nodes are added, removed or cloned.`,
+		Args:      cobra.RangeArgs(1, 2),
+		ValidArgs: []string{manager.IDEVSCode, manager.IDEJetBrains},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			path := ""
+			if len(args) > 1 {
+				path = args[1]
+			}
+			
+			return mgr.GenerateIDEWorkspace(args[0], path, manager.IDEOptions{IncludeLazy: includeLazy})
+		},
+	}
+	
+	cmd.Flags().BoolVar(&includeLazy, "include-lazy", false, "Clone lazy nodes and include them")
+	
+	return cmd
+}
+
+// newContextCmd creates the context command
+func (a *App) newContextCmd() *cobra.Command {
+	var write bool
+	var refresh bool
+	var recursive bool
+	var files []string
+	
+	cmd := &cobra.Command{
+		Use:   "context [path]",
+		Short: "Generate AI agent context for a node",
+		Long: `Generate workspace context for coding agents running inside a node.
+
+The context includes the tree with a "YOU ARE HERE" marker, parent, sibling and
+child nodes with their URLs and branches, and related nodes from the dependency graph.
+
+Without flags the context is printed. Use --write to write it into CLAUDE.md and
+AGENTS.md in the node directory; content outside the muno block is preserved.
+Use --refresh to rewrite only files that already contain a muno block, and -r to
+process every cloned node in the subtree.`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			
+			return mgr.ShowContext(path, manager.ContextOptions{
+				Write:     write,
+				Refresh:   refresh,
+				Recursive: recursive,
+				Files:     files,
+			})
+		},
+	}
+	
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "Write context files into node directories")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Rewrite only existing generated context files")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Process every cloned node in the subtree")
+	cmd.Flags().StringSliceVar(&files, "file", nil, "Context file names (default: CLAUDE.md, AGENTS.md)")
+	
+	return cmd
+}
+
+// newAgentCmd creates the agent command with its session subcommands
+func (a *App) newAgentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Manage coding agent sessions in nodes",
+		Long: `Launch and track coding agent sessions running inside node directories.
+
+Sessions run the command from the agent.command setting (default: claude) with
+the generated tree context written to CLAUDE.md/AGENTS.md and exposed through
+MUNO_CONTEXT_FILE. Sessions are hosted in tmux when available, otherwise in the
+background with output logged under .muno/sessions. Configure with:
+
+  overrides:
+    agent:
+      command: "codex"
+      terminal: "tmux"   # auto, tmux or background
+
+Active sessions are marked with 🤖 in 'muno tree'.`,
+	}
+	
+	cmd.AddCommand(a.newAgentStartCmd())
+	cmd.AddCommand(a.newAgentListCmd())
+	cmd.AddCommand(a.newAgentAttachCmd())
+	cmd.AddCommand(a.newAgentStopCmd())
+	
+	return cmd
+}
+
+// newAgentStartCmd creates the agent start subcommand
+func (a *App) newAgentStartCmd() *cobra.Command {
+	var command string
+	var terminal string
+	
+	cmd := &cobra.Command{
+		Use:   "start [path]",
+		Short: "Start an agent session in a node",
+		Args:  cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			
+			return mgr.StartAgent(path, manager.AgentOptions{
+				Command:  command,
+				Terminal: terminal,
+			})
+		},
+	}
+	
+	cmd.Flags().StringVarP(&command, "command", "c", "", "Command to launch (default: agent.command)")
+	cmd.Flags().StringVar(&terminal, "terminal", "", "Session host: auto, tmux or background (default: agent.terminal)")
+	
+	return cmd
+}
+
+// newAgentListCmd creates the agent list subcommand
+func (a *App) newAgentListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List agent sessions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.ListAgents()
+		},
+	}
+}
+
+// newAgentAttachCmd creates the agent attach subcommand
+func (a *App) newAgentAttachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "attach [path]",
+		Short: "Attach to a node's agent session",
+		Long: `Attach to a node's agent session.
+
+tmux sessions are attached directly; background sessions follow the session log.`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			
+			return mgr.AttachAgent(path)
+		},
+	}
+}
+
+// newAgentStopCmd creates the agent stop subcommand
+func (a *App) newAgentStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop [path]",
+		Short: "Stop a node's agent session",
+		Args:  cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTreePaths,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			
+			return mgr.StopAgent(path)
+		},
+	}
+}
+
+// newStatsCmd creates the stats command
+func (a *App) newStatsCmd() *cobra.Command {
+	var opts manager.StatsOptions
+	
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show timings of git operations per repository",
+		Long: `Show metrics recorded by muno commands in this workspace.
+
+Every clone, pull, fetch, push, commit and status is timed per repository and
+added to .muno/metrics.json, so the slowest repositories stand out. Metrics can
+be exported in Prometheus text format or as OpenTelemetry (OTLP/JSON), to stdout,
+a file, or an OTLP/HTTP collector.`,
+		Example: `  muno stats
+  muno stats --top 5
+  muno stats --format prometheus --output metrics.prom
+  muno stats --otlp-endpoint http://localhost:4318
+  muno stats --reset`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			return mgr.ShowStats(opts)
+		},
+	}
+	
+	cmd.Flags().StringVarP(&opts.Format, "format", "f", manager.StatsFormatTable, "Output format (table, prometheus, otlp)")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Write the export to a file")
+	cmd.Flags().StringVar(&opts.OTLPEndpoint, "otlp-endpoint", "", "Send metrics to an OTLP/HTTP collector")
+	cmd.Flags().IntVar(&opts.Top, "top", 10, "Number of slowest repositories to show (0 = all)")
+	cmd.Flags().BoolVar(&opts.Reset, "reset", false, "Clear recorded metrics")
+	
+	return cmd
+}
+
+// newJournalCmd creates the journal command
+func (a *App) newJournalCmd() *cobra.Command {
+	var node string
+	var since string
+	
+	cmd := &cobra.Command{
+		Use:   "journal",
+		Short: "Show the history of operations that changed the workspace",
+		Long: `Show the operation journal of this workspace.
+
+Every pull, commit, push, add and remove appends an entry to .muno/journal.jsonl
+with the command line, user, start and end time, and for each repository the
+result and HEAD commit before and after. Use it to reconstruct what a bulk
+operation did.`,
+		Example: `  muno journal
+  muno journal --node team/backend
+  muno journal --since 2h
+  muno journal --since 2026-01-31`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := manager.JournalOptions{Node: node}
+			if since != "" {
+				t, err := manager.ParseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				opts.Since = t
+			}
+			
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			return mgr.ShowJournal(opts)
+		},
+	}
+	
+	cmd.Flags().StringVar(&node, "node", "", "Only show operations on this node or below it")
+	cmd.Flags().StringVar(&since, "since", "", "Only show operations since a duration ago (2h, 3d) or a date")
+	cmd.RegisterFlagCompletionFunc("node", completeTreePaths)
+	
+	return cmd
+}
+
+// newDaemonCmd creates the daemon command with its control subcommands
+func (a *App) newDaemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Serve the workspace tree from a background process",
+		Long: `Run a daemon that keeps the workspace tree, status cache and config
+watchers in memory and serves them on a local Unix socket
+(.muno/daemon.sock). Only the user running the daemon can use the socket.
+
+While the daemon runs, 'muno status -r' reads the tree and node status from
+it instead of loading every config and running git in every repository, and
+shell completion lists child nodes from it. Both ask the daemon first and
+load the workspace only when no daemon answers. Other commands,
+including tree and list, always load the workspace themselves. Config
+changes are picked up automatically.
+
+Examples:
+  muno daemon &          # Serve the current workspace
+  muno daemon status     # Show whether a daemon is running
+  muno daemon stop       # Stop it`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			defer mgr.Close()
+			
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			
+			return mgr.RunDaemon(ctx)
+		},
+	}
+	
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show whether a daemon serves this workspace",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.DaemonStatus()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stop",
+		Short: "Stop the daemon serving this workspace",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mgr, err := a.loadManager()
+			if err != nil {
+				return fmt.Errorf("loading workspace: %w", err)
+			}
+			
+			return mgr.StopDaemon()
+		},
+	})
+	
+	return cmd
+}
 
+// completeTreePaths completes a tree path argument, using the daemon when it runs
+func completeTreePaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	
+	// Ask the daemon first; only without one is the workspace loaded
+	mgr, err := manager.ConnectDaemonFromCurrentDir()
+	if err != nil {
+		mgr, err = manager.LoadFromCurrentDir()
+	}
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	
+	paths, err := mgr.CompleteTreePaths(toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	
+	directive := cobra.ShellCompDirectiveNoFileComp
+	for _, p := range paths {
+		if strings.HasSuffix(p, "/") {
+			// Let the user keep descending into parents
+			directive |= cobra.ShellCompDirectiveNoSpace
+			break
+		}
+	}
+	return paths, directive
+}
 
 // newVersionCmd creates the version command
 func (a *App) newVersionCmd() *cobra.Command {
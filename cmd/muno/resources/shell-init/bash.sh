@@ -73,6 +73,27 @@ _{{CMD_NAME}}_complete() {
 }
 complete -F _{{CMD_NAME}}_complete {{CMD_NAME}}
 
+# Browse the tree with muno ui and cd into the selected node
+{{CMD_NAME}}u() {
+    local muno_cmd="muno"
+    if command -v muno-local >/dev/null 2>&1; then
+        muno_cmd="muno-local"
+    fi
+
+    local selected
+    selected=$($muno_cmd ui "$@") || return 1
+    [ -z "$selected" ] && return 0
+
+    if [ -d "$selected" ]; then
+        _MUNO_PREV="$($muno_cmd path . --relative 2>/dev/null || echo '/')"
+        cd "$selected"
+        echo "📍 $($muno_cmd path . --relative 2>/dev/null || pwd)"
+    else
+        echo "❌ Not a directory: $selected" >&2
+        return 1
+    fi
+}
+
 # Optional aliases
 alias {{CMD_NAME}}t='muno tree'
 alias {{CMD_NAME}}s='muno status --recursive'
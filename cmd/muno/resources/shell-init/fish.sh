@@ -75,6 +75,27 @@ end
 
 complete -c {{CMD_NAME}} -a '(__{{CMD_NAME}}_complete)'
 
+# Browse the tree with muno ui and cd into the selected node
+function {{CMD_NAME}}u
+    set -l muno_cmd "muno"
+    if command -v muno-local >/dev/null 2>&1
+        set muno_cmd "muno-local"
+    end
+
+    set -l selected ($muno_cmd ui $argv)
+    or return 1
+    test -z "$selected" && return 0
+
+    if test -d "$selected"
+        set -g _MUNO_PREV ($muno_cmd path . --relative 2>/dev/null; or echo '/')
+        cd $selected
+        echo "📍 "($muno_cmd path . --relative 2>/dev/null; or pwd)
+    else
+        echo "❌ Not a directory: $selected" >&2
+        return 1
+    end
+end
+
 # Optional aliases
 alias {{CMD_NAME}}t='muno tree'
 alias {{CMD_NAME}}s='muno status --recursive'
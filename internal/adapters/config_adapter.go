@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	
 	"github.com/taokim/muno/internal/config"
 	"github.com/taokim/muno/internal/interfaces"
-	"gopkg.in/yaml.v3"
 )
 
 // ConfigAdapter wraps the existing config package to implement ConfigProvider
 type ConfigAdapter struct {
-	cache map[string]interface{}
+	mu      sync.Mutex
+	cache   map[string]interface{}
+	watches ConfigWatchSet
+	done    chan struct{} // Closed by Close so watch forwarders stop
 }
 
 // NewConfigAdapter creates a new config adapter
@@ -24,84 +27,87 @@ func NewConfigAdapter() interfaces.ConfigProvider {
 
 // Load loads configuration from a file
 func (c *ConfigAdapter) Load(path string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
 	// Check cache first
 	if cached, ok := c.cache[path]; ok {
 		return cached, nil
 	}
 	
 	// Determine config type based on file extension
-	ext := filepath.Ext(path)
+	format, err := config.FormatForPath(path)
+	if err != nil {
+		return nil, err
+	}
 	
-	switch ext {
-	case ".yaml", ".yml":
-		// Load YAML config
-		data, err := os.ReadFile(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	
+	// Try to load as ConfigTree (muno.yaml, muno.json, muno.toml, ...)
+	if isConfigFileName(filepath.Base(path)) {
+		cfg, err := config.DecodeTree(format, data, config.ConfigDir(path))
 		if err != nil {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
-		}
-		
-		// Try to load as ConfigTree (muno.yaml)
-		if filepath.Base(path) == "muno.yaml" {
-			var cfg config.ConfigTree
-			if err := yaml.Unmarshal(data, &cfg); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-			}
-			c.cache[path] = &cfg
-			return &cfg, nil
-		}
-		
-		// Generic YAML loading
-		var cfg interface{}
-		if err := yaml.Unmarshal(data, &cfg); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
+			return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 		}
+		cfg.Path = filepath.Dir(path)
+		cfg.File = path
 		c.cache[path] = cfg
 		return cfg, nil
-		
-	case ".json":
-		// Load JSON config (if needed in future)
-		return nil, fmt.Errorf("JSON config not yet implemented")
-		
-	default:
-		return nil, fmt.Errorf("unsupported config format: %s", ext)
 	}
+	
+	// Generic loading
+	var cfg interface{}
+	if err := config.Unmarshal(format, data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", format, err)
+	}
+	c.cache[path] = cfg
+	return cfg, nil
 }
 
 // Save saves configuration to a file
 func (c *ConfigAdapter) Save(path string, cfg interface{}) error {
 	// Update cache
+	c.mu.Lock()
 	c.cache[path] = cfg
+	c.mu.Unlock()
 	
 	// Determine format based on extension
-	ext := filepath.Ext(path)
+	format, err := config.FormatForPath(path)
+	if err != nil {
+		return err
+	}
 	
-	switch ext {
-	case ".yaml", ".yml":
-		// Handle ConfigTree specifically
-		if configTree, ok := cfg.(*config.ConfigTree); ok {
-			return configTree.Save(path)
-		}
-		
-		// Generic YAML save
-		data, err := yaml.Marshal(cfg)
-		if err != nil {
-			return fmt.Errorf("failed to marshal config: %w", err)
-		}
-		
-		// Ensure directory exists
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create config directory: %w", err)
+	// Handle ConfigTree specifically
+	if configTree, ok := cfg.(*config.ConfigTree); ok {
+		return configTree.Save(path)
+	}
+	
+	// Generic save
+	data, err := config.Marshal(format, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	
+	// Ensure directory exists
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	
+	return os.WriteFile(path, data, 0644)
+}
+
+// isConfigFileName reports whether name is one of files.config_names
+func isConfigFileName(name string) bool {
+	for _, configName := range config.GetConfigFileNames() {
+		if name == configName {
+			return true
 		}
-		
-		return os.WriteFile(path, data, 0644)
-		
-	case ".json":
-		return fmt.Errorf("JSON config not yet implemented")
-		
-	default:
-		return fmt.Errorf("unsupported config format: %s", ext)
 	}
+	return false
 }
 
 // Exists checks if a config file exists
@@ -110,11 +116,47 @@ func (c *ConfigAdapter) Exists(path string) bool {
 	return err == nil
 }
 
-// Watch watches for configuration changes (placeholder for now)
+// Watch watches config files under path for changes by polling. Cached
+// configs are invalidated before each event is delivered.
 func (c *ConfigAdapter) Watch(path string) (<-chan interfaces.ConfigEvent, error) {
-	// This would use fsnotify or similar in a full implementation
-	// For now, return a dummy channel
-	ch := make(chan interfaces.ConfigEvent)
-	close(ch)
-	return ch, fmt.Errorf("config watching not yet implemented")
-}
\ No newline at end of file
+	source, err := c.watches.Watch(path)
+	if err != nil {
+		return nil, fmt.Errorf("watching %s: %w", path, err)
+	}
+	
+	c.mu.Lock()
+	if c.done == nil {
+		c.done = make(chan struct{})
+	}
+	done := c.done
+	c.mu.Unlock()
+	
+	events := make(chan interfaces.ConfigEvent, cap(source))
+	go func() {
+		defer close(events)
+		for event := range source {
+			c.mu.Lock()
+			delete(c.cache, event.Path)
+			c.mu.Unlock()
+			// A consumer that stopped reading must not keep this goroutine alive
+			select {
+			case events <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+	
+	return events, nil
+}
+
+// Close stops all watchers started by Watch
+func (c *ConfigAdapter) Close() error {
+	c.mu.Lock()
+	if c.done != nil {
+		close(c.done)
+		c.done = nil
+	}
+	c.mu.Unlock()
+	return c.watches.Close()
+}
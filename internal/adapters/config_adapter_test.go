@@ -1,13 +1,16 @@
 package adapters
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
 )
 
 func TestConfigAdapter(t *testing.T) {
@@ -173,12 +176,63 @@ value: 100
 		err := os.WriteFile(configPath, []byte("version: \"3\"\nname: initial"), 0644)
 		require.NoError(t, err)
 		
-		adapter := NewConfigAdapter()
+		adapter := &ConfigAdapter{cache: make(map[string]interface{})}
+		adapter.watches.Interval = 10 * time.Millisecond
+		defer adapter.Close()
+		
+		// Cached configs are dropped when the file changes
+		adapter.cache[configPath] = "stale"
 		events, err := adapter.Watch(configPath)
-		// Currently returns an error as it's not implemented
+		require.NoError(t, err)
+		
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(configPath, later, later))
+		
+		select {
+		case event := <-events:
+			assert.Equal(t, interfaces.ConfigEventModified, event.Type)
+			assert.Equal(t, configPath, event.Path)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for config event")
+		}
+		adapter.mu.Lock()
+		assert.NotContains(t, adapter.cache, configPath)
+		adapter.mu.Unlock()
+		
+		// Closing stops the watcher and closes the channel
+		require.NoError(t, adapter.Close())
+		for range events {
+		}
+		
+		_, err = adapter.Watch(filepath.Join(tmpDir, "missing.yaml"))
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "not yet implemented")
-		assert.NotNil(t, events) // Channel is created but closed
+	})
+	
+	t.Run("Watch without a reader", func(t *testing.T) {
+		root := t.TempDir()
+		writeWatchedConfig(t, filepath.Join(root, "muno.yaml"), &config.ConfigTree{
+			Workspace: config.WorkspaceTree{Name: "ws", ReposDir: ".nodes"},
+		})
+		
+		adapter := &ConfigAdapter{cache: make(map[string]interface{})}
+		adapter.watches.Interval = 10 * time.Millisecond
+		events, err := adapter.Watch(root)
+		require.NoError(t, err)
+		
+		// More events than both channels hold, and nobody reading them
+		for i := 0; i < 40; i++ {
+			writeWatchedConfig(t, filepath.Join(root, ".nodes", fmt.Sprintf("team%02d", i), "muno.yaml"), &config.ConfigTree{})
+		}
+		require.Eventually(t, func() bool { return len(events) == cap(events) }, 5*time.Second, 10*time.Millisecond)
+		
+		// Close stops the blocked forwarder; only what it already delivered
+		// remains, and the channel closes
+		require.NoError(t, adapter.Close())
+		received := 0
+		for range events {
+			received++
+		}
+		assert.Equal(t, cap(events), received)
 	})
 	
 	t.Run("Load JSON config", func(t *testing.T) {
@@ -190,9 +244,35 @@ value: 100
 		require.NoError(t, err)
 		
 		adapter := NewConfigAdapter()
-		_, err = adapter.Load(configPath)
-		// JSON not implemented yet
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "JSON config not yet implemented")
+		cfg, err := adapter.Load(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "test"}, cfg)
+	})
+	
+	t.Run("JSON and TOML workspace configs round-trip", func(t *testing.T) {
+		for _, name := range []string{"muno.json", "muno.toml"} {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, name)
+			
+			saved := &config.ConfigTree{
+				Workspace: config.WorkspaceTree{Name: "test", ReposDir: ".nodes"},
+				Nodes:     []config.NodeDefinition{{Name: "api", URL: "https://github.com/org/api.git", Overrides: map[string]interface{}{"git.default_branch": "develop"}}},
+			}
+			adapter := NewConfigAdapter()
+			require.NoError(t, adapter.Save(configPath, saved))
+			
+			loaded, err := NewConfigAdapter().Load(configPath)
+			require.NoError(t, err, name)
+			tree, ok := loaded.(*config.ConfigTree)
+			require.True(t, ok, name)
+			assert.Equal(t, saved.Nodes, tree.Nodes, name)
+			assert.Equal(t, configPath, tree.File, name)
+		}
+	})
+	
+	t.Run("Unsupported format", func(t *testing.T) {
+		adapter := NewConfigAdapter()
+		_, err := adapter.Load(filepath.Join(t.TempDir(), "config.ini"))
+		assert.EqualError(t, err, "unsupported config format: .ini")
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,331 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/constants"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// DefaultConfigPollInterval is how often watched config files are checked for changes
+const DefaultConfigPollInterval = time.Second
+
+// ConfigWatchSet runs polling config watchers until closed. The zero value is ready to use.
+type ConfigWatchSet struct {
+	Interval time.Duration // Poll interval (default: DefaultConfigPollInterval)
+
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Watch emits events for config files under path. If path is a directory, every
+// muno config in the distributed tree below it is watched, following node
+// directories the same way the tree maps nodes to the filesystem. Local files
+// referenced by config nodes are included. The channel closes when the set is closed.
+func (s *ConfigWatchSet) Watch(path string) (<-chan interfaces.ConfigEvent, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.ctx == nil {
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+	}
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultConfigPollInterval
+	}
+
+	return WatchConfigFiles(ctx, path, interval), nil
+}
+
+// Close stops all watchers started by the set
+func (s *ConfigWatchSet) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.ctx, s.cancel = nil, nil
+	return nil
+}
+
+// configFileStamp identifies a version of a watched file
+type configFileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// WatchConfigFiles polls config files under path until ctx is done
+func WatchConfigFiles(ctx context.Context, path string, interval time.Duration) <-chan interfaces.ConfigEvent {
+	events := make(chan interfaces.ConfigEvent, 16)
+
+	// Snapshot before returning so changes made right after Watch are reported
+	scanner := newConfigScanner()
+	previous := scanner.scan(path)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := scanner.scan(path)
+			for _, event := range diffConfigFiles(previous, current) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			previous = current
+		}
+	}()
+
+	return events
+}
+
+// diffConfigFiles returns events for files created, modified or removed between two scans
+func diffConfigFiles(previous, current map[string]configFileStamp) []interfaces.ConfigEvent {
+	now := time.Now()
+	var events []interfaces.ConfigEvent
+
+	for path, stamp := range current {
+		old, existed := previous[path]
+		switch {
+		case !existed:
+			events = append(events, interfaces.ConfigEvent{Type: interfaces.ConfigEventCreated, Path: path, Timestamp: now})
+		case old != stamp:
+			events = append(events, interfaces.ConfigEvent{Type: interfaces.ConfigEventModified, Path: path, Timestamp: now})
+		}
+	}
+	for path := range previous {
+		if _, exists := current[path]; !exists {
+			events = append(events, interfaces.ConfigEvent{Type: interfaces.ConfigEventRemoved, Path: path, Timestamp: now})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+// scanConfigFiles collects the config files to watch below path with their stamps
+func scanConfigFiles(path string) map[string]configFileStamp {
+	return newConfigScanner().scan(path)
+}
+
+// configScanner collects the config files of a tree. Between scans it keeps
+// what it parsed and listed, so a poll of an unchanged tree only stats files
+// and directories; configs are parsed again only when they or a file they
+// include change, and directories are listed again only when their mtime does.
+type configScanner struct {
+	configs  map[string]*scannedConfig
+	listings map[string]*dirListing
+}
+
+// scannedConfig is what the tree walk needs from a parsed config
+type scannedConfig struct {
+	deps     map[string]configFileStamp // The config and the local files it references, as parsed
+	refs     []string                   // Local files referenced by includes and nodes
+	childDir string                     // Where the config's child nodes live
+	broken   bool                       // The config did not parse; its children are not walked
+}
+
+// dirListing holds the subdirectories of a directory at one mtime
+type dirListing struct {
+	modTime time.Time
+	dirs    []string
+}
+
+func newConfigScanner() *configScanner {
+	return &configScanner{
+		configs:  make(map[string]*scannedConfig),
+		listings: make(map[string]*dirListing),
+	}
+}
+
+// scan returns the config files to watch below path with their stamps.
+// Parses and listings not used by this scan are dropped.
+func (s *configScanner) scan(path string) map[string]configFileStamp {
+	files := make(map[string]configFileStamp)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return files
+	}
+	if !info.IsDir() {
+		files[path] = stampOf(info)
+		return files
+	}
+
+	next := &configScanner{
+		configs:  make(map[string]*scannedConfig),
+		listings: make(map[string]*dirListing),
+	}
+	s.scanDir(path, files, make(map[string]bool), next)
+	s.configs, s.listings = next.configs, next.listings
+	return files
+}
+
+// scanDir records config files in a node directory and descends into its
+// child nodes. Parses and listings it uses are kept in next.
+func (s *configScanner) scanDir(dir string, files map[string]configFileStamp, visited map[string]bool, next *configScanner) {
+	if visited[dir] {
+		return
+	}
+	visited[dir] = true
+
+	configPath := ""
+	for _, name := range config.GetConfigFileNames() {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			files[candidate] = stampOf(info)
+			if configPath == "" {
+				configPath = candidate
+			}
+		}
+	}
+
+	// Children live in repos_dir of a config or git repo, and directly below other parents
+	childDir := dir
+	if configPath != "" {
+		parsed := s.parseConfig(configPath, dir)
+		if parsed == nil {
+			return
+		}
+		next.configs[configPath] = parsed
+		if parsed.broken {
+			return
+		}
+		for _, refPath := range parsed.refs {
+			if info, err := os.Stat(refPath); err == nil && !info.IsDir() {
+				files[refPath] = stampOf(info)
+			}
+		}
+		childDir = parsed.childDir
+	} else if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		childDir = filepath.Join(dir, constants.DefaultReposDir)
+	}
+
+	listing := s.listDir(childDir)
+	if listing == nil {
+		return
+	}
+	next.listings[childDir] = listing
+	for _, name := range listing.dirs {
+		if childDir == dir && strings.HasPrefix(name, ".") {
+			// Hidden directories of a plain parent are not nodes
+			continue
+		}
+		s.scanDir(filepath.Join(childDir, name), files, visited, next)
+	}
+}
+
+// parseConfig returns the parse of the config at configPath in dir, reusing
+// the previous one while the config and the files it references are unchanged
+func (s *configScanner) parseConfig(configPath, dir string) *scannedConfig {
+	if parsed, ok := s.configs[configPath]; ok && unchangedFiles(parsed.deps) {
+		return parsed
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return nil
+	}
+	parsed := &scannedConfig{
+		deps:     map[string]configFileStamp{configPath: stampOf(info)},
+		childDir: filepath.Join(dir, constants.DefaultReposDir),
+	}
+	cfg, err := config.LoadTree(configPath)
+	if err != nil {
+		parsed.broken = true
+		return parsed
+	}
+	if cfg.Workspace.ReposDir != "" {
+		parsed.childDir = filepath.Join(dir, cfg.Workspace.ReposDir)
+	}
+	refs := append([]string(nil), cfg.Include...)
+	for _, node := range cfg.Nodes {
+		refs = append(refs, node.File)
+	}
+	for _, refPath := range refs {
+		if refPath == "" || strings.Contains(refPath, "://") {
+			continue
+		}
+		if !filepath.IsAbs(refPath) {
+			refPath = filepath.Join(dir, refPath)
+		}
+		parsed.refs = append(parsed.refs, refPath)
+		if info, err := os.Stat(refPath); err == nil && !info.IsDir() {
+			parsed.deps[refPath] = stampOf(info)
+		} else {
+			// Parse again once the file shows up
+			parsed.deps[refPath] = configFileStamp{}
+		}
+	}
+	return parsed
+}
+
+// listDir returns the subdirectories of dir other than .git and .muno,
+// reusing the previous listing while the directory's mtime is unchanged
+func (s *configScanner) listDir(dir string) *dirListing {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil
+	}
+	if listing, ok := s.listings[dir]; ok && listing.modTime.Equal(info.ModTime()) {
+		return listing
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	listing := &dirListing{modTime: info.ModTime()}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == ".git" || entry.Name() == ".muno" {
+			continue
+		}
+		listing.dirs = append(listing.dirs, entry.Name())
+	}
+	return listing
+}
+
+// unchangedFiles reports whether every file still has its recorded stamp; a
+// zero stamp stands for a file that did not exist
+func unchangedFiles(stamps map[string]configFileStamp) bool {
+	for path, stamp := range stamps {
+		var current configFileStamp
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			current = stampOf(info)
+		}
+		if current != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// stampOf returns the stamp of a file
+func stampOf(info os.FileInfo) configFileStamp {
+	return configFileStamp{modTime: info.ModTime(), size: info.Size()}
+}
@@ -0,0 +1,181 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+func writeWatchedConfig(t *testing.T, path string, cfg *config.ConfigTree) {
+	t.Helper()
+	require.NoError(t, cfg.Save(path))
+}
+
+func TestScanConfigFiles(t *testing.T) {
+	root := t.TempDir()
+	writeWatchedConfig(t, filepath.Join(root, "muno.yaml"), &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "ws", ReposDir: ".nodes"},
+		Nodes: []config.NodeDefinition{
+			{Name: "team", File: "configs/team.yaml"},
+			{Name: "remote", File: "https://example.com/muno.yaml"},
+		},
+	})
+	writeWatchedConfig(t, filepath.Join(root, "configs", "team.yaml"), &config.ConfigTree{})
+
+	// A repo with its own muno.yaml and a nested child repo
+	platform := filepath.Join(root, ".nodes", "platform")
+	require.NoError(t, os.MkdirAll(filepath.Join(platform, ".git"), 0755))
+	writeWatchedConfig(t, filepath.Join(platform, "muno.yaml"), &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "platform", ReposDir: "repos"},
+	})
+	writeWatchedConfig(t, filepath.Join(platform, "repos", "svc", "muno.yaml"), &config.ConfigTree{})
+
+	// A plain repo whose children live in the default repos dir
+	api := filepath.Join(root, ".nodes", "api")
+	require.NoError(t, os.MkdirAll(filepath.Join(api, ".git"), 0755))
+	writeWatchedConfig(t, filepath.Join(api, ".nodes", "sdk", "muno.yml"), &config.ConfigTree{})
+
+	// Config files inside repository sources are not part of the tree
+	writeWatchedConfig(t, filepath.Join(api, "testdata", "muno.yaml"), &config.ConfigTree{})
+
+	files := scanConfigFiles(root)
+	var paths []string
+	for path := range files {
+		rel, err := filepath.Rel(root, path)
+		require.NoError(t, err)
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	sort.Strings(paths)
+
+	assert.Equal(t, []string{
+		".nodes/api/.nodes/sdk/muno.yml",
+		".nodes/platform/muno.yaml",
+		".nodes/platform/repos/svc/muno.yaml",
+		"configs/team.yaml",
+		"muno.yaml",
+	}, paths)
+}
+
+func TestConfigScanner_ParsesOnlyChangedConfigs(t *testing.T) {
+	root := t.TempDir()
+	rootConfig := filepath.Join(root, "muno.yaml")
+	writeWatchedConfig(t, rootConfig, &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "ws", ReposDir: ".nodes"},
+		Nodes:     []config.NodeDefinition{{Name: "team", File: "team.yaml"}},
+	})
+	teamConfig := filepath.Join(root, ".nodes", "api", "muno.yaml")
+	writeWatchedConfig(t, teamConfig, &config.ConfigTree{})
+
+	scanner := newConfigScanner()
+	files := scanner.scan(root)
+	assert.Contains(t, files, teamConfig)
+	assert.NotContains(t, files, filepath.Join(root, "team.yaml"))
+	parsed := scanner.configs[rootConfig]
+	require.NotNil(t, parsed)
+	listing := scanner.listings[filepath.Join(root, ".nodes")]
+	require.NotNil(t, listing)
+
+	// Unchanged files are only stat'ed
+	scanner.scan(root)
+	assert.Same(t, parsed, scanner.configs[rootConfig])
+	assert.Same(t, listing, scanner.listings[filepath.Join(root, ".nodes")])
+
+	// A referenced file that appears makes the config parse again
+	writeWatchedConfig(t, filepath.Join(root, "team.yaml"), &config.ConfigTree{})
+	files = scanner.scan(root)
+	assert.Contains(t, files, filepath.Join(root, "team.yaml"))
+	assert.NotSame(t, parsed, scanner.configs[rootConfig])
+
+	// A new node directory is listed on the next scan
+	webConfig := filepath.Join(root, ".nodes", "web", "muno.yaml")
+	writeWatchedConfig(t, webConfig, &config.ConfigTree{})
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(root, ".nodes"), later, later))
+	assert.Contains(t, scanner.scan(root), webConfig)
+
+	// Removed nodes are dropped from the kept state
+	require.NoError(t, os.RemoveAll(filepath.Join(root, ".nodes", "api")))
+	require.NoError(t, os.Chtimes(filepath.Join(root, ".nodes"), later.Add(time.Minute), later.Add(time.Minute)))
+	assert.NotContains(t, scanner.scan(root), teamConfig)
+	assert.NotContains(t, scanner.configs, teamConfig)
+}
+
+func TestDiffConfigFiles(t *testing.T) {
+	now := time.Now()
+	previous := map[string]configFileStamp{
+		"/a/muno.yaml": {modTime: now, size: 10},
+		"/b/muno.yaml": {modTime: now, size: 10},
+		"/c/muno.yaml": {modTime: now, size: 10},
+	}
+	current := map[string]configFileStamp{
+		"/a/muno.yaml": {modTime: now, size: 10},
+		"/b/muno.yaml": {modTime: now, size: 12},
+		"/d/muno.yaml": {modTime: now, size: 10},
+	}
+
+	events := diffConfigFiles(previous, current)
+	require.Len(t, events, 3)
+	assert.Equal(t, interfaces.ConfigEventModified, events[0].Type)
+	assert.Equal(t, "/b/muno.yaml", events[0].Path)
+	assert.Equal(t, interfaces.ConfigEventRemoved, events[1].Type)
+	assert.Equal(t, "/c/muno.yaml", events[1].Path)
+	assert.Equal(t, interfaces.ConfigEventCreated, events[2].Type)
+	assert.Equal(t, "/d/muno.yaml", events[2].Path)
+}
+
+func TestWatchConfigFiles(t *testing.T) {
+	root := t.TempDir()
+	writeWatchedConfig(t, filepath.Join(root, "muno.yaml"), &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "ws", ReposDir: ".nodes"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := WatchConfigFiles(ctx, root, 10*time.Millisecond)
+
+	// A teammate's config node appears after a pull
+	nested := filepath.Join(root, ".nodes", "team", "muno.yaml")
+	writeWatchedConfig(t, nested, &config.ConfigTree{})
+
+	select {
+	case event := <-events:
+		assert.Equal(t, interfaces.ConfigEventCreated, event.Type)
+		assert.Equal(t, nested, event.Path)
+		assert.False(t, event.Timestamp.IsZero())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config event")
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestConfigWatchSet(t *testing.T) {
+	var set ConfigWatchSet
+
+	_, err := set.Watch(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	events, err := set.Watch(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, set.Close())
+
+	// The channel closes once the set is closed
+	for range events {
+	}
+
+	// The set can be reused after closing
+	events, err = set.Watch(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, set.Close())
+	for range events {
+	}
+}
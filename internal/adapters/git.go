@@ -32,55 +32,55 @@ func NewRealGitWithExecutor(executor interfaces.CommandExecutor) *RealGit {
 
 // Clone implements GitInterface.Clone
 func (g *RealGit) Clone(url, path string) error {
-	_, err := g.executor.Execute("git", "clone", url, path)
-	return err
+	output, err := g.executor.Execute("git", "clone", url, path)
+	return gitError(err, output)
 }
 
 // CloneWithOptions implements GitInterface.CloneWithOptions
 func (g *RealGit) CloneWithOptions(url, path string, options ...string) error {
 	args := append([]string{"clone"}, options...)
 	args = append(args, url, path)
-	_, err := g.executor.Execute("git", args...)
-	return err
+	output, err := g.executor.Execute("git", args...)
+	return gitError(err, output)
 }
 
 // Pull implements GitInterface.Pull
 func (g *RealGit) Pull(path string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "pull")
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "pull")
+	return gitError(err, output)
 }
 
 // PullWithOptions implements GitInterface.PullWithOptions
 func (g *RealGit) PullWithOptions(path string, options ...string) error {
 	args := append([]string{"pull"}, options...)
-	_, err := g.executor.ExecuteInDir(path, "git", args...)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", args...)
+	return gitError(err, output)
 }
 
 // Push implements GitInterface.Push
 func (g *RealGit) Push(path string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "push")
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "push")
+	return gitError(err, output)
 }
 
 // PushWithOptions implements GitInterface.PushWithOptions
 func (g *RealGit) PushWithOptions(path string, options ...string) error {
 	args := append([]string{"push"}, options...)
-	_, err := g.executor.ExecuteInDir(path, "git", args...)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", args...)
+	return gitError(err, output)
 }
 
 // Fetch implements GitInterface.Fetch
 func (g *RealGit) Fetch(path string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "fetch")
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "fetch")
+	return gitError(err, output)
 }
 
 // FetchWithOptions implements GitInterface.FetchWithOptions
 func (g *RealGit) FetchWithOptions(path string, options ...string) error {
 	args := append([]string{"fetch"}, options...)
-	_, err := g.executor.ExecuteInDir(path, "git", args...)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", args...)
+	return gitError(err, output)
 }
 
 // Status implements GitInterface.Status
@@ -175,51 +175,51 @@ func (g *RealGit) IsRepo(path string) bool {
 // Add implements GitInterface.Add
 func (g *RealGit) Add(path string, files ...string) error {
 	args := append([]string{"add"}, files...)
-	_, err := g.executor.ExecuteInDir(path, "git", args...)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", args...)
+	return gitError(err, output)
 }
 
 // AddAll implements GitInterface.AddAll
 func (g *RealGit) AddAll(path string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "add", "-A")
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "add", "-A")
+	return gitError(err, output)
 }
 
 // Commit implements GitInterface.Commit
 func (g *RealGit) Commit(path, message string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "commit", "-m", message)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "commit", "-m", message)
+	return gitError(err, output)
 }
 
 // CommitWithOptions implements GitInterface.CommitWithOptions
 func (g *RealGit) CommitWithOptions(path, message string, options ...string) error {
 	args := append([]string{"commit", "-m", message}, options...)
-	_, err := g.executor.ExecuteInDir(path, "git", args...)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", args...)
+	return gitError(err, output)
 }
 
 // Checkout implements GitInterface.Checkout
 func (g *RealGit) Checkout(path, branch string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "checkout", branch)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "checkout", branch)
+	return gitError(err, output)
 }
 
 // CheckoutNew implements GitInterface.CheckoutNew
 func (g *RealGit) CheckoutNew(path, branch string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "checkout", "-b", branch)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "checkout", "-b", branch)
+	return gitError(err, output)
 }
 
 // CreateBranch implements GitInterface.CreateBranch
 func (g *RealGit) CreateBranch(path, branch string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "branch", branch)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "branch", branch)
+	return gitError(err, output)
 }
 
 // DeleteBranch implements GitInterface.DeleteBranch
 func (g *RealGit) DeleteBranch(path, branch string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "branch", "-d", branch)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "branch", "-d", branch)
+	return gitError(err, output)
 }
 
 // ListBranches implements GitInterface.ListBranches
@@ -242,14 +242,14 @@ func (g *RealGit) ListBranches(path string) ([]string, error) {
 
 // Tag implements GitInterface.Tag
 func (g *RealGit) Tag(path, tag string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "tag", tag)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "tag", tag)
+	return gitError(err, output)
 }
 
 // TagWithMessage implements GitInterface.TagWithMessage
 func (g *RealGit) TagWithMessage(path, tag, message string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "tag", "-a", tag, "-m", message)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "tag", "-a", tag, "-m", message)
+	return gitError(err, output)
 }
 
 // ListTags implements GitInterface.ListTags
@@ -272,19 +272,52 @@ func (g *RealGit) ListTags(path string) ([]string, error) {
 
 // Reset implements GitInterface.Reset
 func (g *RealGit) Reset(path string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "reset")
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "reset")
+	return gitError(err, output)
 }
 
 // ResetHard implements GitInterface.ResetHard
 func (g *RealGit) ResetHard(path string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "reset", "--hard")
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "reset", "--hard")
+	return gitError(err, output)
 }
 
 // ResetSoft implements GitInterface.ResetSoft
 func (g *RealGit) ResetSoft(path string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "reset", "--soft")
+	output, err := g.executor.ExecuteInDir(path, "git", "reset", "--soft")
+	return gitError(err, output)
+}
+
+// ResetCommit moves the current branch to commit; mode is soft, mixed or hard
+func (g *RealGit) ResetCommit(path, commit, mode string) error {
+	output, err := g.executor.ExecuteInDir(path, "git", "reset", "--"+mode, commit)
+	return gitError(err, output)
+}
+
+// StashPush stashes local changes, including untracked files, and reports
+// whether there was anything to stash
+func (g *RealGit) StashPush(path, message string) (bool, error) {
+	output, err := g.executor.ExecuteInDir(path, "git", "stash", "push", "--include-untracked", "-m", message)
+	if err != nil {
+		return false, gitError(err, output)
+	}
+	return !strings.Contains(string(output), "No local changes to save"), nil
+}
+
+// StashPop restores the most recent stash
+func (g *RealGit) StashPop(path string) error {
+	output, err := g.executor.ExecuteInDir(path, "git", "stash", "pop")
+	return gitError(err, output)
+}
+
+// gitError adds git's output to a failed command's error
+func gitError(err error, output []byte) error {
+	if err == nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(string(output)); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
 	return err
 }
 
@@ -306,6 +339,16 @@ func (g *RealGit) DiffStaged(path string) (string, error) {
 	return string(output), nil
 }
 
+// DiffNumstat returns git diff --numstat output; args select what is compared
+func (g *RealGit) DiffNumstat(path string, args ...string) (string, error) {
+	args = append([]string{"diff", "--numstat", "--no-renames"}, args...)
+	output, err := g.executor.ExecuteInDir(path, "git", args...)
+	if err != nil {
+		return "", gitError(err, output)
+	}
+	return string(output), nil
+}
+
 // DiffWithBranch implements GitInterface.DiffWithBranch
 func (g *RealGit) DiffWithBranch(path, branch string) (string, error) {
 	output, err := g.executor.ExecuteInDir(path, "git", "diff", branch)
@@ -345,14 +388,14 @@ func (g *RealGit) LogOneline(path string, limit int) ([]string, error) {
 
 // AddRemote implements GitInterface.AddRemote
 func (g *RealGit) AddRemote(path, name, url string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "remote", "add", name, url)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "remote", "add", name, url)
+	return gitError(err, output)
 }
 
 // RemoveRemote implements GitInterface.RemoveRemote
 func (g *RealGit) RemoveRemote(path, name string) error {
-	_, err := g.executor.ExecuteInDir(path, "git", "remote", "remove", name)
-	return err
+	output, err := g.executor.ExecuteInDir(path, "git", "remote", "remove", name)
+	return gitError(err, output)
 }
 
 // ListRemotes implements GitInterface.ListRemotes
@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"strconv"
 	"strings"
 	
 	"github.com/taokim/muno/internal/git"
@@ -35,20 +36,85 @@ func (g *GitProviderWrapper) Pull(path string, options interfaces.PullOptions) e
 
 // Push implements GitProvider.Push
 func (g *GitProviderWrapper) Push(path string, options interfaces.PushOptions) error {
-	// Call the underlying RealGit.Push
-	return g.RealGit.Push(path)
+	var args []string
+	if options.ForceWithLease && options.Lease != "" {
+		args = append(args, "--force-with-lease="+options.Lease)
+	} else if options.ForceWithLease {
+		args = append(args, "--force-with-lease")
+	} else if options.Force {
+		args = append(args, "--force")
+	}
+	if options.SetUpstream {
+		args = append(args, "--set-upstream")
+	}
+	if options.Quiet {
+		args = append(args, "--quiet")
+	}
+	if options.DryRun {
+		args = append(args, "--dry-run")
+	}
+	if options.Remote != "" || options.Branch != "" || options.Refspec != "" {
+		remote := options.Remote
+		if remote == "" {
+			remote = "origin"
+		}
+		args = append(args, remote)
+		if options.Refspec != "" {
+			args = append(args, options.Refspec)
+		} else if options.Branch != "" {
+			args = append(args, options.Branch)
+		}
+	}
+	if len(args) == 0 {
+		// Call the underlying RealGit.Push
+		return g.RealGit.Push(path)
+	}
+	return g.RealGit.PushWithOptions(path, args...)
 }
 
 // Commit implements GitProvider.Commit
 func (g *GitProviderWrapper) Commit(path string, message string, options interfaces.CommitOptions) error {
-	// Call the underlying RealGit.Commit
-	return g.RealGit.Commit(path, message)
+	var args []string
+	if options.All {
+		args = append(args, "--all")
+	}
+	if options.Amend {
+		args = append(args, "--amend")
+	}
+	if options.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if options.Amend && message == "" {
+		// Keep the message of the amended commit
+		output, err := g.executor.ExecuteInDir(path, "git", append([]string{"commit", "--no-edit"}, args...)...)
+		return gitError(err, output)
+	}
+	if len(args) == 0 {
+		// Call the underlying RealGit.Commit
+		return g.RealGit.Commit(path, message)
+	}
+	return g.RealGit.CommitWithOptions(path, message, args...)
 }
 
 // Fetch implements GitProvider.Fetch
 func (g *GitProviderWrapper) Fetch(path string, options interfaces.FetchOptions) error {
-	// Call the underlying RealGit.Fetch
-	return g.RealGit.Fetch(path)
+	var args []string
+	if options.All {
+		args = append(args, "--all")
+	}
+	if options.Prune {
+		args = append(args, "--prune")
+	}
+	if options.Tags {
+		args = append(args, "--tags")
+	}
+	if options.Quiet {
+		args = append(args, "--quiet")
+	}
+	if len(args) == 0 {
+		return g.RealGit.Fetch(path)
+	}
+	return g.RealGit.FetchWithOptions(path, args...)
 }
 
 // Status implements GitProvider.Status
@@ -72,6 +138,17 @@ func (g *GitProviderWrapper) Status(path string) (*interfaces.GitStatus, error)
 		status.Branch = branch
 	}
 	
+	// Count commits ahead of and behind the upstream branch, if one is set
+	if output, err := g.executor.ExecuteInDir(path, "git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		status.Upstream = strings.TrimSpace(string(output))
+	}
+	if output, err := g.executor.ExecuteInDir(path, "git", "rev-list", "--left-right", "--count", "HEAD...@{upstream}"); err == nil {
+		if fields := strings.Fields(string(output)); len(fields) == 2 {
+			status.Ahead, _ = strconv.Atoi(fields[0])
+			status.Behind, _ = strconv.Atoi(fields[1])
+		}
+	}
+	
 	// Get the raw git status output
 	statusOutput, err := g.RealGit.Status(path)
 	if err != nil {
@@ -172,6 +249,169 @@ func (g *GitProviderWrapper) Checkout(path string, branch string) error {
 	return g.RealGit.Checkout(path, branch)
 }
 
+// CheckoutNew implements GitProvider.CheckoutNew
+func (g *GitProviderWrapper) CheckoutNew(path string, branch string) error {
+	output, err := g.executor.ExecuteInDir(path, "git", "checkout", "-b", branch)
+	return gitError(err, output)
+}
+
+// DeleteBranch implements GitProvider.DeleteBranch, deleting the branch even
+// when it is not merged
+func (g *GitProviderWrapper) DeleteBranch(path string, branch string) error {
+	output, err := g.executor.ExecuteInDir(path, "git", "branch", "-D", branch)
+	return gitError(err, output)
+}
+
+// Log implements GitProvider.Log
+func (g *GitProviderWrapper) Log(path string, options interfaces.LogOptions) ([]interfaces.LogEntry, error) {
+	output, err := g.executor.ExecuteInDir(path, "git", "log", "--format=%H%x09%s", logRange(options), "--")
+	if err != nil {
+		return nil, gitError(err, output)
+	}
+
+	var entries []interfaces.LogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		sha, subject, _ := strings.Cut(line, "\t")
+		entries = append(entries, interfaces.LogEntry{SHA: sha, Subject: subject})
+	}
+	return entries, nil
+}
+
+// RemoteBranch implements GitProvider.RemoteBranch, asking the remote for the
+// commit its branch points to. It returns an empty string when the remote has
+// no such branch.
+func (g *GitProviderWrapper) RemoteBranch(path string, remote string, branch string) (string, error) {
+	output, err := g.executor.ExecuteInDir(path, "git", "ls-remote", "--heads", remote, "refs/heads/"+branch)
+	if err != nil {
+		return "", gitError(err, output)
+	}
+	if fields := strings.Fields(string(output)); len(fields) > 0 {
+		return fields[0], nil
+	}
+	return "", nil
+}
+
+// CreateBundle implements GitProvider.CreateBundle, writing the commits of
+// the range and the branch it ends at to a bundle file
+func (g *GitProviderWrapper) CreateBundle(path string, file string, options interfaces.LogOptions) error {
+	output, err := g.executor.ExecuteInDir(path, "git", "bundle", "create", file, logRange(options))
+	return gitError(err, output)
+}
+
+// FetchBundle implements GitProvider.FetchBundle, creating the local branch
+// from the branch of the same name in the bundle
+func (g *GitProviderWrapper) FetchBundle(path string, file string, branch string) error {
+	refspec := "refs/heads/" + branch + ":refs/heads/" + branch
+	output, err := g.executor.ExecuteInDir(path, "git", "fetch", file, refspec)
+	return gitError(err, output)
+}
+
+// FormatPatch implements GitProvider.FormatPatch, returning the patch files
+// written to dir in order
+func (g *GitProviderWrapper) FormatPatch(path string, dir string, options interfaces.LogOptions) ([]string, error) {
+	output, err := g.executor.ExecuteInDir(path, "git", "format-patch", "-o", dir, logRange(options))
+	if err != nil {
+		return nil, gitError(err, output)
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// ApplyPatches implements GitProvider.ApplyPatches with git am, aborting on
+// conflicts so the repository is left as it was
+func (g *GitProviderWrapper) ApplyPatches(path string, files []string) error {
+	args := append([]string{"am", "--3way"}, files...)
+	output, err := g.executor.ExecuteInDir(path, "git", args...)
+	if err != nil {
+		g.executor.ExecuteInDir(path, "git", "am", "--abort")
+		return gitError(err, output)
+	}
+	return nil
+}
+
+// logRange renders LogOptions as a git revision range
+func logRange(options interfaces.LogOptions) string {
+	revision := options.To
+	if revision == "" {
+		revision = "HEAD"
+	}
+	if options.From != "" {
+		revision = options.From + ".." + revision
+	}
+	return revision
+}
+
+// ResetTo implements GitProvider.ResetTo
+func (g *GitProviderWrapper) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	mode := options.Mode
+	if mode == "" {
+		mode = interfaces.ResetMixed
+	}
+	return g.RealGit.ResetCommit(path, commit, string(mode))
+}
+
+// Stash implements GitProvider.Stash
+func (g *GitProviderWrapper) Stash(path string, message string) (bool, error) {
+	return g.RealGit.StashPush(path, message)
+}
+
+// StashPop implements GitProvider.StashPop
+func (g *GitProviderWrapper) StashPop(path string) error {
+	return g.RealGit.StashPop(path)
+}
+
+// DiffStat implements GitProvider.DiffStat
+func (g *GitProviderWrapper) DiffStat(path string, options interfaces.DiffOptions) ([]interfaces.DiffStat, error) {
+	var args []string
+	if options.Staged {
+		args = append(args, "--cached")
+	} else {
+		args = append(args, "HEAD")
+	}
+	if len(options.Paths) > 0 {
+		args = append(append(args, "--"), options.Paths...)
+	}
+
+	output, err := g.RealGit.DiffNumstat(path, args...)
+	if err != nil && !options.Staged {
+		// Without a first commit there is no HEAD; everything is staged
+		args[0] = "--cached"
+		output, err = g.RealGit.DiffNumstat(path, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(output), nil
+}
+
+// parseNumstat parses git diff --numstat output; binary files show - counts
+func parseNumstat(output string) []interfaces.DiffStat {
+	var stats []interfaces.DiffStat
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		stat := interfaces.DiffStat{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Insertions, _ = strconv.Atoi(fields[0])
+			stat.Deletions, _ = strconv.Atoi(fields[1])
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
 // Add implements GitProvider.Add with the correct signature
 func (g *GitProviderWrapper) Add(path string, files []string) error {
 	// Convert slice to variadic arguments
@@ -0,0 +1,259 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+func TestGitProviderWrapper_FetchReportsBehind(t *testing.T) {
+	upstream, _ := setupTestRepo(t)
+	cmd := NewRealCommandExecutor()
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	_, err := cmd.Execute("git", "clone", upstream, clone)
+	require.NoError(t, err)
+
+	provider := NewGitProvider()
+	status, err := provider.Status(clone)
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.Behind)
+
+	// A new upstream commit only shows up after fetching
+	require.NoError(t, os.WriteFile(filepath.Join(upstream, "new.txt"), []byte("new"), 0644))
+	_, err = cmd.ExecuteInDir(upstream, "git", "add", ".")
+	require.NoError(t, err)
+	_, err = cmd.ExecuteInDir(upstream, "git", "commit", "-m", "Upstream change")
+	require.NoError(t, err)
+
+	require.NoError(t, provider.Fetch(clone, interfaces.FetchOptions{All: true, Prune: true, Quiet: true}))
+	assert.FileExists(t, filepath.Join(clone, ".git", "FETCH_HEAD"))
+
+	status, err = provider.Status(clone)
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.Behind)
+	assert.Equal(t, 0, status.Ahead)
+}
+
+func TestGitProviderWrapper_ResetAndStash(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+	cmd := NewRealCommandExecutor()
+	provider := NewGitProvider()
+
+	head := func() string {
+		out, err := cmd.ExecuteInDir(repo, "git", "rev-parse", "HEAD")
+		require.NoError(t, err)
+		return strings.TrimSpace(string(out))
+	}
+	initial := head()
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "test.txt"), []byte("second"), 0644))
+	_, err := cmd.ExecuteInDir(repo, "git", "commit", "-am", "Second")
+	require.NoError(t, err)
+
+	// Nothing to stash in a clean repository
+	stashed, err := provider.Stash(repo, "muno test")
+	require.NoError(t, err)
+	assert.False(t, stashed)
+
+	// Local changes survive a hard reset when stashed around it
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "local.txt"), []byte("wip"), 0644))
+	stashed, err = provider.Stash(repo, "muno test")
+	require.NoError(t, err)
+	assert.True(t, stashed)
+	assert.NoFileExists(t, filepath.Join(repo, "local.txt"))
+
+	require.NoError(t, provider.ResetTo(repo, initial, interfaces.ResetOptions{Mode: interfaces.ResetHard}))
+	assert.Equal(t, initial, head())
+	data, err := os.ReadFile(filepath.Join(repo, "test.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "initial content", string(data))
+
+	require.NoError(t, provider.StashPop(repo))
+	assert.FileExists(t, filepath.Join(repo, "local.txt"))
+
+	err = provider.ResetTo(repo, "0000000000000000000000000000000000000000", interfaces.ResetOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "0000000000")
+}
+
+func TestGitProviderWrapper_DiffStatAndCommit(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+	cmd := NewRealCommandExecutor()
+	provider := NewGitProvider()
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "test.txt"), []byte("changed\nlines\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "data.bin"), []byte{0, 1, 2}, 0644))
+	require.NoError(t, provider.Add(repo, []string{"data.bin"}))
+
+	staged, err := provider.DiffStat(repo, interfaces.DiffOptions{Staged: true})
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.DiffStat{{Path: "data.bin", Binary: true}}, staged)
+
+	all, err := provider.DiffStat(repo, interfaces.DiffOptions{Paths: []string{"test.txt"}})
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.DiffStat{{Path: "test.txt", Insertions: 2, Deletions: 1}}, all)
+
+	// --all picks up the unstaged change as well
+	require.NoError(t, provider.Commit(repo, "Both", interfaces.CommitOptions{All: true}))
+	out, err := cmd.ExecuteInDir(repo, "git", "status", "--porcelain")
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(string(out)))
+
+	// Amending without a message keeps the previous one
+	require.NoError(t, provider.Commit(repo, "", interfaces.CommitOptions{Amend: true}))
+	out, err = cmd.ExecuteInDir(repo, "git", "log", "-1", "--format=%s")
+	require.NoError(t, err)
+	assert.Equal(t, "Both", strings.TrimSpace(string(out)))
+}
+
+func TestGitProviderWrapper_PushSetsUpstream(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+	cmd := NewRealCommandExecutor()
+	provider := NewGitProvider()
+
+	remote := t.TempDir()
+	_, err := cmd.ExecuteInDir(remote, "git", "init", "--bare")
+	require.NoError(t, err)
+	_, err = cmd.ExecuteInDir(repo, "git", "remote", "add", "origin", remote)
+	require.NoError(t, err)
+	_, err = cmd.ExecuteInDir(repo, "git", "checkout", "-b", "feature")
+	require.NoError(t, err)
+
+	status, err := provider.Status(repo)
+	require.NoError(t, err)
+	assert.Empty(t, status.Upstream)
+
+	require.NoError(t, provider.Push(repo, interfaces.PushOptions{SetUpstream: true, Branch: "feature", Quiet: true}))
+	status, err = provider.Status(repo)
+	require.NoError(t, err)
+	assert.Equal(t, "origin/feature", status.Upstream)
+	assert.Equal(t, 0, status.Ahead)
+
+	// Rewritten history goes through with a lease on the fetched remote branch
+	_, err = cmd.ExecuteInDir(repo, "git", "commit", "--amend", "-m", "Rewritten")
+	require.NoError(t, err)
+	assert.Error(t, provider.Push(repo, interfaces.PushOptions{Quiet: true}))
+	require.NoError(t, provider.Push(repo, interfaces.PushOptions{ForceWithLease: true, Quiet: true}))
+
+	// An explicit lease only overwrites the commit it names
+	out, err := cmd.ExecuteInDir(repo, "git", "rev-parse", "HEAD")
+	require.NoError(t, err)
+	pushed := strings.TrimSpace(string(out))
+	reset := interfaces.PushOptions{Remote: "origin", Refspec: ":refs/heads/feature", ForceWithLease: true, Quiet: true}
+	reset.Lease = "refs/heads/feature:0000000000000000000000000000000000000001"
+	assert.Error(t, provider.Push(repo, reset))
+	reset.Lease = "refs/heads/feature:" + pushed
+	require.NoError(t, provider.Push(repo, reset))
+}
+
+func TestGitProviderWrapper_BranchLogAndRemoteBranch(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+	cmd := NewRealCommandExecutor()
+	provider := NewGitProvider()
+
+	remote := t.TempDir()
+	_, err := cmd.ExecuteInDir(remote, "git", "init", "--bare")
+	require.NoError(t, err)
+	_, err = cmd.ExecuteInDir(repo, "git", "remote", "add", "origin", remote)
+	require.NoError(t, err)
+	base := strings.TrimSpace(string(mustRun(t, cmd, repo, "git", "rev-parse", "HEAD")))
+
+	require.NoError(t, provider.CheckoutNew(repo, "topic"))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "topic.txt"), []byte("topic\n"), 0644))
+	require.NoError(t, provider.Add(repo, []string{"topic.txt"}))
+	require.NoError(t, provider.Commit(repo, "Add topic", interfaces.CommitOptions{}))
+
+	entries, err := provider.Log(repo, interfaces.LogOptions{From: base, To: "topic"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Add topic", entries[0].Subject)
+
+	sha, err := provider.RemoteBranch(repo, "origin", "topic")
+	require.NoError(t, err)
+	assert.Empty(t, sha)
+
+	// A dry run leaves the remote untouched
+	require.NoError(t, provider.Push(repo, interfaces.PushOptions{Branch: "topic", DryRun: true, Quiet: true}))
+	sha, err = provider.RemoteBranch(repo, "origin", "topic")
+	require.NoError(t, err)
+	assert.Empty(t, sha)
+
+	require.NoError(t, provider.Push(repo, interfaces.PushOptions{Branch: "topic", Quiet: true}))
+	sha, err = provider.RemoteBranch(repo, "origin", "topic")
+	require.NoError(t, err)
+	assert.Equal(t, entries[0].SHA, sha)
+
+	// Refspecs restore or delete the remote branch
+	require.NoError(t, provider.Push(repo, interfaces.PushOptions{Refspec: base + ":refs/heads/topic", Force: true, Quiet: true}))
+	sha, _ = provider.RemoteBranch(repo, "origin", "topic")
+	assert.Equal(t, base, sha)
+	require.NoError(t, provider.Push(repo, interfaces.PushOptions{Refspec: ":refs/heads/topic", Quiet: true}))
+	sha, _ = provider.RemoteBranch(repo, "origin", "topic")
+	assert.Empty(t, sha)
+
+	_, err = cmd.ExecuteInDir(repo, "git", "checkout", "-")
+	require.NoError(t, err)
+	require.NoError(t, provider.DeleteBranch(repo, "topic"))
+	assert.Error(t, provider.DeleteBranch(repo, "topic"))
+}
+
+func mustRun(t *testing.T, cmd *RealCommandExecutor, dir string, name string, args ...string) []byte {
+	t.Helper()
+	output, err := cmd.ExecuteInDir(dir, name, args...)
+	require.NoError(t, err)
+	return output
+}
+
+func TestGitProviderWrapper_BundleAndPatches(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+	cmd := NewRealCommandExecutor()
+	provider := NewGitProvider()
+	base := strings.TrimSpace(string(mustRun(t, cmd, repo, "git", "rev-parse", "HEAD")))
+
+	bundleClone := filepath.Join(t.TempDir(), "bundle")
+	patchClone := filepath.Join(t.TempDir(), "patch")
+	for _, clone := range []string{bundleClone, patchClone} {
+		mustRun(t, cmd, repo, "git", "clone", "--quiet", repo, clone)
+		mustRun(t, cmd, clone, "git", "config", "user.email", "test@example.com")
+		mustRun(t, cmd, clone, "git", "config", "user.name", "Test User")
+	}
+
+	require.NoError(t, provider.CheckoutNew(repo, "topic"))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "topic.txt"), []byte("topic\n"), 0644))
+	require.NoError(t, provider.Add(repo, []string{"topic.txt"}))
+	require.NoError(t, provider.Commit(repo, "Add topic", interfaces.CommitOptions{}))
+	topicRange := interfaces.LogOptions{From: base, To: "topic"}
+
+	bundle := filepath.Join(t.TempDir(), "topic.bundle")
+	require.NoError(t, provider.CreateBundle(repo, bundle, topicRange))
+	require.NoError(t, provider.FetchBundle(bundleClone, bundle, "topic"))
+	entries, err := provider.Log(bundleClone, topicRange)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Add topic", entries[0].Subject)
+
+	patches, err := provider.FormatPatch(repo, t.TempDir(), topicRange)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	require.NoError(t, provider.CheckoutNew(patchClone, "topic"))
+	require.NoError(t, provider.ApplyPatches(patchClone, patches))
+	entries, err = provider.Log(patchClone, topicRange)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Add topic", entries[0].Subject)
+
+	// A patch that does not apply leaves the repository as it was
+	mustRun(t, cmd, patchClone, "git", "checkout", "--quiet", "-b", "conflict", base)
+	require.NoError(t, os.WriteFile(filepath.Join(patchClone, "topic.txt"), []byte("other\n"), 0644))
+	require.NoError(t, provider.Add(patchClone, []string{"topic.txt"}))
+	require.NoError(t, provider.Commit(patchClone, "Conflicting topic", interfaces.CommitOptions{}))
+	assert.Error(t, provider.ApplyPatches(patchClone, patches))
+	_, err = os.Stat(filepath.Join(patchClone, ".git", "rebase-apply"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -112,6 +113,21 @@ func TestRealGit_CloneWithOptions(t *testing.T) {
 	})
 }
 
+func TestRealGit_PullReportsGitOutput(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	mockCmd := &mocks.MockCommandExecutor{
+		ExecuteInDirFunc: func(dir string, name string, args ...string) ([]byte, error) {
+			return []byte("fatal: Not possible to fast-forward, aborting.\n"), exitErr
+		},
+	}
+
+	git := NewRealGitWithExecutor(mockCmd)
+	err := git.Pull(t.TempDir())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, exitErr)
+	assert.Equal(t, "exit status 1: fatal: Not possible to fast-forward, aborting.", err.Error())
+}
+
 func TestRealGit_Status(t *testing.T) {
 	repoDir, git := setupTestRepo(t)
 	
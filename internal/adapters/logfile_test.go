@@ -0,0 +1,52 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "muno.log")
+	file, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	read := func(p string) string {
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+
+	_, err = file.Write([]byte("late\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestRotatingFile_AppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "muno.log")
+	require.NoError(t, os.WriteFile(path, []byte("12345678"), 0644))
+
+	file, err := OpenRotatingFile(path, 10, 0)
+	require.NoError(t, err)
+	defer file.Close()
+
+	// The existing size counts towards the limit; without backups the old log is dropped
+	_, err = file.Write([]byte("abc\n"))
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc\n", string(data))
+	assert.Equal(t, path, file.Path())
+}
@@ -0,0 +1,269 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// LogFormat selects how log entries are encoded
+type LogFormat string
+
+const (
+	// LogFormatLogfmt writes key=value pairs, one entry per line
+	LogFormatLogfmt LogFormat = "logfmt"
+	// LogFormatJSON writes one JSON object per line
+	LogFormatJSON LogFormat = "json"
+)
+
+var logLevelNames = map[interfaces.LogLevel]string{
+	interfaces.LogLevelDebug: "debug",
+	interfaces.LogLevelInfo:  "info",
+	interfaces.LogLevelWarn:  "warn",
+	interfaces.LogLevelError: "error",
+	interfaces.LogLevelFatal: "fatal",
+}
+
+// ParseLogLevel parses a level name such as "debug" or "warn"
+func ParseLogLevel(name string) (interfaces.LogLevel, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return interfaces.LogLevelDebug, nil
+	case "info":
+		return interfaces.LogLevelInfo, nil
+	case "warn", "warning":
+		return interfaces.LogLevelWarn, nil
+	case "error":
+		return interfaces.LogLevelError, nil
+	case "fatal":
+		return interfaces.LogLevelFatal, nil
+	}
+	return interfaces.LogLevelInfo, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", name)
+}
+
+// ParseLogFormat parses a format name, "logfmt" or "json"
+func ParseLogFormat(name string) (LogFormat, error) {
+	switch format := LogFormat(strings.ToLower(name)); format {
+	case LogFormatLogfmt, LogFormatJSON:
+		return format, nil
+	}
+	return LogFormatLogfmt, fmt.Errorf("unknown log format %q (use logfmt or json)", name)
+}
+
+// LogSink is one destination of a Logger with its own threshold and encoding
+type LogSink struct {
+	Writer  io.Writer
+	Level   interfaces.LogLevel
+	Format  LogFormat
+	Console bool // Follows SetLevel; other sinks, such as log files, keep their level
+}
+
+// Logger is a structured LogProvider writing each entry to every sink whose
+// level it meets. Loggers derived with WithFields share sinks and levels.
+type Logger struct {
+	core   *loggerCore
+	fields []interfaces.Field
+}
+
+type loggerCore struct {
+	mu    sync.Mutex
+	sinks []LogSink
+	now   func() time.Time
+	exit  func(int)
+	muted int // Console sinks are skipped while positive
+}
+
+var _ interfaces.LogProvider = (*Logger)(nil)
+
+// NewLogger creates a logger writing to sinks
+func NewLogger(sinks ...LogSink) *Logger {
+	return &Logger{core: &loggerCore{
+		sinks: sinks,
+		now:   time.Now,
+		exit:  os.Exit,
+	}}
+}
+
+// Debug logs a debug message
+func (l *Logger) Debug(message string, fields ...interfaces.Field) {
+	l.log(interfaces.LogLevelDebug, message, fields)
+}
+
+// Info logs an informational message
+func (l *Logger) Info(message string, fields ...interfaces.Field) {
+	l.log(interfaces.LogLevelInfo, message, fields)
+}
+
+// Warn logs a warning
+func (l *Logger) Warn(message string, fields ...interfaces.Field) {
+	l.log(interfaces.LogLevelWarn, message, fields)
+}
+
+// Error logs an error
+func (l *Logger) Error(message string, fields ...interfaces.Field) {
+	l.log(interfaces.LogLevelError, message, fields)
+}
+
+// Fatal logs a message and exits the process
+func (l *Logger) Fatal(message string, fields ...interfaces.Field) {
+	l.log(interfaces.LogLevelFatal, message, fields)
+	l.Close()
+	l.core.exit(1)
+}
+
+// WithFields returns a logger adding fields to every entry
+func (l *Logger) WithFields(fields ...interfaces.Field) interfaces.LogProvider {
+	merged := make([]interfaces.Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+	return &Logger{core: l.core, fields: merged}
+}
+
+// SetLevel sets the threshold of the console sinks
+func (l *Logger) SetLevel(level interfaces.LogLevel) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	for i := range l.core.sinks {
+		if l.core.sinks[i].Console {
+			l.core.sinks[i].Level = level
+		}
+	}
+}
+
+// MuteConsole stops writing to console sinks until the returned function is
+// called, for screens such as the tree browser that own the terminal
+func (l *Logger) MuteConsole() (restore func()) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.muted++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.core.mu.Lock()
+			defer l.core.mu.Unlock()
+			l.core.muted--
+		})
+	}
+}
+
+// Close closes sink writers such as log files. Standard streams stay open.
+func (l *Logger) Close() error {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	var firstErr error
+	for _, sink := range l.core.sinks {
+		if sink.Writer == os.Stdout || sink.Writer == os.Stderr {
+			continue
+		}
+		if closer, ok := sink.Writer.(io.Closer); ok {
+			if err := closer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// log encodes an entry once per format and writes it to matching sinks
+func (l *Logger) log(level interfaces.LogLevel, message string, fields []interfaces.Field) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	entry := make([]interfaces.Field, 0, 3+len(l.fields)+len(fields))
+	entry = append(entry,
+		interfaces.Field{Key: "time", Value: l.core.now().UTC().Format(time.RFC3339Nano)},
+		interfaces.Field{Key: "level", Value: logLevelNames[level]},
+		interfaces.Field{Key: "msg", Value: message},
+	)
+	entry = append(entry, l.fields...)
+	entry = append(entry, fields...)
+
+	encoded := make(map[LogFormat][]byte)
+	for _, sink := range l.core.sinks {
+		if level < sink.Level || (sink.Console && l.core.muted > 0) {
+			continue
+		}
+		line, ok := encoded[sink.Format]
+		if !ok {
+			line = encodeEntry(sink.Format, entry)
+			encoded[sink.Format] = line
+		}
+		// A failing log destination must not fail the operation being logged
+		_, _ = sink.Writer.Write(line)
+	}
+}
+
+// encodeEntry renders fields as one line in format
+func encodeEntry(format LogFormat, fields []interfaces.Field) []byte {
+	var b strings.Builder
+	if format == LogFormatJSON {
+		b.WriteByte('{')
+		for i, field := range fields {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(field.Key)
+			value, err := json.Marshal(jsonValue(field.Value))
+			if err != nil {
+				value, _ = json.Marshal(fmt.Sprint(field.Value))
+			}
+			b.Write(key)
+			b.WriteByte(':')
+			b.Write(value)
+		}
+		b.WriteString("}\n")
+		return []byte(b.String())
+	}
+
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(field.Key)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(field.Value))
+	}
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+// jsonValue converts values without a useful JSON form to strings
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return value
+}
+
+// logfmtValue renders a value, quoting it when it contains spaces, quotes or '='
+func logfmtValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		s = ""
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" || strings.ContainsAny(s, " =\"\t\n\r") {
+		return strconv.Quote(s)
+	}
+	return s
+}
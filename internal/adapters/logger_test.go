@@ -0,0 +1,123 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+func newTestLogger(sinks ...LogSink) *Logger {
+	logger := NewLogger(sinks...)
+	logger.core.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return logger
+}
+
+func TestLogger_Logfmt(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(LogSink{Writer: &buf, Level: interfaces.LogLevelInfo, Format: LogFormatLogfmt})
+
+	logger.Debug("hidden")
+	logger.Warn("pull failed",
+		interfaces.Field{Key: "node", Value: "/api"},
+		interfaces.Field{Key: "error", Value: errors.New("exit status 1")},
+		interfaces.Field{Key: "attempts", Value: 2})
+
+	assert.Equal(t, `time=2026-01-02T03:04:05Z level=warn msg="pull failed" node=/api error="exit status 1" attempts=2`+"\n", buf.String())
+}
+
+func TestLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(LogSink{Writer: &buf, Level: interfaces.LogLevelDebug, Format: LogFormatJSON})
+
+	logger.Info("fetched", interfaces.Field{Key: "took", Value: 1500 * time.Millisecond}, interfaces.Field{Key: "ok", Value: true})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "fetched", entry["msg"])
+	assert.Equal(t, "1.5s", entry["took"])
+	assert.Equal(t, true, entry["ok"])
+	assert.Equal(t, "2026-01-02T03:04:05Z", entry["time"])
+}
+
+func TestLogger_WithFieldsAndSinks(t *testing.T) {
+	var console, file bytes.Buffer
+	logger := newTestLogger(
+		LogSink{Writer: &console, Level: interfaces.LogLevelError, Format: LogFormatLogfmt, Console: true},
+		LogSink{Writer: &file, Level: interfaces.LogLevelInfo, Format: LogFormatJSON},
+	)
+
+	child := logger.WithFields(interfaces.Field{Key: "op", Value: "pull"})
+	child.WithFields(interfaces.Field{Key: "node", Value: "/web"}).Info("started")
+	child.Error("aborted")
+	logger.Info("parent")
+
+	assert.Equal(t, `time=2026-01-02T03:04:05Z level=error msg=aborted op=pull`+"\n", console.String())
+
+	lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"op":"pull","node":"/web"`)
+	assert.NotContains(t, lines[2], `"op"`)
+
+	// SetLevel applies to console sinks, including those of derived loggers
+	child.SetLevel(interfaces.LogLevelDebug)
+	logger.Debug("now visible")
+	assert.Contains(t, console.String(), "msg=\"now visible\"")
+	assert.NotContains(t, file.String(), "now visible")
+
+	// The log file keeps its level when the console is quieted
+	logger.SetLevel(interfaces.LogLevelError)
+	logger.Info("file only")
+	assert.NotContains(t, console.String(), "file only")
+	assert.Contains(t, file.String(), "file only")
+}
+
+func TestLogger_MuteConsole(t *testing.T) {
+	var console, file bytes.Buffer
+	logger := newTestLogger(
+		LogSink{Writer: &console, Level: interfaces.LogLevelInfo, Format: LogFormatLogfmt, Console: true},
+		LogSink{Writer: &file, Level: interfaces.LogLevelInfo, Format: LogFormatLogfmt},
+	)
+
+	restore := logger.MuteConsole()
+	logger.WithFields(interfaces.Field{Key: "node", Value: "/api"}).Error("pull failed")
+	assert.Empty(t, console.String())
+	assert.Contains(t, file.String(), "pull failed")
+
+	restore()
+	restore()
+	logger.Error("visible again")
+	assert.Contains(t, console.String(), "visible again")
+}
+
+func TestLogger_Fatal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(LogSink{Writer: &buf, Level: interfaces.LogLevelInfo, Format: LogFormatLogfmt})
+	code := 0
+	logger.core.exit = func(c int) { code = c }
+
+	logger.Fatal("boom")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, buf.String(), "level=fatal msg=boom")
+}
+
+func TestParseLogLevelAndFormat(t *testing.T) {
+	level, err := ParseLogLevel("WARNING")
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.LogLevelWarn, level)
+	_, err = ParseLogLevel("loud")
+	assert.Error(t, err)
+
+	format, err := ParseLogFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, LogFormatJSON, format)
+	_, err = ParseLogFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,329 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// MetricKind is the type of a metric series
+type MetricKind string
+
+const (
+	MetricCounter   MetricKind = "counter"
+	MetricGauge     MetricKind = "gauge"
+	MetricHistogram MetricKind = "histogram"
+)
+
+// DefaultBuckets are the histogram upper bounds, in seconds for durations
+var DefaultBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Series is one metric name with one set of tags. Tags are "key:value"
+// strings, kept sorted so equal tag sets share a series.
+type Series struct {
+	Name    string     `json:"name"`
+	Kind    MetricKind `json:"kind"`
+	Tags    []string   `json:"tags,omitempty"`
+	Value   float64    `json:"value,omitempty"`   // Counter total or last gauge value
+	Count   int64      `json:"count,omitempty"`   // Histogram observations
+	Sum     float64    `json:"sum,omitempty"`     // Histogram sum
+	Min     float64    `json:"min,omitempty"`     // Histogram minimum
+	Max     float64    `json:"max,omitempty"`     // Histogram maximum
+	Buckets []int64    `json:"buckets,omitempty"` // Observations per DefaultBuckets bound, plus overflow
+	Updated time.Time  `json:"updated"`
+}
+
+// Tag returns the value of tag key, or "" when the series does not have it
+func (s *Series) Tag(key string) string {
+	for _, tag := range s.Tags {
+		if k, v, ok := strings.Cut(tag, ":"); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// MetricsSnapshot holds recorded series keyed by name and tags
+type MetricsSnapshot struct {
+	Since  time.Time          `json:"since"`
+	Series map[string]*Series `json:"series"`
+}
+
+// NewMetricsSnapshot creates an empty snapshot starting at since
+func NewMetricsSnapshot(since time.Time) *MetricsSnapshot {
+	return &MetricsSnapshot{Since: since, Series: make(map[string]*Series)}
+}
+
+// Sorted returns the series ordered by name and tags
+func (s *MetricsSnapshot) Sorted() []*Series {
+	keys := make([]string, 0, len(s.Series))
+	for key := range s.Series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	series := make([]*Series, 0, len(keys))
+	for _, key := range keys {
+		series = append(series, s.Series[key])
+	}
+	return series
+}
+
+// Merge adds other's observations to s
+func (s *MetricsSnapshot) Merge(other *MetricsSnapshot) {
+	if other.Since.Before(s.Since) || s.Since.IsZero() {
+		s.Since = other.Since
+	}
+	for key, in := range other.Series {
+		existing, ok := s.Series[key]
+		if !ok || existing.Kind != in.Kind {
+			copied := *in
+			copied.Buckets = append([]int64(nil), in.Buckets...)
+			s.Series[key] = &copied
+			continue
+		}
+		switch in.Kind {
+		case MetricCounter:
+			existing.Value += in.Value
+		case MetricGauge:
+			if !in.Updated.Before(existing.Updated) {
+				existing.Value = in.Value
+			}
+		case MetricHistogram:
+			if in.Count == 0 {
+				continue
+			}
+			if existing.Count == 0 || in.Min < existing.Min {
+				existing.Min = in.Min
+			}
+			if existing.Count == 0 || in.Max > existing.Max {
+				existing.Max = in.Max
+			}
+			existing.Count += in.Count
+			existing.Sum += in.Sum
+			for i := range existing.Buckets {
+				if i < len(in.Buckets) {
+					existing.Buckets[i] += in.Buckets[i]
+				}
+			}
+		}
+		if in.Updated.After(existing.Updated) {
+			existing.Updated = in.Updated
+		}
+	}
+}
+
+// observe records a value into the series for name and tags
+func (s *MetricsSnapshot) observe(kind MetricKind, name string, tags []string, value float64, now time.Time) {
+	tags = normalizeTags(tags)
+	key := name
+	if len(tags) > 0 {
+		key += "{" + strings.Join(tags, ",") + "}"
+	}
+
+	series, ok := s.Series[key]
+	if !ok || series.Kind != kind {
+		series = &Series{Name: name, Kind: kind, Tags: tags}
+		if kind == MetricHistogram {
+			series.Buckets = make([]int64, len(DefaultBuckets)+1)
+		}
+		s.Series[key] = series
+	}
+	series.Updated = now
+
+	switch kind {
+	case MetricCounter:
+		series.Value += value
+	case MetricGauge:
+		series.Value = value
+	case MetricHistogram:
+		if series.Count == 0 || value < series.Min {
+			series.Min = value
+		}
+		if series.Count == 0 || value > series.Max {
+			series.Max = value
+		}
+		series.Count++
+		series.Sum += value
+		series.Buckets[sort.SearchFloat64s(DefaultBuckets, value)]++
+	}
+}
+
+// normalizeTags sorts tags and drops empty ones
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if tag != "" {
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// LoadMetrics reads a snapshot written by Metrics.Flush. A missing file is an
+// empty snapshot.
+func LoadMetrics(path string) (*MetricsSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewMetricsSnapshot(time.Time{}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshot := NewMetricsSnapshot(time.Time{})
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("parsing metrics file: %w", err)
+	}
+	if snapshot.Series == nil {
+		snapshot.Series = make(map[string]*Series)
+	}
+	return snapshot, nil
+}
+
+// RemoveMetrics deletes the metrics file, waiting for flushes in progress
+func RemoveMetrics(path string) error {
+	unlock, err := config.LockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Metrics is an in-process MetricsProvider. Flush adds everything recorded
+// since the previous flush to the metrics file, so totals accumulate across
+// muno invocations.
+type Metrics struct {
+	mu      sync.Mutex
+	path    string
+	pending *MetricsSnapshot
+	now     func() time.Time
+}
+
+var _ interfaces.MetricsProvider = (*Metrics)(nil)
+
+// NewMetrics creates a metrics provider persisting to path, or keeping
+// metrics in memory only when path is empty
+func NewMetrics(path string) *Metrics {
+	return &Metrics{
+		path:    path,
+		pending: NewMetricsSnapshot(time.Now()),
+		now:     time.Now,
+	}
+}
+
+// Counter adds value to a counter
+func (m *Metrics) Counter(name string, value int64, tags ...string) {
+	m.record(MetricCounter, name, tags, float64(value))
+}
+
+// Gauge sets a gauge to value
+func (m *Metrics) Gauge(name string, value float64, tags ...string) {
+	m.record(MetricGauge, name, tags, value)
+}
+
+// Histogram records one observation
+func (m *Metrics) Histogram(name string, value float64, tags ...string) {
+	m.record(MetricHistogram, name, tags, value)
+}
+
+// Timer returns a timer recording durations in seconds into histogram name
+func (m *Metrics) Timer(name string) interfaces.TimerMetric {
+	return &metricsTimer{metrics: m, name: name, start: m.now()}
+}
+
+// Snapshot returns a copy of the metrics recorded since the last flush
+func (m *Metrics) Snapshot() *MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := NewMetricsSnapshot(m.pending.Since)
+	snapshot.Merge(m.pending)
+	return snapshot
+}
+
+// Flush adds the metrics recorded since the last flush to the metrics file
+func (m *Metrics) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.path == "" || len(m.pending.Series) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("creating metrics directory: %w", err)
+	}
+	// Other muno processes flush to the same file; the lock keeps their
+	// read-merge-write cycles from dropping each other's metrics
+	unlock, err := config.LockFile(m.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("writing metrics: %w", err)
+	}
+	defer unlock()
+
+	stored, err := LoadMetrics(m.path)
+	if err != nil {
+		// Start over rather than failing every command on a corrupt file
+		stored = NewMetricsSnapshot(time.Time{})
+	}
+	stored.Merge(m.pending)
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := config.WriteFileAtomic(m.path, data, 0644); err != nil {
+		return fmt.Errorf("writing metrics: %w", err)
+	}
+
+	m.pending = NewMetricsSnapshot(m.now())
+	return nil
+}
+
+func (m *Metrics) record(kind MetricKind, name string, tags []string, value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending.observe(kind, name, tags, value, m.now())
+}
+
+// metricsTimer records elapsed time into a histogram
+type metricsTimer struct {
+	metrics *Metrics
+	name    string
+	start   time.Time
+}
+
+// Start restarts the timer
+func (t *metricsTimer) Start() {
+	t.start = t.metrics.now()
+}
+
+// Stop records and returns the time since Start
+func (t *metricsTimer) Stop() time.Duration {
+	elapsed := t.metrics.now().Sub(t.start)
+	t.Record(elapsed)
+	return elapsed
+}
+
+// Record records a duration measured elsewhere
+func (t *metricsTimer) Record(duration time.Duration) {
+	t.metrics.Histogram(t.name, duration.Seconds())
+}
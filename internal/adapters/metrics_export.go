@@ -0,0 +1,281 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metricPrefix namespaces exported metric names
+const metricPrefix = "muno"
+
+// WritePrometheus writes snapshot in the Prometheus text exposition format
+func WritePrometheus(w io.Writer, snapshot *MetricsSnapshot) error {
+	var b strings.Builder
+	typed := make(map[string]bool)
+
+	for _, series := range snapshot.Sorted() {
+		name := prometheusName(series.Name)
+		if series.Kind == MetricCounter && !strings.HasSuffix(name, "_total") {
+			name += "_total"
+		}
+		if !typed[name] {
+			fmt.Fprintf(&b, "# TYPE %s %s\n", name, series.Kind)
+			typed[name] = true
+		}
+
+		labels := prometheusLabels(series.Tags)
+		switch series.Kind {
+		case MetricCounter, MetricGauge:
+			fmt.Fprintf(&b, "%s%s %s\n", name, formatLabels(labels), formatFloat(series.Value))
+		case MetricHistogram:
+			var cumulative int64
+			for i, bound := range DefaultBuckets {
+				if i < len(series.Buckets) {
+					cumulative += series.Buckets[i]
+				}
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name, formatLabels(append(labels, [2]string{"le", formatFloat(bound)})), cumulative)
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, formatLabels(append(labels, [2]string{"le", "+Inf"})), series.Count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", name, formatLabels(labels), formatFloat(series.Sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, formatLabels(labels), series.Count)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// prometheusName turns "git.pull.duration_seconds" into "muno_git_pull_duration_seconds"
+func prometheusName(name string) string {
+	var b strings.Builder
+	b.WriteString(metricPrefix + "_")
+	for _, r := range name {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// prometheusLabels splits "key:value" tags into label pairs
+func prometheusLabels(tags []string) [][2]string {
+	labels := make([][2]string, 0, len(tags))
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			key, value = "tag", tag
+		}
+		labels = append(labels, [2]string{strings.TrimPrefix(prometheusName(key), metricPrefix+"_"), value})
+	}
+	return labels
+}
+
+func formatLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = label[0] + "=" + strconv.Quote(label[1])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// OTLP JSON encoding of ExportMetricsServiceRequest. 64-bit integers are
+// strings as required by the protobuf JSON mapping.
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpMetric struct {
+	Name      string         `json:"name"`
+	Sum       *otlpSum       `json:"sum,omitempty"`
+	Gauge     *otlpGauge     `json:"gauge,omitempty"`
+	Histogram *otlpHistogram `json:"histogram,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type otlpNumberPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsDouble          float64         `json:"asDouble"`
+}
+
+type otlpHistogramPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	Min               float64         `json:"min"`
+	Max               float64         `json:"max"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE
+const otlpCumulative = 2
+
+// WriteOTLP writes snapshot as an OTLP/JSON metrics export request
+func WriteOTLP(w io.Writer, snapshot *MetricsSnapshot, now time.Time) error {
+	data, err := json.Marshal(buildOTLPRequest(snapshot, now))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// PushOTLP sends snapshot to an OTLP/HTTP collector, e.g. http://localhost:4318
+func PushOTLP(endpoint string, snapshot *MetricsSnapshot, now time.Time) error {
+	var body bytes.Buffer
+	if err := WriteOTLP(&body, snapshot, now); err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/metrics") {
+		url += "/v1/metrics"
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", &body)
+	if err != nil {
+		return fmt.Errorf("sending metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+func buildOTLPRequest(snapshot *MetricsSnapshot, now time.Time) otlpRequest {
+	start := strconv.FormatInt(snapshot.Since.UnixNano(), 10)
+	end := strconv.FormatInt(now.UnixNano(), 10)
+
+	metrics := []otlpMetric{}
+	index := make(map[string]int)
+	for _, series := range snapshot.Sorted() {
+		name := metricPrefix + "." + series.Name
+		i, ok := index[name]
+		if !ok {
+			metric := otlpMetric{Name: name}
+			switch series.Kind {
+			case MetricCounter:
+				metric.Sum = &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+			case MetricGauge:
+				metric.Gauge = &otlpGauge{}
+			case MetricHistogram:
+				metric.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulative}
+			}
+			metrics = append(metrics, metric)
+			i = len(metrics) - 1
+			index[name] = i
+		}
+
+		attributes := otlpAttributes(series.Tags)
+		metric := &metrics[i]
+		switch {
+		case metric.Sum != nil && series.Kind == MetricCounter:
+			metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpNumberPoint{
+				Attributes: attributes, StartTimeUnixNano: start, TimeUnixNano: end, AsDouble: series.Value,
+			})
+		case metric.Gauge != nil && series.Kind == MetricGauge:
+			metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpNumberPoint{
+				Attributes: attributes, StartTimeUnixNano: start, TimeUnixNano: end, AsDouble: series.Value,
+			})
+		case metric.Histogram != nil && series.Kind == MetricHistogram:
+			counts := make([]string, len(DefaultBuckets)+1)
+			for j := range counts {
+				var count int64
+				if j < len(series.Buckets) {
+					count = series.Buckets[j]
+				}
+				counts[j] = strconv.FormatInt(count, 10)
+			}
+			metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, otlpHistogramPoint{
+				Attributes:        attributes,
+				StartTimeUnixNano: start,
+				TimeUnixNano:      end,
+				Count:             strconv.FormatInt(series.Count, 10),
+				Sum:               series.Sum,
+				Min:               series.Min,
+				Max:               series.Max,
+				BucketCounts:      counts,
+				ExplicitBounds:    DefaultBuckets,
+			})
+		}
+	}
+
+	return otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpAnyValue{StringValue: "muno"}},
+		}},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: "github.com/taokim/muno"},
+			Metrics: metrics,
+		}},
+	}}}
+}
+
+func otlpAttributes(tags []string) []otlpAttribute {
+	var attributes []otlpAttribute
+	for _, label := range prometheusLabels(tags) {
+		attributes = append(attributes, otlpAttribute{Key: label[0], Value: otlpAnyValue{StringValue: label[1]}})
+	}
+	return attributes
+}
@@ -0,0 +1,208 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetrics(path string) *Metrics {
+	metrics := NewMetrics(path)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	metrics.pending.Since = now
+	metrics.now = func() time.Time { return now }
+	return metrics
+}
+
+func TestMetrics_Record(t *testing.T) {
+	metrics := newTestMetrics("")
+
+	metrics.Counter("git.pull", 1, "result:ok", "node:/api")
+	metrics.Counter("git.pull", 2, "node:/api", "result:ok")
+	metrics.Gauge("repos", 3)
+	metrics.Gauge("repos", 5)
+	metrics.Histogram("git.pull.duration_seconds", 0.2, "node:/api")
+	metrics.Histogram("git.pull.duration_seconds", 3, "node:/api")
+
+	timer := metrics.Timer("manager.initialize")
+	timer.Record(1500 * time.Millisecond)
+
+	snapshot := metrics.Snapshot()
+	require.Len(t, snapshot.Series, 4)
+
+	// Tag order does not matter
+	counter := snapshot.Series["git.pull{node:/api,result:ok}"]
+	require.NotNil(t, counter)
+	assert.Equal(t, float64(3), counter.Value)
+	assert.Equal(t, "/api", counter.Tag("node"))
+	assert.Equal(t, "", counter.Tag("missing"))
+
+	assert.Equal(t, float64(5), snapshot.Series["repos"].Value)
+
+	histogram := snapshot.Series["git.pull.duration_seconds{node:/api}"]
+	assert.Equal(t, int64(2), histogram.Count)
+	assert.Equal(t, 3.2, histogram.Sum)
+	assert.Equal(t, 0.2, histogram.Min)
+	assert.Equal(t, float64(3), histogram.Max)
+	assert.Equal(t, int64(1), histogram.Buckets[3]) // le 0.25
+	assert.Equal(t, int64(1), histogram.Buckets[7]) // le 5
+
+	assert.Equal(t, 1.5, snapshot.Series["manager.initialize"].Sum)
+}
+
+func TestMetrics_FlushAccumulates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".muno", "metrics.json")
+
+	first := newTestMetrics(path)
+	first.Counter("git.clone", 1)
+	first.Histogram("git.clone.duration_seconds", 2, "node:/web")
+	require.NoError(t, first.Flush())
+	// A second flush only adds what was recorded since
+	require.NoError(t, first.Flush())
+
+	second := newTestMetrics(path)
+	second.Counter("git.clone", 2)
+	second.Histogram("git.clone.duration_seconds", 4, "node:/web")
+	require.NoError(t, second.Flush())
+
+	stored, err := LoadMetrics(path)
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), stored.Series["git.clone"].Value)
+	histogram := stored.Series["git.clone.duration_seconds{node:/web}"]
+	assert.Equal(t, int64(2), histogram.Count)
+	assert.Equal(t, float64(6), histogram.Sum)
+	assert.Equal(t, float64(2), histogram.Min)
+	assert.Equal(t, float64(4), histogram.Max)
+
+	// A corrupt file is replaced rather than failing every command
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err = LoadMetrics(path)
+	assert.Error(t, err)
+	second.Counter("git.clone", 1)
+	require.NoError(t, second.Flush())
+	stored, err = LoadMetrics(path)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), stored.Series["git.clone"].Value)
+}
+
+func TestMetrics_ConcurrentFlushesKeepEverySeries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".muno", "metrics.json")
+
+	// Each Metrics stands for a separate muno process flushing to one file
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metrics := newTestMetrics(path)
+			for j := 0; j < 5; j++ {
+				metrics.Counter("git.pull", 1)
+				assert.NoError(t, metrics.Flush())
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored, err := LoadMetrics(path)
+	require.NoError(t, err)
+	assert.Equal(t, float64(40), stored.Series["git.pull"].Value)
+
+	require.NoError(t, RemoveMetrics(path))
+	assert.NoFileExists(t, path)
+	require.NoError(t, RemoveMetrics(path))
+}
+
+func TestLoadMetrics_Missing(t *testing.T) {
+	snapshot, err := LoadMetrics(filepath.Join(t.TempDir(), "none.json"))
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.Series)
+}
+
+func TestWritePrometheus(t *testing.T) {
+	metrics := newTestMetrics("")
+	metrics.Counter("git.pull", 2, "node:/api", "result:ok")
+	metrics.Gauge("tree.nodes", 4)
+	metrics.Histogram("git.pull.duration_seconds", 0.3, "node:/api")
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePrometheus(&buf, metrics.Snapshot()))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE muno_git_pull_total counter\nmuno_git_pull_total{node=\"/api\",result=\"ok\"} 2\n")
+	assert.Contains(t, out, "# TYPE muno_tree_nodes gauge\nmuno_tree_nodes 4\n")
+	assert.Contains(t, out, "# TYPE muno_git_pull_duration_seconds histogram\n")
+	assert.Contains(t, out, `muno_git_pull_duration_seconds_bucket{node="/api",le="0.25"} 0`)
+	assert.Contains(t, out, `muno_git_pull_duration_seconds_bucket{node="/api",le="0.5"} 1`)
+	assert.Contains(t, out, `muno_git_pull_duration_seconds_bucket{node="/api",le="+Inf"} 1`)
+	assert.Contains(t, out, `muno_git_pull_duration_seconds_sum{node="/api"} 0.3`)
+	assert.Contains(t, out, `muno_git_pull_duration_seconds_count{node="/api"} 1`)
+}
+
+func TestWriteOTLP(t *testing.T) {
+	metrics := newTestMetrics("")
+	metrics.Counter("git.pull", 2, "node:/api")
+	metrics.Counter("git.pull", 1, "node:/web")
+	metrics.Histogram("git.pull.duration_seconds", 0.3, "node:/api")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteOTLP(&buf, metrics.Snapshot(), time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC)))
+
+	var request otlpRequest
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &request))
+	scope := request.ResourceMetrics[0].ScopeMetrics[0]
+	require.Len(t, scope.Metrics, 2)
+
+	byName := make(map[string]otlpMetric)
+	for _, metric := range scope.Metrics {
+		byName[metric.Name] = metric
+	}
+
+	pull := byName["muno.git.pull"]
+	require.NotNil(t, pull.Sum)
+	assert.True(t, pull.Sum.IsMonotonic)
+	require.Len(t, pull.Sum.DataPoints, 2)
+	assert.Equal(t, "node", pull.Sum.DataPoints[0].Attributes[0].Key)
+
+	duration := byName["muno.git.pull.duration_seconds"]
+	require.NotNil(t, duration.Histogram)
+	point := duration.Histogram.DataPoints[0]
+	assert.Equal(t, "1", point.Count)
+	assert.Len(t, point.BucketCounts, len(DefaultBuckets)+1)
+	assert.Equal(t, "1", point.BucketCounts[4])
+	assert.Contains(t, buf.String(), `"startTimeUnixNano":"1767323045000000000"`)
+}
+
+func TestPushOTLP(t *testing.T) {
+	var path, contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	metrics := newTestMetrics("")
+	metrics.Counter("git.fetch", 1)
+	require.NoError(t, PushOTLP(server.URL+"/", metrics.Snapshot(), time.Now()))
+	assert.Equal(t, "/v1/metrics", path)
+	assert.Equal(t, "application/json", contentType)
+	assert.True(t, strings.Contains(body, `"name":"muno.git.fetch"`))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	assert.ErrorContains(t, PushOTLP(failing.URL, metrics.Snapshot(), time.Now()), "400")
+}
@@ -114,7 +114,7 @@ func (t *TreeAdapterStub) loadNestedConfigs(parentPath string, defaultReposDir s
 	}
 
 	// Check if there's a muno.yaml in this directory
-	configPath := filepath.Join(physicalPath, "muno.yaml")
+	configPath := config.ConfigFilePath(physicalPath)
 	if !t.fsProvider.Exists(configPath) {
 		// No config found - don't mark as scanned so we can try again later
 		return
@@ -20,6 +20,7 @@ type DefaultConfiguration struct {
 	Git       GitDefaults       `yaml:"git"`
 	Display   DisplayDefaults   `yaml:"display"`
 	Behavior  BehaviorDefaults  `yaml:"behavior"`
+	Agent     AgentDefaults     `yaml:"agent"`
 }
 
 // WorkspaceDefaults contains default workspace settings
@@ -48,6 +49,9 @@ type GitDefaults struct {
 	DefaultBranch string `yaml:"default_branch"`
 	CloneTimeout  int    `yaml:"clone_timeout"`
 	ShallowDepth  int    `yaml:"shallow_depth"`
+	FetchInterval string `yaml:"fetch_interval"`
+	FetchStaleAfter string `yaml:"fetch_stale_after"`
+	ProtectedBranches []string `yaml:"protected_branches"`
 }
 
 // DisplayDefaults contains display settings
@@ -88,6 +92,13 @@ type BehaviorDefaults struct {
 	Interactive       bool `yaml:"interactive"`
 }
 
+// AgentDefaults contains agent session settings
+type AgentDefaults struct {
+	Command           string `yaml:"command"`
+	Terminal          string `yaml:"terminal"`
+	WriteContextFiles bool   `yaml:"write_context_files"`
+}
+
 var (
 	// defaultConfig is the parsed default configuration
 	defaultConfig *DefaultConfiguration
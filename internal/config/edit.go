@@ -0,0 +1,347 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config files are edited in place rather than rewritten. Save parses the
+// existing file into a yaml.Node tree, applies only the values that changed
+// and encodes the tree again, so comments, key order, anchors, quoting and
+// the blank lines between entries survive muno add, remove and friends.
+
+// editConfigFile returns the file at path updated to hold cfg. ok is false
+// when there is no existing config document to edit.
+func editConfigFile(path string, format string, cfg *ConfigTree) (data []byte, ok bool, err error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, nil
+	}
+	doc, err := ParseNode(format, original)
+	if err != nil || doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, false, nil
+	}
+
+	// What the file says now, with includes, variables, templates and
+	// defaults resolved and in the shape the encoder writes, tells values
+	// that were only implied from ones written
+	current, err := DecodeTree(format, original, ConfigDir(path))
+	if err != nil {
+		current = &ConfigTree{}
+		if err := doc.Decode(current); err != nil {
+			return nil, false, nil
+		}
+	}
+	var implied, updated yaml.Node
+	if err := implied.Encode(MergeWithDefaults(current)); err != nil {
+		return nil, false, err
+	}
+	if err := updated.Encode(cfg); err != nil {
+		return nil, false, err
+	}
+	clearPositions(&updated)
+
+	editNode(doc.Content[0], &updated, &implied)
+	if format != FormatYAML {
+		data, err := EncodeNode(format, doc)
+		return data, true, err
+	}
+	data, err = encodeEditedYAML(original, doc)
+	return data, true, err
+}
+
+// editNode updates dst in place to hold the value of src. implied is the
+// schema's view of dst's current value, or nil when unknown.
+func editNode(dst, src, implied *yaml.Node) {
+	// Values that still mean the same, such as ${var} references, stay as written
+	if sameValue(dst, src) || (implied != nil && sameValue(implied, src)) {
+		return
+	}
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		editMapping(dst, src, implied)
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		editSequence(dst, src, implied)
+	case dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode:
+		quoted := dst.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0
+		dst.Value, dst.Tag = src.Value, src.Tag
+		if !quoted || src.Tag != "!!str" {
+			dst.Style = src.Style
+		}
+	default:
+		// Aliases that no longer match and kind changes take the new value
+		replaceNode(dst, src)
+	}
+}
+
+// editMapping updates the keys of dst in its own order. Keys the schema does
+// not know, such as merge keys, are kept; keys that were only implied by
+// defaults or merges are not written out.
+func editMapping(dst, src, implied *yaml.Node) {
+	srcValues := mappingValues(src)
+	var impliedValues map[string]*yaml.Node
+	if implied != nil && implied.Kind == yaml.MappingNode {
+		impliedValues = mappingValues(implied)
+	}
+
+	present := make(map[string]bool)
+	content := make([]*yaml.Node, 0, len(dst.Content))
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key, value := dst.Content[i], dst.Content[i+1]
+		present[key.Value] = true
+		if newValue, ok := srcValues[key.Value]; ok {
+			editNode(value, newValue, impliedValues[key.Value])
+		} else if _, known := impliedValues[key.Value]; (known || impliedValues == nil) && key.Value != "<<" {
+			continue // The value was removed
+		}
+		content = append(content, key, value)
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		if present[key.Value] {
+			continue
+		}
+		if current, ok := impliedValues[key.Value]; ok && sameValue(current, value) {
+			continue
+		}
+		content = append(content, key, value)
+	}
+	finishCollection(dst, src, content)
+}
+
+// editSequence updates the items of dst. Items that are mappings with a name
+// key, like nodes, are matched by name; others by position. Named items that
+// only come from an include are not written out.
+func editSequence(dst, src, implied *yaml.Node) {
+	var impliedItems []*yaml.Node
+	if implied != nil && implied.Kind == yaml.SequenceNode {
+		impliedItems = implied.Content
+	}
+
+	items := make([]*yaml.Node, 0, len(src.Content))
+	dstNamed, ok := itemsByName(dst.Content)
+	if _, srcNamed := itemsByName(src.Content); ok && srcNamed {
+		impliedNamed, _ := itemsByName(impliedItems)
+		for _, item := range src.Content {
+			name := itemName(item)
+			if existing, found := dstNamed[name]; found {
+				editNode(existing, item, impliedNamed[name])
+				item = existing
+			} else if current, ok := impliedNamed[name]; ok && sameValue(current, item) {
+				continue // Still defined by an include
+			}
+			items = append(items, item)
+		}
+	} else {
+		for i, item := range src.Content {
+			if i < len(dst.Content) {
+				var impliedItem *yaml.Node
+				if i < len(impliedItems) {
+					impliedItem = impliedItems[i]
+				}
+				editNode(dst.Content[i], item, impliedItem)
+				item = dst.Content[i]
+			}
+			items = append(items, item)
+		}
+	}
+	finishCollection(dst, src, items)
+}
+
+// finishCollection sets the new content of a mapping or sequence. An empty
+// flow collection like nodes: [] becomes a block when it gains entries.
+func finishCollection(dst, src *yaml.Node, content []*yaml.Node) {
+	if len(dst.Content) == 0 && len(content) > 0 && src.Style&yaml.FlowStyle == 0 {
+		dst.Style &^= yaml.FlowStyle
+	}
+	dst.Content = content
+}
+
+// replaceNode overwrites dst with src, keeping dst's comments, anchor and
+// position so aliases and blank lines still refer to it
+func replaceNode(dst, src *yaml.Node) {
+	kept := *dst
+	*dst = *src
+	dst.HeadComment, dst.LineComment, dst.FootComment = kept.HeadComment, kept.LineComment, kept.FootComment
+	dst.Line, dst.Column = kept.Line, kept.Column
+	if kept.Kind != yaml.AliasNode {
+		dst.Anchor = kept.Anchor
+	}
+}
+
+// sameValue reports whether two nodes decode to the same value, following
+// aliases and merge keys
+func sameValue(a, b *yaml.Node) bool {
+	var av, bv interface{}
+	if err := a.Decode(&av); err != nil {
+		return false
+	}
+	if err := b.Decode(&bv); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// mappingValues indexes a mapping's values by key
+func mappingValues(node *yaml.Node) map[string]*yaml.Node {
+	values := make(map[string]*yaml.Node, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		values[node.Content[i].Value] = node.Content[i+1]
+	}
+	return values
+}
+
+// itemsByName indexes sequence items by their name key. ok is false unless
+// every item is a mapping with a unique name.
+func itemsByName(items []*yaml.Node) (map[string]*yaml.Node, bool) {
+	named := make(map[string]*yaml.Node, len(items))
+	for _, item := range items {
+		name := itemName(item)
+		if name == "" || named[name] != nil {
+			return nil, false
+		}
+		named[name] = item
+	}
+	return named, len(items) > 0
+}
+
+// itemName returns the name key of a sequence item, or ""
+func itemName(item *yaml.Node) string {
+	item = resolveAlias(item)
+	if item.Kind != yaml.MappingNode {
+		return ""
+	}
+	if name, ok := mappingValues(item)["name"]; ok && name.Kind == yaml.ScalarNode {
+		return name.Value
+	}
+	return ""
+}
+
+// clearPositions zeroes line numbers so new nodes can be told from edited ones
+func clearPositions(node *yaml.Node) {
+	node.Line, node.Column = 0, 0
+	for _, child := range node.Content {
+		clearPositions(child)
+	}
+}
+
+// encodeEditedYAML encodes an edited document with the indentation of the
+// original file and puts back the blank lines yaml.v3 drops between entries
+func encodeEditedYAML(original []byte, doc *yaml.Node) ([]byte, error) {
+	plainMergeKeys(doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(yamlIndent(original))
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	var encoded yaml.Node
+	if err := yaml.Unmarshal(buf.Bytes(), &encoded); err != nil {
+		return buf.Bytes(), nil
+	}
+	blank := make(map[int]bool)
+	markBlankLines(doc, &encoded, strings.Split(string(original), "\n"), blank)
+
+	lines := strings.Split(buf.String(), "\n")
+	var out strings.Builder
+	for i, line := range lines {
+		if blank[i+1] && i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+			out.WriteByte('\n')
+		}
+		out.WriteString(line)
+		if i < len(lines)-1 {
+			out.WriteByte('\n')
+		}
+	}
+	return []byte(out.String()), nil
+}
+
+// plainMergeKeys stops yaml.v3 from writing merge keys as "!!merge <<"
+func plainMergeKeys(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if key := node.Content[i]; key.Value == "<<" && key.Tag == "!!merge" {
+				key.Tag = ""
+			}
+		}
+	}
+	for _, child := range node.Content {
+		plainMergeKeys(child)
+	}
+}
+
+// markBlankLines walks the edited document and its re-parsed encoding side
+// by side and records the encoded lines that need a blank line before them:
+// entries that had one in the original, and new entries in collections
+// whose entries are separated by blank lines
+func markBlankLines(edited, encoded *yaml.Node, original []string, blank map[int]bool) {
+	if edited.Kind != encoded.Kind || len(edited.Content) != len(encoded.Content) {
+		return
+	}
+	var entries, encodedEntries []*yaml.Node
+	switch edited.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(edited.Content); i += 2 {
+			entries = append(entries, edited.Content[i])
+			encodedEntries = append(encodedEntries, encoded.Content[i])
+		}
+	case yaml.SequenceNode:
+		entries, encodedEntries = edited.Content, encoded.Content
+	}
+	if edited.Style&yaml.FlowStyle == 0 {
+		spaced := false
+		for i, entry := range entries {
+			if i > 0 && entry.Line > 0 && blankBefore(original, entry) {
+				spaced = true
+			}
+		}
+		for i, entry := range entries {
+			if i > 0 && ((entry.Line > 0 && blankBefore(original, entry)) || (entry.Line == 0 && spaced)) {
+				blank[entryStart(encodedEntries[i])] = true
+			}
+		}
+	}
+	for i := range edited.Content {
+		markBlankLines(edited.Content[i], encoded.Content[i], original, blank)
+	}
+}
+
+// entryStart returns the first line of an entry, including its head comment
+func entryStart(entry *yaml.Node) int {
+	head := entry.HeadComment
+	if head == "" && entry.Kind == yaml.MappingNode && len(entry.Content) > 0 {
+		head = entry.Content[0].HeadComment
+	}
+	if head == "" {
+		return entry.Line
+	}
+	return entry.Line - strings.Count(head, "\n") - 1
+}
+
+// blankBefore reports whether an entry of the original file follows a blank line
+func blankBefore(original []string, entry *yaml.Node) bool {
+	start := entryStart(entry)
+	return start >= 2 && start-2 < len(original) && strings.TrimSpace(original[start-2]) == ""
+}
+
+// yamlIndent returns the indentation width used by a YAML file
+func yamlIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+		if indent := len(line) - len(content); indent > 0 {
+			return min(max(indent, 2), 8)
+		}
+	}
+	return 4
+}
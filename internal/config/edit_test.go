@@ -0,0 +1,111 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/edit")
+
+// editCases are the config edits checked against testdata/edit. Each input
+// <name> is loaded, edited and saved, and must then match <name>.golden.
+var editCases = map[string]func(cfg *ConfigTree){
+	"add_node.yaml": func(cfg *ConfigTree) {
+		cfg.Nodes = append(cfg.Nodes, NodeDefinition{Name: "docs", URL: "git@github.com:acme/docs.git", Fetch: FetchLazy})
+	},
+	"remove_node.yaml": func(cfg *ConfigTree) {
+		cfg.Nodes = append(cfg.Nodes[:1], cfg.Nodes[2:]...)
+	},
+	"update_values.yaml": func(cfg *ConfigTree) {
+		cfg.Nodes[0].Fetch = FetchEager
+		cfg.Nodes[0].Metadata["tier"] = "2"
+		cfg.Overrides["git"].(map[string]interface{})["default_branch"] = "develop"
+	},
+	"anchors.yaml": func(cfg *ConfigTree) {
+		cfg.Nodes[1].Fetch = FetchEager
+		cfg.Nodes = append(cfg.Nodes, NodeDefinition{Name: "search", URL: "git@github.com:acme/search.git", Fetch: FetchLazy})
+	},
+	"empty_nodes.yaml": func(cfg *ConfigTree) {
+		cfg.Nodes = append(cfg.Nodes, NodeDefinition{Name: "api", URL: "git@github.com:acme/api.git"})
+	},
+	"order.json": func(cfg *ConfigTree) {
+		cfg.Nodes = append(cfg.Nodes, NodeDefinition{Name: "web", URL: "git@github.com:acme/web.git"})
+	},
+}
+
+func TestConfigTreeSave_EditGolden(t *testing.T) {
+	for name, edit := range editCases {
+		t.Run(name, func(t *testing.T) {
+			input := filepath.Join("testdata", "edit", name)
+			ext := filepath.Ext(name)
+			golden := strings.TrimSuffix(input, ext) + ".golden" + ext
+			original, err := os.ReadFile(input)
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "muno"+ext)
+			require.NoError(t, os.WriteFile(path, original, 0644))
+			cfg, err := LoadTree(path)
+			require.NoError(t, err)
+			edit(cfg)
+			require.NoError(t, cfg.Save(path))
+			got, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, got, 0644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+
+			// Saving again without changes leaves the file alone
+			cfg, err = LoadTree(path)
+			require.NoError(t, err)
+			require.NoError(t, cfg.Save(path))
+			again, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(got), string(again))
+		})
+	}
+}
+
+func TestConfigTreeSave_RoundTripUnchanged(t *testing.T) {
+	for name := range editCases {
+		original, err := os.ReadFile(filepath.Join("testdata", "edit", name))
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "muno"+filepath.Ext(name))
+		require.NoError(t, os.WriteFile(path, original, 0644))
+
+		cfg, err := LoadTree(path)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Save(path))
+		saved, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, string(original), string(saved), name)
+	}
+}
+
+func TestConfigTreeSave_KeepsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "muno.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("workspace:\n  name: acme\nx-owner: platform\nnodes:\n  - name: api\n    url: a.git\n    fetch: \"\"\n"), 0644))
+
+	cfg, err := LoadTree(path)
+	require.NoError(t, err)
+	cfg.Nodes[0].URL = "b.git"
+	require.NoError(t, cfg.Save(path))
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "workspace:\n  name: acme\nx-owner: platform\nnodes:\n  - name: api\n    url: b.git\n    fetch: \"\"\n", string(saved))
+}
+
+func TestYAMLIndent(t *testing.T) {
+	assert.Equal(t, 4, yamlIndent([]byte("workspace: {name: a}\n")))
+	assert.Equal(t, 2, yamlIndent([]byte("# c\n\nworkspace:\n  name: a\n")))
+	assert.Equal(t, 4, yamlIndent([]byte("workspace:\n    name: a\n")))
+}
@@ -0,0 +1,412 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A config may share definitions instead of repeating them on every node:
+//
+//	vars:
+//	  gh: git@github.com:acme
+//	include:
+//	  - shared/go-services.yaml
+//	templates:
+//	  go-service:
+//	    fetch: lazy
+//	    overrides: {git: {default_branch: main}}
+//	nodes:
+//	  - name: api
+//	    url: ${gh}/api.git
+//	    template: go-service
+//
+// ${name} expands to a variable and ${env:NAME} to an environment variable;
+// ${name:-fallback} gives a fallback and $$ a literal $. Configs fetched
+// from another repository into the remote config cache cannot read the
+// environment, so a shared config cannot pick up local secrets. Included
+// fragments may hold vars, templates, nodes and further includes; the
+// including file wins when both define the same name. All of it is resolved
+// when the config is decoded, before it is validated.
+
+// NodeTemplate holds defaults that nodes pull in with template: <name>
+type NodeTemplate struct {
+	Fetch     string                 `yaml:"fetch,omitempty"`
+	Overrides map[string]interface{} `yaml:"overrides,omitempty"`
+	Metadata  map[string]string      `yaml:"metadata,omitempty"`
+}
+
+// fragmentKeys are the keys an included fragment may set
+var fragmentKeys = map[string]bool{"vars": true, "include": true, "templates": true, "nodes": true}
+
+// DecodeTree decodes config data with its includes, variables and node
+// templates resolved. Relative includes are read from dir, and generate:
+// nodes point at their generated config there. Defaults are not applied
+// and the result is not validated.
+func DecodeTree(format string, data []byte, dir string) (*ConfigTree, error) {
+	doc, err := ParseNode(format, data)
+	if err != nil {
+		return nil, err
+	}
+	var cfg ConfigTree
+	root := documentRoot(doc)
+	if root == nil {
+		return &cfg, nil
+	}
+	if root.Kind == yaml.MappingNode {
+		if err := resolveIncludes(root, dir, nil); err != nil {
+			return nil, err
+		}
+		env := envLookup(!isRemoteConfigDir(dir))
+		vars, err := resolveVars(root, env)
+		if err != nil {
+			return nil, err
+		}
+		if err := expandValues(root, vars, env, ""); err != nil {
+			return nil, err
+		}
+	}
+	if err := root.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.applyTemplates(); err != nil {
+		return nil, err
+	}
+
+	// Generated children are read from the config muno sync writes, which
+	// must not be a hand-written file
+	for i := range cfg.Nodes {
+		node := &cfg.Nodes[i]
+		if node.Generate != nil && node.File != "" && !IsGeneratedConfigPath(node.File, node.Name) {
+			return nil, fmt.Errorf("node %s cannot have both file and generate fields", node.Name)
+		}
+		if node.Generate != nil && node.URL == "" {
+			if abs, err := filepath.Abs(dir); err == nil {
+				dir = abs
+			}
+			node.File = GeneratedConfigPath(dir, node.Name)
+		}
+	}
+	return &cfg, nil
+}
+
+// applyTemplates merges each node's template under the node's own settings
+func (c *ConfigTree) applyTemplates() error {
+	for i := range c.Nodes {
+		node := &c.Nodes[i]
+		if node.Template == "" {
+			continue
+		}
+		tmpl, ok := c.Templates[node.Template]
+		if !ok {
+			return fmt.Errorf("node %s: unknown template %q", node.Name, node.Template)
+		}
+		if node.Fetch == "" {
+			node.Fetch = tmpl.Fetch
+		}
+		if len(tmpl.Overrides) > 0 {
+			overrides := deepCopy(tmpl.Overrides)
+			deepMerge(overrides, node.Overrides)
+			node.Overrides = overrides
+		}
+		if len(tmpl.Metadata) > 0 {
+			metadata := make(map[string]string, len(tmpl.Metadata)+len(node.Metadata))
+			for k, v := range tmpl.Metadata {
+				metadata[k] = v
+			}
+			for k, v := range node.Metadata {
+				metadata[k] = v
+			}
+			node.Metadata = metadata
+		}
+	}
+	return nil
+}
+
+// resolveIncludes merges the fragments listed under include: into root.
+// chain holds the files being included, to catch cycles.
+func resolveIncludes(root *yaml.Node, dir string, chain []string) error {
+	include := mappingValues(root)["include"]
+	if include == nil {
+		return nil
+	}
+	var paths []string
+	if err := include.Decode(&paths); err != nil {
+		return fmt.Errorf("include: %w", err)
+	}
+
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		for _, seen := range chain {
+			if seen == p {
+				return fmt.Errorf("include cycle: %s", strings.Join(append(chain, p), " -> "))
+			}
+		}
+		format, err := FormatForPath(p)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", p, err)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", p, err)
+		}
+		doc, err := ParseNode(format, data)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", p, err)
+		}
+		fragment := documentRoot(doc)
+		if fragment == nil {
+			continue
+		}
+		if fragment.Kind != yaml.MappingNode {
+			return fmt.Errorf("include %s: expected a mapping", p)
+		}
+		for i := 0; i+1 < len(fragment.Content); i += 2 {
+			if key := fragment.Content[i].Value; !fragmentKeys[key] {
+				return fmt.Errorf("include %s: %q is not allowed in a fragment (use vars, templates, nodes or include)", p, key)
+			}
+		}
+		if err := resolveIncludes(fragment, filepath.Dir(p), append(chain, p)); err != nil {
+			return err
+		}
+		mergeFragment(root, fragment)
+	}
+	return nil
+}
+
+// mergeFragment adds the vars, templates and nodes of an included fragment
+// that root does not define itself. Included nodes go before root's own.
+func mergeFragment(root, fragment *yaml.Node) {
+	values := mappingValues(fragment)
+	for _, key := range []string{"vars", "templates"} {
+		from := values[key]
+		if from == nil || from.Kind != yaml.MappingNode {
+			continue
+		}
+		to := mappingEntry(root, key, yaml.MappingNode)
+		defined := mappingValues(to)
+		for i := 0; i+1 < len(from.Content); i += 2 {
+			if defined[from.Content[i].Value] == nil {
+				to.Content = append(to.Content, from.Content[i], from.Content[i+1])
+			}
+		}
+	}
+
+	from := values["nodes"]
+	if from == nil || from.Kind != yaml.SequenceNode {
+		return
+	}
+	to := mappingEntry(root, "nodes", yaml.SequenceNode)
+	defined := make(map[string]bool)
+	for _, item := range to.Content {
+		defined[itemName(item)] = true
+	}
+	var added []*yaml.Node
+	for _, item := range from.Content {
+		if name := itemName(item); name == "" || !defined[name] {
+			added = append(added, item)
+		}
+	}
+	to.Content = append(added, to.Content...)
+}
+
+// mappingEntry returns the value of key in a mapping, adding an empty
+// collection of the given kind when it is missing or null
+func mappingEntry(mapping *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := resolveAlias(mapping.Content[i+1])
+			if value.Kind != kind {
+				*value = yaml.Node{Kind: kind}
+			}
+			return value
+		}
+	}
+	value := &yaml.Node{Kind: kind}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+// envLookup resolves ${env:NAME}, or refuses to when the environment is
+// not available to the config
+func envLookup(allowed bool) func(name string) (string, bool, error) {
+	return func(name string) (string, bool, error) {
+		if !allowed {
+			return "", false, fmt.Errorf("${env:%s}: environment variables are not available in remote configs", name)
+		}
+		value, ok := os.LookupEnv(name)
+		return value, ok, nil
+	}
+}
+
+// isRemoteConfigDir reports whether dir lies in a remote config cache
+func isRemoteConfigDir(dir string) bool {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return strings.Contains(filepath.ToSlash(dir)+"/", "/"+RemoteConfigDir+"/")
+}
+
+// resolveVars returns the expanded values of the vars mapping. Variables
+// may refer to each other and, through env, to the environment.
+func resolveVars(root *yaml.Node, env func(name string) (string, bool, error)) (map[string]string, error) {
+	raw := make(map[string]string)
+	if node := mappingValues(root)["vars"]; node != nil {
+		if err := node.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("vars: %w", err)
+		}
+	}
+
+	vars := make(map[string]string, len(raw))
+	resolving := make(map[string]bool)
+	var resolve func(name string) (string, bool, error)
+	resolve = func(name string) (string, bool, error) {
+		if value, ok := vars[name]; ok {
+			return value, true, nil
+		}
+		if envName, ok := strings.CutPrefix(name, "env:"); ok {
+			return env(envName)
+		}
+		value, ok := raw[name]
+		if !ok {
+			return "", false, nil
+		}
+		if resolving[name] {
+			return "", false, fmt.Errorf("variable cycle through ${%s}", name)
+		}
+		resolving[name] = true
+		expanded, err := expandString(value, resolve)
+		if err != nil {
+			return "", false, err
+		}
+		vars[name] = expanded
+		return expanded, true, nil
+	}
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, _, err := resolve(name); err != nil {
+			return nil, fmt.Errorf("vars.%s: %w", name, err)
+		}
+	}
+	return vars, nil
+}
+
+// expandValues expands variables in every string of the document except
+// the vars and include sections. path names the node for errors.
+func expandValues(node *yaml.Node, vars map[string]string, env func(name string) (string, bool, error), path string) error {
+	lookup := func(name string) (string, bool, error) {
+		if envName, ok := strings.CutPrefix(name, "env:"); ok {
+			return env(envName)
+		}
+		value, ok := vars[name]
+		return value, ok, nil
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!str" || !strings.Contains(node.Value, "$") {
+			return nil
+		}
+		expanded, err := expandString(node.Value, lookup)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if expanded != node.Value && node.Style&(yaml.TaggedStyle|yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			// Plain values take the type of what they expand to
+			node.Tag = ""
+		}
+		node.Value = expanded
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if path == "" && (key == "vars" || key == "include") {
+				continue
+			}
+			if err := expandValues(node.Content[i+1], vars, env, joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if name := itemName(item); name != "" {
+				itemPath = fmt.Sprintf("%s[%s]", path, name)
+			}
+			if err := expandValues(item, vars, env, itemPath); err != nil {
+				return err
+			}
+		}
+	}
+	// Aliases are expanded where their anchor is defined
+	return nil
+}
+
+// expandString replaces ${name} and ${name:-fallback} in s using lookup;
+// $$ stands for a literal $. Names may carry a prefix such as env:.
+func expandString(s string, lookup func(name string) (string, bool, error)) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			out.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			out.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unclosed ${ in %q", s)
+			}
+			name, fallback, hasFallback := strings.Cut(s[i+2:i+2+end], ":-")
+			value, ok, err := lookup(name)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				if !hasFallback {
+					return "", fmt.Errorf("undefined variable ${%s}", name)
+				}
+				value = fallback
+			}
+			out.WriteString(value)
+			i += end + 2
+		default:
+			out.WriteByte('$')
+		}
+	}
+	return out.String(), nil
+}
+
+// documentRoot returns the top-level node of a parsed document, or nil
+// when it is empty
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return nil
+		}
+		return doc.Content[0]
+	}
+	if doc.Kind == 0 {
+		return nil
+	}
+	return doc
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
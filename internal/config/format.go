@@ -0,0 +1,484 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// FormatForPath returns the config format of a file from its extension
+func FormatForPath(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config format: %s", ext)
+	}
+}
+
+// FormatExtension returns the file extension used for a config format
+func FormatExtension(format string) (string, error) {
+	switch format {
+	case FormatYAML, FormatJSON, FormatTOML:
+		return "." + format, nil
+	default:
+		return "", fmt.Errorf("unknown config format %q (use yaml, json or toml)", format)
+	}
+}
+
+// ConfigFilePath returns the first config file found in dir, following the
+// files.config_names order, or dir/muno.yaml when there is none
+func ConfigFilePath(dir string) string {
+	for _, name := range GetConfigFileNames() {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return filepath.Join(dir, "muno.yaml")
+}
+
+// Unmarshal decodes config data in the given format into v. Every format is
+// read into a YAML node first, so the yaml struct tags are the schema for all
+// of them.
+func Unmarshal(format string, data []byte, v interface{}) error {
+	if format == FormatYAML {
+		return yaml.Unmarshal(data, v)
+	}
+	node, err := ParseNode(format, data)
+	if err != nil {
+		return err
+	}
+	if node.Kind == 0 {
+		return nil
+	}
+	return node.Decode(v)
+}
+
+// Marshal encodes v in the given config format
+func Marshal(format string, v interface{}) ([]byte, error) {
+	if format == FormatYAML {
+		return yaml.Marshal(v)
+	}
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	return EncodeNode(format, &node)
+}
+
+// ParseNode parses config data in the given format into a YAML node. JSON
+// keeps its key order; TOML tables come back with sorted keys.
+func ParseNode(format string, data []byte) (*yaml.Node, error) {
+	switch format {
+	case FormatYAML:
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		return &node, nil
+	case FormatJSON:
+		if len(bytes.TrimSpace(data)) == 0 {
+			return &yaml.Node{}, nil
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		node, err := parseJSONValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, fmt.Errorf("unexpected data after the top-level JSON value")
+		}
+		return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{node}}, nil
+	case FormatTOML:
+		var doc map[string]interface{}
+		if _, err := toml.Decode(string(data), &doc); err != nil {
+			return nil, err
+		}
+		var node yaml.Node
+		if err := node.Encode(doc); err != nil {
+			return nil, err
+		}
+		return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&node}}, nil
+	default:
+		return nil, fmt.Errorf("unknown config format %q (use yaml, json or toml)", format)
+	}
+}
+
+// EncodeNode writes a YAML node in the given config format
+func EncodeNode(format string, node *yaml.Node) ([]byte, error) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	switch format {
+	case FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		var compact bytes.Buffer
+		if err := writeJSONNode(&compact, node); err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	case FormatTOML:
+		node = resolveAlias(node)
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("TOML documents must be tables")
+		}
+		var buf bytes.Buffer
+		if err := writeTOMLTable(&buf, nil, node, false); err != nil {
+			return nil, err
+		}
+		return bytes.TrimLeft(buf.Bytes(), "\n"), nil
+	default:
+		return nil, fmt.Errorf("unknown config format %q (use yaml, json or toml)", format)
+	}
+}
+
+// parseJSONValue reads the next JSON value as a YAML node, keeping key order
+func parseJSONValue(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch value := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if value == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			child, err := parseJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(value.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+// resolveAlias follows YAML aliases to the anchored node
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// scalarValue decodes a YAML scalar to a bool, int64, float64, string or nil
+func scalarValue(node *yaml.Node) (interface{}, error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var b bool
+		err := node.Decode(&b)
+		return b, err
+	case "!!int":
+		var i int64
+		if err := node.Decode(&i); err == nil {
+			return i, nil
+		}
+		var f float64
+		err := node.Decode(&f)
+		return f, err
+	case "!!float":
+		var f float64
+		err := node.Decode(&f)
+		return f, err
+	default:
+		return node.Value, nil
+	}
+}
+
+// writeJSONNode writes a YAML node as compact JSON
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, node.Content[i].Value)
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, child); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		value, err := scalarValue(node)
+		if err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case string:
+			writeJSONString(buf, v)
+		case float64:
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				return fmt.Errorf("JSON cannot represent %s", node.Value)
+			}
+			buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		default:
+			data, _ := json.Marshal(v)
+			buf.Write(data)
+		}
+	default:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+// writeJSONString writes s as a JSON string without HTML escaping
+func writeJSONString(buf *bytes.Buffer, s string) {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	buf.Write(bytes.TrimRight(out.Bytes(), "\n"))
+}
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// writeTOMLTable writes a mapping as a TOML table. Plain values come first,
+// then sub-tables and arrays of tables, each under its own header.
+func writeTOMLTable(buf *bytes.Buffer, keys []string, node *yaml.Node, arrayItem bool) error {
+	type entry struct {
+		key   string
+		value *yaml.Node
+	}
+	var values, tables []entry
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		e := entry{key: node.Content[i].Value, value: resolveAlias(node.Content[i+1])}
+		switch {
+		case e.value.Kind == yaml.ScalarNode && e.value.ShortTag() == "!!null":
+			// TOML has no null; leaving the key out means the same
+		case e.value.Kind == yaml.MappingNode || isTOMLTableArray(e.value):
+			tables = append(tables, e)
+		default:
+			values = append(values, e)
+		}
+	}
+
+	if arrayItem {
+		fmt.Fprintf(buf, "\n[[%s]]\n", tomlKeyPath(keys))
+	} else if len(keys) > 0 && (len(values) > 0 || len(tables) == 0) {
+		fmt.Fprintf(buf, "\n[%s]\n", tomlKeyPath(keys))
+	}
+	for _, e := range values {
+		buf.WriteString(tomlKey(e.key) + " = ")
+		if err := writeTOMLValue(buf, e.value); err != nil {
+			return fmt.Errorf("%s: %w", tomlKeyPath(append(keys, e.key)), err)
+		}
+		buf.WriteByte('\n')
+	}
+	for _, e := range tables {
+		childKeys := append(append([]string{}, keys...), e.key)
+		if e.value.Kind == yaml.MappingNode {
+			if err := writeTOMLTable(buf, childKeys, e.value, false); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, item := range e.value.Content {
+			if err := writeTOMLTable(buf, childKeys, resolveAlias(item), true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isTOMLTableArray reports whether a sequence is written as [[array]] tables
+func isTOMLTableArray(node *yaml.Node) bool {
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		return false
+	}
+	for _, item := range node.Content {
+		if resolveAlias(item).Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTOMLValue writes an inline TOML value
+func writeTOMLValue(buf *bytes.Buffer, node *yaml.Node) error {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := writeTOMLValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.MappingNode:
+		buf.WriteString("{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(" " + tomlKey(node.Content[i].Value) + " = ")
+			if err := writeTOMLValue(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(" }")
+	case yaml.ScalarNode:
+		value, err := scalarValue(node)
+		if err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case nil:
+			return fmt.Errorf("TOML cannot represent null")
+		case string:
+			buf.WriteString(tomlString(v))
+		case float64:
+			buf.WriteString(tomlFloat(v))
+		default:
+			fmt.Fprint(buf, v)
+		}
+	default:
+		return fmt.Errorf("unsupported YAML node")
+	}
+	return nil
+}
+
+// tomlKeyPath joins table keys into a dotted TOML header
+func tomlKeyPath(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = tomlKey(key)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// tomlKey returns key bare when TOML allows it, quoted otherwise
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlString returns s as a TOML basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlFloat formats f so TOML reads it back as a float
+func tomlFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleConfigTree() *ConfigTree {
+	return &ConfigTree{
+		Workspace: WorkspaceTree{Name: "test", ReposDir: ".nodes"},
+		Nodes: []NodeDefinition{
+			{
+				Name:      "api",
+				URL:       "git@github.com:org/api.git",
+				Fetch:     "eager",
+				Overrides: map[string]interface{}{"git": map[string]interface{}{"default_branch": "develop", "clone_timeout": 600}},
+				Metadata:  map[string]string{"team": "core \"platform\""},
+				DependsOn: []string{"lib"},
+			},
+			{Name: "team", File: "team/muno.toml"},
+		},
+		Overrides: map[string]interface{}{"git.protected_branches": []interface{}{"main", "release/*"}},
+		Navigator: &NavigatorConfig{Cache: NavigatorCacheConfig{Enabled: true, TTL: "30s"}},
+	}
+}
+
+func TestConfigTree_SaveLoadEveryFormat(t *testing.T) {
+	for _, name := range []string{"muno.yaml", "muno.json", "muno.toml"} {
+		path := filepath.Join(t.TempDir(), name)
+		cfg := sampleConfigTree()
+		require.NoError(t, cfg.Save(path), name)
+
+		loaded, err := LoadTree(path)
+		require.NoError(t, err, name)
+		assert.Equal(t, cfg.Workspace, loaded.Workspace, name)
+		assert.Equal(t, cfg.Navigator, loaded.Navigator, name)
+		assert.Equal(t, path, loaded.File, name)
+		require.Len(t, loaded.Nodes, 2, name)
+		assert.Equal(t, cfg.Nodes[0].Metadata, loaded.Nodes[0].Metadata, name)
+		assert.Equal(t, cfg.Nodes[0].DependsOn, loaded.Nodes[0].DependsOn, name)
+		assert.Equal(t, 600, loaded.Nodes[0].Overrides["git"].(map[string]interface{})["clone_timeout"], name)
+		assert.Equal(t, cfg.Nodes[1], loaded.Nodes[1], name)
+	}
+}
+
+func TestMarshal_JSONKeepsFieldOrder(t *testing.T) {
+	data, err := Marshal(FormatJSON, &ConfigTree{
+		Workspace: WorkspaceTree{Name: "test"},
+		Nodes:     []NodeDefinition{{Name: "api", URL: "https://example.com/api.git?a=1&b=2"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "workspace": {
+    "name": "test"
+  },
+  "nodes": [
+    {
+      "name": "api",
+      "url": "https://example.com/api.git?a=1&b=2"
+    }
+  ]
+}
+`, string(data))
+}
+
+func TestMarshal_TOMLTables(t *testing.T) {
+	data, err := Marshal(FormatTOML, sampleConfigTree())
+	require.NoError(t, err)
+	assert.Equal(t, `[workspace]
+name = "test"
+repos_dir = ".nodes"
+
+[[nodes]]
+name = "api"
+url = "git@github.com:org/api.git"
+fetch = "eager"
+depends_on = ["lib"]
+
+[nodes.overrides.git]
+clone_timeout = 600
+default_branch = "develop"
+
+[nodes.metadata]
+team = "core \"platform\""
+
+[[nodes]]
+name = "team"
+file = "team/muno.toml"
+
+[overrides]
+"git.protected_branches" = ["main", "release/*"]
+
+[navigator.cache]
+enabled = true
+ttl = "30s"
+`, string(data))
+}
+
+func TestParseNode_JSONKeepsKeyOrder(t *testing.T) {
+	node, err := ParseNode(FormatJSON, []byte("{\n\t\"zeta\": 1,\n\t\"alpha\": [true, null, 2.5]\n}"))
+	require.NoError(t, err)
+	out, err := EncodeNode(FormatYAML, node)
+	require.NoError(t, err)
+	assert.Equal(t, "zeta: 1\nalpha:\n  - true\n  - null\n  - 2.5\n", string(out))
+
+	_, err = ParseNode(FormatJSON, []byte(`{"a": 1} {"b": 2}`))
+	assert.EqualError(t, err, "unexpected data after the top-level JSON value")
+}
+
+func TestEncodeNode_TOMLRejectsNullInArrays(t *testing.T) {
+	node, err := ParseNode(FormatYAML, []byte("list: [a, null]\nskipped: null\n"))
+	require.NoError(t, err)
+	_, err = EncodeNode(FormatTOML, node)
+	assert.EqualError(t, err, "list: TOML cannot represent null")
+}
+
+func TestFormatForPath(t *testing.T) {
+	for path, want := range map[string]string{"muno.yaml": FormatYAML, ".muno.yml": FormatYAML, "team.JSON": FormatJSON, "muno.toml": FormatTOML} {
+		format, err := FormatForPath(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, format, path)
+	}
+	_, err := FormatForPath("muno.ini")
+	assert.EqualError(t, err, "unsupported config format: .ini")
+}
+
+func TestConfigFilePath(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, filepath.Join(dir, "muno.yaml"), ConfigFilePath(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "muno.toml"), nil, 0644))
+	assert.Equal(t, filepath.Join(dir, "muno.toml"), ConfigFilePath(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "muno.json"), nil, 0644))
+	assert.Equal(t, filepath.Join(dir, "muno.json"), ConfigFilePath(dir), "files.config_names order wins")
+}
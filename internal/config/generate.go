@@ -0,0 +1,327 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// A generate: node builds its children from a repository source instead of
+// listing them by hand:
+//
+//	- name: services
+//	  generate:
+//	    list: repos.txt            # or dir: /srv/git, or plugin: <name>
+//	    include: ["^svc-"]
+//	    exclude: ["-archive$"]
+//	    name: '{{.Name | trimPrefix "svc-"}}'
+//	    fetch: lazy
+//
+// The children are written to a generated config under .muno/generated
+// next to the config, and the node then behaves like a file: node pointing
+// at it. muno sync evaluates the sources again.
+
+// GeneratedDir is where generated configs live, relative to their config
+const GeneratedDir = ".muno/generated"
+
+// GenerateSpec describes where a generate: node finds its repositories
+type GenerateSpec struct {
+	List     string   `yaml:"list,omitempty"`     // Text, CSV or JSON file listing repositories
+	Dir      string   `yaml:"dir,omitempty"`      // Directory of bare repositories
+	Plugin   string   `yaml:"plugin,omitempty"`   // Plugin providing a repository catalog
+	Command  string   `yaml:"command,omitempty"`  // Plugin command returning the catalog (default: catalog)
+	Args     []string `yaml:"args,omitempty"`     // Arguments for the plugin command
+	Include  []string `yaml:"include,omitempty"`  // Keep repositories whose name matches any of these
+	Exclude  []string `yaml:"exclude,omitempty"`  // Drop repositories whose name matches any of these
+	Name     string   `yaml:"name,omitempty"`     // Template for node names, e.g. {{.Name}}
+	Fetch    string   `yaml:"fetch,omitempty"`    // Fetch mode of the generated nodes
+	Template string   `yaml:"template,omitempty"` // Node template the generated nodes use
+}
+
+// GeneratedRepo is a repository found by a generate: source
+type GeneratedRepo struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Fetch string `json:"fetch,omitempty"`
+}
+
+// nameFuncs are the functions available in generate name templates
+var nameFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+}
+
+// Source describes where the spec reads repositories from
+func (g *GenerateSpec) Source() string {
+	switch {
+	case g.List != "":
+		return g.List
+	case g.Dir != "":
+		return g.Dir
+	default:
+		return "plugin " + g.Plugin
+	}
+}
+
+// Validate checks that the spec has exactly one source and that its
+// patterns and name template parse
+func (g *GenerateSpec) Validate() error {
+	sources := 0
+	for _, s := range []string{g.List, g.Dir, g.Plugin} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("generate needs exactly one of list, dir or plugin")
+	}
+	if _, err := compilePatterns(g.Include); err != nil {
+		return err
+	}
+	if _, err := compilePatterns(g.Exclude); err != nil {
+		return err
+	}
+	if g.Name != "" {
+		if _, err := template.New("name").Funcs(nameFuncs).Parse(g.Name); err != nil {
+			return fmt.Errorf("invalid name template: %w", err)
+		}
+	}
+	return nil
+}
+
+// GeneratedConfigPath returns the generated config of a generate: node
+// defined in the config in dir
+func GeneratedConfigPath(dir, nodeName string) string {
+	return filepath.Join(dir, filepath.FromSlash(GeneratedDir), nodeName+".yaml")
+}
+
+// IsGeneratedConfigPath reports whether file is the generated config of the
+// generate: node nodeName, as DecodeTree sets it
+func IsGeneratedConfigPath(file, nodeName string) bool {
+	dir := filepath.Dir(filepath.Dir(filepath.Dir(file)))
+	return file == GeneratedConfigPath(dir, nodeName)
+}
+
+// ConfigDir returns the directory of a config file, following symlinks so
+// that relative paths in linked configs resolve next to the real file
+func ConfigDir(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	return filepath.Dir(path)
+}
+
+// GenerateTree builds the config holding the children of a generate: node
+// from the repositories its source returned. templates are the node
+// templates of the config defining the node.
+func GenerateTree(nodeName string, spec *GenerateSpec, repos []GeneratedRepo, templates map[string]NodeTemplate) (*ConfigTree, error) {
+	include, err := compilePatterns(spec.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compilePatterns(spec.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	var naming *template.Template
+	if spec.Name != "" {
+		if naming, err = template.New("name").Funcs(nameFuncs).Parse(spec.Name); err != nil {
+			return nil, fmt.Errorf("invalid name template: %w", err)
+		}
+	}
+
+	cfg := &ConfigTree{Workspace: WorkspaceTree{Name: nodeName}, Nodes: []NodeDefinition{}}
+	if spec.Template != "" {
+		tmpl, ok := templates[spec.Template]
+		if !ok {
+			return nil, fmt.Errorf("unknown template %q", spec.Template)
+		}
+		cfg.Templates = map[string]NodeTemplate{spec.Template: tmpl}
+	}
+
+	seen := make(map[string]string)
+	for _, repo := range repos {
+		if repo.Name == "" {
+			repo.Name = extractRepoNameFromURL(repo.URL)
+		}
+		if (len(include) > 0 && !matchesAny(include, repo.Name)) || matchesAny(exclude, repo.Name) {
+			continue
+		}
+		name := repo.Name
+		if naming != nil {
+			var buf bytes.Buffer
+			if err := naming.Execute(&buf, repo); err != nil {
+				return nil, fmt.Errorf("naming %s: %w", repo.Name, err)
+			}
+			name = strings.TrimSpace(buf.String())
+		}
+		if name == "" || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("invalid node name %q for %s", name, repo.URL)
+		}
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("%s and %s are both named %q", other, repo.URL, name)
+		}
+		seen[name] = repo.URL
+
+		fetch := repo.Fetch
+		if fetch == "" {
+			fetch = spec.Fetch
+		}
+		cfg.Nodes = append(cfg.Nodes, NodeDefinition{Name: name, URL: repo.URL, Fetch: fetch, Template: spec.Template})
+	}
+	sort.Slice(cfg.Nodes, func(i, j int) bool { return cfg.Nodes[i].Name < cfg.Nodes[j].Name })
+	return cfg, nil
+}
+
+// ReadRepoListing reads a repository listing. JSON files hold an array of
+// URLs or of {name, url, fetch} objects; CSV files have a header row with a
+// url column and optional name and fetch columns; other files list one
+// repository per line as "url [name]", with # starting a comment.
+func ReadRepoListing(path string) ([]GeneratedRepo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var catalog interface{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+		}
+		return ParseRepoCatalog(catalog)
+	case ".csv":
+		return parseRepoCSV(data)
+	}
+
+	var repos []GeneratedRepo
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			repos = append(repos, GeneratedRepo{URL: fields[0]})
+		default:
+			repos = append(repos, GeneratedRepo{URL: fields[0], Name: fields[1]})
+		}
+	}
+	return repos, nil
+}
+
+// ParseRepoCatalog reads repositories from decoded JSON: an array of URLs
+// or of {name, url, fetch} objects, as listings and plugins provide them
+func ParseRepoCatalog(catalog interface{}) ([]GeneratedRepo, error) {
+	items, ok := catalog.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("repository catalog must be an array")
+	}
+	repos := make([]GeneratedRepo, 0, len(items))
+	for i, item := range items {
+		switch v := item.(type) {
+		case string:
+			repos = append(repos, GeneratedRepo{URL: v})
+		case map[string]interface{}:
+			repo := GeneratedRepo{}
+			repo.Name, _ = v["name"].(string)
+			repo.URL, _ = v["url"].(string)
+			repo.Fetch, _ = v["fetch"].(string)
+			if repo.URL == "" {
+				return nil, fmt.Errorf("catalog entry %d has no url", i)
+			}
+			repos = append(repos, repo)
+		default:
+			return nil, fmt.Errorf("catalog entry %d must be a URL or an object", i)
+		}
+	}
+	return repos, nil
+}
+
+func parseRepoCSV(data []byte) ([]GeneratedRepo, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	urlColumn, ok := columns["url"]
+	if !ok {
+		return nil, fmt.Errorf("CSV listing needs a url column")
+	}
+	cell := func(row []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var repos []GeneratedRepo
+	for _, row := range rows[1:] {
+		if urlColumn >= len(row) || strings.TrimSpace(row[urlColumn]) == "" {
+			continue
+		}
+		repos = append(repos, GeneratedRepo{URL: strings.TrimSpace(row[urlColumn]), Name: cell(row, "name"), Fetch: cell(row, "fetch")})
+	}
+	return repos, nil
+}
+
+// ScanRepoDir lists the bare repositories (name.git) in a directory
+func ScanRepoDir(dir string) ([]GeneratedRepo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var repos []GeneratedRepo
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasSuffix(e.Name(), ".git") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+			continue
+		}
+		repos = append(repos, GeneratedRepo{Name: strings.TrimSuffix(e.Name(), ".git"), URL: path})
+	}
+	return repos, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRepoListing(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	repos, err := ReadRepoListing(write("repos.txt", "# services\ngit@github.com:acme/api.git\n\nhttps://github.com/acme/web.git frontend # the site\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []GeneratedRepo{
+		{URL: "git@github.com:acme/api.git"},
+		{URL: "https://github.com/acme/web.git", Name: "frontend"},
+	}, repos)
+
+	repos, err = ReadRepoListing(write("repos.csv", "name,url,fetch\napi,git@github.com:acme/api.git,lazy\n,https://github.com/acme/web.git,\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []GeneratedRepo{
+		{Name: "api", URL: "git@github.com:acme/api.git", Fetch: "lazy"},
+		{URL: "https://github.com/acme/web.git"},
+	}, repos)
+
+	repos, err = ReadRepoListing(write("repos.json", `["git@github.com:acme/api.git", {"name": "site", "url": "https://github.com/acme/web.git", "fetch": "eager"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []GeneratedRepo{
+		{URL: "git@github.com:acme/api.git"},
+		{Name: "site", URL: "https://github.com/acme/web.git", Fetch: "eager"},
+	}, repos)
+
+	_, err = ReadRepoListing(write("bad.csv", "name,repo\napi,x\n"))
+	assert.ErrorContains(t, err, "needs a url column")
+	_, err = ReadRepoListing(write("bad.json", `[{"name": "api"}]`))
+	assert.ErrorContains(t, err, "catalog entry 0 has no url")
+}
+
+func TestGenerateTree(t *testing.T) {
+	repos := []GeneratedRepo{
+		{URL: "git@github.com:acme/svc-api.git"},
+		{URL: "git@github.com:acme/svc-web.git", Fetch: "eager"},
+		{URL: "git@github.com:acme/svc-old-archive.git"},
+		{URL: "git@github.com:acme/tools.git"},
+	}
+	templates := map[string]NodeTemplate{"svc": {Fetch: "lazy"}}
+	spec := &GenerateSpec{
+		List:     "repos.txt",
+		Include:  []string{"^svc-"},
+		Exclude:  []string{"-archive$"},
+		Name:     `{{.Name | trimPrefix "svc-" | upper}}`,
+		Fetch:    "lazy",
+		Template: "svc",
+	}
+
+	cfg, err := GenerateTree("services", spec, repos, templates)
+	require.NoError(t, err)
+	assert.Equal(t, "services", cfg.Workspace.Name)
+	assert.Equal(t, templates, cfg.Templates)
+	assert.Equal(t, []NodeDefinition{
+		{Name: "API", URL: "git@github.com:acme/svc-api.git", Fetch: "lazy", Template: "svc"},
+		{Name: "WEB", URL: "git@github.com:acme/svc-web.git", Fetch: "eager", Template: "svc"},
+	}, cfg.Nodes)
+
+	_, err = GenerateTree("services", &GenerateSpec{List: "repos.txt", Name: "same"}, repos, nil)
+	assert.ErrorContains(t, err, `are both named "same"`)
+	_, err = GenerateTree("services", &GenerateSpec{List: "repos.txt", Template: "missing"}, repos, nil)
+	assert.ErrorContains(t, err, `unknown template "missing"`)
+}
+
+func TestGenerateSpec_Validate(t *testing.T) {
+	assert.NoError(t, (&GenerateSpec{Dir: "/srv/git"}).Validate())
+	assert.ErrorContains(t, (&GenerateSpec{}).Validate(), "exactly one of list, dir or plugin")
+	assert.ErrorContains(t, (&GenerateSpec{List: "a.txt", Plugin: "github"}).Validate(), "exactly one of list, dir or plugin")
+	assert.ErrorContains(t, (&GenerateSpec{List: "a.txt", Include: []string{"("}}).Validate(), "invalid pattern")
+	assert.ErrorContains(t, (&GenerateSpec{List: "a.txt", Name: "{{.Name"}).Validate(), "invalid name template")
+
+	cfg := &ConfigTree{
+		Workspace: WorkspaceTree{Name: "acme"},
+		Nodes:     []NodeDefinition{{Name: "svc", URL: "https://github.com/acme/svc.git", Generate: &GenerateSpec{List: "a.txt"}}},
+	}
+	assert.ErrorContains(t, cfg.Validate(), "cannot have both URL and generate")
+
+	cfg.Nodes[0] = NodeDefinition{Name: "svc", File: "svc.yaml", Generate: &GenerateSpec{List: "a.txt"}}
+	assert.ErrorContains(t, cfg.Validate(), "cannot have both file and generate")
+	cfg.Nodes[0].File = GeneratedConfigPath("/ws", "svc")
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestScanRepoDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"api.git", "web.git"} {
+		require.NoError(t, exec.Command("git", "init", "--quiet", "--bare", filepath.Join(dir, name)).Run())
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "notes.git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "plain"), 0755))
+
+	repos, err := ScanRepoDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []GeneratedRepo{
+		{Name: "api", URL: filepath.Join(dir, "api.git")},
+		{Name: "web", URL: filepath.Join(dir, "web.git")},
+	}, repos)
+}
+
+func TestLoadTree_GenerateNode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "muno.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("workspace:\n  name: acme\nnodes:\n  - name: services\n    generate:\n      list: repos.txt\n"), 0644))
+
+	cfg, err := LoadTree(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.Nodes, 1)
+	assert.Equal(t, GeneratedConfigPath(ConfigDir(path), "services"), cfg.Nodes[0].File)
+	assert.Equal(t, "repos.txt", cfg.Nodes[0].Generate.Source())
+
+	// A hand-written file would be overwritten by muno sync
+	require.NoError(t, os.WriteFile(path, []byte("workspace:\n  name: acme\nnodes:\n  - name: services\n    file: services.yaml\n    generate:\n      list: repos.txt\n"), 0644))
+	_, err = LoadTree(path)
+	assert.ErrorContains(t, err, "node services cannot have both file and generate fields")
+}
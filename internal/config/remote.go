@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// A file: node may reference a config published in another git repository:
+//
+//	file: git+ssh://git@github.com/acme/platform-config.git//teams/muno.yaml@v2
+//
+// The part before "//" names the repository, the part after it the config's
+// path inside the repository, and the optional @ref a branch, tag or commit.
+// Without a ref the repository's default branch is used.
+
+// RemoteConfigDir is where remote configs are cached, relative to the workspace
+const RemoteConfigDir = ".muno/cache/configs"
+
+// remoteSchemes are the transports accepted after the git+ prefix
+var remoteSchemes = map[string]bool{"ssh": true, "https": true, "http": true, "git": true, "file": true}
+
+// RemoteRef is a parsed remote config reference
+type RemoteRef struct {
+	Repo string // Repository URL without the git+ prefix
+	Path string // Config file path inside the repository
+	Ref  string // Branch, tag or commit; empty for the default branch
+}
+
+// IsRemoteRef reports whether a file: value references a config in a git repository
+func IsRemoteRef(file string) bool {
+	return strings.HasPrefix(file, "git+")
+}
+
+// ParseRemoteRef parses a git+<scheme>://<repo>//<path>[@ref] reference
+func ParseRemoteRef(file string) (RemoteRef, error) {
+	rest := strings.TrimPrefix(file, "git+")
+	scheme, location, ok := strings.Cut(rest, "://")
+	if !IsRemoteRef(file) || !ok || !remoteSchemes[scheme] {
+		return RemoteRef{}, fmt.Errorf("invalid remote config %q (use git+ssh, git+https, git+http, git+git or git+file)", file)
+	}
+	repo, configPath, ok := strings.Cut(location, "//")
+	if !ok || repo == "" || strings.Trim(repo, "/") == "" {
+		return RemoteRef{}, fmt.Errorf("remote config %q has no //path to the config file", file)
+	}
+
+	ref := ""
+	if i := strings.LastIndex(configPath, "@"); i >= 0 {
+		configPath, ref = configPath[:i], configPath[i+1:]
+		if ref == "" {
+			return RemoteRef{}, fmt.Errorf("remote config %q has an empty @ref", file)
+		}
+	}
+	configPath = path.Clean(configPath)
+	if configPath == "." || path.IsAbs(configPath) || configPath == ".." || strings.HasPrefix(configPath, "../") {
+		return RemoteRef{}, fmt.Errorf("remote config %q must name a file inside the repository", file)
+	}
+
+	return RemoteRef{Repo: scheme + "://" + repo, Path: configPath, Ref: ref}, nil
+}
+
+// String returns the reference in the form accepted by ParseRemoteRef
+func (r RemoteRef) String() string {
+	s := "git+" + r.Repo + "//" + r.Path
+	if r.Ref != "" {
+		s += "@" + r.Ref
+	}
+	return s
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemoteRef(t *testing.T) {
+	tests := map[string]RemoteRef{
+		"git+ssh://git@github.com/acme/platform-config.git//muno.yaml@v2": {Repo: "ssh://git@github.com/acme/platform-config.git", Path: "muno.yaml", Ref: "v2"},
+		"git+https://example.com/acme/cfg.git//teams/core/muno.toml":      {Repo: "https://example.com/acme/cfg.git", Path: "teams/core/muno.toml"},
+		"git+file:///srv/git/cfg.git//./muno.json@release/2024":           {Repo: "file:///srv/git/cfg.git", Path: "muno.json", Ref: "release/2024"},
+	}
+	for file, want := range tests {
+		assert.True(t, IsRemoteRef(file), file)
+		got, err := ParseRemoteRef(file)
+		require.NoError(t, err, file)
+		assert.Equal(t, want, got, file)
+	}
+	assert.Equal(t, "git+ssh://host/cfg.git//muno.yaml@v2", RemoteRef{Repo: "ssh://host/cfg.git", Path: "muno.yaml", Ref: "v2"}.String())
+	assert.False(t, IsRemoteRef("team/muno.yaml"))
+}
+
+func TestParseRemoteRef_Invalid(t *testing.T) {
+	for file, want := range map[string]string{
+		"git+ftp://host/cfg.git//muno.yaml":    `invalid remote config "git+ftp://host/cfg.git//muno.yaml" (use git+ssh, git+https, git+http, git+git or git+file)`,
+		"git+ssh://host/cfg.git":               `remote config "git+ssh://host/cfg.git" has no //path to the config file`,
+		"git+ssh://host/cfg.git//muno.yaml@":   `remote config "git+ssh://host/cfg.git//muno.yaml@" has an empty @ref`,
+		"git+ssh://host/cfg.git//../muno.yaml": `remote config "git+ssh://host/cfg.git//../muno.yaml" must name a file inside the repository`,
+	} {
+		_, err := ParseRemoteRef(file)
+		assert.EqualError(t, err, want, file)
+	}
+
+	cfg := &ConfigTree{Workspace: WorkspaceTree{Name: "ws"}, Nodes: []NodeDefinition{{Name: "platform", File: "git+ssh://host/cfg.git"}}}
+	assert.EqualError(t, cfg.Validate(), `node platform: remote config "git+ssh://host/cfg.git" has no //path to the config file`)
+}
@@ -106,6 +106,7 @@ var nodeSpecificKeys = map[string]bool{
 	"git.default_branch":  true,
 	"git.default_remote":  true,
 	"git.shallow_depth":   true,
+	"git.protected_branches": true,
 	"fetch":              true,
 }
 
@@ -245,6 +246,9 @@ func defaultsToMap(d *DefaultConfiguration) map[string]interface{} {
 			"default_branch": d.Git.DefaultBranch,
 			"clone_timeout":  d.Git.CloneTimeout,
 			"shallow_depth":  d.Git.ShallowDepth,
+			"fetch_interval": d.Git.FetchInterval,
+			"fetch_stale_after": d.Git.FetchStaleAfter,
+			"protected_branches": d.Git.ProtectedBranches,
 		},
 		"behavior": map[string]interface{}{
 			"auto_clone_on_nav":    d.Behavior.AutoCloneOnNav,
@@ -262,5 +266,10 @@ func defaultsToMap(d *DefaultConfiguration) map[string]interface{} {
 			"state_file":        d.Files.StateFile,
 			"legacy_state_file": d.Files.LegacyStateFile,
 		},
+		"agent": map[string]interface{}{
+			"command":             d.Agent.Command,
+			"terminal":            d.Agent.Terminal,
+			"write_context_files": d.Agent.WriteContextFiles,
+		},
 	}
 }
\ No newline at end of file
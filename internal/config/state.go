@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"time"
 )
 
@@ -12,6 +14,48 @@ type State struct {
 	Timestamp       string            `json:"timestamp"`
 	CurrentNodePath string            `json:"current_node_path,omitempty"` // Current node in tree
 	Sessions        map[string]Session `json:"sessions"`                    // Active Claude sessions
+	StatusCache     map[string]RepoStatus `json:"status_cache,omitempty"`   // Cached repository states by workspace-relative path
+	Fetches         map[string]string  `json:"fetches,omitempty"`            // Last successful fetch (RFC3339) by node path
+	Changes         map[string]Change  `json:"changes,omitempty"`            // Cross-repository changes by name
+	CurrentChange   string             `json:"current_change,omitempty"`     // Change muno change status and land default to
+
+	loaded *State // The state as read or last written, to merge changes from other processes into
+}
+
+// RepoStatus is a cached repository state together with the signature it was computed for
+type RepoStatus struct {
+	Head      string          `json:"head"`                 // Commit HEAD points to (or the ref for unborn branches)
+	IndexTime int64           `json:"index_time"`           // Modification time of .git/index in nanoseconds
+	IndexSize int64           `json:"index_size"`           // Size of .git/index
+	Worktree  string          `json:"worktree"`             // Hash over tracked file stats, their directories and ignore rules
+	State     string          `json:"state"`                // Repository state: cloned or modified
+	CheckedAt string          `json:"checked_at,omitempty"` // When git status last ran
+	Node      *RepoNodeStatus `json:"node,omitempty"`       // Status details recorded by the navigator
+}
+
+// RepoNodeStatus holds the details navigator status shows for a repository
+type RepoNodeStatus struct {
+	Branch    string `json:"branch,omitempty"`
+	RemoteURL string `json:"remote_url,omitempty"`
+	Staged    int    `json:"staged,omitempty"`
+	Unstaged  int    `json:"unstaged,omitempty"`
+	Untracked int    `json:"untracked,omitempty"`
+	Behind    int    `json:"behind,omitempty"`
+	Lazy      bool   `json:"lazy,omitempty"`
+}
+
+// Change is a topic branch started in several repositories with muno change
+type Change struct {
+	Branch  string                `json:"branch"`
+	Created string                `json:"created"`
+	Landed  string                `json:"landed,omitempty"`
+	Repos   map[string]ChangeRepo `json:"repos"` // By node path
+}
+
+// ChangeRepo records where a change branched off in one repository
+type ChangeRepo struct {
+	BaseBranch string `json:"base_branch"`
+	BaseCommit string `json:"base_commit,omitempty"`
 }
 
 // Session represents an active Claude Code session
@@ -21,31 +65,71 @@ type Session struct {
 	PID          int    `json:"pid,omitempty"`
 	StartTime    string `json:"start_time"`
 	LastActivity string `json:"last_activity"`
+	Command      string `json:"command,omitempty"`      // Command launched in the node directory
+	Terminal     string `json:"terminal,omitempty"`     // tmux or background
+	TmuxSession  string `json:"tmux_session,omitempty"` // tmux session name when Terminal is tmux
+	LogFile      string `json:"log_file,omitempty"`     // Output log for background sessions
 }
 
 // LoadState reads state from JSON file
 func LoadState(path string) (*State, error) {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	state, err := readState(path)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
 		return &State{
 			Sessions: make(map[string]Session),
+			loaded:   &State{},
 		}, nil
 	}
+	return state, nil
+}
 
+// readState reads the state file, returning nil when there is none
+func readState(path string) (*State, error) {
 	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("reading state file: %w", err)
 	}
 
-	var state State
-	if err := json.Unmarshal(data, &state); err != nil {
+	state, err := parseState(data)
+	if err != nil {
 		return nil, fmt.Errorf("parsing state: %w", err)
 	}
+	return state, nil
+}
 
+// parseState decodes state together with an unmodified copy to merge against
+func parseState(data []byte) (*State, error) {
+	var state, loaded State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return nil, err
+	}
+	state.loaded = &loaded
 	return &state, nil
 }
 
-// SaveState writes state to JSON file
+// SaveState writes state to JSON file. Under a lock shared by all muno
+// processes it re-reads the file and keeps what other processes changed
+// since this state was loaded, then replaces the file in one rename.
 func (s *State) SaveState(path string) error {
+	unlock, err := LockFile(stateLockPath(path))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// A corrupt state file is replaced rather than merged
+	if current, err := readState(path); err == nil && current != nil {
+		s.merge(current)
+	}
 	s.Timestamp = time.Now().Format(time.RFC3339)
 
 	data, err := json.MarshalIndent(s, "", "  ")
@@ -53,13 +137,62 @@ func (s *State) SaveState(path string) error {
 		return fmt.Errorf("marshaling state: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
 		return fmt.Errorf("writing state file: %w", err)
 	}
 
+	if written, err := parseState(data); err == nil {
+		s.loaded = written.loaded
+	}
 	return nil
 }
 
+// stateLockPath returns the lock file guarding the state file at path. It
+// lives in the workspace's .muno directory, which is kept out of git.
+func stateLockPath(path string) string {
+	return filepath.Join(filepath.Dir(path), ".muno", filepath.Base(path)+".lock")
+}
+
+// merge takes every value s left unchanged since it was loaded from current,
+// the state another process may have written in the meantime
+func (s *State) merge(current *State) {
+	base := s.loaded
+	if base == nil {
+		base = &State{}
+	}
+
+	if s.CurrentNodePath == base.CurrentNodePath {
+		s.CurrentNodePath = current.CurrentNodePath
+	}
+	if s.CurrentChange == base.CurrentChange {
+		s.CurrentChange = current.CurrentChange
+	}
+	s.Sessions = mergeStateMap(base.Sessions, s.Sessions, current.Sessions)
+	s.StatusCache = mergeStateMap(base.StatusCache, s.StatusCache, current.StatusCache)
+	s.Fetches = mergeStateMap(base.Fetches, s.Fetches, current.Fetches)
+	s.Changes = mergeStateMap(base.Changes, s.Changes, current.Changes)
+}
+
+// mergeStateMap applies the entries added, changed or removed in mine
+// relative to base on top of current
+func mergeStateMap[V any](base, mine, current map[string]V) map[string]V {
+	merged := make(map[string]V, len(current))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key := range base {
+		if _, ok := mine[key]; !ok {
+			delete(merged, key)
+		}
+	}
+	for key, value := range mine {
+		if old, ok := base[key]; !ok || !reflect.DeepEqual(old, value) {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
 // SetCurrentNode updates the current node path
 func (s *State) SetCurrentNode(path string) {
 	s.CurrentNodePath = path
@@ -67,6 +67,43 @@ func TestSaveState(t *testing.T) {
 	assert.NotEmpty(t, loadedState.Timestamp)
 }
 
+func TestSaveStateMergesConcurrentWriters(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	initial := &State{
+		Sessions: map[string]Session{"/old": {NodePath: "/old", Status: "running"}},
+		Fetches:  map[string]string{"/api": "2026-01-01T00:00:00Z"},
+	}
+	require.NoError(t, initial.SaveState(statePath))
+
+	// Two commands load the same state and change different parts of it
+	first, err := LoadState(statePath)
+	require.NoError(t, err)
+	second, err := LoadState(statePath)
+	require.NoError(t, err)
+
+	first.AddSession("/web", 42)
+	first.Fetches["/api"] = "2026-02-01T00:00:00Z"
+	require.NoError(t, first.SaveState(statePath))
+
+	second.RemoveSession("/old")
+	second.SetCurrentNode("/docs")
+	require.NoError(t, second.SaveState(statePath))
+
+	merged, err := LoadState(statePath)
+	require.NoError(t, err)
+	assert.Contains(t, merged.Sessions, "/web")
+	assert.NotContains(t, merged.Sessions, "/old")
+	assert.Equal(t, "2026-02-01T00:00:00Z", merged.Fetches["/api"])
+	assert.Equal(t, "/docs", merged.CurrentNodePath)
+
+	// Writes go through a rename; no temp files are left behind
+	entries, err := os.ReadDir(filepath.Dir(statePath))
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".tmp")
+	}
+}
+
 func TestSetGetCurrentNode(t *testing.T) {
 	state := &State{
 		Sessions: make(map[string]Session),
@@ -5,8 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	
-	"gopkg.in/yaml.v3"
 )
 
 // Fetch mode constants
@@ -19,12 +17,17 @@ const (
 // ConfigTree represents the tree-based configuration
 type ConfigTree struct {
 	Workspace     WorkspaceTree          `yaml:"workspace"`
+	Vars          map[string]string       `yaml:"vars,omitempty"`      // Variables for ${name} expansion
+	Include       []string                `yaml:"include,omitempty"`   // Fragments merged into this config
+	Templates     map[string]NodeTemplate `yaml:"templates,omitempty"` // Node templates by name
 	Nodes         []NodeDefinition       `yaml:"nodes"`  // Flat list of direct children only
 	Defaults      TreeDefaults           `yaml:"defaults,omitempty"` // Default settings for repositories
 	Overrides     map[string]interface{} `yaml:"overrides,omitempty"` // Workspace-level config overrides
+	Navigator     *NavigatorConfig       `yaml:"navigator,omitempty"` // Tree navigation and status cache settings
 	
 	// Runtime fields (not in YAML)
-	Path string `yaml:"-"`  // Path to this config file
+	Path string `yaml:"-"`  // Directory containing this config file
+	File string `yaml:"-"`  // Path to this config file; its extension selects the format
 }
 
 // TreeDefaults contains default settings for tree-based configuration
@@ -32,6 +35,21 @@ type TreeDefaults struct {
 	SSHPreference bool `yaml:"ssh_preference,omitempty"` // Default: true (prefer SSH over HTTPS for GitHub)
 }
 
+// NavigatorConfig configures tree navigation and status caching
+type NavigatorConfig struct {
+	Type            string               `yaml:"type,omitempty"`              // Navigator type: "filesystem" or "cached"
+	Cache           NavigatorCacheConfig `yaml:"cache,omitempty"`             // Status cache settings
+	LazyLoadTimeout string               `yaml:"lazy_load_timeout,omitempty"` // Timeout for cloning lazy nodes, e.g. "5m"
+	RefreshInterval string               `yaml:"refresh_interval,omitempty"`  // How often expired cache entries are swept, e.g. "5m"
+}
+
+// NavigatorCacheConfig configures the navigator status cache
+type NavigatorCacheConfig struct {
+	Enabled bool   `yaml:"enabled,omitempty"`  // Cache node status in memory and on disk
+	TTL     string `yaml:"ttl,omitempty"`      // How long cached status is considered fresh, e.g. "30s"
+	MaxSize int    `yaml:"max_size,omitempty"` // Maximum number of in-memory cache entries
+}
+
 // WorkspaceTree represents workspace configuration for v3
 type WorkspaceTree struct {
 	Name     string `yaml:"name"`
@@ -51,6 +69,9 @@ type NodeDefinition struct {
 	DefaultBranch string                 `yaml:"default_branch,omitempty"` // Node's default branch override
 	Overrides     map[string]interface{} `yaml:"overrides,omitempty"`     // Node-level config overrides
 	Metadata      map[string]string      `yaml:"metadata,omitempty"`       // Flexible metadata key-value pairs
+	DependsOn     []string               `yaml:"depends_on,omitempty"`     // Nodes this node depends on (names or tree paths)
+	Template      string                 `yaml:"template,omitempty"`       // Name of a template whose settings this node inherits
+	Generate      *GenerateSpec          `yaml:"generate,omitempty"`       // Source the node's children are generated from
 }
 
 // IsLazy determines if a node should be lazy based on its fetch mode
@@ -159,23 +180,29 @@ func DefaultConfigTree(projectName string) *ConfigTree {
 	}
 }
 
-// LoadTree reads a tree configuration from a YAML file
+// LoadTree reads a tree configuration from a YAML, JSON or TOML file
 func LoadTree(path string) (*ConfigTree, error) {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return nil, err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading config file: %w", err)
 	}
 
-	var cfg ConfigTree
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	// Includes, variables and templates are resolved before validation
+	decoded, err := DecodeTree(format, data, ConfigDir(path))
+	if err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
 	// Merge with defaults - project config overrides defaults
-	cfg = *MergeWithDefaults(&cfg)
+	cfg := *MergeWithDefaults(decoded)
 	
 	// Store the path
 	cfg.Path = filepath.Dir(path)
+	cfg.File = path
 	
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -190,13 +217,17 @@ func LoadTree(path string) (*ConfigTree, error) {
 // This is useful for path resolution where we need to distinguish between
 // "user explicitly set repos_dir to .nodes" vs "repos_dir not set (use parent directory directly)"
 func LoadTreeReposDir(path string) (string, error) {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return "", err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return "", fmt.Errorf("reading config file: %w", err)
 	}
 
-	var cfg ConfigTree
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	cfg, err := DecodeTree(format, data, ConfigDir(path))
+	if err != nil {
 		return "", fmt.Errorf("parsing config: %w", err)
 	}
 
@@ -204,15 +235,25 @@ func LoadTreeReposDir(path string) (string, error) {
 	return cfg.Workspace.ReposDir, nil
 }
 
-// Save writes a tree configuration to a YAML file
+// Save writes a tree configuration in the format given by the file extension.
+// An existing file is edited in place, keeping its comments, key order and
+// anchors; only the values that changed are rewritten.
 func (c *ConfigTree) Save(path string) error {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return err
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("creating config directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(c)
+	data, edited, err := editConfigFile(path, format, c)
+	if !edited && err == nil {
+		data, err = Marshal(format, c)
+	}
 	if err != nil {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
@@ -240,6 +281,19 @@ func (c *ConfigTree) Validate() error {
 		hasURL := node.URL != ""
 		hasFile := node.File != ""
 		
+		if node.Generate != nil {
+			if hasURL {
+				return fmt.Errorf("node %s cannot have both URL and generate fields", node.Name)
+			}
+			if hasFile && !IsGeneratedConfigPath(node.File, node.Name) {
+				return fmt.Errorf("node %s cannot have both file and generate fields", node.Name)
+			}
+			if err := node.Generate.Validate(); err != nil {
+				return fmt.Errorf("node %s: %w", node.Name, err)
+			}
+			hasFile = true
+		}
+		
 		if hasURL && hasFile {
 			return fmt.Errorf("node %s cannot have both URL and file fields", node.Name)
 		}
@@ -247,6 +301,12 @@ func (c *ConfigTree) Validate() error {
 		if !hasURL && !hasFile {
 			return fmt.Errorf("node %s must have either URL or file field", node.Name)
 		}
+		
+		if IsRemoteRef(node.File) {
+			if _, err := ParseRemoteRef(node.File); err != nil {
+				return fmt.Errorf("node %s: %w", node.Name, err)
+			}
+		}
 	}
 
 	return nil
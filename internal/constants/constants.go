@@ -26,6 +26,10 @@ var (
 		".muno.yaml",
 		"muno.yml",
 		".muno.yml",
+		"muno.json",
+		".muno.json",
+		"muno.toml",
+		".muno.toml",
 	}
 
 	// EagerLoadPatterns are repository name patterns that trigger eager loading
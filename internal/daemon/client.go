@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// dialTimeout bounds how long the CLI waits before falling back to direct mode
+const dialTimeout = 200 * time.Millisecond
+
+// Client is a navigator.TreeNavigator backed by a running daemon
+type Client struct {
+	rpc         *rpc.Client
+	currentPath string
+}
+
+var _ navigator.TreeNavigator = (*Client)(nil)
+
+// Connect connects to the daemon serving workspace. It fails quickly when no
+// daemon is running so callers can fall back to loading the workspace directly.
+func Connect(workspace string) (*Client, error) {
+	socket := SocketPath(workspace)
+	if err := checkSocket(socket); err != nil {
+		return nil, fmt.Errorf("daemon not running: %w", err)
+	}
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("daemon not running: %w", err)
+	}
+	return &Client{
+		rpc:         jsonrpc.NewClient(conn),
+		currentPath: "/",
+	}, nil
+}
+
+// Close closes the connection to the daemon
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
+
+// Ping returns information about the daemon
+func (c *Client) Ping() (*Info, error) {
+	var info Info
+	if err := c.rpc.Call("Daemon.Ping", Empty{}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Shutdown asks the daemon to stop
+func (c *Client) Shutdown() error {
+	err := c.rpc.Call("Daemon.Shutdown", Empty{}, &Empty{})
+	if errors.Is(err, rpc.ErrShutdown) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// The daemon closed the connection while stopping
+		return nil
+	}
+	return err
+}
+
+// GetCurrentPath returns the client's position in the tree
+func (c *Client) GetCurrentPath() (string, error) {
+	return c.currentPath, nil
+}
+
+// Navigate changes the client's position in the tree
+func (c *Client) Navigate(target string) error {
+	resolved := c.resolve(target)
+	node, err := c.GetNode(resolved)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("node not found: %s", resolved)
+	}
+	c.currentPath = resolved
+	return nil
+}
+
+// GetNode retrieves a single node by its path
+func (c *Client) GetNode(nodePath string) (*navigator.Node, error) {
+	var reply NodeReply
+	if err := c.rpc.Call("Navigator.GetNode", PathArgs{Path: c.resolve(nodePath)}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Node, nil
+}
+
+// ListChildren returns all direct children of a node
+func (c *Client) ListChildren(nodePath string) ([]*navigator.Node, error) {
+	var children []*navigator.Node
+	if err := c.rpc.Call("Navigator.ListChildren", PathArgs{Path: c.resolve(nodePath)}, &children); err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+// GetTree returns a tree view starting from path with specified depth
+func (c *Client) GetTree(nodePath string, depth int) (*navigator.TreeView, error) {
+	var view navigator.TreeView
+	if err := c.rpc.Call("Navigator.GetTree", TreeArgs{Path: c.resolve(nodePath), Depth: depth}, &view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// GetNodeStatus returns the current status of a node
+func (c *Client) GetNodeStatus(nodePath string) (*navigator.NodeStatus, error) {
+	var status navigator.NodeStatus
+	if err := c.rpc.Call("Navigator.GetNodeStatus", PathArgs{Path: c.resolve(nodePath)}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// RefreshStatus forces a status refresh for a node and its children
+func (c *Client) RefreshStatus(nodePath string) error {
+	return c.rpc.Call("Navigator.RefreshStatus", PathArgs{Path: c.resolve(nodePath)}, &Empty{})
+}
+
+// IsLazy checks if a node is configured for lazy loading
+func (c *Client) IsLazy(nodePath string) (bool, error) {
+	var lazy bool
+	if err := c.rpc.Call("Navigator.IsLazy", PathArgs{Path: c.resolve(nodePath)}, &lazy); err != nil {
+		return false, err
+	}
+	return lazy, nil
+}
+
+// TriggerLazyLoad clones a lazy node in the daemon
+func (c *Client) TriggerLazyLoad(nodePath string) error {
+	return c.rpc.Call("Navigator.TriggerLazyLoad", PathArgs{Path: c.resolve(nodePath)}, &Empty{})
+}
+
+// resolve turns a path relative to the client's position into an absolute tree path
+func (c *Client) resolve(target string) string {
+	if target == "" {
+		return c.currentPath
+	}
+	if !strings.HasPrefix(target, "/") {
+		target = path.Join(c.currentPath, target)
+	}
+	return path.Clean(target)
+}
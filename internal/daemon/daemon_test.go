@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// startTestServer serves an in-memory tree and returns the workspace and Serve's result
+func startTestServer(t *testing.T, nav navigator.TreeNavigator) (*Server, <-chan error) {
+	workspace := t.TempDir()
+	server := NewServer(workspace, nav)
+
+	done := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { done <- server.Serve(ctx) }()
+
+	require.Eventually(t, func() bool {
+		client, err := Connect(workspace)
+		if err != nil {
+			return false
+		}
+		client.Close()
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+	return server, done
+}
+
+func testTree() *navigator.InMemoryNavigator {
+	nav := navigator.NewInMemoryNavigator()
+	nav.AddNode("/backend", &navigator.Node{Path: "/backend", Name: "backend", Type: navigator.NodeTypeRepo, URL: "https://github.com/acme/backend.git"})
+	nav.AddNode("/backend/auth", &navigator.Node{Path: "/backend/auth", Name: "auth", Type: navigator.NodeTypeRepo})
+	nav.AddNode("/frontend", &navigator.Node{Path: "/frontend", Name: "frontend", Type: navigator.NodeTypeRepo})
+	nav.SetNodeStatus("/backend", &navigator.NodeStatus{Exists: true, Cloned: true, Branch: "main", Unstaged: 2})
+	return nav
+}
+
+func TestClientMirrorsNavigator(t *testing.T) {
+	server, _ := startTestServer(t, testTree())
+
+	client, err := Connect(server.workspace)
+	require.NoError(t, err)
+	defer client.Close()
+
+	node, err := client.GetNode("/backend")
+	require.NoError(t, err)
+	require.NotNil(t, node)
+	assert.Equal(t, "https://github.com/acme/backend.git", node.URL)
+
+	missing, err := client.GetNode("/nope")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	children, err := client.ListChildren("/")
+	require.NoError(t, err)
+	var names []string
+	for _, child := range children {
+		names = append(names, child.Name)
+	}
+	assert.ElementsMatch(t, []string{"backend", "frontend"}, names)
+
+	view, err := client.GetTree("/backend", -1)
+	require.NoError(t, err)
+	assert.Contains(t, view.Nodes, "/backend/auth")
+
+	status, err := client.GetNodeStatus("/backend")
+	require.NoError(t, err)
+	assert.Equal(t, "main", status.Branch)
+	assert.Equal(t, 2, status.Unstaged)
+
+	lazy, err := client.IsLazy("/frontend")
+	require.NoError(t, err)
+	assert.False(t, lazy)
+
+	assert.NoError(t, client.RefreshStatus("/backend"))
+
+	// Errors from the navigator reach the client
+	assert.ErrorContains(t, client.TriggerLazyLoad("/frontend"), "not configured for lazy loading")
+	_, err = client.ListChildren("/nope")
+	assert.Error(t, err)
+}
+
+func TestClientNavigate(t *testing.T) {
+	server, _ := startTestServer(t, testTree())
+
+	client, err := Connect(server.workspace)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Navigate("/backend"))
+	current, err := client.GetCurrentPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/backend", current)
+
+	// Relative paths resolve from the client's position
+	node, err := client.GetNode("auth")
+	require.NoError(t, err)
+	require.NotNil(t, node)
+	assert.Equal(t, "/backend/auth", node.Path)
+
+	require.NoError(t, client.Navigate("../frontend"))
+	current, _ = client.GetCurrentPath()
+	assert.Equal(t, "/frontend", current)
+
+	assert.Error(t, client.Navigate("/missing"))
+}
+
+func TestServerPingInvalidateAndShutdown(t *testing.T) {
+	base := testTree()
+	cached := navigator.NewCachedNavigator(base, time.Hour)
+	server, done := startTestServer(t, cached)
+
+	// A second daemon for the same workspace is refused
+	assert.ErrorContains(t, NewServer(server.workspace, cached).Serve(context.Background()), "already running")
+
+	client, err := Connect(server.workspace)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.GetNode("/backend")
+	require.NoError(t, err)
+	require.NoError(t, server.Invalidate("/backend"))
+
+	info, err := client.Ping()
+	require.NoError(t, err)
+	assert.Equal(t, server.workspace, info.Workspace)
+	assert.Equal(t, 1, info.Reloads)
+	require.NotNil(t, info.Cache)
+	assert.Equal(t, 0, info.Cache.Size)
+
+	require.NoError(t, client.Shutdown())
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("daemon did not stop")
+	}
+	assert.NoFileExists(t, server.Socket())
+
+	_, err = Connect(server.workspace)
+	assert.Error(t, err)
+}
+
+func TestSocketPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/work", ".muno", SocketFileName), SocketPath("/work"))
+
+	deep := "/" + strings.Repeat("nested/", 20) + "workspace"
+	socket := SocketPath(deep)
+	assert.LessOrEqual(t, len(socket), maxSocketPathLen)
+	assert.Equal(t, socket, SocketPath(deep))
+	assert.NotEqual(t, socket, SocketPath(deep+"2"))
+	assert.Equal(t, filepath.Join(os.TempDir(), fmt.Sprintf("muno-%d", os.Getuid())), filepath.Dir(socket))
+}
+
+func TestServerRestrictsSocket(t *testing.T) {
+	server, done := startTestServer(t, testTree())
+	info, err := os.Stat(server.Socket())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	client, err := Connect(server.workspace)
+	require.NoError(t, err)
+	require.NoError(t, client.Shutdown())
+	client.Close()
+	<-done
+
+	// Something other than a daemon socket at the path is never dialed
+	require.NoError(t, os.WriteFile(server.Socket(), []byte("spoof"), 0600))
+	_, err = Connect(server.workspace)
+	assert.ErrorContains(t, err, "is not a socket")
+}
+
+func TestPrepareSocketDir_PrivateFallback(t *testing.T) {
+	socket := SocketPath("/" + strings.Repeat("nested/", 20) + "workspace")
+	dir := filepath.Dir(socket)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		defer os.Remove(dir)
+	}
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	// A fallback directory others can write to is locked down
+	require.NoError(t, prepareSocketDir(socket))
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+}
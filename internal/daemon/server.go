@@ -0,0 +1,228 @@
+// Package daemon serves a workspace's tree navigator over a local Unix socket
+// so CLI invocations can reuse the loaded tree and status cache instead of
+// re-reading every config.
+package daemon
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// SocketFileName is the socket name below the workspace .muno directory
+const SocketFileName = "daemon.sock"
+
+// maxSocketPathLen keeps socket paths under the sun_path limit of every platform
+const maxSocketPathLen = 100
+
+// SocketPath returns the socket the daemon for workspace listens on. Workspaces
+// too deep for a Unix socket path use a hashed name in a directory of the
+// user's own below the temp directory.
+func SocketPath(workspace string) string {
+	socket := filepath.Join(workspace, ".muno", SocketFileName)
+	if len(socket) <= maxSocketPathLen {
+		return socket
+	}
+	sum := sha1.Sum([]byte(workspace))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("muno-%d", os.Getuid()), fmt.Sprintf("%x.sock", sum[:8]))
+}
+
+// prepareSocketDir creates the directory of socket. The shared temp directory
+// fallback must be private to the user, so another user cannot plant a socket
+// there or reach the daemon through it.
+func prepareSocketDir(socket string) error {
+	dir := filepath.Dir(socket)
+	if filepath.Dir(dir) != filepath.Clean(os.TempDir()) {
+		return os.MkdirAll(dir, 0755)
+	}
+	if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+		return err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() || !ownedByUser(info) {
+		return fmt.Errorf("%s is not a directory owned by the current user", dir)
+	}
+	return os.Chmod(dir, 0700)
+}
+
+// checkSocket refuses sockets another user created, which could impersonate
+// the daemon
+func checkSocket(socket string) error {
+	info, err := os.Lstat(socket)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s is not a socket", socket)
+	}
+	if !ownedByUser(info) {
+		return fmt.Errorf("%s is owned by another user", socket)
+	}
+	return nil
+}
+
+// Info describes a running daemon
+type Info struct {
+	PID       int
+	Workspace string
+	Socket    string
+	StartedAt time.Time
+	Reloads   int
+	Cache     *navigator.CacheStats
+}
+
+// Server holds a navigator in memory and serves it over RPC
+type Server struct {
+	workspace string
+	socket    string
+
+	mu        sync.RWMutex // Held for writing while the navigator reloads
+	nav       navigator.TreeNavigator
+	startedAt time.Time
+	reloads   int
+
+	cancel context.CancelFunc
+}
+
+// NewServer creates a daemon server for workspace serving nav
+func NewServer(workspace string, nav navigator.TreeNavigator) *Server {
+	return &Server{
+		workspace: workspace,
+		socket:    SocketPath(workspace),
+		nav:       nav,
+	}
+}
+
+// Socket returns the socket path the server listens on
+func (s *Server) Socket() string {
+	return s.socket
+}
+
+// Serve listens on the workspace socket until ctx is done or a client asks
+// the daemon to shut down. It fails if another daemon is already serving.
+func (s *Server) Serve(ctx context.Context) error {
+	if client, err := Connect(s.workspace); err == nil {
+		client.Close()
+		return fmt.Errorf("daemon already running on %s", s.socket)
+	}
+
+	// A socket left behind by a daemon that did not exit cleanly
+	if err := os.Remove(s.socket); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing stale socket: %w", err)
+	}
+	if err := prepareSocketDir(s.socket); err != nil {
+		return fmt.Errorf("creating socket directory: %w", err)
+	}
+
+	listener, err := net.Listen("unix", s.socket)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.socket, err)
+	}
+	defer os.Remove(s.socket)
+	// Only the user may talk to the daemon: it clones and shuts down on request
+	if err := os.Chmod(s.socket, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("restricting %s: %w", s.socket, err)
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("Navigator", &NavigatorService{server: s}); err != nil {
+		listener.Close()
+		return err
+	}
+	if err := server.RegisterName("Daemon", &DaemonService{server: s}); err != nil {
+		listener.Close()
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.mu.Lock()
+	s.cancel = cancel
+	s.startedAt = time.Now()
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("accepting connection: %w", err)
+		}
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// Invalidate reloads the navigator's config and drops cached entries below
+// treePath. It is called when a config file in the workspace changes.
+func (s *Server) Invalidate(treePath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reloads++
+	if reloader, ok := s.nav.(navigator.ConfigReloader); ok {
+		if err := reloader.ReloadConfig(); err != nil {
+			return err
+		}
+	}
+	if cached, ok := s.nav.(*navigator.CachedNavigator); ok {
+		cached.InvalidateSubtree(treePath)
+	}
+	return nil
+}
+
+// RefreshStatus drops the status served for treePath and its children, e.g.
+// after the daemon fetched remote refs
+func (s *Server) RefreshStatus(treePath string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.nav.RefreshStatus(treePath)
+}
+
+// info describes the server for Daemon.Ping
+func (s *Server) info() Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info := Info{
+		PID:       os.Getpid(),
+		Workspace: s.workspace,
+		Socket:    s.socket,
+		StartedAt: s.startedAt,
+		Reloads:   s.reloads,
+	}
+	if cached, ok := s.nav.(*navigator.CachedNavigator); ok {
+		stats := cached.GetCacheStats()
+		info.Cache = &stats
+	}
+	return info
+}
+
+// shutdown stops Serve
+func (s *Server) shutdown() {
+	s.mu.RLock()
+	cancel := s.cancel
+	s.mu.RUnlock()
+	if cancel != nil {
+		cancel()
+	}
+}
@@ -0,0 +1,126 @@
+package daemon
+
+import (
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// PathArgs addresses a node by tree path
+type PathArgs struct {
+	Path string
+}
+
+// TreeArgs requests a tree view below Path down to Depth levels
+type TreeArgs struct {
+	Path  string
+	Depth int
+}
+
+// NodeReply carries a node, which is nil when the path does not exist
+type NodeReply struct {
+	Node *navigator.Node
+}
+
+// Empty is used for calls without arguments or results
+type Empty struct{}
+
+// NavigatorService mirrors navigator.TreeNavigator over RPC. Navigation state
+// (Navigate, GetCurrentPath) belongs to each client and is not served.
+type NavigatorService struct {
+	server *Server
+}
+
+// GetNode retrieves a single node by its path
+func (s *NavigatorService) GetNode(args PathArgs, reply *NodeReply) error {
+	s.server.mu.RLock()
+	defer s.server.mu.RUnlock()
+
+	node, err := s.server.nav.GetNode(args.Path)
+	if err != nil {
+		return err
+	}
+	reply.Node = node
+	return nil
+}
+
+// ListChildren returns all direct children of a node
+func (s *NavigatorService) ListChildren(args PathArgs, reply *[]*navigator.Node) error {
+	s.server.mu.RLock()
+	defer s.server.mu.RUnlock()
+
+	children, err := s.server.nav.ListChildren(args.Path)
+	if err != nil {
+		return err
+	}
+	*reply = children
+	return nil
+}
+
+// GetTree returns a tree view starting from a path
+func (s *NavigatorService) GetTree(args TreeArgs, reply *navigator.TreeView) error {
+	s.server.mu.RLock()
+	defer s.server.mu.RUnlock()
+
+	view, err := s.server.nav.GetTree(args.Path, args.Depth)
+	if err != nil {
+		return err
+	}
+	*reply = *view
+	return nil
+}
+
+// GetNodeStatus returns the current status of a node
+func (s *NavigatorService) GetNodeStatus(args PathArgs, reply *navigator.NodeStatus) error {
+	s.server.mu.RLock()
+	defer s.server.mu.RUnlock()
+
+	status, err := s.server.nav.GetNodeStatus(args.Path)
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
+
+// RefreshStatus forces a status refresh for a node and its children
+func (s *NavigatorService) RefreshStatus(args PathArgs, reply *Empty) error {
+	s.server.mu.RLock()
+	defer s.server.mu.RUnlock()
+	return s.server.nav.RefreshStatus(args.Path)
+}
+
+// IsLazy checks if a node is configured for lazy loading
+func (s *NavigatorService) IsLazy(args PathArgs, reply *bool) error {
+	s.server.mu.RLock()
+	defer s.server.mu.RUnlock()
+
+	lazy, err := s.server.nav.IsLazy(args.Path)
+	if err != nil {
+		return err
+	}
+	*reply = lazy
+	return nil
+}
+
+// TriggerLazyLoad clones a lazy node
+func (s *NavigatorService) TriggerLazyLoad(args PathArgs, reply *Empty) error {
+	s.server.mu.RLock()
+	defer s.server.mu.RUnlock()
+	return s.server.nav.TriggerLazyLoad(args.Path)
+}
+
+// DaemonService controls the daemon itself
+type DaemonService struct {
+	server *Server
+}
+
+// Ping reports daemon information
+func (s *DaemonService) Ping(args Empty, reply *Info) error {
+	*reply = s.server.info()
+	return nil
+}
+
+// Shutdown stops the daemon after the reply is sent
+func (s *DaemonService) Shutdown(args Empty, reply *Empty) error {
+	go s.server.shutdown()
+	return nil
+}
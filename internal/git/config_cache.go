@@ -0,0 +1,280 @@
+package git
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ConfigCache keeps snapshots of the repositories that remote configs are
+// read from. Each repository is fetched once into a bare clone under
+// repos/, and each repository@ref is pinned to the commit it resolved to
+// and unpacked under <key>/tree, so configs load without network access
+// until the pin is refreshed.
+type ConfigCache struct {
+	dir    string
+	runner CommandRunner
+}
+
+// ConfigPin records the commit a repository@ref is pinned to
+type ConfigPin struct {
+	Repo    string    `json:"repo"`
+	Ref     string    `json:"ref,omitempty"`
+	Commit  string    `json:"commit"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// NewConfigCache creates a cache rooted at dir
+func NewConfigCache(dir string) *ConfigCache {
+	return &ConfigCache{dir: dir, runner: &DefaultCommandRunner{}}
+}
+
+// WithRunner makes the cache run git through runner
+func (c *ConfigCache) WithRunner(runner CommandRunner) *ConfigCache {
+	c.runner = runner
+	return c
+}
+
+// Snapshot returns the directory holding the repository at the pinned
+// commit of ref, fetching and pinning it on first use. An empty ref means
+// the default branch.
+func (c *ConfigCache) Snapshot(repo, ref string) (string, error) {
+	entry := c.entryDir(repo, ref)
+	tree := filepath.Join(entry, "tree")
+	pin, err := c.readPin(entry)
+	if err == nil {
+		if _, statErr := os.Stat(tree); statErr == nil {
+			return tree, nil
+		}
+		// The snapshot went missing; unpack the pinned commit again
+		bare, err := c.bareRepo(repo, false)
+		if err != nil {
+			return "", err
+		}
+		if _, err := c.runGit(bare, "cat-file", "-e", pin.Commit+"^{commit}"); err != nil {
+			if _, err := c.bareRepo(repo, true); err != nil {
+				return "", err
+			}
+		}
+		return tree, c.unpackCommit(bare, pin.Commit, tree)
+	}
+
+	bare, err := c.bareRepo(repo, false)
+	if err != nil {
+		return "", err
+	}
+	commit, err := c.resolveCommit(bare, ref)
+	if err != nil {
+		// The ref may be newer than an existing clone
+		if _, fetchErr := c.bareRepo(repo, true); fetchErr != nil {
+			return "", fetchErr
+		}
+		if commit, err = c.resolveCommit(bare, ref); err != nil {
+			return "", err
+		}
+	}
+	if err := c.unpackCommit(bare, commit, tree); err != nil {
+		return "", err
+	}
+	return tree, c.writePin(entry, ConfigPin{Repo: repo, Ref: ref, Commit: commit, Fetched: time.Now()})
+}
+
+// Refresh fetches the repository and moves the pin of ref to the commit it
+// names now. It returns the previously pinned commit, empty if there was
+// none, and the new one.
+func (c *ConfigCache) Refresh(repo, ref string) (from, to string, err error) {
+	entry := c.entryDir(repo, ref)
+	if pin, err := c.readPin(entry); err == nil {
+		from = pin.Commit
+	}
+	bare, err := c.bareRepo(repo, true)
+	if err != nil {
+		return from, "", err
+	}
+	to, err = c.resolveCommit(bare, ref)
+	if err != nil {
+		return from, "", err
+	}
+	tree := filepath.Join(entry, "tree")
+	if _, statErr := os.Stat(tree); to != from || statErr != nil {
+		if err := c.unpackCommit(bare, to, tree); err != nil {
+			return from, "", err
+		}
+	}
+	return from, to, c.writePin(entry, ConfigPin{Repo: repo, Ref: ref, Commit: to, Fetched: time.Now()})
+}
+
+// Pins lists the pinned references in the cache
+func (c *ConfigCache) Pins() ([]ConfigPin, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pins []ConfigPin
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == "repos" {
+			continue
+		}
+		if pin, err := c.readPin(filepath.Join(c.dir, e.Name())); err == nil {
+			pins = append(pins, pin)
+		}
+	}
+	return pins, nil
+}
+
+// entryDir returns the cache directory of a repository@ref
+func (c *ConfigCache) entryDir(repo, ref string) string {
+	return filepath.Join(c.dir, cacheKey(repo+"@"+ref))
+}
+
+// bareRepo returns the bare clone of repo, cloning it if needed and
+// fetching all branches and tags when fetch is set
+func (c *ConfigCache) bareRepo(repo string, fetch bool) (string, error) {
+	bare := filepath.Join(c.dir, "repos", cacheKey(repo)+".git")
+	if _, err := os.Stat(bare); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(bare), 0755); err != nil {
+			return "", err
+		}
+		if _, err := c.runGit("", "clone", "--bare", "--quiet", repo, bare); err != nil {
+			os.RemoveAll(bare)
+			return "", err
+		}
+		return bare, nil
+	}
+	if fetch {
+		if _, err := c.runGit(bare, "fetch", "--quiet", "--prune", "--force", "--tags", repo, "+refs/heads/*:refs/heads/*"); err != nil {
+			return "", err
+		}
+	}
+	return bare, nil
+}
+
+func (c *ConfigCache) readPin(entry string) (ConfigPin, error) {
+	var pin ConfigPin
+	data, err := os.ReadFile(filepath.Join(entry, "pin.json"))
+	if err != nil {
+		return pin, err
+	}
+	if err := json.Unmarshal(data, &pin); err != nil {
+		return pin, err
+	}
+	if pin.Commit == "" {
+		return pin, fmt.Errorf("pin without commit")
+	}
+	return pin, nil
+}
+
+func (c *ConfigCache) writePin(entry string, pin ConfigPin) error {
+	data, err := json.MarshalIndent(pin, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(entry, "pin.json"), append(data, '\n'), 0644)
+}
+
+// resolveCommit returns the commit a ref names in a bare repository
+func (c *ConfigCache) resolveCommit(bare, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, err := c.runGit(bare, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("ref %s not found", ref)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// unpackCommit replaces dir with the files of a commit
+func (c *ConfigCache) unpackCommit(bare, commit, dir string) error {
+	cmd := exec.Command("git", "--git-dir", bare, "archive", "--format=tar", commit)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	archive, err := c.runner.Output(cmd)
+	if err != nil {
+		return fmt.Errorf("git archive failed: %s\n%s", err, stderr.String())
+	}
+
+	tmp := dir + ".tmp"
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			os.RemoveAll(tmp)
+			return err
+		}
+		target := filepath.Join(tmp, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target, tmp+string(filepath.Separator)) {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeArchiveFile(target, tr, os.FileMode(hdr.Mode)&0777)
+		case tar.TypeSymlink:
+			err = os.Symlink(hdr.Linkname, target)
+		}
+		if err != nil {
+			os.RemoveAll(tmp)
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+func writeArchiveFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runGit runs git against a bare repository, or without one when bare is empty
+func (c *ConfigCache) runGit(bare string, args ...string) (string, error) {
+	command := args[0]
+	if bare != "" {
+		args = append([]string{"--git-dir", bare}, args...)
+	}
+	output, err := c.runner.CombinedOutput(exec.Command("git", args...))
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %s\n%s", command, err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// cacheKey returns a short stable directory name for a cache entry
+func cacheKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createConfigRepo creates a bare repository holding muno.yaml, tagged v2,
+// and a work tree to publish changes from
+func createConfigRepo(t *testing.T) (work, bare string) {
+	skipIfNoGit(t)
+	dir := t.TempDir()
+	work, bare = filepath.Join(dir, "work"), filepath.Join(dir, "platform.git")
+	runTestGit(t, dir, "init", "--quiet", "--bare", bare)
+	runTestGit(t, dir, "clone", "--quiet", bare, work)
+	commitConfig(t, work, "workspace:\n  name: platform\n")
+	runTestGit(t, work, "tag", "v2")
+	runTestGit(t, work, "push", "--quiet", "origin", "HEAD", "v2")
+	return work, bare
+}
+
+func commitConfig(t *testing.T, work, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(work, "muno.yaml"), []byte(content), 0644))
+	runTestGit(t, work, "add", "muno.yaml")
+	runTestGit(t, work, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "update config")
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestConfigCache_PinsUntilRefresh(t *testing.T) {
+	work, bare := createConfigRepo(t)
+	cache := NewConfigCache(filepath.Join(t.TempDir(), "configs"))
+
+	snapshot, err := cache.Snapshot("file://"+bare, "v2")
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(snapshot, "muno.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "workspace:\n  name: platform\n", string(data))
+
+	// Moving the tag upstream does not change the pinned snapshot
+	commitConfig(t, work, "workspace:\n  name: platform-v2\n")
+	runTestGit(t, work, "tag", "-f", "v2")
+	runTestGit(t, work, "push", "--quiet", "--force", "origin", "HEAD", "v2")
+	again, err := cache.Snapshot("file://"+bare, "v2")
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, again)
+	data, err = os.ReadFile(filepath.Join(again, "muno.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "workspace:\n  name: platform\n", string(data))
+
+	from, to, err := cache.Refresh("file://"+bare, "v2")
+	require.NoError(t, err)
+	assert.NotEqual(t, from, to)
+	data, err = os.ReadFile(filepath.Join(snapshot, "muno.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "workspace:\n  name: platform-v2\n", string(data))
+
+	pins, err := cache.Pins()
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+	assert.Equal(t, ConfigPin{Repo: "file://" + bare, Ref: "v2", Commit: to, Fetched: pins[0].Fetched}, pins[0])
+
+	from, current, err := cache.Refresh("file://"+bare, "v2")
+	require.NoError(t, err)
+	assert.Equal(t, to, from)
+	assert.Equal(t, to, current)
+}
+
+func TestConfigCache_DefaultBranchAndMissingRef(t *testing.T) {
+	_, bare := createConfigRepo(t)
+	cache := NewConfigCache(filepath.Join(t.TempDir(), "configs"))
+
+	snapshot, err := cache.Snapshot(bare, "")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(snapshot, "muno.yaml"))
+
+	_, err = cache.Snapshot(bare, "v9")
+	assert.EqualError(t, err, "ref v9 not found")
+
+	// A snapshot removed from the cache is unpacked again from the pin
+	require.NoError(t, os.RemoveAll(snapshot))
+	snapshot, err = cache.Snapshot(bare, "")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(snapshot, "muno.yaml"))
+}
+
+func TestConfigCache_RunsGitThroughRunner(t *testing.T) {
+	var commands [][]string
+	runner := &MockCommandRunner{CombinedOutputFunc: func(cmd *exec.Cmd) ([]byte, error) {
+		commands = append(commands, cmd.Args)
+		return []byte("fatal: repository not found"), errors.New("exit status 128")
+	}}
+	cache := NewConfigCache(filepath.Join(t.TempDir(), "configs")).WithRunner(runner)
+
+	_, err := cache.Snapshot("https://example.com/platform.git", "v2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git clone failed: exit status 128\nfatal: repository not found")
+	require.Len(t, commands, 1)
+	assert.Equal(t, []string{"git", "clone", "--bare", "--quiet", "https://example.com/platform.git"}, commands[0][:5])
+}
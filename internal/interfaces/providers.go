@@ -23,6 +23,14 @@ type ConfigEvent struct {
 	Error     error
 }
 
+// ConfigEvent types
+const (
+	ConfigEventCreated  = "created"
+	ConfigEventModified = "modified"
+	ConfigEventRemoved  = "removed"
+	ConfigEventError    = "error"
+)
+
 // GitProvider abstracts git operations
 type GitProvider interface {
 	Clone(url, path string, options CloneOptions) error
@@ -32,7 +40,19 @@ type GitProvider interface {
 	Commit(path string, message string, options CommitOptions) error
 	Branch(path string) (string, error)
 	Checkout(path string, branch string) error
+	CheckoutNew(path string, branch string) error
+	DeleteBranch(path string, branch string) error
 	Fetch(path string, options FetchOptions) error
+	ResetTo(path string, commit string, options ResetOptions) error
+	Stash(path string, message string) (bool, error)
+	StashPop(path string) error
+	DiffStat(path string, options DiffOptions) ([]DiffStat, error)
+	Log(path string, options LogOptions) ([]LogEntry, error)
+	RemoteBranch(path string, remote string, branch string) (string, error)
+	CreateBundle(path string, file string, options LogOptions) error
+	FetchBundle(path string, file string, branch string) error
+	FormatPatch(path string, dir string, options LogOptions) ([]string, error)
+	ApplyPatches(path string, files []string) error
 	Add(path string, files []string) error
 	Remove(path string, files []string) error
 	GetRemoteURL(path string) (string, error)
@@ -59,8 +79,13 @@ type PullOptions struct {
 // PushOptions for git push operations
 type PushOptions struct {
 	Force     bool
+	ForceWithLease bool // Refuse to overwrite remote commits we have not seen
+	Lease     string // With ForceWithLease, <ref>:<commit> the remote must still point to
 	SetUpstream bool
+	Remote    string // Remote to push to, origin when only Branch is set
 	Branch    string
+	Refspec   string // Pushed instead of Branch, e.g. ":refs/heads/topic" deletes topic
+	DryRun    bool
 	Quiet     bool
 }
 
@@ -79,6 +104,46 @@ type CommitOptions struct {
 	NoVerify  bool
 }
 
+// DiffOptions for git diff operations
+type DiffOptions struct {
+	Staged bool     // Compare the index with HEAD instead of the working tree
+	Paths  []string // Limit the diff to these paths
+}
+
+// DiffStat is the size of the change to one file
+type DiffStat struct {
+	Path       string
+	Insertions int
+	Deletions  int
+	Binary     bool
+}
+
+// LogOptions selects the commits listed by git log
+type LogOptions struct {
+	From string // Leave out commits reachable from this ref
+	To   string // List commits reachable from this ref, HEAD when empty
+}
+
+// LogEntry is one commit listed by git log
+type LogEntry struct {
+	SHA     string
+	Subject string
+}
+
+// ResetOptions for git reset operations
+type ResetOptions struct {
+	Mode      ResetMode
+}
+
+// ResetMode selects what git reset does to the index and working tree
+type ResetMode string
+
+const (
+	ResetMixed ResetMode = "mixed" // Keep working tree changes, unstaged
+	ResetSoft  ResetMode = "soft"  // Keep working tree changes, staged
+	ResetHard  ResetMode = "hard"  // Discard working tree changes
+)
+
 // GitStatus represents the status of a git repository
 type GitStatus struct {
 	Branch        string
@@ -92,6 +157,7 @@ type GitStatus struct {
 	Files         []GitFileStatus
 	Ahead         int
 	Behind        int
+	Upstream      string // Upstream branch such as origin/main, empty when none is set
 }
 
 // GitPullResult represents the result of a git pull operation
@@ -192,6 +258,8 @@ type NodeInfo struct {
 	IsLazy      bool
 	IsCloned    bool
 	HasChanges  bool
+	ConfigError     string // Why the node's config could not be loaded, if it failed
+	ConfigErrorFile string // The config file or reference that failed to load
 	Children    []NodeInfo
 	Parent      *NodeInfo
 }
@@ -229,10 +229,68 @@ func (g *gitProviderAdapter) Checkout(path string, branch string) error {
 	return g.git.Checkout(path, branch)
 }
 
+func (g *gitProviderAdapter) CheckoutNew(path string, branch string) error {
+	return g.git.CheckoutNew(path, branch)
+}
+
+func (g *gitProviderAdapter) DeleteBranch(path string, branch string) error {
+	return g.git.DeleteBranch(path, branch)
+}
+
+func (g *gitProviderAdapter) Log(path string, options interfaces.LogOptions) ([]interfaces.LogEntry, error) {
+	// GitInterface only logs from HEAD, so we'll return an error
+	return nil, fmt.Errorf("log range not implemented")
+}
+
+func (g *gitProviderAdapter) RemoteBranch(path string, remote string, branch string) (string, error) {
+	// GitInterface doesn't have ls-remote, so we'll return an error
+	return "", fmt.Errorf("remote branch lookup not implemented")
+}
+
+func (g *gitProviderAdapter) CreateBundle(path string, file string, options interfaces.LogOptions) error {
+	// GitInterface doesn't have bundles, so we'll return an error
+	return fmt.Errorf("bundle not implemented")
+}
+
+func (g *gitProviderAdapter) FetchBundle(path string, file string, branch string) error {
+	// GitInterface doesn't have bundles, so we'll return an error
+	return fmt.Errorf("bundle not implemented")
+}
+
+func (g *gitProviderAdapter) FormatPatch(path string, dir string, options interfaces.LogOptions) ([]string, error) {
+	// GitInterface doesn't have format-patch, so we'll return an error
+	return nil, fmt.Errorf("format-patch not implemented")
+}
+
+func (g *gitProviderAdapter) ApplyPatches(path string, files []string) error {
+	// GitInterface doesn't have am, so we'll return an error
+	return fmt.Errorf("apply patches not implemented")
+}
+
 func (g *gitProviderAdapter) Fetch(path string, options interfaces.FetchOptions) error {
 	return g.git.Fetch(path)
 }
 
+func (g *gitProviderAdapter) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	// GitInterface only resets to HEAD
+	return fmt.Errorf("reset to commit not implemented")
+}
+
+func (g *gitProviderAdapter) Stash(path string, message string) (bool, error) {
+	// GitInterface doesn't have Stash, so we'll return an error
+	return false, fmt.Errorf("stash not implemented")
+}
+
+func (g *gitProviderAdapter) StashPop(path string) error {
+	// GitInterface doesn't have StashPop, so we'll return an error
+	return fmt.Errorf("stash pop not implemented")
+}
+
+func (g *gitProviderAdapter) DiffStat(path string, options interfaces.DiffOptions) ([]interfaces.DiffStat, error) {
+	// GitInterface doesn't have a numstat diff, so we'll return an error
+	return nil, fmt.Errorf("diff stat not implemented")
+}
+
 func (g *gitProviderAdapter) Remove(path string, files []string) error {
 	// GitInterface doesn't have Remove, so we'll return an error
 	return fmt.Errorf("remove not implemented")
@@ -362,7 +420,7 @@ func (t *treeProviderAdapter) SetState(state interfaces.TreeState) error {
 func (t *treeProviderAdapter) nodeToNodeInfo(path string, node *tree.TreeNode) interfaces.NodeInfo {
 	// Check actual filesystem state for the node
 	fsPath := t.mgr.ComputeFilesystemPath(path)
-	actualState := tree.GetRepoState(fsPath)
+	actualState := t.mgr.RepoState(fsPath)
 	
 	info := interfaces.NodeInfo{
 		Name:       node.Name,
@@ -376,6 +434,10 @@ func (t *treeProviderAdapter) nodeToNodeInfo(path string, node *tree.TreeNode) i
 		Children:   []interfaces.NodeInfo{},
 		Parent:     nil,
 	}
+	if node.ConfigError != nil {
+		info.ConfigError = node.ConfigError.Message
+		info.ConfigErrorFile = node.ConfigError.File
+	}
 	
 	// Add children
 	for _, childName := range node.Children {
@@ -0,0 +1,417 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// Agent session terminal modes
+const (
+	AgentTerminalAuto       = "auto"
+	AgentTerminalTmux       = "tmux"
+	AgentTerminalBackground = "background"
+)
+
+// agentSessionsDir holds per-session context and log files under .muno
+const agentSessionsDir = "sessions"
+
+// lookPath finds executables; replaced in tests
+var lookPath = exec.LookPath
+
+// runInteractive runs a command attached to the current terminal; replaced in tests
+var runInteractive = func(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// AgentOptions controls how an agent session is started
+type AgentOptions struct {
+	Command  string // Command to launch (default: agent.command)
+	Terminal string // auto, tmux or background (default: agent.terminal)
+}
+
+// StartAgent launches the configured agent command in a node's directory and records the session
+func (m *Manager) StartAgent(path string, opts AgentOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	node, err := m.resolveAgentNode(path)
+	if err != nil {
+		return err
+	}
+	if node.Path != "/" && !node.IsCloned {
+		return fmt.Errorf("node %s is not cloned (run 'muno clone' first)", node.Path)
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	if existing, ok := state.Sessions[node.Path]; ok && processAlive(existing.PID) {
+		return fmt.Errorf("session already running for %s (pid %d); use 'muno agent attach' or 'muno agent stop'", node.Path, existing.PID)
+	}
+
+	command := opts.Command
+	if command == "" {
+		command = m.agentSetting("command", "claude")
+	}
+	terminal, err := m.resolveAgentTerminal(opts.Terminal)
+	if err != nil {
+		return err
+	}
+
+	nodeDir := m.computeFilesystemPath(node.Path)
+	slug := agentSessionSlug(node.Path)
+	sessionsDir := filepath.Join(m.workspace, ".muno", agentSessionsDir)
+	if err := m.fsProvider.MkdirAll(sessionsDir, 0755); err != nil {
+		return fmt.Errorf("creating sessions directory: %w", err)
+	}
+
+	graph := m.contextGraph()
+	content, err := m.renderNodeContext(node.Path, graph)
+	if err != nil {
+		return err
+	}
+	contextFile := filepath.Join(sessionsDir, slug+".context.md")
+	if err := m.fsProvider.WriteFile(contextFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing context file: %w", err)
+	}
+	if m.agentSetting("write_context_files", "true") == "true" {
+		if _, err := m.writeNodeContext(node, graph, DefaultContextFiles, false); err != nil {
+			m.logProvider.Warn(fmt.Sprintf("Failed to write context files for %s: %v", node.Path, err))
+		}
+	}
+
+	session := config.Session{
+		NodePath: node.Path,
+		Command:  command,
+		Terminal: terminal,
+	}
+	env := []string{
+		"MUNO_WORKSPACE=" + m.workspace,
+		"MUNO_NODE_PATH=" + node.Path,
+		"MUNO_CONTEXT_FILE=" + contextFile,
+	}
+
+	switch terminal {
+	case AgentTerminalTmux:
+		session.TmuxSession = "muno-" + slug
+		session.PID, err = m.startTmuxSession(session.TmuxSession, nodeDir, command, env)
+	default:
+		session.LogFile = filepath.Join(sessionsDir, slug+".log")
+		env = append(env, "MUNO_SESSION_LOG="+session.LogFile)
+		session.PID, err = m.startBackgroundSession(nodeDir, command, env)
+	}
+	if err != nil {
+		return fmt.Errorf("starting agent: %w", err)
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	session.Status = "running"
+	session.StartTime = now
+	session.LastActivity = now
+	state.Sessions[node.Path] = session
+	if err := m.saveState(state); err != nil {
+		return err
+	}
+
+	m.uiProvider.Success(fmt.Sprintf("🤖 Started agent for %s (pid %d)", node.Path, session.PID))
+	m.uiProvider.Info(fmt.Sprintf("   Command: %s", command))
+	m.uiProvider.Info(fmt.Sprintf("   Directory: %s", nodeDir))
+	if session.TmuxSession != "" {
+		m.uiProvider.Info(fmt.Sprintf("   tmux session: %s", session.TmuxSession))
+	} else {
+		m.uiProvider.Info(fmt.Sprintf("   Log: %s", session.LogFile))
+	}
+	m.uiProvider.Info(fmt.Sprintf("   Attach with: muno agent attach %s", node.Path))
+	m.metricsProvider.Counter("manager.agent_start", 1)
+
+	return nil
+}
+
+// startTmuxSession starts a detached tmux session and returns the PID of its pane
+func (m *Manager) startTmuxSession(name string, dir string, command string, env []string) (int, error) {
+	ctx := context.Background()
+	args := []string{"new-session", "-d", "-s", name, "-c", dir}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+	args = append(args, command)
+
+	result, err := m.processProvider.Execute(ctx, "tmux", args, interfaces.ProcessOptions{Silent: true})
+	if err != nil {
+		return 0, err
+	}
+	if result.ExitCode != 0 {
+		return 0, fmt.Errorf("tmux new-session failed: %s", strings.TrimSpace(result.Stderr))
+	}
+
+	result, err = m.processProvider.Execute(ctx, "tmux", []string{"display-message", "-p", "-t", name, "#{pane_pid}"}, interfaces.ProcessOptions{Silent: true})
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(result.Stdout))
+	if err != nil {
+		return 0, fmt.Errorf("reading tmux pane pid: %w", err)
+	}
+	return pid, nil
+}
+
+// startBackgroundSession starts the command detached from the terminal, logging to MUNO_SESSION_LOG
+func (m *Manager) startBackgroundSession(dir string, command string, env []string) (int, error) {
+	script := `trap '' HUP; exec ` + command + ` >>"$MUNO_SESSION_LOG" 2>&1 </dev/null`
+	proc, err := m.processProvider.StartBackground(context.Background(), "sh", []string{"-c", script}, interfaces.ProcessOptions{
+		WorkingDir: dir,
+		Env:        env,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return proc.Pid(), nil
+}
+
+// ListAgents shows recorded agent sessions and removes sessions whose process has exited
+func (m *Manager) ListAgents() error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	stale := pruneDeadSessions(state)
+	if len(stale) > 0 {
+		if err := m.saveState(state); err != nil {
+			return err
+		}
+	}
+
+	m.uiProvider.Info("🤖 Agent Sessions")
+	m.uiProvider.Info("─────────────────")
+	if len(state.Sessions) == 0 {
+		m.uiProvider.Info("No active sessions")
+	}
+	for _, path := range sortedSessionPaths(state) {
+		session := state.Sessions[path]
+		line := fmt.Sprintf("%s  pid %d  %s", path, session.PID, session.Terminal)
+		if session.Command != "" {
+			line += "  " + session.Command
+		}
+		if started, err := time.Parse(time.RFC3339, session.StartTime); err == nil {
+			line += fmt.Sprintf("  (up %s)", time.Since(started).Round(time.Second))
+		}
+		m.uiProvider.Info(line)
+	}
+	for _, path := range stale {
+		m.uiProvider.Warning(fmt.Sprintf("💀 %s exited; removed from state", path))
+	}
+
+	return nil
+}
+
+// AttachAgent attaches the terminal to a node's agent session
+func (m *Manager) AttachAgent(path string) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	session, err := m.liveAgentSession(path)
+	if err != nil {
+		return err
+	}
+
+	if session.TmuxSession != "" {
+		if os.Getenv("TMUX") != "" {
+			return runInteractive("tmux", "switch-client", "-t", session.TmuxSession)
+		}
+		return runInteractive("tmux", "attach-session", "-t", session.TmuxSession)
+	}
+
+	// Background sessions have no terminal; follow their output instead
+	m.uiProvider.Info(fmt.Sprintf("📜 Following %s (Ctrl-C to detach)", session.LogFile))
+	return runInteractive("tail", "-f", session.LogFile)
+}
+
+// StopAgent terminates a node's agent session and removes it from state
+func (m *Manager) StopAgent(path string) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	node, err := m.resolveAgentNode(path)
+	if err != nil {
+		return err
+	}
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	session, ok := state.Sessions[node.Path]
+	if !ok {
+		return fmt.Errorf("no agent session for %s", node.Path)
+	}
+
+	if session.TmuxSession != "" {
+		if _, err := m.processProvider.Execute(context.Background(), "tmux", []string{"kill-session", "-t", session.TmuxSession}, interfaces.ProcessOptions{Silent: true}); err != nil {
+			m.logProvider.Debug(fmt.Sprintf("tmux kill-session %s: %v", session.TmuxSession, err))
+		}
+	}
+	if processAlive(session.PID) {
+		if err := terminateProcess(session.PID); err != nil {
+			return fmt.Errorf("stopping pid %d: %w", session.PID, err)
+		}
+	}
+
+	state.RemoveSession(node.Path)
+	if err := m.saveState(state); err != nil {
+		return err
+	}
+
+	m.uiProvider.Success(fmt.Sprintf("🛑 Stopped agent for %s", node.Path))
+	m.metricsProvider.Counter("manager.agent_stop", 1)
+	return nil
+}
+
+// liveAgentSession returns the recorded session for path if its process is still running
+func (m *Manager) liveAgentSession(path string) (config.Session, error) {
+	node, err := m.resolveAgentNode(path)
+	if err != nil {
+		return config.Session{}, err
+	}
+	state, err := m.loadState()
+	if err != nil {
+		return config.Session{}, err
+	}
+	session, ok := state.Sessions[node.Path]
+	if !ok {
+		return config.Session{}, fmt.Errorf("no agent session for %s", node.Path)
+	}
+	if !processAlive(session.PID) {
+		state.RemoveSession(node.Path)
+		m.saveState(state)
+		return config.Session{}, fmt.Errorf("agent session for %s has exited", node.Path)
+	}
+	return session, nil
+}
+
+// activeAgentSessions returns live sessions keyed by node path, for display
+func (m *Manager) activeAgentSessions() map[string]config.Session {
+	if m.workspace == "" {
+		return nil
+	}
+	state, err := m.loadState()
+	if err != nil {
+		return nil
+	}
+	pruneDeadSessions(state)
+	return state.Sessions
+}
+
+// resolveAgentNode resolves path (or the current directory) to a tree node
+func (m *Manager) resolveAgentNode(path string) (interfaces.NodeInfo, error) {
+	targetPath := path
+	if targetPath == "" {
+		var err error
+		targetPath, err = m.getCurrentTreePath()
+		if err != nil {
+			return interfaces.NodeInfo{}, fmt.Errorf("resolving current tree path: %w", err)
+		}
+	}
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return interfaces.NodeInfo{}, fmt.Errorf("getting node: %w", err)
+	}
+	return node, nil
+}
+
+// resolveAgentTerminal picks the terminal mode, falling back to background when tmux is missing
+func (m *Manager) resolveAgentTerminal(requested string) (string, error) {
+	terminal := requested
+	if terminal == "" {
+		terminal = m.agentSetting("terminal", AgentTerminalAuto)
+	}
+
+	switch terminal {
+	case AgentTerminalAuto:
+		if _, err := lookPath("tmux"); err == nil {
+			return AgentTerminalTmux, nil
+		}
+		return AgentTerminalBackground, nil
+	case AgentTerminalTmux:
+		if _, err := lookPath("tmux"); err != nil {
+			return "", fmt.Errorf("tmux not found in PATH")
+		}
+		return terminal, nil
+	case AgentTerminalBackground:
+		return terminal, nil
+	default:
+		return "", fmt.Errorf("unsupported terminal: %s (use auto, tmux or background)", terminal)
+	}
+}
+
+// agentSetting reads agent.<key> from the config resolver
+func (m *Manager) agentSetting(key string, fallback string) string {
+	return m.setting("agent."+key, fallback)
+}
+
+// setting returns a dotted config value such as "git.fetch_interval" as a
+// string, or fallback when unset
+func (m *Manager) setting(key string, fallback string) string {
+	if m.configResolver == nil {
+		return fallback
+	}
+	value := m.configResolver.GetValue(key, nil)
+	if value == nil {
+		return fallback
+	}
+	if s := fmt.Sprint(value); s != "" {
+		return s
+	}
+	return fallback
+}
+
+// pruneDeadSessions removes sessions whose process is gone and returns their paths
+func pruneDeadSessions(state *config.State) []string {
+	var stale []string
+	for _, path := range sortedSessionPaths(state) {
+		if !processAlive(state.Sessions[path].PID) {
+			state.RemoveSession(path)
+			stale = append(stale, path)
+		}
+	}
+	return stale
+}
+
+// sortedSessionPaths returns session node paths in order
+func sortedSessionPaths(state *config.State) []string {
+	paths := make([]string, 0, len(state.Sessions))
+	for path := range state.Sessions {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// agentSessionSlug converts a tree path into a file and tmux-safe name
+func agentSessionSlug(path string) string {
+	slug := strings.Trim(path, "/")
+	if slug == "" {
+		return "root"
+	}
+	return strings.NewReplacer("/", "-", ".", "_", ":", "_").Replace(slug)
+}
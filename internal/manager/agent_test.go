@@ -0,0 +1,208 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+// stubAgentProcesses replaces process helpers with fakes tracking live PIDs
+func stubAgentProcesses(t *testing.T, tmuxAvailable bool) (map[int]bool, *[]string) {
+	alive := map[int]bool{}
+	var interactive []string
+
+	origAlive, origTerminate, origLookPath, origInteractive := processAlive, terminateProcess, lookPath, runInteractive
+	t.Cleanup(func() {
+		processAlive, terminateProcess, lookPath, runInteractive = origAlive, origTerminate, origLookPath, origInteractive
+	})
+
+	processAlive = func(pid int) bool { return alive[pid] }
+	terminateProcess = func(pid int) error {
+		delete(alive, pid)
+		return nil
+	}
+	lookPath = func(name string) (string, error) {
+		if tmuxAvailable {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+	runInteractive = func(name string, args ...string) error {
+		interactive = append(interactive, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	return alive, &interactive
+}
+
+func setupAgentWorkspace(t *testing.T) (*Manager, string, *mocks.MockProcessProvider, *mocks.MockUIProvider) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	proc := mocks.NewMockProcessProvider()
+	ui := mocks.NewMockUIProvider()
+	mgr.processProvider = proc
+	mgr.uiProvider = ui
+	return mgr, tmpDir, proc, ui
+}
+
+func TestStartAgent_Background(t *testing.T) {
+	alive, _ := stubAgentProcesses(t, false)
+	alive[12345] = true
+	mgr, tmpDir, proc, _ := setupAgentWorkspace(t)
+
+	require.NoError(t, mgr.StartAgent("/api", AgentOptions{Command: "codex"}))
+
+	assert.Contains(t, proc.GetCalls(), "StartBackground(sh)")
+	state, err := config.LoadState(filepath.Join(tmpDir, config.GetStateFileName()))
+	require.NoError(t, err)
+	session := state.Sessions["/api"]
+	assert.Equal(t, 12345, session.PID)
+	assert.Equal(t, "running", session.Status)
+	assert.Equal(t, "codex", session.Command)
+	assert.Equal(t, AgentTerminalBackground, session.Terminal)
+	assert.Equal(t, filepath.Join(tmpDir, ".muno", "sessions", "api.log"), session.LogFile)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".muno", "sessions", "api.context.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "- Current node: /api")
+	assert.FileExists(t, filepath.Join(tmpDir, ".nodes", "api", "CLAUDE.md"))
+
+	// A second start for the same node is refused while the process lives
+	assert.ErrorContains(t, mgr.StartAgent("/api", AgentOptions{}), "already running")
+}
+
+func TestStartAgent_Tmux(t *testing.T) {
+	alive, _ := stubAgentProcesses(t, true)
+	alive[4242] = true
+	mgr, tmpDir, proc, _ := setupAgentWorkspace(t)
+	proc.SetResult("tmux", &interfaces.ProcessResult{Stdout: "4242\n"})
+	mgr.configResolver.SetWorkspaceConfig(map[string]interface{}{
+		"agent": map[string]interface{}{"command": "aider", "write_context_files": false},
+	})
+
+	require.NoError(t, mgr.StartAgent("/web", AgentOptions{}))
+
+	state, err := mgr.loadState()
+	require.NoError(t, err)
+	session := state.Sessions["/web"]
+	assert.Equal(t, 4242, session.PID)
+	assert.Equal(t, "aider", session.Command)
+	assert.Equal(t, "muno-web", session.TmuxSession)
+	assert.NoFileExists(t, filepath.Join(tmpDir, ".nodes", "web", "CLAUDE.md"))
+}
+
+func TestStartAgent_Errors(t *testing.T) {
+	stubAgentProcesses(t, false)
+	mgr, _, _, _ := setupAgentWorkspace(t)
+
+	assert.ErrorContains(t, mgr.StartAgent("/docs", AgentOptions{}), "not cloned")
+	assert.ErrorContains(t, mgr.StartAgent("/api", AgentOptions{Terminal: "tmux"}), "tmux not found")
+	assert.ErrorContains(t, mgr.StartAgent("/api", AgentOptions{Terminal: "screen"}), "unsupported terminal")
+
+	uninitialized := &Manager{}
+	assert.Error(t, uninitialized.StartAgent("/api", AgentOptions{}))
+	assert.Error(t, uninitialized.ListAgents())
+	assert.Error(t, uninitialized.AttachAgent("/api"))
+	assert.Error(t, uninitialized.StopAgent("/api"))
+}
+
+func TestListAgents_PrunesDeadSessions(t *testing.T) {
+	alive, _ := stubAgentProcesses(t, false)
+	alive[100] = true
+	mgr, _, _, ui := setupAgentWorkspace(t)
+
+	state, err := mgr.loadState()
+	require.NoError(t, err)
+	state.Sessions["/api"] = config.Session{NodePath: "/api", PID: 100, Terminal: AgentTerminalBackground, Command: "claude"}
+	state.Sessions["/web"] = config.Session{NodePath: "/web", PID: 200, Terminal: AgentTerminalBackground}
+	require.NoError(t, mgr.saveState(state))
+
+	require.NoError(t, mgr.ListAgents())
+
+	messages := strings.Join(ui.GetMessages(), "\n")
+	assert.Contains(t, messages, "/api  pid 100  background  claude")
+	assert.Contains(t, messages, "/web exited")
+
+	state, err = mgr.loadState()
+	require.NoError(t, err)
+	assert.Len(t, state.Sessions, 1)
+	assert.Contains(t, state.Sessions, "/api")
+}
+
+func TestAttachAndStopAgent(t *testing.T) {
+	alive, interactive := stubAgentProcesses(t, true)
+	alive[300] = true
+	mgr, _, proc, _ := setupAgentWorkspace(t)
+	t.Setenv("TMUX", "")
+
+	state, err := mgr.loadState()
+	require.NoError(t, err)
+	state.Sessions["/api"] = config.Session{NodePath: "/api", PID: 300, Terminal: AgentTerminalTmux, TmuxSession: "muno-api"}
+	require.NoError(t, mgr.saveState(state))
+
+	require.NoError(t, mgr.AttachAgent("/api"))
+	assert.Equal(t, []string{"tmux attach-session -t muno-api"}, *interactive)
+
+	require.NoError(t, mgr.StopAgent("/api"))
+	assert.Contains(t, proc.GetCalls(), "Execute(tmux)")
+	assert.False(t, alive[300])
+
+	state, err = mgr.loadState()
+	require.NoError(t, err)
+	assert.Empty(t, state.Sessions)
+
+	assert.ErrorContains(t, mgr.StopAgent("/api"), "no agent session")
+	assert.ErrorContains(t, mgr.AttachAgent("/api"), "no agent session")
+}
+
+func TestAttachAgent_ExitedSession(t *testing.T) {
+	stubAgentProcesses(t, false)
+	mgr, _, _, _ := setupAgentWorkspace(t)
+
+	state, err := mgr.loadState()
+	require.NoError(t, err)
+	state.Sessions["/api"] = config.Session{NodePath: "/api", PID: 999, LogFile: "api.log"}
+	require.NoError(t, mgr.saveState(state))
+
+	assert.ErrorContains(t, mgr.AttachAgent("/api"), "has exited")
+	state, err = mgr.loadState()
+	require.NoError(t, err)
+	assert.Empty(t, state.Sessions)
+}
+
+func TestShowTree_AgentIndicator(t *testing.T) {
+	alive, _ := stubAgentProcesses(t, false)
+	alive[500] = true
+	mgr, _, _, ui := setupAgentWorkspace(t)
+
+	state, err := mgr.loadState()
+	require.NoError(t, err)
+	state.Sessions["/web"] = config.Session{NodePath: "/web", PID: 500}
+	require.NoError(t, mgr.saveState(state))
+
+	require.NoError(t, mgr.ShowTreeAtPath("/", 0))
+
+	var webLine, apiLine string
+	for _, msg := range ui.GetMessages() {
+		if strings.Contains(msg, "── web") {
+			webLine = msg
+		}
+		if strings.Contains(msg, "── api") {
+			apiLine = msg
+		}
+	}
+	assert.Contains(t, webLine, "🤖 agent")
+	assert.NotContains(t, apiLine, "🤖")
+}
+
+func TestAgentSessionSlug(t *testing.T) {
+	assert.Equal(t, "root", agentSessionSlug("/"))
+	assert.Equal(t, "api", agentSessionSlug("/api"))
+	assert.Equal(t, "team-api_v2", agentSessionSlug("/team/api.v2"))
+}
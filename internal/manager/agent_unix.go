@@ -0,0 +1,22 @@
+//go:build !windows
+
+package manager
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether a process with the given PID exists
+var processAlive = func(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// terminateProcess asks a process to exit
+var terminateProcess = func(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
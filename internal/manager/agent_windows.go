@@ -0,0 +1,28 @@
+//go:build windows
+
+package manager
+
+import "os"
+
+// processAlive reports whether a process with the given PID exists
+var processAlive = func(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// On Windows FindProcess opens a handle and fails if the process is gone
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
+
+// terminateProcess asks a process to exit
+var terminateProcess = func(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,459 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tree"
+)
+
+// ChangeStartOptions controls muno change start
+type ChangeStartOptions struct {
+	Paths  []string // Nodes whose repositories join the change, the current node when empty
+	Select bool     // Ask which of the repositories join the change
+}
+
+// ChangeLandOptions controls muno change land
+type ChangeLandOptions struct {
+	DryRun bool // Only check that every remote would accept the push
+}
+
+// landRepo is a repository taking part in landing a change
+type landRepo struct {
+	path    string
+	fsPath  string
+	remote  string
+	commits int
+	before  string // Commit the remote branch pointed to before landing, empty when it did not exist
+	pushed  string // Commit pushed to the remote branch, the lease for rolling it back
+}
+
+// StartChange creates the topic branch of a new change in the repositories
+// below the given nodes. When any repository fails, the branch is removed
+// again from the ones already switched to it.
+func (m *Manager) StartChange(name string, opts ChangeStartOptions) (err error) {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.journalOperation("change-start", "name", name)(&err)
+
+	if err := validateChangeName(name); err != nil {
+		return err
+	}
+	state, err := m.loadState()
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if existing, ok := state.Changes[name]; ok && existing.Landed == "" {
+		return fmt.Errorf("change %s already exists", name)
+	}
+
+	repos, err := m.changeRepos(opts.Paths)
+	if err != nil {
+		return err
+	}
+	if opts.Select && len(repos) > 0 {
+		labels := make([]string, len(repos))
+		byLabel := make(map[string]interfaces.NodeInfo)
+		for i, repo := range repos {
+			labels[i] = repo.Path
+			byLabel[repo.Path] = repo
+		}
+		chosen, err := m.uiProvider.MultiSelect(fmt.Sprintf("Select repositories for change %s", name), labels)
+		if err != nil {
+			return err
+		}
+		repos = repos[:0]
+		for _, label := range chosen {
+			repos = append(repos, byLabel[label])
+		}
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no cloned repositories to start change %s in", name)
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("🔀 Starting change %s in %d repositories", name, len(repos)))
+	m.uiProvider.Info("─────────────────")
+
+	change := config.Change{
+		Branch:  name,
+		Created: time.Now().UTC().Format(time.RFC3339),
+		Repos:   make(map[string]config.ChangeRepo),
+	}
+	var started []interfaces.NodeInfo
+	for _, repo := range repos {
+		fsPath := m.computeFilesystemPath(repo.Path)
+		base, err := m.startChangeRepo(fsPath, name)
+		if err != nil {
+			m.uiProvider.Error(fmt.Sprintf("❌ %s: %v", repo.Path, err))
+			m.rollbackChangeStart(change, started)
+			return fmt.Errorf("starting change %s in %s: %w", name, repo.Path, err)
+		}
+		change.Repos[repo.Path] = base
+		started = append(started, repo)
+		m.uiProvider.Info(fmt.Sprintf("📦 %s: %s → %s", repo.Path, base.BaseBranch, name))
+	}
+
+	if state.Changes == nil {
+		state.Changes = make(map[string]config.Change)
+	}
+	state.Changes[name] = change
+	state.CurrentChange = name
+	if err := m.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	m.uiProvider.Info("")
+	m.uiProvider.Success(fmt.Sprintf("✅ Change %s started. Commit in each repository, then run 'muno change land'", name))
+	return nil
+}
+
+// startChangeRepo switches one repository to the change branch and returns
+// where it branched off
+func (m *Manager) startChangeRepo(fsPath string, branch string) (config.ChangeRepo, error) {
+	status, err := m.gitProvider.Status(fsPath)
+	if err != nil {
+		return config.ChangeRepo{}, fmt.Errorf("reading status: %w", err)
+	}
+	if status.Branch == branch {
+		return config.ChangeRepo{}, fmt.Errorf("already on branch %s", branch)
+	}
+	base := config.ChangeRepo{BaseBranch: status.Branch, BaseCommit: tree.HeadCommit(fsPath)}
+
+	done := m.journalStep("checkout", fsPath)
+	err = m.gitProvider.CheckoutNew(fsPath, branch)
+	done(err)
+	return base, err
+}
+
+// rollbackChangeStart switches repositories back to their base branch, or
+// their base commit when it was detached, and deletes the change branch
+func (m *Manager) rollbackChangeStart(change config.Change, started []interfaces.NodeInfo) {
+	for i := len(started) - 1; i >= 0; i-- {
+		repo := started[i]
+		fsPath := m.computeFilesystemPath(repo.Path)
+		base := change.Repos[repo.Path]
+		// A detached base has no branch to return to, only its commit
+		target := base.BaseBranch
+		if (target == "" || target == "HEAD") && base.BaseCommit != "" {
+			target = base.BaseCommit
+		}
+		if err := m.gitProvider.Checkout(fsPath, target); err != nil {
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  Could not switch %s back to %s: %v", repo.Path, target, err))
+			continue
+		}
+		if err := m.gitProvider.DeleteBranch(fsPath, change.Branch); err != nil {
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  Could not delete branch %s in %s: %v", change.Branch, repo.Path, err))
+			continue
+		}
+		m.uiProvider.Info(fmt.Sprintf("↩️  %s: back on %s", repo.Path, target))
+	}
+}
+
+// changeRepos returns the cloned repositories below the given nodes
+func (m *Manager) changeRepos(paths []string) ([]interfaces.NodeInfo, error) {
+	if len(paths) == 0 {
+		current, err := m.getCurrentTreePath()
+		if err != nil {
+			return nil, fmt.Errorf("resolving current tree path: %w", err)
+		}
+		paths = []string{current}
+	}
+
+	var repos []interfaces.NodeInfo
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		node, err := m.treeProvider.GetNode(path)
+		if err != nil {
+			return nil, fmt.Errorf("getting node %s: %w", path, err)
+		}
+		for _, repo := range m.subtreeRepos(node) {
+			if !seen[repo.Path] {
+				seen[repo.Path] = true
+				repos = append(repos, repo)
+			}
+		}
+	}
+	return repos, nil
+}
+
+// ShowChange prints the commits and push state of a change in each of its
+// repositories. An empty name shows the current change.
+func (m *Manager) ShowChange(name string) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	_, name, change, err := m.loadChange(name)
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("🔀 Change %s (%d repositories)", name, len(change.Repos))
+	if change.Landed != "" {
+		header += fmt.Sprintf(" ✅ landed %s", change.Landed)
+	}
+	m.uiProvider.Info(header)
+	m.uiProvider.Info("─────────────────")
+
+	total := 0
+	for _, path := range changePaths(change) {
+		fsPath := m.computeFilesystemPath(path)
+		commits, err := m.gitProvider.Log(fsPath, interfaces.LogOptions{From: changeBase(change.Repos[path]), To: change.Branch})
+		if err != nil {
+			m.uiProvider.Error(fmt.Sprintf("📦 %s  ❌ %v", path, err))
+			continue
+		}
+		total += len(commits)
+
+		line := fmt.Sprintf("📦 %s  %s  %s", path, pluralize(len(commits), "commit"), m.changePushState(path, fsPath, change.Branch))
+		if status, err := m.gitProvider.Status(fsPath); err == nil && status.Branch != change.Branch {
+			line += fmt.Sprintf("  ⚠️  checked out: %s", status.Branch)
+		}
+		m.uiProvider.Info(line)
+		for _, commit := range commits {
+			m.uiProvider.Info(fmt.Sprintf("   %s %s", shortSHA(commit.SHA), commit.Subject))
+		}
+	}
+
+	m.uiProvider.Info("")
+	m.uiProvider.Info(fmt.Sprintf("📊 %s in %d repositories", pluralize(total, "commit"), len(change.Repos)))
+	return nil
+}
+
+// changePushState describes whether the change branch was pushed, using the
+// remote-tracking branch from the last fetch or push
+func (m *Manager) changePushState(path string, fsPath string, branch string) string {
+	remote := m.nodeSetting(path, "git.default_remote", "origin")
+	pending, err := m.gitProvider.Log(fsPath, interfaces.LogOptions{From: remote + "/" + branch, To: branch})
+	switch {
+	case err != nil:
+		return "○ not pushed"
+	case len(pending) == 0:
+		return "✓ pushed"
+	default:
+		return fmt.Sprintf("⬆️  %d to push", len(pending))
+	}
+}
+
+// LandChange pushes a change to every repository's remote, all or none. All
+// repositories are checked and every remote must accept a dry-run push
+// before anything is pushed; when a push still fails, the branches already
+// pushed are restored to where they were.
+func (m *Manager) LandChange(name string, opts ChangeLandOptions) (err error) {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.journalOperation("change-land", "name", name, "dry_run", opts.DryRun)(&err)
+
+	state, name, change, err := m.loadChange(name)
+	if err != nil {
+		return err
+	}
+	if change.Landed != "" {
+		return fmt.Errorf("change %s already landed", name)
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("🛬 Landing change %s", name))
+	m.uiProvider.Info("─────────────────")
+
+	repos, problems := m.landPreflight(change)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			m.uiProvider.Error(fmt.Sprintf("❌ %s", problem))
+		}
+		return fmt.Errorf("change %s cannot land: %d repositories failed pre-flight checks", name, len(problems))
+	}
+	if len(repos) == 0 {
+		m.uiProvider.Info(fmt.Sprintf("📭 Nothing to land: change %s has no commits", name))
+		return nil
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("🔍 Verifying %d remotes accept the push...", len(repos)))
+	rejected := 0
+	for _, repo := range repos {
+		if err := m.gitProvider.Push(repo.fsPath, interfaces.PushOptions{Remote: repo.remote, Branch: change.Branch, DryRun: true, Quiet: true}); err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ %s: %v", repo.path, err))
+			rejected++
+			continue
+		}
+		m.uiProvider.Info(fmt.Sprintf("   ✅ %s (%s)", repo.path, pluralize(repo.commits, "commit")))
+	}
+	if rejected > 0 {
+		return fmt.Errorf("change %s cannot land: %d remotes rejected the push", name, rejected)
+	}
+	if opts.DryRun {
+		m.uiProvider.Success(fmt.Sprintf("✅ All %d remotes would accept change %s", len(repos), name))
+		return nil
+	}
+
+	m.uiProvider.Info("")
+	var pushed []landRepo
+	for _, repo := range repos {
+		m.uiProvider.Info(fmt.Sprintf("📦 Pushing: %s", repo.path))
+		repo.pushed = tree.BranchCommit(repo.fsPath, change.Branch)
+		if err := m.gitProvider.Push(repo.fsPath, interfaces.PushOptions{Remote: repo.remote, Branch: change.Branch, SetUpstream: true}); err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", err))
+			m.logNodeFailure("change-land", repo.path, err)
+			restored := m.rollbackLand(change.Branch, pushed)
+			return fmt.Errorf("landing change %s failed at %s: %w (rolled back %d of %d pushed repositories)",
+				name, repo.path, err, restored, len(pushed))
+		}
+		m.uiProvider.Success("   ✅ Success")
+		pushed = append(pushed, repo)
+	}
+
+	change.Landed = time.Now().UTC().Format(time.RFC3339)
+	state.Changes[name] = change
+	if state.CurrentChange == name {
+		state.CurrentChange = ""
+	}
+	if err := m.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	m.metricsProvider.Counter("manager.change.land", int64(len(pushed)))
+	commits := 0
+	for _, repo := range pushed {
+		commits += repo.commits
+	}
+	m.uiProvider.Info("")
+	m.uiProvider.Info(fmt.Sprintf("📊 Landed change %s: %s in %d repositories", name, pluralize(commits, "commit"), len(pushed)))
+	return nil
+}
+
+// landPreflight lists the repositories with commits to land and the problems
+// that keep the change from landing
+func (m *Manager) landPreflight(change config.Change) ([]landRepo, []string) {
+	var repos []landRepo
+	var problems []string
+	for _, path := range changePaths(change) {
+		fsPath := m.computeFilesystemPath(path)
+		status, err := m.gitProvider.Status(fsPath)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: reading status: %v", path, err))
+			continue
+		}
+		if status.Branch != change.Branch {
+			problems = append(problems, fmt.Sprintf("%s: on branch %s, not %s", path, status.Branch, change.Branch))
+			continue
+		}
+		commits, err := m.gitProvider.Log(fsPath, interfaces.LogOptions{From: changeBase(change.Repos[path]), To: change.Branch})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: listing commits: %v", path, err))
+			continue
+		}
+		if len(commits) == 0 {
+			m.uiProvider.Info(fmt.Sprintf("⏭️  %s: no commits, skipped", path))
+			continue
+		}
+		if pattern, ok := m.protectedBranch(path, change.Branch); ok {
+			problems = append(problems, fmt.Sprintf("%s: branch %s is protected (git.protected_branches: %s)", path, change.Branch, pattern))
+			continue
+		}
+
+		remote := m.nodeSetting(path, "git.default_remote", "origin")
+		before, err := m.gitProvider.RemoteBranch(fsPath, remote, change.Branch)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: reading %s: %v", path, remote, err))
+			continue
+		}
+		repos = append(repos, landRepo{path: path, fsPath: fsPath, remote: remote, commits: len(commits), before: before})
+	}
+	return repos, problems
+}
+
+// rollbackLand restores the remote branch of each pushed repository, deleting
+// branches the push created, and returns how many were restored
+func (m *Manager) rollbackLand(branch string, pushed []landRepo) int {
+	if len(pushed) == 0 {
+		return 0
+	}
+	m.uiProvider.Warning(fmt.Sprintf("↩️  Rolling back %d pushed repositories", len(pushed)))
+	restored := 0
+	for i := len(pushed) - 1; i >= 0; i-- {
+		repo := pushed[i]
+		// The lease leaves the remote branch alone when someone pushed to it
+		// after us
+		options := interfaces.PushOptions{
+			Remote:         repo.remote,
+			Refspec:        repo.before + ":refs/heads/" + branch,
+			ForceWithLease: true,
+		}
+		if repo.pushed != "" {
+			options.Lease = "refs/heads/" + branch + ":" + repo.pushed
+		}
+		if err := m.gitProvider.Push(repo.fsPath, options); err != nil {
+			target := "delete it"
+			if repo.before != "" {
+				target = "reset it to " + repo.before
+			}
+			m.uiProvider.Error(fmt.Sprintf("   ❌ %s: %v. Restore %s/%s by hand: %s", repo.path, err, repo.remote, branch, target))
+			continue
+		}
+		m.uiProvider.Info(fmt.Sprintf("   ↩️  %s restored", repo.path))
+		restored++
+	}
+	return restored
+}
+
+// loadChange reads the named change, or the current one when name is empty
+func (m *Manager) loadChange(name string) (*config.State, string, config.Change, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return nil, "", config.Change{}, fmt.Errorf("loading state: %w", err)
+	}
+	if name == "" {
+		name = state.CurrentChange
+	}
+	if name == "" {
+		return nil, "", config.Change{}, fmt.Errorf("no current change; start one with 'muno change start <name>'")
+	}
+	change, ok := state.Changes[name]
+	if !ok {
+		return nil, "", config.Change{}, fmt.Errorf("change %s not found", name)
+	}
+	return state, name, change, nil
+}
+
+// validateChangeName rejects names git would not accept as a branch name
+func validateChangeName(name string) error {
+	if name == "" {
+		return fmt.Errorf("change name is required")
+	}
+	if strings.ContainsAny(name, " ~^:?*[\\") || strings.Contains(name, "..") || strings.Contains(name, "@{") ||
+		strings.HasPrefix(name, "-") || strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") ||
+		strings.HasSuffix(name, ".lock") {
+		return fmt.Errorf("invalid change name %q: it must be a valid branch name", name)
+	}
+	return nil
+}
+
+// changePaths returns the node paths of a change in order
+func changePaths(change config.Change) []string {
+	paths := make([]string, 0, len(change.Repos))
+	for path := range change.Repos {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// changeBase returns the ref a change branched off in a repository
+func changeBase(repo config.ChangeRepo) string {
+	if repo.BaseCommit != "" {
+		return repo.BaseCommit
+	}
+	return repo.BaseBranch
+}
+
+// pluralize renders "1 commit" or "3 commits"
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,428 @@
+package manager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tree"
+)
+
+// Change archive formats
+const (
+	ChangeFormatBundle = "bundle" // One git bundle per repository
+	ChangeFormatPatch  = "patch"  // A git format-patch series per repository
+)
+
+// changeManifestFile is the manifest inside a change archive
+const changeManifestFile = "manifest.json"
+
+// changeManifestVersion is the manifest version muno change export writes
+const changeManifestVersion = 1
+
+// ChangeExportOptions controls muno change export
+type ChangeExportOptions struct {
+	Output string // Archive path, <name>.muno-change.tar.gz when empty
+	Format string // bundle (default) or patch
+}
+
+// ChangeManifest describes the repositories in a change archive
+type ChangeManifest struct {
+	Version  int                  `json:"version"`
+	Name     string               `json:"name"`
+	Branch   string               `json:"branch"`
+	Format   string               `json:"format"`
+	Exported string               `json:"exported"`
+	Repos    []ChangeManifestRepo `json:"repos"`
+}
+
+// ChangeManifestRepo is one repository in a change archive
+type ChangeManifestRepo struct {
+	Path       string   `json:"path"` // Tree path of the node
+	BaseBranch string   `json:"base_branch"`
+	BaseCommit string   `json:"base_commit,omitempty"`
+	Head       string   `json:"head"`
+	Commits    int      `json:"commits"`
+	Files      []string `json:"files"` // Bundle or patches in the archive, in order
+}
+
+// ExportChange writes the commits of a change in every repository to a single
+// archive with a manifest of tree paths, base commits and branches, for
+// review on machines without access to the remotes. An empty name exports the
+// current change.
+func (m *Manager) ExportChange(name string, opts ChangeExportOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	format := opts.Format
+	if format == "" {
+		format = ChangeFormatBundle
+	}
+	if format != ChangeFormatBundle && format != ChangeFormatPatch {
+		return fmt.Errorf("unknown format %q (use %s or %s)", format, ChangeFormatBundle, ChangeFormatPatch)
+	}
+
+	_, name, change, err := m.loadChange(name)
+	if err != nil {
+		return err
+	}
+	output := opts.Output
+	if output == "" {
+		output = strings.ReplaceAll(name, "/", "-") + ".muno-change.tar.gz"
+	}
+
+	staging, err := os.MkdirTemp("", "muno-change-")
+	if err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+	if err := os.MkdirAll(filepath.Join(staging, "repos"), 0755); err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+
+	manifest := ChangeManifest{
+		Version:  changeManifestVersion,
+		Name:     name,
+		Branch:   change.Branch,
+		Format:   format,
+		Exported: time.Now().UTC().Format(time.RFC3339),
+	}
+	files := []string{changeManifestFile}
+	commits := 0
+	for _, treePath := range changePaths(change) {
+		repo := change.Repos[treePath]
+		fsPath := m.computeFilesystemPath(treePath)
+		rangeOpts := interfaces.LogOptions{From: changeBase(repo), To: change.Branch}
+		log, err := m.gitProvider.Log(fsPath, rangeOpts)
+		if err != nil {
+			return fmt.Errorf("listing commits in %s: %w", treePath, err)
+		}
+		if len(log) == 0 {
+			m.uiProvider.Info(fmt.Sprintf("⏭️  %s: no commits, skipped", treePath))
+			continue
+		}
+
+		entry := ChangeManifestRepo{
+			Path:       treePath,
+			BaseBranch: repo.BaseBranch,
+			BaseCommit: repo.BaseCommit,
+			Head:       log[0].SHA,
+			Commits:    len(log),
+		}
+		id := changeArchiveID(treePath)
+		if format == ChangeFormatBundle {
+			file := path.Join("repos", id+".bundle")
+			if err := m.gitProvider.CreateBundle(fsPath, filepath.Join(staging, filepath.FromSlash(file)), rangeOpts); err != nil {
+				return fmt.Errorf("bundling %s: %w", treePath, err)
+			}
+			entry.Files = []string{file}
+		} else {
+			dir := filepath.Join(staging, "repos", id)
+			patches, err := m.gitProvider.FormatPatch(fsPath, dir, rangeOpts)
+			if err != nil {
+				return fmt.Errorf("formatting patches for %s: %w", treePath, err)
+			}
+			for _, patch := range patches {
+				entry.Files = append(entry.Files, path.Join("repos", id, filepath.Base(patch)))
+			}
+		}
+		files = append(files, entry.Files...)
+		manifest.Repos = append(manifest.Repos, entry)
+		commits += len(log)
+		m.uiProvider.Info(fmt.Sprintf("📦 %s: %s (%s)", treePath, pluralize(len(log), "commit"), format))
+	}
+	if len(manifest.Repos) == 0 {
+		return fmt.Errorf("change %s has no commits to export", name)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(staging, changeManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	if err := writeChangeArchive(output, staging, files); err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	m.uiProvider.Success(fmt.Sprintf("✅ Exported change %s to %s (%s in %d repositories)",
+		name, output, pluralize(commits, "commit"), len(manifest.Repos)))
+	return nil
+}
+
+// ImportChange applies a change archive written by ExportChange to the nodes
+// at the tree paths in its manifest, checking out the change branch in each.
+// Every repository is checked before anything is applied, and repositories
+// already imported are switched back when one fails.
+func (m *Manager) ImportChange(archive string) (err error) {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.journalOperation("change-import", "archive", archive)(&err)
+
+	staging, err := os.MkdirTemp("", "muno-change-")
+	if err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := extractChangeArchive(archive, staging); err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+	manifest, err := readChangeManifest(filepath.Join(staging, changeManifestFile))
+	if err != nil {
+		return err
+	}
+	m.journalArg("name", manifest.Name)
+
+	state, err := m.loadState()
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if existing, ok := state.Changes[manifest.Name]; ok && existing.Landed == "" {
+		return fmt.Errorf("change %s already exists", manifest.Name)
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("📥 Importing change %s (%d repositories)", manifest.Name, len(manifest.Repos)))
+	m.uiProvider.Info("─────────────────")
+
+	previous, problems := m.importPreflight(manifest, staging)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			m.uiProvider.Error(fmt.Sprintf("❌ %s", problem))
+		}
+		return fmt.Errorf("cannot import change %s: %d repositories failed checks", manifest.Name, len(problems))
+	}
+
+	// Rolling back switches each repository back to the branch it was on
+	rollback := config.Change{Branch: manifest.Branch, Repos: make(map[string]config.ChangeRepo)}
+	change := config.Change{
+		Branch:  manifest.Branch,
+		Created: time.Now().UTC().Format(time.RFC3339),
+		Repos:   make(map[string]config.ChangeRepo),
+	}
+	var imported []interfaces.NodeInfo
+	for _, repo := range manifest.Repos {
+		node := interfaces.NodeInfo{Path: repo.Path}
+		fsPath := m.computeFilesystemPath(repo.Path)
+		rollback.Repos[repo.Path] = config.ChangeRepo{BaseBranch: previous[repo.Path], BaseCommit: tree.HeadCommit(fsPath)}
+
+		done := m.journalStep("checkout", fsPath)
+		created, err := m.importChangeRepo(fsPath, previous[repo.Path], manifest, repo, staging)
+		done(err)
+		if err != nil {
+			m.uiProvider.Error(fmt.Sprintf("❌ %s: %v", repo.Path, err))
+			if created {
+				imported = append(imported, node)
+			}
+			m.rollbackChangeStart(rollback, imported)
+			return fmt.Errorf("importing change %s into %s: %w", manifest.Name, repo.Path, err)
+		}
+		imported = append(imported, node)
+		change.Repos[repo.Path] = config.ChangeRepo{BaseBranch: repo.BaseBranch, BaseCommit: repo.BaseCommit}
+		m.uiProvider.Info(fmt.Sprintf("📦 %s: %s on %s", repo.Path, pluralize(repo.Commits, "commit"), manifest.Branch))
+	}
+
+	if state.Changes == nil {
+		state.Changes = make(map[string]config.Change)
+	}
+	state.Changes[manifest.Name] = change
+	state.CurrentChange = manifest.Name
+	if err := m.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	m.uiProvider.Info("")
+	m.uiProvider.Success(fmt.Sprintf("✅ Imported change %s. Review it with 'muno change status'", manifest.Name))
+	return nil
+}
+
+// importPreflight checks that every node in the manifest exists, is cloned
+// and has no uncommitted changes to tracked files, and returns the branch each
+// one is on
+func (m *Manager) importPreflight(manifest *ChangeManifest, staging string) (map[string]string, []string) {
+	previous := make(map[string]string)
+	var problems []string
+	for _, repo := range manifest.Repos {
+		node, err := m.treeProvider.GetNode(repo.Path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: node not found in this workspace", repo.Path))
+			continue
+		}
+		if !node.IsCloned {
+			problems = append(problems, fmt.Sprintf("%s: not cloned; run 'muno clone %s'", repo.Path, repo.Path))
+			continue
+		}
+		status, err := m.gitProvider.Status(m.computeFilesystemPath(repo.Path))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: reading status: %v", repo.Path, err))
+			continue
+		}
+		if status.HasStaged || status.HasModified {
+			problems = append(problems, fmt.Sprintf("%s: has uncommitted changes", repo.Path))
+			continue
+		}
+		for _, file := range repo.Files {
+			if _, err := os.Stat(filepath.Join(staging, filepath.FromSlash(file))); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s missing from archive", repo.Path, file))
+				break
+			}
+		}
+		previous[repo.Path] = status.Branch
+	}
+	return previous, problems
+}
+
+// importChangeRepo creates and checks out the change branch in one
+// repository, reporting whether the branch was created
+func (m *Manager) importChangeRepo(fsPath string, previous string, manifest *ChangeManifest, repo ChangeManifestRepo, staging string) (bool, error) {
+	files := make([]string, len(repo.Files))
+	for i, file := range repo.Files {
+		files[i] = filepath.Join(staging, filepath.FromSlash(file))
+	}
+
+	if manifest.Format == ChangeFormatPatch {
+		base := repo.BaseCommit
+		if base == "" {
+			base = repo.BaseBranch
+		}
+		if err := m.gitProvider.Checkout(fsPath, base); err != nil {
+			return false, fmt.Errorf("checking out base %s: %w", base, err)
+		}
+		if err := m.gitProvider.CheckoutNew(fsPath, manifest.Branch); err != nil {
+			m.gitProvider.Checkout(fsPath, previous)
+			return false, err
+		}
+		if err := m.gitProvider.ApplyPatches(fsPath, files); err != nil {
+			return true, fmt.Errorf("applying patches: %w", err)
+		}
+		return true, nil
+	}
+
+	if len(files) != 1 {
+		return false, fmt.Errorf("expected one bundle, found %d", len(files))
+	}
+	if err := m.gitProvider.FetchBundle(fsPath, files[0], manifest.Branch); err != nil {
+		return false, fmt.Errorf("fetching bundle: %w", err)
+	}
+	if err := m.gitProvider.Checkout(fsPath, manifest.Branch); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// readChangeManifest reads and checks the manifest of a change archive
+func readChangeManifest(file string) (*ChangeManifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("archive has no %s", changeManifestFile)
+	}
+	var manifest ChangeManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if manifest.Version > changeManifestVersion {
+		return nil, fmt.Errorf("archive version %d is newer than this muno supports (%d)", manifest.Version, changeManifestVersion)
+	}
+	if manifest.Format != ChangeFormatBundle && manifest.Format != ChangeFormatPatch {
+		return nil, fmt.Errorf("unknown archive format %q", manifest.Format)
+	}
+	if err := validateChangeName(manifest.Branch); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// changeArchiveID turns a tree path into a file name, e.g. /platform/auth
+// becomes platform__auth
+func changeArchiveID(treePath string) string {
+	return strings.ReplaceAll(strings.Trim(treePath, "/"), "/", "__")
+}
+
+// writeChangeArchive writes the given files below dir to a gzipped tar
+func writeChangeArchive(archive string, dir string, files []string) error {
+	out, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// extractChangeArchive unpacks the regular files of a gzipped tar into dir,
+// rejecting paths that would escape it
+func extractChangeArchive(archive string, dir string) error {
+	in, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("unsafe path %q in archive", header.Name)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, tr)
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
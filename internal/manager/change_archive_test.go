@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+func TestExportImportChange_Bundle(t *testing.T) {
+	mgr, apiPath, _, ui, git := setupChangeWorkspace(t)
+	git.SetLog(apiPath, "develop..login", []interfaces.LogEntry{
+		{SHA: "2222222222bbbb", Subject: "Validate tokens"},
+		{SHA: "1111111111aaaa", Subject: "Add login endpoint"},
+	})
+	archive := filepath.Join(t.TempDir(), "login.tar.gz")
+
+	require.NoError(t, mgr.ExportChange("", ChangeExportOptions{Output: archive}))
+	assert.Contains(t, git.GetCalls(), "CreateBundle("+apiPath+", develop..login)")
+	assert.Contains(t, ui.GetMessages(), "INFO: ⏭️  /web: no commits, skipped")
+
+	staging := t.TempDir()
+	require.NoError(t, extractChangeArchive(archive, staging))
+	manifest, err := readChangeManifest(filepath.Join(staging, changeManifestFile))
+	require.NoError(t, err)
+	assert.Equal(t, "login", manifest.Name)
+	assert.Equal(t, []ChangeManifestRepo{{
+		Path:       "/api",
+		BaseBranch: "develop",
+		Head:       "2222222222bbbb",
+		Commits:    2,
+		Files:      []string{"repos/api.bundle"},
+	}}, manifest.Repos)
+
+	// The reviewer's workspace maps the tree path to its own node directory
+	reviewer, reviewerDir, _, reviewerGit := setupCommitWorkspace(t)
+	reviewerAPI := filepath.Join(reviewerDir, ".nodes", "api")
+	require.NoError(t, reviewer.ImportChange(archive))
+	assert.Contains(t, reviewerGit.GetCalls(), "FetchBundle("+reviewerAPI+", api.bundle, login)")
+	assert.Contains(t, reviewerGit.GetCalls(), "Checkout("+reviewerAPI+", login)")
+
+	_, name, change, err := reviewer.loadChange("")
+	require.NoError(t, err)
+	assert.Equal(t, "login", name)
+	assert.Equal(t, "develop", change.Repos["/api"].BaseBranch)
+
+	assert.EqualError(t, reviewer.ImportChange(archive), "change login already exists")
+}
+
+func TestExportImportChange_Patch(t *testing.T) {
+	mgr, apiPath, _, _, git := setupChangeWorkspace(t)
+	git.SetLog(apiPath, "develop..login", []interfaces.LogEntry{{SHA: "1111111111aaaa", Subject: "Add login endpoint"}})
+	archive := filepath.Join(t.TempDir(), "login.tar.gz")
+	require.NoError(t, mgr.ExportChange("login", ChangeExportOptions{Output: archive, Format: ChangeFormatPatch}))
+
+	reviewer, reviewerDir, _, reviewerGit := setupCommitWorkspace(t)
+	reviewerAPI := filepath.Join(reviewerDir, ".nodes", "api")
+	require.NoError(t, reviewer.ImportChange(archive))
+	assert.Contains(t, reviewerGit.GetCalls(), "Checkout("+reviewerAPI+", develop)")
+	assert.Contains(t, reviewerGit.GetCalls(), "CheckoutNew("+reviewerAPI+", login)")
+	assert.Contains(t, reviewerGit.GetCalls(), "ApplyPatches("+reviewerAPI+", [0001-1111111111aaaa.patch])")
+
+	assert.EqualError(t, mgr.ExportChange("login", ChangeExportOptions{Format: "zip"}), `unknown format "zip" (use bundle or patch)`)
+}
+
+func TestImportChange_ChecksAndRollback(t *testing.T) {
+	mgr, apiPath, webPath, _, git := setupChangeWorkspace(t)
+	git.SetLog(apiPath, "develop..login", []interfaces.LogEntry{{SHA: "1111111111aaaa"}})
+	git.SetLog(webPath, "main..login", []interfaces.LogEntry{{SHA: "3333333333cccc"}})
+	archive := filepath.Join(t.TempDir(), "login.tar.gz")
+	require.NoError(t, mgr.ExportChange("login", ChangeExportOptions{Output: archive}))
+
+	reviewer, reviewerDir, ui, reviewerGit := setupCommitWorkspace(t)
+	reviewerAPI := filepath.Join(reviewerDir, ".nodes", "api")
+	reviewerWeb := filepath.Join(reviewerDir, ".nodes", "web")
+
+	// Nothing is applied while a worktree is dirty
+	reviewerGit.SetStatus(reviewerWeb, &interfaces.GitStatus{Branch: "main", HasModified: true})
+	err := reviewer.ImportChange(archive)
+	assert.EqualError(t, err, "cannot import change login: 1 repositories failed checks")
+	assert.Contains(t, ui.GetMessages(), "ERROR: ❌ /web: has uncommitted changes")
+	assert.NotContains(t, reviewerGit.GetCalls(), "FetchBundle("+reviewerAPI+", api.bundle, login)")
+
+	// A failing repository switches the imported ones back
+	reviewerGit.SetStatus(reviewerWeb, &interfaces.GitStatus{Branch: "main", IsClean: true})
+	reviewerGit.SetError("fetch-bundle", reviewerWeb, errors.New("missing prerequisite"))
+	err = reviewer.ImportChange(archive)
+	assert.EqualError(t, err, "importing change login into /web: fetching bundle: missing prerequisite")
+	assert.Contains(t, reviewerGit.GetCalls(), "Checkout("+reviewerAPI+", develop)")
+	assert.Contains(t, reviewerGit.GetCalls(), "DeleteBranch("+reviewerAPI+", login)")
+	assert.NotContains(t, reviewerGit.GetCalls(), "DeleteBranch("+reviewerWeb+", login)")
+}
+
+func TestExtractChangeArchive_RejectsUnsafePaths(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "evil.tar.gz")
+	out, err := os.Create(archive)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0644, Size: 1}))
+	_, err = tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, out.Close())
+
+	dir := t.TempDir()
+	assert.EqualError(t, extractChangeArchive(archive, dir), `unsafe path "../evil" in archive`)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "evil"))
+}
+
+func TestChangeArchiveID(t *testing.T) {
+	assert.Equal(t, "api", changeArchiveID("/api"))
+	assert.Equal(t, "platform__auth", changeArchiveID("/platform/auth"))
+}
@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func setupChangeWorkspace(t *testing.T) (*Manager, string, string, *mocks.MockUIProvider, *mocks.MockGitProvider) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", IsClean: true})
+	require.NoError(t, mgr.StartChange("login", ChangeStartOptions{Paths: []string{"/"}}))
+	ui.Reset()
+	return mgr, apiPath, webPath, ui, git
+}
+
+func pushCalls(git *mocks.MockGitProvider) []string {
+	var calls []string
+	for _, call := range git.GetCalls() {
+		if strings.HasPrefix(call, "Push(") {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func TestStartChange(t *testing.T) {
+	mgr, apiPath, webPath, _, git := setupChangeWorkspace(t)
+
+	assert.Contains(t, git.GetCalls(), "CheckoutNew("+apiPath+", login)")
+	assert.Contains(t, git.GetCalls(), "CheckoutNew("+webPath+", login)")
+
+	_, name, change, err := mgr.loadChange("")
+	require.NoError(t, err)
+	assert.Equal(t, "login", name)
+	assert.Equal(t, "develop", change.Repos["/api"].BaseBranch)
+	assert.Equal(t, "main", change.Repos["/web"].BaseBranch)
+
+	assert.EqualError(t, mgr.StartChange("login", ChangeStartOptions{Paths: []string{"/"}}), "change login already exists")
+	assert.EqualError(t, mgr.StartChange("bad name", ChangeStartOptions{}), `invalid change name "bad name": it must be a valid branch name`)
+}
+
+func TestStartChange_RollsBackOnFailure(t *testing.T) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.SetError("checkout", webPath, errors.New("uncommitted changes"))
+
+	err := mgr.StartChange("login", ChangeStartOptions{Paths: []string{"/api", "/web"}})
+	assert.EqualError(t, err, "starting change login in /web: uncommitted changes")
+	assert.Contains(t, git.GetCalls(), "Checkout("+apiPath+", develop)")
+	assert.Contains(t, git.GetCalls(), "DeleteBranch("+apiPath+", login)")
+	assert.Contains(t, ui.GetMessages(), "INFO: ↩️  /api: back on develop")
+
+	_, _, _, err = mgr.loadChange("login")
+	assert.EqualError(t, err, "change login not found")
+}
+
+func TestStartChange_RollsBackDetachedBase(t *testing.T) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	writeTestFile(t, filepath.Join(apiPath, ".git", "HEAD"), "1111111111aaaa\n")
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "HEAD", IsClean: true})
+	git.SetError("checkout", webPath, errors.New("uncommitted changes"))
+
+	assert.Error(t, mgr.StartChange("login", ChangeStartOptions{Paths: []string{"/api", "/web"}}))
+	assert.Contains(t, git.GetCalls(), "Checkout("+apiPath+", 1111111111aaaa)")
+	assert.NotContains(t, git.GetCalls(), "Checkout("+apiPath+", HEAD)")
+	assert.Contains(t, ui.GetMessages(), "INFO: ↩️  /api: back on 1111111111aaaa")
+}
+
+func TestShowChange(t *testing.T) {
+	mgr, apiPath, webPath, ui, git := setupChangeWorkspace(t)
+	git.SetLog(apiPath, "develop..login", []interfaces.LogEntry{
+		{SHA: "1111111111aaaa", Subject: "Add login endpoint"},
+		{SHA: "2222222222bbbb", Subject: "Validate tokens"},
+	})
+	git.SetLog(apiPath, "origin/login..login", []interfaces.LogEntry{{SHA: "1111111111aaaa"}})
+	git.SetError("log", webPath, errors.New("bad revision"))
+
+	require.NoError(t, mgr.ShowChange(""))
+	messages := ui.GetMessages()
+	assert.Contains(t, messages, "INFO: 🔀 Change login (2 repositories)")
+	assert.Contains(t, messages, "INFO: 📦 /api  2 commits  ⬆️  1 to push")
+	assert.Contains(t, messages, "INFO:    11111111 Add login endpoint")
+	assert.Contains(t, messages, "ERROR: 📦 /web  ❌ bad revision")
+	assert.Contains(t, messages, "INFO: 📊 2 commits in 2 repositories")
+}
+
+func TestLandChange(t *testing.T) {
+	mgr, apiPath, webPath, ui, git := setupChangeWorkspace(t)
+	git.SetLog(apiPath, "develop..login", []interfaces.LogEntry{{SHA: "1111111111aaaa", Subject: "Add login endpoint"}})
+
+	// Dry runs check the remotes without pushing
+	require.NoError(t, mgr.LandChange("", ChangeLandOptions{DryRun: true}))
+	assert.Equal(t, []string{"Push(" + apiPath + ", --dry-run)"}, pushCalls(git))
+
+	require.NoError(t, mgr.LandChange("", ChangeLandOptions{}))
+	assert.Equal(t, []string{"Push(" + apiPath + ", --dry-run)", "Push(" + apiPath + ", --dry-run)", "Push(" + apiPath + ")"}, pushCalls(git))
+	options, _ := git.GetPushOptions(apiPath)
+	assert.Equal(t, interfaces.PushOptions{Remote: "origin", Branch: "login", SetUpstream: true}, options)
+	assert.NotContains(t, git.GetCalls(), "Push("+webPath+")", "repositories without commits are skipped")
+	assert.Contains(t, ui.GetMessages(), "INFO: 📊 Landed change login: 1 commit in 1 repositories")
+
+	assert.EqualError(t, mgr.LandChange("login", ChangeLandOptions{}), "change login already landed")
+	assert.EqualError(t, mgr.LandChange("", ChangeLandOptions{}), "no current change; start one with 'muno change start <name>'")
+}
+
+func TestLandChange_RollsBackPushedRepos(t *testing.T) {
+	mgr, apiPath, webPath, ui, git := setupChangeWorkspace(t)
+	git.SetLog(apiPath, "develop..login", []interfaces.LogEntry{{SHA: "1111111111aaaa"}})
+	git.SetLog(webPath, "main..login", []interfaces.LogEntry{{SHA: "3333333333cccc"}})
+	git.SetRemoteBranch(apiPath, "0000000000ffff")
+	git.SetError("push", webPath, errors.New("pre-receive hook declined"))
+	writeTestFile(t, filepath.Join(apiPath, ".git", "refs", "heads", "login"), "1111111111aaaa\n")
+
+	err := mgr.LandChange("login", ChangeLandOptions{})
+	assert.EqualError(t, err, "landing change login failed at /web: pre-receive hook declined (rolled back 1 of 1 pushed repositories)")
+	assert.Contains(t, pushCalls(git), "Push("+apiPath+", 0000000000ffff:refs/heads/login)")
+
+	// The rollback only overwrites the commit it pushed
+	options, ok := git.GetPushOptions(apiPath)
+	require.True(t, ok)
+	assert.Equal(t, interfaces.PushOptions{
+		Remote:         "origin",
+		Refspec:        "0000000000ffff:refs/heads/login",
+		ForceWithLease: true,
+		Lease:          "refs/heads/login:1111111111aaaa",
+	}, options)
+	assert.Contains(t, ui.GetMessages(), "INFO:    ↩️  /api restored")
+
+	// The change can land again once the remote is fixed
+	_, _, change, err := mgr.loadChange("login")
+	require.NoError(t, err)
+	assert.Empty(t, change.Landed)
+}
+
+func TestLandChange_NothingPushedWhenChecksFail(t *testing.T) {
+	mgr, apiPath, webPath, _, git := setupChangeWorkspace(t)
+	git.SetLog(apiPath, "develop..login", []interfaces.LogEntry{{SHA: "1111111111aaaa"}})
+	git.SetLog(webPath, "main..login", []interfaces.LogEntry{{SHA: "3333333333cccc"}})
+	git.SetError("push-dry-run", webPath, errors.New("non-fast-forward"))
+
+	err := mgr.LandChange("login", ChangeLandOptions{})
+	assert.EqualError(t, err, "change login cannot land: 1 remotes rejected the push")
+	assert.NotContains(t, pushCalls(git), "Push("+apiPath+")")
+
+	// A repository switched away from the change branch fails pre-flight
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main"})
+	err = mgr.LandChange("login", ChangeLandOptions{})
+	assert.EqualError(t, err, "change login cannot land: 1 repositories failed pre-flight checks")
+}
+
+func TestValidateChangeName(t *testing.T) {
+	assert.NoError(t, validateChangeName("feature/login-v2"))
+	assert.EqualError(t, validateChangeName(""), "change name is required")
+	for _, name := range []string{"a..b", "-x", "x/", "a:b", "x.lock", "a@{1}"} {
+		assert.Error(t, validateChangeName(name), name)
+	}
+}
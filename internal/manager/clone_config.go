@@ -8,6 +8,7 @@ import (
 
 	"github.com/taokim/muno/internal/config"
 	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tree"
 )
 
 // visitNodeForClone is the unified function that visits and processes any node (config or git)
@@ -32,7 +33,12 @@ func (m *Manager) visitNodeForClone(node interfaces.NodeInfo, recursive bool, in
 			configFilePath = m.resolveConfigPath(node.ConfigFile, node.Path)
 		}
 		
-		targetConfigPath := filepath.Join(nodeFsPath, "muno.yaml")
+		// Keep the config's extension so its format is still detected
+		linkName := "muno.yaml"
+		if format, err := config.FormatForPath(configFilePath); err == nil && format != config.FormatYAML {
+			linkName = "muno." + format
+		}
+		targetConfigPath := filepath.Join(nodeFsPath, linkName)
 		
 		// Remove existing file/symlink if it exists (important for correcting wrong symlinks from git)
 		if _, err := os.Lstat(targetConfigPath); err == nil {
@@ -55,7 +61,7 @@ func (m *Manager) visitNodeForClone(node interfaces.NodeInfo, recursive bool, in
 			return nil
 		}
 		
-		m.logProvider.Info(fmt.Sprintf("Cloning repository %s from %s", node.Name, node.Repository))
+		m.uiProvider.Info(fmt.Sprintf("Cloning repository %s from %s", node.Name, node.Repository))
 		cloneOptions := interfaces.CloneOptions{
 			SSHPreference: m.getSSHPreference(),
 		}
@@ -78,7 +84,7 @@ func (m *Manager) visitNodeForClone(node interfaces.NodeInfo, recursive bool, in
 	}
 	
 	// Step 4: Load muno.yaml from the directory (if it exists)
-	munoYamlPath := filepath.Join(nodeFsPath, "muno.yaml")
+	munoYamlPath := config.ConfigFilePath(nodeFsPath)
 	if _, err := os.Stat(munoYamlPath); err != nil {
 		// No muno.yaml, nothing more to do
 		return nil
@@ -112,7 +118,7 @@ func (m *Manager) visitNodeForClone(node interfaces.NodeInfo, recursive bool, in
 		} else if nodeDef.File != "" {
 			// Config reference node
 			childConfigFile := nodeDef.File
-			if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") {
+			if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") && !config.IsRemoteRef(childConfigFile) {
 				// Resolve relative to the real path of the current config
 				realConfigPath, err := filepath.EvalSymlinks(munoYamlPath)
 				if err != nil {
@@ -193,7 +199,7 @@ func (m *Manager) processConfigNode(node interfaces.NodeInfo, recursive bool, in
 // but kept for backward compatibility
 func (m *Manager) cloneConfigNodeRecursive(dirPath string, nodePath string, includeLazy bool, toClone *[]interfaces.NodeInfo) error {
 	// Load the muno.yaml from the directory
-	munoYamlPath := filepath.Join(dirPath, "muno.yaml")
+	munoYamlPath := config.ConfigFilePath(dirPath)
 	cfg, err := config.LoadTree(munoYamlPath)
 	if err != nil {
 		m.logProvider.Warn(fmt.Sprintf("Failed to load config from %s: %v", munoYamlPath, err))
@@ -219,7 +225,7 @@ func (m *Manager) cloneConfigNodeRecursive(dirPath string, nodePath string, incl
 			}
 		} else if nodeDef.File != "" {
 			childConfigFile := nodeDef.File
-			if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") {
+			if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") && !config.IsRemoteRef(childConfigFile) {
 				realConfigPath, err := filepath.EvalSymlinks(munoYamlPath)
 				if err != nil {
 					realConfigPath = munoYamlPath
@@ -255,6 +261,16 @@ func (m *Manager) resolveConfigPath(configFile string, nodePath string) string {
 		return configFile
 	}
 
+	// Remote git references resolve to their snapshot in the workspace cache
+	if config.IsRemoteRef(configFile) {
+		cached, err := tree.ResolveRemoteConfig(m.workspace, configFile)
+		if err != nil {
+			m.logProvider.Warn(fmt.Sprintf("Failed to fetch remote config %s: %v", configFile, err))
+			return configFile
+		}
+		return cached
+	}
+
 	// Check if it's a URL (remote config)
 	if strings.HasPrefix(configFile, "http://") || strings.HasPrefix(configFile, "https://") {
 		// TODO: Handle remote config files
@@ -274,7 +290,7 @@ func (m *Manager) resolveConfigPath(configFile string, nodePath string) string {
 	parentFsPath := m.computeFilesystemPath(parentPath)
 	
 	// Check if there's a muno.yaml in the parent directory
-	parentConfigPath := filepath.Join(parentFsPath, "muno.yaml")
+	parentConfigPath := config.ConfigFilePath(parentFsPath)
 	if _, err := os.Stat(parentConfigPath); err == nil {
 		// Resolve relative to the parent config file's directory
 		parentConfigDir := filepath.Dir(parentConfigPath)
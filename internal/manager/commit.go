@@ -0,0 +1,350 @@
+package manager
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// ChangeIDTrailer is the commit trailer linking commits made together in
+// several repositories
+const ChangeIDTrailer = "Muno-Change-Id"
+
+// CommitOptions controls muno commit
+type CommitOptions struct {
+	Message   string
+	Recursive bool     // Commit every changed repository below the node
+	All       bool     // Stage modified and deleted tracked files (git commit -a)
+	Amend     bool     // Amend the last commit of each repository
+	NoVerify  bool     // Skip pre-commit and commit-msg hooks
+	Paths     []string // Stage changes matching these pathspecs, relative to each repository
+	Select    bool     // Ask which of the changed repositories to commit
+	ChangeID  string   // Add a Muno-Change-Id trailer with this id to every commit
+}
+
+// commitCandidate is a repository with changes to commit
+type commitCandidate struct {
+	node  interfaces.NodeInfo
+	files []string // Files matching the pathspecs, staged before committing
+	stats []interfaces.DiffStat
+}
+
+// NewChangeID returns a new id for the Muno-Change-Id trailer
+func NewChangeID() (string, error) {
+	id := make([]byte, 10)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("generating change id: %w", err)
+	}
+	return "I" + hex.EncodeToString(id), nil
+}
+
+// CommitNode commits changes at a node, or in every changed repository below
+// it when recursive
+func (m *Manager) CommitNode(path string, message string, recursive bool) error {
+	return m.CommitNodeWithOptions(path, CommitOptions{Message: message, Recursive: recursive})
+}
+
+// CommitNodeWithOptions commits changes at a node. Recursively, only the
+// repositories with something to commit are committed: staged changes, or
+// with All or Paths the changes that would be staged. With Amend and Select
+// the user may also pick repositories with nothing staged, to reword their
+// last commit. Their combined diffstat is shown first, and with Select the
+// user picks which ones to commit. A last commit already on the upstream is
+// never amended.
+func (m *Manager) CommitNodeWithOptions(path string, opts CommitOptions) (err error) {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.journalOperation("commit", "path", path, "message", opts.Message, "recursive", opts.Recursive)(&err)
+
+	if opts.All && len(opts.Paths) > 0 {
+		return fmt.Errorf("--all cannot be combined with paths")
+	}
+	if opts.Message == "" && !opts.Amend {
+		return fmt.Errorf("commit message is required")
+	}
+	if opts.Message == "" && opts.ChangeID != "" {
+		return fmt.Errorf("a change id needs a commit message")
+	}
+
+	targetPath := path
+	if targetPath == "" {
+		var err error
+		targetPath, err = m.getCurrentTreePath()
+		if err != nil {
+			return fmt.Errorf("resolving current tree path: %w", err)
+		}
+	}
+
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return fmt.Errorf("getting node: %w", err)
+	}
+
+	if opts.Recursive {
+		return m.commitRecursive(node, opts)
+	}
+
+	// Single node commit
+	fullPath := m.computeFilesystemPath(node.Path)
+	m.uiProvider.Info(fmt.Sprintf("Committing changes: %s", opts.Message))
+	m.uiProvider.Info(fmt.Sprintf("  Tree path: %s", node.Path))
+	m.uiProvider.Info(fmt.Sprintf("  Directory: %s", fullPath))
+	if opts.Amend {
+		status, err := m.gitProvider.Status(fullPath)
+		if err != nil {
+			return fmt.Errorf("getting status: %w", err)
+		}
+		if amendPushed(status) {
+			return fmt.Errorf("refusing to amend: the last commit of %s is already pushed", node.Path)
+		}
+	}
+	return m.commitRepo(fullPath, opts.Paths, opts)
+}
+
+// commitRecursive commits the changed repositories in the node's subtree
+func (m *Manager) commitRecursive(node interfaces.NodeInfo, opts CommitOptions) error {
+	repos := m.subtreeRepos(node)
+	candidates := []commitCandidate{}
+	for _, repo := range repos {
+		fullPath := m.computeFilesystemPath(repo.Path)
+		status, err := m.gitProvider.Status(fullPath)
+		if err != nil {
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  Skipping %s: %v", repo.Path, err))
+			continue
+		}
+		files, ok := commitChanges(status, opts)
+		if !ok && !(opts.Amend && opts.Select) {
+			continue
+		}
+		if opts.Amend && amendPushed(status) {
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  Skipping %s: the last commit is already pushed", repo.Path))
+			continue
+		}
+
+		candidate := commitCandidate{node: repo, files: files}
+		diffOpts := interfaces.DiffOptions{Staged: !opts.All && len(opts.Paths) == 0, Paths: files}
+		if stats, err := m.gitProvider.DiffStat(fullPath, diffOpts); err == nil {
+			candidate.stats = stats
+		}
+		candidate.stats = append(candidate.stats, untrackedStats(status, files, candidate.stats)...)
+		candidates = append(candidates, candidate)
+	}
+
+	if len(candidates) == 0 {
+		m.uiProvider.Info(fmt.Sprintf("📭 Nothing to commit in %d repositories", len(repos)))
+		return nil
+	}
+
+	m.uiProvider.Info("📝 Changes to commit")
+	m.uiProvider.Info("─────────────────")
+	m.showCommitDiffstat(candidates)
+
+	if opts.Select {
+		labels := make([]string, len(candidates))
+		byLabel := make(map[string]commitCandidate)
+		for i, candidate := range candidates {
+			labels[i] = fmt.Sprintf("%s (%s)", candidate.node.Path, diffSummary(candidate.stats))
+			byLabel[labels[i]] = candidate
+		}
+		chosen, err := m.uiProvider.MultiSelect("Select repositories to commit", labels)
+		if err != nil {
+			return err
+		}
+		candidates = candidates[:0]
+		for _, label := range chosen {
+			if candidate, ok := byLabel[label]; ok {
+				candidates = append(candidates, candidate)
+			}
+		}
+		if len(candidates) == 0 {
+			m.uiProvider.Info("Commit cancelled")
+			return nil
+		}
+	}
+
+	if opts.ChangeID != "" {
+		m.uiProvider.Info(fmt.Sprintf("🔗 %s: %s", ChangeIDTrailer, opts.ChangeID))
+	}
+	m.uiProvider.Info("")
+
+	successCount := 0
+	failedRepos := []string{}
+	for _, candidate := range candidates {
+		m.uiProvider.Info(fmt.Sprintf("📦 Committing: %s", candidate.node.Path))
+		if err := m.commitRepo(m.computeFilesystemPath(candidate.node.Path), candidate.files, opts); err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", err))
+			m.logNodeFailure("commit", candidate.node.Path, err)
+			failedRepos = append(failedRepos, candidate.node.Path)
+			continue
+		}
+		m.uiProvider.Success("   ✅ Success")
+		successCount++
+	}
+
+	m.metricsProvider.Counter("manager.commit", int64(successCount))
+	m.uiProvider.Info("")
+	m.uiProvider.Info(fmt.Sprintf("📊 Results: %d succeeded, %d failed", successCount, len(failedRepos)))
+	if len(failedRepos) > 0 {
+		return fmt.Errorf("commit failed for %d repositories", len(failedRepos))
+	}
+	return nil
+}
+
+// commitRepo stages files, if any, and commits in one repository
+func (m *Manager) commitRepo(fullPath string, files []string, opts CommitOptions) error {
+	if len(files) > 0 {
+		if err := m.gitProvider.Add(fullPath, files); err != nil {
+			return fmt.Errorf("staging changes: %w", err)
+		}
+	}
+	return m.gitProvider.Commit(fullPath, commitMessage(opts), interfaces.CommitOptions{
+		All:      opts.All,
+		Amend:    opts.Amend,
+		NoVerify: opts.NoVerify,
+	})
+}
+
+// subtreeRepos returns the cloned repositories in the node's subtree, parents
+// before their children
+func (m *Manager) subtreeRepos(node interfaces.NodeInfo) []interfaces.NodeInfo {
+	if node.IsConfig && node.ConfigFile != "" {
+		return m.collectClonedRepos(node)
+	}
+	var repos []interfaces.NodeInfo
+	if node.IsCloned && node.Repository != "" {
+		repos = append(repos, node)
+	}
+	for _, child := range node.Children {
+		repos = append(repos, m.subtreeRepos(child)...)
+	}
+	return repos
+}
+
+// commitChanges reports whether a repository has something to commit and
+// which files to stage for it
+func commitChanges(status *interfaces.GitStatus, opts CommitOptions) ([]string, bool) {
+	if len(opts.Paths) > 0 {
+		var files []string
+		for _, file := range status.Files {
+			if matchPathspec(file.Path, opts.Paths) {
+				files = append(files, file.Path)
+			}
+		}
+		return files, len(files) > 0
+	}
+
+	for _, file := range status.Files {
+		if file.Staged || (opts.All && file.Status != "untracked") {
+			return nil, true
+		}
+	}
+	return nil, status.HasStaged || (opts.All && status.HasModified)
+}
+
+// amendPushed reports whether amending would rewrite a commit the upstream
+// already has
+func amendPushed(status *interfaces.GitStatus) bool {
+	return status.Upstream != "" && status.Ahead == 0
+}
+
+// matchPathspec reports whether file matches one of the pathspecs: the file
+// itself, a directory containing it, or a glob
+func matchPathspec(file string, specs []string) bool {
+	file = strings.TrimSuffix(file, "/")
+	for _, spec := range specs {
+		spec = strings.TrimSuffix(strings.TrimPrefix(spec, "./"), "/")
+		if spec == "" || spec == "." || file == spec || strings.HasPrefix(file, spec+"/") {
+			return true
+		}
+		if ok, _ := path.Match(spec, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// untrackedStats lists new files among those to stage, which git diff does
+// not report
+func untrackedStats(status *interfaces.GitStatus, files []string, stats []interfaces.DiffStat) []interfaces.DiffStat {
+	seen := make(map[string]bool)
+	for _, stat := range stats {
+		seen[stat.Path] = true
+	}
+	staged := make(map[string]bool)
+	for _, file := range files {
+		staged[file] = true
+	}
+	var untracked []interfaces.DiffStat
+	for _, file := range status.Files {
+		if file.Status == "untracked" && staged[file.Path] && !seen[file.Path] {
+			untracked = append(untracked, interfaces.DiffStat{Path: file.Path})
+		}
+	}
+	return untracked
+}
+
+// commitMessage returns the message with the change id trailer, if any
+func commitMessage(opts CommitOptions) string {
+	if opts.ChangeID == "" {
+		return opts.Message
+	}
+	return strings.TrimRight(opts.Message, "\n") + "\n\n" + ChangeIDTrailer + ": " + opts.ChangeID
+}
+
+// showCommitDiffstat prints the files changed in each repository and a total
+func (m *Manager) showCommitDiffstat(candidates []commitCandidate) {
+	files, insertions, deletions := 0, 0, 0
+	for _, candidate := range candidates {
+		m.uiProvider.Info(fmt.Sprintf("📦 %s  (%s)", candidate.node.Path, diffSummary(candidate.stats)))
+		width := 0
+		for _, stat := range candidate.stats {
+			width = max(width, len(stat.Path))
+		}
+		for _, stat := range candidate.stats {
+			m.uiProvider.Info(fmt.Sprintf("   %-*s | %s", width, stat.Path, diffGraph(stat)))
+			files++
+			insertions += stat.Insertions
+			deletions += stat.Deletions
+		}
+	}
+	m.uiProvider.Info("")
+	m.uiProvider.Info(fmt.Sprintf("📊 %d repositories, %d files changed, %d insertions(+), %d deletions(-)",
+		len(candidates), files, insertions, deletions))
+}
+
+// diffSummary renders "3 files, +10 -2"
+func diffSummary(stats []interfaces.DiffStat) string {
+	insertions, deletions := 0, 0
+	for _, stat := range stats {
+		insertions += stat.Insertions
+		deletions += stat.Deletions
+	}
+	noun := "files"
+	if len(stats) == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("%d %s, +%d -%d", len(stats), noun, insertions, deletions)
+}
+
+// diffGraph renders one diffstat line the way git diff --stat does, with the
+// bar scaled down to at most 40 characters
+func diffGraph(stat interfaces.DiffStat) string {
+	if stat.Binary {
+		return "Bin"
+	}
+	total := stat.Insertions + stat.Deletions
+	if total == 0 {
+		return "new"
+	}
+	plus, minus := stat.Insertions, stat.Deletions
+	if total > 40 {
+		plus = (stat.Insertions*40 + total - 1) / total
+		minus = 40 - plus
+	}
+	return fmt.Sprintf("%d %s%s", total, strings.Repeat("+", plus), strings.Repeat("-", minus))
+}
@@ -0,0 +1,188 @@
+package manager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func setupCommitWorkspace(t *testing.T) (*Manager, string, *mocks.MockUIProvider, *mocks.MockGitProvider) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+	return mgr, tmpDir, ui, mgr.gitProvider.(*mocks.MockGitProvider)
+}
+
+func commitCalls(git *mocks.MockGitProvider) []string {
+	var calls []string
+	for _, call := range git.GetCalls() {
+		if strings.HasPrefix(call, "Commit(") || strings.HasPrefix(call, "Add(") {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func TestCommitRecursive_OnlyChangedRepos(t *testing.T) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", HasStaged: true, Files: []interfaces.GitFileStatus{
+		{Path: "main.go", Status: "modified", Staged: true},
+		{Path: "notes.txt", Status: "untracked"},
+	}})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", HasModified: true, Files: []interfaces.GitFileStatus{
+		{Path: "app.js", Status: "modified"},
+	}})
+	git.SetDiffStat(apiPath, []interfaces.DiffStat{
+		{Path: "main.go", Insertions: 8, Deletions: 2},
+		{Path: "logo.png", Binary: true},
+	})
+
+	require.NoError(t, mgr.CommitNode("/", "Fix login", true))
+
+	assert.Equal(t, []string{"Commit(" + apiPath + ", Fix login)"}, commitCalls(git))
+	assert.Contains(t, git.GetCalls(), "DiffStat("+apiPath+", staged=true, [])")
+	messages := ui.GetMessages()
+	assert.Contains(t, messages, "INFO: 📦 /api  (2 files, +8 -2)")
+	assert.Contains(t, messages, "INFO:    main.go  | 10 ++++++++--")
+	assert.Contains(t, messages, "INFO:    logo.png | Bin")
+	assert.Contains(t, messages, "INFO: 📊 1 repositories, 2 files changed, 8 insertions(+), 2 deletions(-)")
+	assert.Contains(t, messages, "INFO: 📊 Results: 1 succeeded, 0 failed")
+
+	ui.Reset()
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", IsClean: true})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", IsClean: true})
+	require.NoError(t, mgr.CommitNode("/", "Again", true))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📭 Nothing to commit in 2 repositories")
+}
+
+func TestCommitRecursive_AllAndPaths(t *testing.T) {
+	mgr, tmpDir, _, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", HasUntracked: true, Files: []interfaces.GitFileStatus{
+		{Path: "docs/guide.md", Status: "untracked"},
+	}})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", HasModified: true, Files: []interfaces.GitFileStatus{
+		{Path: "app.js", Status: "modified"},
+		{Path: "docs/index.md", Status: "modified"},
+	}})
+
+	// --all commits tracked changes only, so untracked files do not count
+	require.NoError(t, mgr.CommitNodeWithOptions("/", CommitOptions{Message: "Bump", Recursive: true, All: true}))
+	assert.Equal(t, []string{"Commit(" + webPath + ", Bump)"}, commitCalls(git))
+
+	// Pathspecs stage the matching files in each repository
+	git.Reset()
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", HasUntracked: true, Files: []interfaces.GitFileStatus{
+		{Path: "docs/guide.md", Status: "untracked"},
+	}})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", HasModified: true, Files: []interfaces.GitFileStatus{
+		{Path: "app.js", Status: "modified"},
+		{Path: "docs/index.md", Status: "modified"},
+	}})
+	require.NoError(t, mgr.CommitNodeWithOptions("/", CommitOptions{Message: "Docs", Recursive: true, Paths: []string{"docs/"}}))
+	assert.ElementsMatch(t, []string{
+		"Add(" + apiPath + ", [docs/guide.md])",
+		"Commit(" + apiPath + ", Docs)",
+		"Add(" + webPath + ", [docs/index.md])",
+		"Commit(" + webPath + ", Docs)",
+	}, commitCalls(git))
+	assert.Contains(t, git.GetCalls(), "DiffStat("+webPath+", staged=false, [docs/index.md])")
+}
+
+func TestCommitRecursive_SelectAndChangeID(t *testing.T) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	for _, name := range []string{"api", "web"} {
+		git.SetStatus(filepath.Join(tmpDir, ".nodes", name), &interfaces.GitStatus{Branch: "main", HasStaged: true})
+	}
+
+	// The mock picks the first offered repository
+	require.NoError(t, mgr.CommitNodeWithOptions("/", CommitOptions{
+		Message:   "Fix",
+		Recursive: true,
+		Select:    true,
+		ChangeID:  "I0123abcd",
+	}))
+
+	calls := commitCalls(git)
+	require.Len(t, calls, 1)
+	assert.True(t, strings.HasSuffix(calls[0], ", Fix\n\nMuno-Change-Id: I0123abcd)"), calls[0])
+	assert.Contains(t, ui.GetMessages(), "INFO: 🔗 Muno-Change-Id: I0123abcd")
+}
+
+func TestCommitRecursive_AmendOnlyChangedRepos(t *testing.T) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", IsClean: true})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", IsClean: true})
+
+	// Clean repositories are left alone
+	require.NoError(t, mgr.CommitNodeWithOptions("/", CommitOptions{Message: "Reword", Recursive: true, Amend: true}))
+	assert.Empty(t, commitCalls(git))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📭 Nothing to commit in 2 repositories")
+
+	// Staged changes are amended in, unless the last commit is pushed
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", Ahead: 1, HasStaged: true})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", HasStaged: true})
+	ui.Reset()
+	require.NoError(t, mgr.CommitNodeWithOptions("/", CommitOptions{Message: "Reword", Recursive: true, Amend: true}))
+	assert.Equal(t, []string{"Commit(" + apiPath + ", Reword)"}, commitCalls(git))
+	assert.Contains(t, ui.GetMessages(), "WARNING: ⚠️  Skipping /web: the last commit is already pushed")
+
+	// Picked through select (the mock picks the first), a clean repository
+	// is reworded
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", IsClean: true})
+	require.NoError(t, mgr.CommitNodeWithOptions("/", CommitOptions{Message: "Again", Recursive: true, Amend: true, Select: true}))
+	assert.Contains(t, commitCalls(git), "Commit("+apiPath+", Again)")
+
+	err := mgr.CommitNodeWithOptions("/web", CommitOptions{Amend: true})
+	assert.EqualError(t, err, "refusing to amend: the last commit of /web is already pushed")
+}
+
+func TestNewChangeID(t *testing.T) {
+	id, err := NewChangeID()
+	require.NoError(t, err)
+	assert.Regexp(t, `^I[0-9a-f]{20}$`, id)
+
+	other, err := NewChangeID()
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other)
+}
+
+func TestCommitNodeWithOptions_Single(t *testing.T) {
+	mgr, tmpDir, _, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+
+	require.NoError(t, mgr.CommitNodeWithOptions("/api", CommitOptions{Message: "Docs", Paths: []string{"README.md"}}))
+	assert.Equal(t, []string{"Add(" + apiPath + ", [README.md])", "Commit(" + apiPath + ", Docs)"}, commitCalls(git))
+
+	err := mgr.CommitNodeWithOptions("/api", CommitOptions{Message: "x", All: true, Paths: []string{"a"}})
+	assert.EqualError(t, err, "--all cannot be combined with paths")
+	err = mgr.CommitNodeWithOptions("/api", CommitOptions{})
+	assert.EqualError(t, err, "commit message is required")
+	require.NoError(t, mgr.CommitNodeWithOptions("/api", CommitOptions{Amend: true}))
+}
+
+func TestMatchPathspec(t *testing.T) {
+	assert.True(t, matchPathspec("docs/guide.md", []string{"docs"}))
+	assert.True(t, matchPathspec("docs/guide.md", []string{"./docs/"}))
+	assert.True(t, matchPathspec("main.go", []string{"*.go"}))
+	assert.True(t, matchPathspec("anything", []string{"."}))
+	assert.False(t, matchPathspec("docsite/a.md", []string{"docs"}))
+	assert.False(t, matchPathspec("cmd/main.go", []string{"*.go"}))
+}
+
+func TestDiffGraph(t *testing.T) {
+	assert.Equal(t, "3 ++-", diffGraph(interfaces.DiffStat{Insertions: 2, Deletions: 1}))
+	assert.Equal(t, "Bin", diffGraph(interfaces.DiffStat{Binary: true}))
+	assert.Equal(t, "new", diffGraph(interfaces.DiffStat{}))
+	assert.Equal(t, "100 "+strings.Repeat("+", 30)+strings.Repeat("-", 10), diffGraph(interfaces.DiffStat{Insertions: 75, Deletions: 25}))
+}
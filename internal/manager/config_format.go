@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/taokim/muno/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ConvertConfig rewrites the workspace config file in another format (yaml,
+// json or toml) next to the original, then removes the original. Configs
+// delegated with file: keep their own format.
+func (m *Manager) ConvertConfig(format string) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	ext, err := config.FormatExtension(format)
+	if err != nil {
+		return err
+	}
+	source := m.workspaceConfigPath()
+	sourceFormat, err := config.FormatForPath(source)
+	if err != nil {
+		return err
+	}
+	if sourceFormat == format {
+		m.uiProvider.Info(fmt.Sprintf("%s is already %s", filepath.Base(source), format))
+		return nil
+	}
+	target := strings.TrimSuffix(source, filepath.Ext(source)) + ext
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s already exists", filepath.Base(target))
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	node, err := config.ParseNode(sourceFormat, data)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", filepath.Base(source), err)
+	}
+	converted, err := config.EncodeNode(format, node)
+	if err != nil {
+		return fmt.Errorf("converting to %s: %w", format, err)
+	}
+
+	// The converted file must load the same tree before the original goes
+	var before, after config.ConfigTree
+	if err := node.Decode(&before); err != nil {
+		return fmt.Errorf("parsing %s: %w", filepath.Base(source), err)
+	}
+	if err := config.Unmarshal(format, converted, &after); err != nil {
+		return fmt.Errorf("converted config does not parse: %w", err)
+	}
+	if len(before.Nodes) != len(after.Nodes) || before.Workspace != after.Workspace {
+		return fmt.Errorf("converted config does not match %s", filepath.Base(source))
+	}
+
+	if err := os.WriteFile(target, converted, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(target), err)
+	}
+	if err := os.Remove(source); err != nil {
+		os.Remove(target)
+		return fmt.Errorf("removing %s: %w", filepath.Base(source), err)
+	}
+	if m.config != nil {
+		m.config.File = target
+	}
+
+	m.uiProvider.Success(fmt.Sprintf("✅ Converted %s to %s", filepath.Base(source), filepath.Base(target)))
+	if sourceFormat == config.FormatYAML && nodeHasComments(node) {
+		m.uiProvider.Warning(fmt.Sprintf("⚠️  Comments in %s were not carried over", filepath.Base(source)))
+	}
+	return nil
+}
+
+// nodeHasComments reports whether a YAML document carries any comments
+func nodeHasComments(node *yaml.Node) bool {
+	if node.HeadComment != "" || node.LineComment != "" || node.FootComment != "" {
+		return true
+	}
+	for _, child := range node.Content {
+		if nodeHasComments(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func TestConvertConfig(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+	yamlPath := filepath.Join(tmpDir, "muno.yaml")
+	require.NoError(t, mgr.config.Save(yamlPath))
+	data, err := os.ReadFile(yamlPath)
+	require.NoError(t, err)
+	writeTestFile(t, yamlPath, "# Acme workspace\n"+string(data))
+
+	require.NoError(t, mgr.ConvertConfig(config.FormatTOML))
+	assert.NoFileExists(t, yamlPath)
+	assert.Contains(t, ui.GetMessages(), "SUCCESS: ✅ Converted muno.yaml to muno.toml")
+	assert.Contains(t, ui.GetMessages(), "WARNING: ⚠️  Comments in muno.yaml were not carried over")
+	loaded, err := config.LoadTree(filepath.Join(tmpDir, "muno.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, mgr.config.Nodes, loaded.Nodes)
+
+	// Later saves keep the new format
+	mgr.config.Nodes = mgr.config.Nodes[:2]
+	require.NoError(t, mgr.saveConfig())
+	loaded, err = config.LoadTree(filepath.Join(tmpDir, "muno.toml"))
+	require.NoError(t, err)
+	assert.Len(t, loaded.Nodes, 2)
+
+	require.NoError(t, mgr.ConvertConfig(config.FormatJSON))
+	assert.FileExists(t, filepath.Join(tmpDir, "muno.json"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "muno.toml"))
+
+	ui.Reset()
+	require.NoError(t, mgr.ConvertConfig(config.FormatJSON))
+	assert.Contains(t, ui.GetMessages(), "INFO: muno.json is already json")
+
+	writeTestFile(t, filepath.Join(tmpDir, "muno.yaml"), "workspace: {name: stale}\n")
+	assert.EqualError(t, mgr.ConvertConfig(config.FormatYAML), "muno.yaml already exists")
+	assert.EqualError(t, mgr.ConvertConfig("ini"), `unknown config format "ini" (use yaml, json or toml)`)
+}
+
+func TestNodeDefinition_JSONSubConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := CreateTestManagerWithConfig(t, tmpDir, &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "acme", ReposDir: ".nodes"},
+		Nodes:     []config.NodeDefinition{{Name: "team", File: "team.json"}},
+	})
+	writeTestFile(t, filepath.Join(tmpDir, "team.json"), `{
+	"workspace": {"name": "team"},
+	"nodes": [{"name": "svc", "url": "https://github.com/acme/svc.git", "overrides": {"git": {"default_branch": "trunk"}}}]
+}`)
+	mgr.config.File = filepath.Join(tmpDir, "muno.yaml")
+
+	def := mgr.nodeDefinition("/team/svc")
+	require.NotNil(t, def)
+	assert.Equal(t, map[string]interface{}{"default_branch": "trunk"}, def.Overrides["git"])
+	assert.Equal(t, "trunk", mgr.nodeSetting("/team/svc", "git.default_branch", "main"))
+}
@@ -0,0 +1,350 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// Markers delimiting the muno-managed block inside agent context files.
+// Content outside the markers is left untouched.
+const (
+	contextBlockStart = "<!-- MUNO CONTEXT START (generated by muno context; do not edit inside this block) -->"
+	contextBlockEnd   = "<!-- MUNO CONTEXT END -->"
+)
+
+// DefaultContextFiles are the agent context files written into each node
+var DefaultContextFiles = []string{"CLAUDE.md", "AGENTS.md"}
+
+// ContextOptions controls agent context generation
+type ContextOptions struct {
+	Write     bool     // Write context files into node directories instead of printing
+	Refresh   bool     // Only rewrite context files that already contain a muno block
+	Recursive bool     // Process every cloned node in the subtree
+	Files     []string // File names to write (default: CLAUDE.md, AGENTS.md)
+}
+
+// ShowContext prints or writes agent context for the node at path
+func (m *Manager) ShowContext(path string, opts ContextOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	targetPath := path
+	if targetPath == "" {
+		var err error
+		targetPath, err = m.getCurrentTreePath()
+		if err != nil {
+			return fmt.Errorf("resolving current tree path: %w", err)
+		}
+	}
+
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return fmt.Errorf("getting node: %w", err)
+	}
+
+	if !opts.Write && !opts.Refresh {
+		content, err := m.GenerateNodeContext(node.Path)
+		if err != nil {
+			return err
+		}
+		fmt.Print(content)
+		return nil
+	}
+
+	files := opts.Files
+	if len(files) == 0 {
+		files = DefaultContextFiles
+	}
+
+	targets := []interfaces.NodeInfo{node}
+	if opts.Recursive {
+		for _, child := range node.Children {
+			targets = append(targets, m.collectRepos(child, false)...)
+		}
+	}
+
+	// One graph serves every node's related-nodes section
+	graph := m.contextGraph()
+	written := 0
+	for _, target := range targets {
+		if target.Path != "/" && !target.IsCloned {
+			continue
+		}
+		count, err := m.writeNodeContext(target, graph, files, opts.Refresh)
+		if err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed at %s: %v", target.Path, err))
+			m.logNodeFailure("context", target.Path, err)
+			continue
+		}
+		if count > 0 {
+			m.uiProvider.Info(fmt.Sprintf("📝 %s (%d files)", target.Path, count))
+		}
+		written += count
+	}
+
+	m.uiProvider.Success(fmt.Sprintf("✅ Updated %d context files", written))
+	m.metricsProvider.Counter("manager.context_files", int64(written))
+	return nil
+}
+
+// writeNodeContext writes the muno block into each context file of a node.
+// In refresh mode, files without an existing block are skipped.
+func (m *Manager) writeNodeContext(node interfaces.NodeInfo, graph *DependencyGraph, files []string, refresh bool) (int, error) {
+	nodeDir := m.computeFilesystemPath(node.Path)
+	block := ""
+	count := 0
+
+	for _, name := range files {
+		filePath := filepath.Join(nodeDir, name)
+
+		var existing string
+		if data, err := os.ReadFile(filePath); err == nil {
+			existing = string(data)
+		} else if refresh {
+			continue
+		}
+		if refresh && !strings.Contains(existing, contextBlockStart) {
+			continue
+		}
+
+		if block == "" {
+			content, err := m.renderNodeContext(node.Path, graph)
+			if err != nil {
+				return count, err
+			}
+			block = contextBlockStart + "\n" + content + contextBlockEnd + "\n"
+		}
+
+		if err := m.fsProvider.WriteFile(filePath, []byte(replaceContextBlock(existing, block)), 0644); err != nil {
+			return count, fmt.Errorf("writing %s: %w", name, err)
+		}
+
+		// Keep generated files out of the repository's status without touching tracked files
+		if existing == "" {
+			m.ensureGitExclude(nodeDir, name)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// replaceContextBlock replaces the muno block in content, or appends it if absent
+func replaceContextBlock(content string, block string) string {
+	start := strings.Index(content, contextBlockStart)
+	if start >= 0 {
+		if end := strings.Index(content[start:], contextBlockEnd); end >= 0 {
+			end = start + end + len(contextBlockEnd)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			return content[:start] + block + content[end:]
+		}
+	}
+
+	if content == "" {
+		return block
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n" + block
+}
+
+// ensureGitExclude adds entry to .git/info/exclude of a repository, if it is one
+func (m *Manager) ensureGitExclude(repoDir string, entry string) {
+	gitDir := filepath.Join(repoDir, ".git")
+	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+		return
+	}
+
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+	data, _ := os.ReadFile(excludePath)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == entry {
+			return
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += entry + "\n"
+
+	if err := m.fsProvider.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return
+	}
+	if err := m.fsProvider.WriteFile(excludePath, []byte(content), 0644); err != nil {
+		m.logProvider.Debug(fmt.Sprintf("Could not update %s: %v", excludePath, err))
+	}
+}
+
+// GenerateNodeContext renders markdown describing where a node sits in the workspace:
+// the tree with a "YOU ARE HERE" marker, its parent, siblings and children, and
+// related nodes from the dependency graph
+func (m *Manager) GenerateNodeContext(path string) (string, error) {
+	if !m.initialized {
+		return "", fmt.Errorf("manager not initialized")
+	}
+	return m.renderNodeContext(path, m.contextGraph())
+}
+
+// contextGraph builds the dependency graph used for related nodes, or nil if it
+// cannot be built
+func (m *Manager) contextGraph() *DependencyGraph {
+	graph, err := m.BuildDependencyGraph()
+	if err != nil {
+		m.logProvider.Debug(fmt.Sprintf("Skipping related nodes: %v", err))
+		return nil
+	}
+	return graph
+}
+
+// renderNodeContext renders the context of the node at path using a prebuilt graph
+func (m *Manager) renderNodeContext(path string, graph *DependencyGraph) (string, error) {
+	node, err := m.treeProvider.GetNode(path)
+	if err != nil {
+		return "", fmt.Errorf("getting node: %w", err)
+	}
+
+	var b strings.Builder
+	workspaceName := ""
+	if m.config != nil {
+		workspaceName = m.config.Workspace.Name
+	}
+
+	b.WriteString("# MUNO Workspace Context\n\n")
+	b.WriteString("This repository is part of a MUNO multi-repository workspace.\n\n")
+	fmt.Fprintf(&b, "- Workspace: %s\n", workspaceName)
+	fmt.Fprintf(&b, "- Workspace root: %s\n", m.workspace)
+	fmt.Fprintf(&b, "- Current node: %s\n", node.Path)
+	if node.Repository != "" {
+		fmt.Fprintf(&b, "- Repository: %s\n", node.Repository)
+	}
+	if branch := m.nodeBranch(node); branch != "" {
+		fmt.Fprintf(&b, "- Branch: %s\n", branch)
+	}
+
+	b.WriteString("\n## Workspace Tree\n\n```\n")
+	b.WriteString(m.generateTreeContext(&node))
+	b.WriteString("```\n")
+
+	if node.Path != "/" {
+		parentPath := filepath.ToSlash(filepath.Dir(node.Path))
+		if parent, err := m.treeProvider.GetNode(parentPath); err == nil {
+			b.WriteString("\n## Parent\n\n")
+			b.WriteString(m.contextNodeLine(node, parent))
+
+			var siblings []interfaces.NodeInfo
+			for _, sibling := range parent.Children {
+				if sibling.Path != node.Path {
+					siblings = append(siblings, sibling)
+				}
+			}
+			if len(siblings) > 0 {
+				b.WriteString("\n## Sibling Nodes\n\n")
+				for _, sibling := range siblings {
+					b.WriteString(m.contextNodeLine(node, sibling))
+				}
+			}
+		}
+	}
+
+	if len(node.Children) > 0 {
+		b.WriteString("\n## Child Nodes\n\n")
+		for _, child := range node.Children {
+			b.WriteString(m.contextNodeLine(node, child))
+		}
+	}
+
+	if related := relatedNodesSection(graph, node.Path); related != "" {
+		b.WriteString("\n## Related Nodes\n\n")
+		b.WriteString(related)
+	}
+
+	b.WriteString("\n## Working Across Nodes\n\n")
+	b.WriteString("- `muno tree` shows the full workspace tree\n")
+	b.WriteString("- `muno status -r` shows repository state across the subtree\n")
+	b.WriteString("- Paths above are relative to this node's directory\n")
+
+	return b.String(), nil
+}
+
+// contextNodeLine renders one related node as a markdown list entry
+func (m *Manager) contextNodeLine(from interfaces.NodeInfo, node interfaces.NodeInfo) string {
+	line := fmt.Sprintf("- **%s** (`%s`)", node.Name, node.Path)
+	if node.Repository != "" {
+		line += " — " + node.Repository
+	}
+	if branch := m.nodeBranch(node); branch != "" {
+		line += fmt.Sprintf(" [branch: %s]", branch)
+	}
+	switch {
+	case node.IsConfig:
+		line += " [config]"
+	case node.IsLazy && !node.IsCloned:
+		line += " [lazy, not cloned]"
+	case !node.IsCloned && node.Path != "/":
+		line += " [not cloned]"
+	}
+
+	fromDir := m.computeFilesystemPath(from.Path)
+	if rel, err := filepath.Rel(fromDir, m.computeFilesystemPath(node.Path)); err == nil {
+		line += fmt.Sprintf(" → `%s`", filepath.ToSlash(rel))
+	}
+	return line + "\n"
+}
+
+// nodeBranch returns the current branch of a cloned node, or "" if unknown
+func (m *Manager) nodeBranch(node interfaces.NodeInfo) string {
+	if !node.IsCloned || node.Repository == "" {
+		return ""
+	}
+	fsPath := m.computeFilesystemPath(node.Path)
+	if _, err := os.Stat(filepath.Join(fsPath, ".git")); err != nil {
+		return ""
+	}
+	branch, err := m.gitProvider.Branch(fsPath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(branch)
+}
+
+// relatedNodesSection lists dependencies and dependents of a node from the dependency graph
+func relatedNodesSection(graph *DependencyGraph, path string) string {
+	if graph == nil {
+		return ""
+	}
+	node, ok := graph.Nodes[path]
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, edge := range node.DependsOn {
+		fmt.Fprintf(&b, "- Depends on `%s` (%s)\n", edge.To, edge.Source)
+	}
+
+	var dependents []string
+	for _, other := range graph.Nodes {
+		for _, edge := range other.DependsOn {
+			if edge.To == path {
+				dependents = append(dependents, fmt.Sprintf("- Used by `%s` (%s)\n", other.Path, edge.Source))
+			}
+		}
+	}
+	sort.Strings(dependents)
+	for _, line := range dependents {
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
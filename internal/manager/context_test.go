@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func setupContextWorkspace(t *testing.T) (*Manager, string) {
+	mgr, tmpDir := CreateTestNodeWorkspace(t, "acme",
+		config.NodeDefinition{Name: "api", URL: "https://github.com/acme/api.git"},
+		config.NodeDefinition{Name: "web", URL: "https://github.com/acme/web.git", DependsOn: []string{"api"}},
+		config.NodeDefinition{Name: "docs", URL: "https://github.com/acme/docs.git", Fetch: config.FetchLazy},
+	)
+
+	gitMock := mocks.NewMockGitProvider()
+	gitMock.SetStatus(filepath.Join(tmpDir, ".nodes", "api"), &interfaces.GitStatus{Branch: "develop", IsClean: true})
+	mgr.gitProvider = gitMock
+
+	return mgr, tmpDir
+}
+
+func TestGenerateNodeContext(t *testing.T) {
+	mgr, _ := setupContextWorkspace(t)
+
+	content, err := mgr.GenerateNodeContext("/api")
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "- Current node: /api")
+	assert.Contains(t, content, "- Repository: https://github.com/acme/api.git")
+	assert.Contains(t, content, "- Branch: develop")
+	assert.Contains(t, content, "api  <-- YOU ARE HERE")
+	assert.Contains(t, content, "## Sibling Nodes")
+	assert.Contains(t, content, "- **web** (`/web`) — https://github.com/acme/web.git")
+	assert.Contains(t, content, "→ `../web`")
+	assert.Contains(t, content, "- **docs** (`/docs`) — https://github.com/acme/docs.git [lazy, not cloned]")
+	assert.Contains(t, content, "- Used by `/web` (declared)")
+}
+
+func TestShowContext_Write(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	apiDir := filepath.Join(tmpDir, ".nodes", "api")
+	writeTestFile(t, filepath.Join(apiDir, "CLAUDE.md"), "# API\n\nProject notes.\n")
+
+	require.NoError(t, mgr.ShowContext("/api", ContextOptions{Write: true}))
+
+	claude, err := os.ReadFile(filepath.Join(apiDir, "CLAUDE.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(claude), "# API\n\nProject notes.\n")
+	assert.Contains(t, string(claude), contextBlockStart)
+	assert.Contains(t, string(claude), "- Current node: /api")
+
+	agents, err := os.ReadFile(filepath.Join(apiDir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(agents), contextBlockStart)
+
+	// Newly created files are excluded from git status, pre-existing ones are not
+	exclude, err := os.ReadFile(filepath.Join(apiDir, ".git", "info", "exclude"))
+	require.NoError(t, err)
+	assert.Equal(t, "AGENTS.md\n", string(exclude))
+
+	// Rewriting replaces the block instead of appending a second one
+	require.NoError(t, mgr.ShowContext("/api", ContextOptions{Write: true}))
+	claude, err = os.ReadFile(filepath.Join(apiDir, "CLAUDE.md"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(claude), contextBlockStart))
+}
+
+func TestShowContext_RefreshRecursive(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	apiDir := filepath.Join(tmpDir, ".nodes", "api")
+	webDir := filepath.Join(tmpDir, ".nodes", "web")
+	writeTestFile(t, filepath.Join(apiDir, "CLAUDE.md"), contextBlockStart+"\nstale\n"+contextBlockEnd+"\n")
+	writeTestFile(t, filepath.Join(webDir, "CLAUDE.md"), "# Web only\n")
+
+	require.NoError(t, mgr.ShowContext("/", ContextOptions{Refresh: true, Recursive: true}))
+
+	api, err := os.ReadFile(filepath.Join(apiDir, "CLAUDE.md"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(api), "stale")
+	assert.Contains(t, string(api), "- Current node: /api")
+
+	web, err := os.ReadFile(filepath.Join(webDir, "CLAUDE.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Web only\n", string(web))
+	assert.NoFileExists(t, filepath.Join(apiDir, "AGENTS.md"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, ".nodes", "docs", "CLAUDE.md"))
+}
+
+func TestReplaceContextBlock(t *testing.T) {
+	block := contextBlockStart + "\nnew\n" + contextBlockEnd + "\n"
+
+	assert.Equal(t, block, replaceContextBlock("", block))
+	assert.Equal(t, "intro\n\n"+block, replaceContextBlock("intro", block))
+	assert.Equal(t, "a\n"+block+"b\n",
+		replaceContextBlock("a\n"+contextBlockStart+"\nold\n"+contextBlockEnd+"\nb\n", block))
+}
+
+func TestShowContext_NotInitialized(t *testing.T) {
+	mgr := &Manager{}
+	assert.Error(t, mgr.ShowContext("/", ContextOptions{}))
+	_, err := mgr.GenerateNodeContext("/")
+	assert.Error(t, err)
+}
+
@@ -0,0 +1,210 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/taokim/muno/internal/daemon"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// daemonMetricsFlushInterval is how often a running daemon saves its metrics,
+// so muno stats sees them without stopping it
+const daemonMetricsFlushInterval = time.Minute
+
+// RunDaemon keeps the workspace navigator, its status cache and config
+// watchers in memory and serves them on the workspace socket until ctx is done
+// or the daemon is stopped
+func (m *Manager) RunDaemon(ctx context.Context) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	nav, err := m.navigatorFactory().CreateCachedFromConfig()
+	if err != nil {
+		return fmt.Errorf("creating navigator: %w", err)
+	}
+	if closer, ok := nav.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	server := daemon.NewServer(m.workspace, nav)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		err := m.WatchConfig(ctx, func(event interfaces.ConfigEvent, treePath string) {
+			m.logProvider.Debug(fmt.Sprintf("Reloading daemon tree below %s after %s of %s", treePath, event.Type, event.Path))
+			if err := server.Invalidate(treePath); err != nil {
+				m.logProvider.Warn(fmt.Sprintf("Failed to reload daemon navigator: %v", err))
+			}
+		})
+		if err != nil {
+			m.logProvider.Warn(fmt.Sprintf("Config watcher stopped: %v", err))
+		}
+	}()
+
+	// Keep remote information fresh when git.fetch_interval is set
+	if interval := m.fetchInterval(); interval > 0 {
+		go func() {
+			err := m.fetchPeriodically(ctx, "/", interval, FetchOptions{Recursive: true, Prune: true}, func() {
+				if err := server.RefreshStatus("/"); err != nil {
+					m.logProvider.Warn(fmt.Sprintf("Failed to refresh daemon status: %v", err))
+				}
+			})
+			if err != nil {
+				m.logProvider.Warn(fmt.Sprintf("Scheduled fetch stopped: %v", err))
+			}
+		}()
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("🛰️  Daemon serving %s", m.workspace))
+	m.uiProvider.Info(fmt.Sprintf("   Socket: %s", server.Socket()))
+	if interval := m.fetchInterval(); interval > 0 {
+		m.uiProvider.Info(fmt.Sprintf("   Fetching every %s", interval))
+	}
+	m.metricsProvider.Counter("manager.daemon_start", 1)
+	go m.flushMetricsPeriodically(ctx, daemonMetricsFlushInterval)
+
+	if err := server.Serve(ctx); err != nil {
+		return err
+	}
+	m.uiProvider.Info("Daemon stopped")
+	return nil
+}
+
+// flushMetricsPeriodically saves the metrics recorded so far every interval
+// until ctx is done
+func (m *Manager) flushMetricsPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.metricsProvider.Flush(); err != nil {
+				m.logProvider.Warn(fmt.Sprintf("Failed to flush metrics: %v", err))
+			}
+		}
+	}
+}
+
+// DaemonStatus shows whether a daemon serves this workspace
+func (m *Manager) DaemonStatus() error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	client, err := daemon.Connect(m.workspace)
+	if err != nil {
+		m.uiProvider.Info("Daemon not running")
+		return nil
+	}
+	defer client.Close()
+
+	info, err := client.Ping()
+	if err != nil {
+		return fmt.Errorf("querying daemon: %w", err)
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("🛰️  Daemon running (pid %d)", info.PID))
+	m.uiProvider.Info(fmt.Sprintf("   Socket: %s", info.Socket))
+	m.uiProvider.Info(fmt.Sprintf("   Uptime: %s", time.Since(info.StartedAt).Round(time.Second)))
+	m.uiProvider.Info(fmt.Sprintf("   Config reloads: %d", info.Reloads))
+	if info.Cache != nil {
+		m.uiProvider.Info(fmt.Sprintf("   Cache: %d/%d entries, %d hits, %d misses",
+			info.Cache.Size, info.Cache.MaxSize, info.Cache.Hits, info.Cache.Misses))
+	}
+	return nil
+}
+
+// StopDaemon asks the daemon serving this workspace to exit
+func (m *Manager) StopDaemon() error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	client, err := daemon.Connect(m.workspace)
+	if err != nil {
+		return fmt.Errorf("daemon is not running")
+	}
+	defer client.Close()
+
+	if err := client.Shutdown(); err != nil {
+		return fmt.Errorf("stopping daemon: %w", err)
+	}
+	m.uiProvider.Success("Daemon stopped")
+	return nil
+}
+
+// CompleteTreePaths returns child node paths matching toComplete for shell
+// completion. Relative input is completed from the current tree position.
+func (m *Manager) CompleteTreePaths(toComplete string) ([]string, error) {
+	if !m.initialized {
+		return nil, fmt.Errorf("manager not initialized")
+	}
+
+	nav := m.daemonNavigator()
+	if nav == nil {
+		var err error
+		nav, err = m.navigatorFactory().CreateFromConfig()
+		if err != nil {
+			return nil, fmt.Errorf("creating navigator: %w", err)
+		}
+	}
+	if closer, ok := nav.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	dirPart, prefix := "", toComplete
+	if i := strings.LastIndex(toComplete, "/"); i >= 0 {
+		dirPart, prefix = toComplete[:i+1], toComplete[i+1:]
+	}
+	parent := dirPart
+	if !strings.HasPrefix(toComplete, "/") {
+		current, err := m.getCurrentTreePath()
+		if err != nil {
+			return nil, err
+		}
+		parent = path.Join(current, dirPart)
+	}
+	parent = path.Clean("/" + parent)
+
+	children, err := nav.ListChildren(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, child := range children {
+		if !strings.HasPrefix(child.Name, prefix) {
+			continue
+		}
+		candidate := dirPart + child.Name
+		if len(child.Children) > 0 {
+			candidate += "/"
+		}
+		matches = append(matches, candidate)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// daemonNavigator returns a navigator backed by the workspace daemon, or nil
+// when no daemon is running
+func (m *Manager) daemonNavigator() navigator.TreeNavigator {
+	client, err := daemon.Connect(m.workspace)
+	if err != nil {
+		return nil
+	}
+	m.logProvider.Debug(fmt.Sprintf("Using daemon at %s", daemon.SocketPath(m.workspace)))
+	return client
+}
@@ -0,0 +1,134 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/adapters"
+	"github.com/taokim/muno/internal/daemon"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func TestRunDaemon(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- mgr.RunDaemon(ctx) }()
+
+	require.Eventually(t, func() bool {
+		client, err := daemon.Connect(tmpDir)
+		if err != nil {
+			return false
+		}
+		client.Close()
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Status and completion go through the daemon while it runs
+	nav := mgr.statusNavigator()
+	require.NotNil(t, nav)
+	_, isClient := nav.(*daemon.Client)
+	assert.True(t, isClient)
+	nav.(*daemon.Client).Close()
+
+	paths, err := mgr.CompleteTreePaths("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "docs", "web"}, paths)
+
+	require.NoError(t, mgr.DaemonStatus())
+	assert.Contains(t, strings.Join(ui.GetMessages(), "\n"), "Daemon running")
+
+	// The CLI reads the tree from the daemon without loading the workspace
+	if findWorkspaceRoot(tmpDir) == tmpDir {
+		oldWd, _ := os.Getwd()
+		require.NoError(t, os.Chdir(tmpDir))
+		defer os.Chdir(oldWd)
+
+		served, err := ConnectDaemonFromCurrentDir()
+		require.NoError(t, err)
+		_, isDaemonTree := served.treeProvider.(*daemonTree)
+		assert.True(t, isDaemonTree)
+		paths, err := served.CompleteTreePaths("")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"api", "docs", "web"}, paths)
+
+		servedUI := mocks.NewMockUIProvider()
+		served.uiProvider = servedUI
+		require.NoError(t, served.StatusNode("/", true))
+		assert.Contains(t, servedUI.GetMessages(), "INFO: Tree Status")
+		node, err := served.treeProvider.GetNode("/api")
+		require.NoError(t, err)
+		assert.Equal(t, "api", node.Name)
+	}
+
+	require.NoError(t, mgr.StopDaemon())
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("daemon did not stop")
+	}
+
+	require.NoError(t, mgr.DaemonStatus())
+	assert.Contains(t, ui.GetMessages(), "INFO: Daemon not running")
+	assert.ErrorContains(t, mgr.StopDaemon(), "not running")
+	assert.Nil(t, mgr.statusNavigator())
+	if findWorkspaceRoot(tmpDir) == tmpDir {
+		_, err = ConnectDaemonFromCurrentDir()
+		assert.ErrorContains(t, err, "daemon not running")
+	}
+}
+
+func TestFlushMetricsPeriodically(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	path := filepath.Join(tmpDir, MetricsFile)
+	mgr.metricsProvider = adapters.NewMetrics(path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		mgr.flushMetricsPeriodically(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	mgr.metricsProvider.Counter("manager.daemon_start", 1)
+	require.Eventually(t, func() bool {
+		stored, err := adapters.LoadMetrics(path)
+		return err == nil && stored.Series["manager.daemon_start"] != nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestCompleteTreePaths_Direct(t *testing.T) {
+	mgr, _ := setupContextWorkspace(t)
+
+	paths, err := mgr.CompleteTreePaths("/w")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/web"}, paths)
+
+	paths, err = mgr.CompleteTreePaths("/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/api", "/docs", "/web"}, paths)
+
+	_, err = mgr.CompleteTreePaths("/missing/")
+	assert.Error(t, err)
+
+	uninitialized := &Manager{}
+	_, err = uninitialized.CompleteTreePaths("")
+	assert.Error(t, err)
+	assert.Error(t, uninitialized.RunDaemon(context.Background()))
+	assert.Error(t, uninitialized.DaemonStatus())
+	assert.Error(t, uninitialized.StopDaemon())
+}
@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// daemonTree is a read-only tree provider backed by the navigator a daemon
+// serves. The whole tree is read in one call, the first time it is needed.
+type daemonTree struct {
+	nav navigator.TreeNavigator
+
+	once sync.Once
+	view *navigator.TreeView
+	err  error
+	path string
+}
+
+func newDaemonTree(nav navigator.TreeNavigator) *daemonTree {
+	return &daemonTree{nav: nav, path: "/"}
+}
+
+// load reads the tree from the daemon
+func (t *daemonTree) load() error {
+	t.once.Do(func() {
+		t.view, t.err = t.nav.GetTree("/", -1)
+	})
+	return t.err
+}
+
+// nodeInfo converts the navigator node at treePath and its subtree
+func (t *daemonTree) nodeInfo(treePath string) (interfaces.NodeInfo, bool) {
+	node, ok := t.view.Nodes[treePath]
+	if !ok {
+		return interfaces.NodeInfo{}, false
+	}
+	info := interfaces.NodeInfo{
+		Name:       node.Name,
+		Path:       node.Path,
+		Repository: node.URL,
+	}
+	if node.Type == navigator.NodeTypeFile {
+		info.IsConfig = true
+		info.ConfigFile = node.File
+	}
+	if status, ok := t.view.Status[treePath]; ok && status != nil {
+		info.IsCloned = status.Cloned
+		info.IsLazy = status.Lazy
+		info.HasChanges = status.Modified
+	}
+	for _, name := range node.Children {
+		if child, ok := t.nodeInfo(path.Join(treePath, name)); ok {
+			info.Children = append(info.Children, child)
+		}
+	}
+	return info, true
+}
+
+func (t *daemonTree) Load(cfg interface{}) error {
+	return nil
+}
+
+func (t *daemonTree) Navigate(treePath string) error {
+	if _, err := t.GetNode(treePath); err != nil {
+		return err
+	}
+	t.path = treePath
+	return nil
+}
+
+func (t *daemonTree) GetCurrent() (interfaces.NodeInfo, error) {
+	return t.GetNode(t.path)
+}
+
+func (t *daemonTree) GetTree() (interfaces.NodeInfo, error) {
+	return t.GetNode("/")
+}
+
+func (t *daemonTree) GetNode(treePath string) (interfaces.NodeInfo, error) {
+	if err := t.load(); err != nil {
+		return interfaces.NodeInfo{}, fmt.Errorf("reading tree from daemon: %w", err)
+	}
+	info, ok := t.nodeInfo(path.Clean("/" + treePath))
+	if !ok {
+		return interfaces.NodeInfo{}, fmt.Errorf("node not found: %s", treePath)
+	}
+	return info, nil
+}
+
+func (t *daemonTree) AddNode(parentPath string, node interfaces.NodeInfo) error {
+	return fmt.Errorf("the daemon tree is read-only")
+}
+
+func (t *daemonTree) RemoveNode(treePath string) error {
+	return fmt.Errorf("the daemon tree is read-only")
+}
+
+func (t *daemonTree) UpdateNode(treePath string, node interfaces.NodeInfo) error {
+	return fmt.Errorf("the daemon tree is read-only")
+}
+
+func (t *daemonTree) ListChildren(treePath string) ([]interfaces.NodeInfo, error) {
+	node, err := t.GetNode(treePath)
+	if err != nil {
+		return nil, err
+	}
+	return node.Children, nil
+}
+
+func (t *daemonTree) GetPath() string {
+	return t.path
+}
+
+func (t *daemonTree) SetPath(treePath string) error {
+	return t.Navigate(treePath)
+}
+
+func (t *daemonTree) GetState() (interfaces.TreeState, error) {
+	return interfaces.TreeState{CurrentPath: t.path}, nil
+}
+
+func (t *daemonTree) SetState(state interfaces.TreeState) error {
+	return fmt.Errorf("the daemon tree is read-only")
+}
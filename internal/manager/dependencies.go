@@ -0,0 +1,238 @@
+package manager
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dependency sources recorded on graph edges
+const (
+	DepSourceDeclared    = "declared"
+	DepSourceGoMod       = "go.mod"
+	DepSourcePackageJSON = "package.json"
+	DepSourcePom         = "pom.xml"
+)
+
+// repoManifest holds the coordinates a repository publishes and the
+// coordinates it consumes, as read from its build manifests
+type repoManifest struct {
+	Provides   []string          // Coordinates published by this repository
+	Requires   map[string]string // Required coordinate -> source manifest
+	LocalPaths map[string]string // Local replacement directory -> source manifest
+	URLs       map[string]string // Dependency specs given as git URLs -> source manifest
+}
+
+func newRepoManifest() *repoManifest {
+	return &repoManifest{
+		Requires:   make(map[string]string),
+		LocalPaths: make(map[string]string),
+		URLs:       make(map[string]string),
+	}
+}
+
+// readRepoManifest reads go.mod, package.json and pom.xml from a repository root.
+// Missing or unparsable manifests are skipped.
+func readRepoManifest(repoPath string) *repoManifest {
+	manifest := newRepoManifest()
+
+	if data, err := os.ReadFile(filepath.Join(repoPath, "go.mod")); err == nil {
+		parseGoMod(string(data), repoPath, manifest)
+	}
+	if data, err := os.ReadFile(filepath.Join(repoPath, "package.json")); err == nil {
+		parsePackageJSON(data, manifest)
+	}
+	if data, err := os.ReadFile(filepath.Join(repoPath, "pom.xml")); err == nil {
+		parsePom(data, manifest)
+	}
+
+	return manifest
+}
+
+// parseGoMod extracts the module path, require entries and replace targets.
+// Only the subset of go.mod syntax relevant to dependency detection is handled.
+func parseGoMod(content string, repoPath string, manifest *repoManifest) {
+	block := ""
+	for _, rawLine := range strings.Split(content, "\n") {
+		line := rawLine
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			parseGoModDirective(block, line, repoPath, manifest)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		directive := fields[0]
+		rest := strings.TrimSpace(strings.TrimPrefix(line, directive))
+
+		switch directive {
+		case "module":
+			manifest.Provides = append(manifest.Provides, strings.Trim(rest, `"`))
+		case "require", "replace":
+			if rest == "(" {
+				block = directive
+			} else {
+				parseGoModDirective(directive, rest, repoPath, manifest)
+			}
+		}
+	}
+}
+
+func parseGoModDirective(directive string, line string, repoPath string, manifest *repoManifest) {
+	switch directive {
+	case "require":
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			manifest.Requires[strings.Trim(fields[0], `"`)] = DepSourceGoMod
+		}
+	case "replace":
+		parts := strings.SplitN(line, "=>", 2)
+		if len(parts) != 2 {
+			return
+		}
+		oldFields := strings.Fields(parts[0])
+		newFields := strings.Fields(parts[1])
+		if len(oldFields) == 0 || len(newFields) == 0 {
+			return
+		}
+		manifest.Requires[strings.Trim(oldFields[0], `"`)] = DepSourceGoMod
+
+		target := strings.Trim(newFields[0], `"`)
+		if strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") || filepath.IsAbs(target) {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(repoPath, target)
+			}
+			manifest.LocalPaths[filepath.Clean(target)] = DepSourceGoMod
+		} else {
+			manifest.Requires[target] = DepSourceGoMod
+		}
+	}
+}
+
+// packageJSON is the subset of package.json used for dependency detection
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+func parsePackageJSON(data []byte, manifest *repoManifest) {
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return
+	}
+
+	if pkg.Name != "" {
+		manifest.Provides = append(manifest.Provides, pkg.Name)
+	}
+
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies, pkg.OptionalDependencies} {
+		for name, spec := range deps {
+			manifest.Requires[name] = DepSourcePackageJSON
+			if isGitDependencySpec(spec) {
+				manifest.URLs[spec] = DepSourcePackageJSON
+			}
+		}
+	}
+}
+
+// pomProject is the subset of pom.xml used for dependency detection
+type pomProject struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Parent     struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+	} `xml:"parent"`
+	Dependencies []pomDependency `xml:"dependencies>dependency"`
+	Managed      []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+}
+
+func parsePom(data []byte, manifest *repoManifest) {
+	var pom pomProject
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return
+	}
+
+	groupID := pom.GroupID
+	if groupID == "" {
+		groupID = pom.Parent.GroupID
+	}
+	if groupID != "" && pom.ArtifactID != "" {
+		manifest.Provides = append(manifest.Provides, groupID+":"+pom.ArtifactID)
+	}
+	if pom.Parent.GroupID != "" && pom.Parent.ArtifactID != "" {
+		manifest.Requires[pom.Parent.GroupID+":"+pom.Parent.ArtifactID] = DepSourcePom
+	}
+
+	for _, deps := range [][]pomDependency{pom.Dependencies, pom.Managed} {
+		for _, dep := range deps {
+			if dep.GroupID != "" && dep.ArtifactID != "" {
+				manifest.Requires[dep.GroupID+":"+dep.ArtifactID] = DepSourcePom
+			}
+		}
+	}
+}
+
+// isGitDependencySpec reports whether an npm dependency spec points at a git repository
+func isGitDependencySpec(spec string) bool {
+	for _, prefix := range []string{"git+", "git://", "git@", "github:", "gitlab:", "bitbucket:", "https://", "http://", "ssh://"} {
+		if strings.HasPrefix(spec, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRepoURL reduces a git URL to a comparable host/path form,
+// e.g. "git@github.com:org/repo.git" -> "github.com/org/repo"
+func normalizeRepoURL(url string) string {
+	u := strings.TrimSpace(url)
+	u = strings.TrimPrefix(u, "git+")
+
+	// npm shorthands
+	for prefix, host := range map[string]string{"github:": "github.com/", "gitlab:": "gitlab.com/", "bitbucket:": "bitbucket.org/"} {
+		if strings.HasPrefix(u, prefix) {
+			u = host + strings.TrimPrefix(u, prefix)
+		}
+	}
+
+	// Drop fragment (npm commit-ish) and query
+	if idx := strings.IndexAny(u, "#?"); idx >= 0 {
+		u = u[:idx]
+	}
+
+	if idx := strings.Index(u, "://"); idx >= 0 {
+		u = u[idx+3:]
+		if at := strings.Index(u, "@"); at >= 0 && at < strings.Index(u+"/", "/") {
+			u = u[at+1:]
+		}
+	} else if at := strings.Index(u, "@"); at >= 0 {
+		// scp-like syntax: git@host:org/repo
+		u = strings.Replace(u[at+1:], ":", "/", 1)
+	}
+
+	u = strings.TrimSuffix(u, "/")
+	u = strings.TrimSuffix(u, ".git")
+	return strings.ToLower(u)
+}
@@ -0,0 +1,234 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tree"
+)
+
+// defaultFetchStaleAfter is used when git.fetch_stale_after is unset or invalid
+const defaultFetchStaleAfter = 24 * time.Hour
+
+// FetchOptions controls which repositories muno fetch updates and how
+type FetchOptions struct {
+	Recursive bool // Fetch every cloned repository below the node
+	Prune     bool // Remove remote-tracking refs that no longer exist upstream
+	Tags      bool // Fetch all tags
+}
+
+// FetchNodes fetches remote refs for a node, or for every cloned repository
+// below it when recursive, and records when each fetch succeeded so status
+// can report how fresh remote information is
+func (m *Manager) FetchNodes(path string, opts FetchOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	targetPath := path
+	if targetPath == "" && opts.Recursive {
+		targetPath = "/"
+	}
+	if targetPath == "" {
+		var err error
+		targetPath, err = m.getCurrentTreePath()
+		if err != nil {
+			return fmt.Errorf("resolving current tree path: %w", err)
+		}
+	}
+
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return fmt.Errorf("getting node: %w", err)
+	}
+
+	var repos []interfaces.NodeInfo
+	if opts.Recursive {
+		repos = m.collectClonedRepos(node)
+	} else if node.IsCloned {
+		repos = []interfaces.NodeInfo{node}
+	}
+	if len(repos) == 0 {
+		m.uiProvider.Info("📭 No cloned repositories found")
+		return nil
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("🔄 Fetching %d repositories...", len(repos)))
+	m.uiProvider.Info("─────────────────")
+
+	fetchOpts := interfaces.FetchOptions{All: true, Prune: opts.Prune, Tags: opts.Tags, Quiet: true}
+	fetched := make(map[string]time.Time)
+	failedRepos := []string{}
+
+	for _, repo := range repos {
+		m.uiProvider.Info(fmt.Sprintf("📦 Fetching: %s", repo.Name))
+		if err := m.gitProvider.Fetch(m.computeFilesystemPath(repo.Path), fetchOpts); err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", err))
+			m.logNodeFailure("fetch", repo.Path, err)
+			failedRepos = append(failedRepos, repo.Name)
+			continue
+		}
+		m.uiProvider.Success("   ✅ Success")
+		fetched[repo.Path] = time.Now()
+	}
+
+	if err := m.recordFetches(fetched); err != nil {
+		m.logProvider.Warn(fmt.Sprintf("Failed to record fetch times: %v", err))
+	}
+	m.metricsProvider.Counter("manager.fetch", int64(len(fetched)))
+
+	m.uiProvider.Info("")
+	m.uiProvider.Info("─────────────────")
+	m.uiProvider.Info(fmt.Sprintf("📊 Results: %d succeeded, %d failed", len(fetched), len(failedRepos)))
+	if len(failedRepos) > 0 {
+		m.uiProvider.Info("")
+		m.uiProvider.Warning("⚠️  Failed repositories:")
+		for _, repo := range failedRepos {
+			m.uiProvider.Info(fmt.Sprintf("   - %s", repo))
+		}
+	}
+	return nil
+}
+
+// FetchPeriodically runs FetchNodes now and then every interval until ctx is done
+func (m *Manager) FetchPeriodically(ctx context.Context, path string, interval time.Duration, opts FetchOptions) error {
+	return m.fetchPeriodically(ctx, path, interval, opts, nil)
+}
+
+// fetchPeriodically is FetchPeriodically with a hook run after each round,
+// which the daemon uses to refresh the status it serves
+func (m *Manager) fetchPeriodically(ctx context.Context, path string, interval time.Duration, opts FetchOptions, afterFetch func()) error {
+	if interval <= 0 {
+		return fmt.Errorf("fetch interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.reloadMu.Lock()
+		err := m.FetchNodes(path, opts)
+		m.reloadMu.Unlock()
+		if err != nil {
+			m.logProvider.Warn(fmt.Sprintf("Scheduled fetch failed: %v", err))
+		}
+		if afterFetch != nil {
+			afterFetch()
+		}
+		m.uiProvider.Info(fmt.Sprintf("⏰ Next fetch in %s", interval))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetchInterval returns git.fetch_interval, or 0 when scheduled fetches are off
+func (m *Manager) fetchInterval() time.Duration {
+	interval, err := time.ParseDuration(m.setting("git.fetch_interval", ""))
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return interval
+}
+
+// fetchStaleAfter returns how old remote information may get before status flags it
+func (m *Manager) fetchStaleAfter() time.Duration {
+	staleAfter, err := time.ParseDuration(m.setting("git.fetch_stale_after", ""))
+	if err != nil || staleAfter <= 0 {
+		return defaultFetchStaleAfter
+	}
+	return staleAfter
+}
+
+// recordFetches stores successful fetch times by node path in the state file
+func (m *Manager) recordFetches(fetched map[string]time.Time) error {
+	if len(fetched) == 0 {
+		return nil
+	}
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+	if state.Fetches == nil {
+		state.Fetches = make(map[string]string)
+	}
+	for path, at := range fetched {
+		state.Fetches[path] = at.UTC().Format(time.RFC3339)
+	}
+	return m.saveState(state)
+}
+
+// loadFetchTimes reads the recorded fetch times from the state file
+func (m *Manager) loadFetchTimes() map[string]time.Time {
+	times := make(map[string]time.Time)
+	state, err := m.loadState()
+	if err != nil {
+		return times
+	}
+	for path, value := range state.Fetches {
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			times[path] = at
+		}
+	}
+	return times
+}
+
+// lastFetch returns when a repository was last fetched. The success times
+// muno records win; fetches made outside muno are picked up from the
+// FETCH_HEAD git writes, unless it is empty as a failed fetch leaves it.
+func (m *Manager) lastFetch(nodePath string, fsPath string) (time.Time, bool) {
+	if recorded, ok := m.fetchTimes[nodePath]; ok {
+		return recorded, true
+	}
+	gitDir := tree.GitDir(fsPath)
+	if gitDir == "" {
+		return time.Time{}, false
+	}
+	if info, err := os.Stat(filepath.Join(gitDir, "FETCH_HEAD")); err == nil && info.Size() > 0 {
+		return info.ModTime(), true
+	}
+	return time.Time{}, false
+}
+
+// freshnessSuffix describes commits waiting upstream and the age of the remote
+// information they are based on, for status lines
+func (m *Manager) freshnessSuffix(nodePath string, fsPath string, behind int) string {
+	suffix := ""
+	if behind > 0 {
+		suffix += fmt.Sprintf(" ⬇️ %d behind", behind)
+	}
+
+	if _, err := os.Stat(filepath.Join(fsPath, ".git")); err != nil {
+		// Not a repository, e.g. the workspace root
+		return suffix
+	}
+	at, ok := m.lastFetch(nodePath, fsPath)
+	if !ok {
+		return suffix + " [never fetched]"
+	}
+	age := time.Since(at)
+	if age > m.fetchStaleAfter() {
+		return suffix + fmt.Sprintf(" [⚠️ fetched %s ago]", formatAge(age))
+	}
+	return suffix + fmt.Sprintf(" [fetched %s ago]", formatAge(age))
+}
+
+// formatAge renders a duration in its largest whole unit, e.g. "5m" or "3d"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
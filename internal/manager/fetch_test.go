@@ -0,0 +1,146 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func TestFetchNodes_AllRecordsFetchTimes(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+	gitMock := mgr.gitProvider.(*mocks.MockGitProvider)
+	gitMock.SetError("fetch", filepath.Join(tmpDir, ".nodes", "web"), errors.New("network down"))
+
+	require.NoError(t, mgr.FetchNodes("", FetchOptions{Recursive: true, Prune: true}))
+
+	calls := gitMock.GetCalls()
+	assert.Contains(t, calls, "Fetch("+filepath.Join(tmpDir, ".nodes", "api")+")")
+	assert.Contains(t, calls, "Fetch("+filepath.Join(tmpDir, ".nodes", "web")+")")
+	assert.NotContains(t, calls, "Fetch("+filepath.Join(tmpDir, ".nodes", "docs")+")")
+
+	output := strings.Join(ui.GetMessages(), "\n")
+	assert.Contains(t, output, "📊 Results: 1 succeeded, 1 failed")
+	assert.Contains(t, output, "   - web")
+
+	// Only the successful fetch is recorded
+	state, err := config.LoadState(filepath.Join(tmpDir, config.GetStateFileName()))
+	require.NoError(t, err)
+	assert.Contains(t, state.Fetches, "/api")
+	assert.NotContains(t, state.Fetches, "/web")
+}
+
+func TestFetchNodes_SingleNode(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	mgr.uiProvider = mocks.NewMockUIProvider()
+
+	require.NoError(t, mgr.FetchNodes("/api", FetchOptions{}))
+
+	calls := mgr.gitProvider.(*mocks.MockGitProvider).GetCalls()
+	assert.Equal(t, []string{"Fetch(" + filepath.Join(tmpDir, ".nodes", "api") + ")"}, calls)
+}
+
+func TestFetchPeriodically_StopsWithContext(t *testing.T) {
+	mgr, _ := setupContextWorkspace(t)
+	mgr.uiProvider = mocks.NewMockUIProvider()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rounds := 0
+	err := mgr.fetchPeriodically(ctx, "/api", time.Millisecond, FetchOptions{}, func() {
+		rounds++
+		if rounds == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, rounds)
+
+	assert.Error(t, mgr.FetchPeriodically(context.Background(), "/api", 0, FetchOptions{}))
+}
+
+func TestStatusNode_ShowsFreshness(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+	gitMock := mgr.gitProvider.(*mocks.MockGitProvider)
+	gitMock.SetStatus(filepath.Join(tmpDir, ".nodes", "api"), &interfaces.GitStatus{Branch: "main", IsClean: true, Behind: 3})
+
+	require.NoError(t, mgr.StatusNode("/api", false))
+	assert.Contains(t, ui.GetMessages(), "INFO: api: branch=main (clean) ⬇️ 3 behind [never fetched]")
+
+	// A failed fetch leaves an empty FETCH_HEAD behind
+	fetchHead := filepath.Join(tmpDir, ".nodes", "api", ".git", "FETCH_HEAD")
+	writeTestFile(t, fetchHead, "")
+	ui.Reset()
+	require.NoError(t, mgr.StatusNode("/api", false))
+	assert.Contains(t, ui.GetMessages(), "INFO: api: branch=main (clean) ⬇️ 3 behind [never fetched]")
+
+	// A fetch made outside muno fills FETCH_HEAD
+	writeTestFile(t, fetchHead, "0123456789abcdef\t\tbranch 'main' of origin\n")
+	require.NoError(t, mgr.StatusNode("", true))
+	assert.Contains(t, ui.GetMessages(), "INFO: api: branch=main (clean) ⬇️ 3 behind [fetched <1m ago]")
+
+	// Old fetches are flagged as stale
+	require.NoError(t, mgr.recordFetches(map[string]time.Time{"/api": time.Now().Add(-72 * time.Hour)}))
+	require.NoError(t, mgr.StatusNode("/api", false))
+	assert.Contains(t, ui.GetMessages(), "INFO: api: branch=main (clean) ⬇️ 3 behind [⚠️ fetched 3d ago]")
+}
+
+func TestFetchSettings(t *testing.T) {
+	mgr, _ := setupContextWorkspace(t)
+	mgr.configResolver = config.NewConfigResolver(config.GetDefaults())
+	assert.Equal(t, time.Duration(0), mgr.fetchInterval())
+	assert.Equal(t, 24*time.Hour, mgr.fetchStaleAfter())
+
+	mgr.configResolver.SetCLIConfig(map[string]interface{}{
+		"git": map[string]interface{}{"fetch_interval": "15m", "fetch_stale_after": "2h"},
+	})
+	assert.Equal(t, 15*time.Minute, mgr.fetchInterval())
+	assert.Equal(t, 2*time.Hour, mgr.fetchStaleAfter())
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "<1m", formatAge(30*time.Second))
+	assert.Equal(t, "5m", formatAge(5*time.Minute+10*time.Second))
+	assert.Equal(t, "2h", formatAge(150*time.Minute))
+	assert.Equal(t, "3d", formatAge(80*time.Hour))
+}
+
+func TestLastFetch_PrefersRecordedFetches(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	apiDir := filepath.Join(tmpDir, ".nodes", "api")
+	recorded := time.Now().Add(-time.Hour).Truncate(time.Second)
+	mgr.fetchTimes = map[string]time.Time{"/api": recorded}
+
+	at, ok := mgr.lastFetch("/api", apiDir)
+	require.True(t, ok)
+	assert.Equal(t, recorded, at)
+
+	// A newer FETCH_HEAD may come from a fetch that failed
+	writeTestFile(t, filepath.Join(apiDir, ".git", "FETCH_HEAD"), "0123456789abcdef\n")
+	at, _ = mgr.lastFetch("/api", apiDir)
+	assert.Equal(t, recorded, at)
+
+	_, ok = mgr.lastFetch("/web", filepath.Join(tmpDir, ".nodes", "web"))
+	assert.False(t, ok)
+
+	// Linked worktrees and submodules point to their git directory from a .git file
+	worktree := filepath.Join(tmpDir, ".nodes", "wt")
+	writeTestFile(t, filepath.Join(worktree, ".git"), "gitdir: ../../gitdirs/wt\n")
+	writeTestFile(t, filepath.Join(tmpDir, "gitdirs", "wt", "FETCH_HEAD"), "")
+	_, ok = mgr.lastFetch("/wt", worktree)
+	assert.False(t, ok, "an empty FETCH_HEAD is no fetch")
+	writeTestFile(t, filepath.Join(tmpDir, "gitdirs", "wt", "FETCH_HEAD"), "0123456789abcdef\n")
+	_, ok = mgr.lastFetch("/wt", worktree)
+	assert.True(t, ok)
+}
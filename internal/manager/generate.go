@@ -0,0 +1,252 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/plugin"
+)
+
+// generateResult is the outcome of evaluating one generate: node
+type generateResult struct {
+	Path    string
+	Source  string
+	Total   int
+	Added   []string
+	Removed []string
+	Err     error
+}
+
+// Sync evaluates the source of every generate: node in the workspace again
+// and rewrites its generated config, so repositories added to or removed
+// from a listing show up in the tree
+func (m *Manager) Sync() (err error) {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.journalOperation("sync")(&err)
+
+	results := m.generateNodes(m.config, "/", config.ConfigDir(m.workspaceConfigPath()), true, make(map[string]bool))
+	if len(results) == 0 {
+		m.uiProvider.Info("📭 No generate: nodes to sync")
+		return nil
+	}
+
+	m.uiProvider.Info("🔄 Syncing generated nodes...")
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			m.uiProvider.Error(fmt.Sprintf("   ❌ %s: %v", r.Path, r.Err))
+			m.logNodeFailure("sync", r.Path, r.Err)
+			continue
+		}
+		m.uiProvider.Success(fmt.Sprintf("   📦 %s: %d repositories from %s (%d added, %d removed)", r.Path, r.Total, r.Source, len(r.Added), len(r.Removed)))
+		for _, name := range r.Added {
+			m.uiProvider.Info(fmt.Sprintf("      + %s", name))
+		}
+		for _, name := range r.Removed {
+			m.uiProvider.Info(fmt.Sprintf("      - %s", name))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d generate: nodes failed", failed, len(results))
+	}
+	return nil
+}
+
+// generateMissing evaluates the workspace's generate: nodes that have no
+// generated config yet, so a freshly added node has children right away
+func (m *Manager) generateMissing() {
+	if m.config == nil {
+		return
+	}
+	configDir := config.ConfigDir(m.workspaceConfigPath())
+	for _, r := range m.generateNodes(m.config, "/", configDir, false, nil) {
+		if r.Err != nil {
+			m.logProvider.Warn(fmt.Sprintf("Failed to generate %s: %v", r.Path, r.Err))
+		}
+	}
+}
+
+// generateNodes evaluates the generate: nodes of a config at treePath.
+// With all set every node is evaluated and the configs below are visited
+// too; otherwise only nodes without a generated config are.
+func (m *Manager) generateNodes(cfg *config.ConfigTree, treePath, configDir string, all bool, visited map[string]bool) []generateResult {
+	var results []generateResult
+	for _, nodeDef := range cfg.Nodes {
+		childPath := path.Join(treePath, nodeDef.Name)
+		if nodeDef.Generate != nil {
+			if _, err := os.Stat(config.GeneratedConfigPath(configDir, nodeDef.Name)); all || err != nil {
+				results = append(results, m.generateNode(nodeDef, childPath, configDir, cfg.Templates))
+			}
+		}
+		if !all {
+			continue
+		}
+		childConfigPath := m.childConfigPath(nodeDef, childPath, configDir)
+		if childConfigPath == "" || visited[childConfigPath] {
+			continue
+		}
+		visited[childConfigPath] = true
+		if childCfg, err := config.LoadTree(childConfigPath); err == nil {
+			results = append(results, m.generateNodes(childCfg, childPath, config.ConfigDir(childConfigPath), all, visited)...)
+		}
+	}
+	return results
+}
+
+// generateNode reads the repositories of a generate: node and writes its
+// generated config. The config always goes to the generated config path
+// next to the defining config, never to a file the node names.
+func (m *Manager) generateNode(nodeDef config.NodeDefinition, treePath, configDir string, templates map[string]config.NodeTemplate) generateResult {
+	spec := nodeDef.Generate
+	result := generateResult{Path: treePath, Source: spec.Source()}
+	file := config.GeneratedConfigPath(configDir, nodeDef.Name)
+
+	repos, err := m.generatedRepos(spec, configDir)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	generated, err := config.GenerateTree(nodeDef.Name, spec, repos, templates)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	before := make(map[string]bool)
+	if previous, err := config.LoadTree(file); err == nil {
+		for _, node := range previous.Nodes {
+			before[node.Name] = true
+		}
+	}
+	for _, node := range generated.Nodes {
+		if !before[node.Name] {
+			result.Added = append(result.Added, node.Name)
+		}
+		delete(before, node.Name)
+	}
+	for name := range before {
+		result.Removed = append(result.Removed, name)
+	}
+	sort.Strings(result.Removed)
+	result.Total = len(generated.Nodes)
+
+	data, err := config.Marshal(config.FormatYAML, generated)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	header := fmt.Sprintf("# Generated by muno sync from %s; changes are overwritten.\n", spec.Source())
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		result.Err = err
+		return result
+	}
+	if err := os.WriteFile(file, append([]byte(header), data...), 0644); err != nil {
+		result.Err = fmt.Errorf("writing %s: %w", file, err)
+	}
+	return result
+}
+
+// generatedRepos reads the repositories listed by a generate: source.
+// Relative list and dir paths are resolved from the defining config.
+func (m *Manager) generatedRepos(spec *config.GenerateSpec, configDir string) ([]config.GeneratedRepo, error) {
+	resolve := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(configDir, p)
+	}
+	switch {
+	case spec.List != "":
+		return config.ReadRepoListing(resolve(spec.List))
+	case spec.Dir != "":
+		return config.ScanRepoDir(resolve(spec.Dir))
+	default:
+		return m.pluginCatalog(spec)
+	}
+}
+
+// pluginCatalog runs the catalog command of a plugin. The command returns
+// the repositories in its result data, in the form ParseRepoCatalog reads.
+func (m *Manager) pluginCatalog(spec *config.GenerateSpec) ([]config.GeneratedRepo, error) {
+	ctx := context.Background()
+	pm := m.pluginManager
+	if pm == nil {
+		created, err := plugin.NewPluginManager()
+		if err != nil {
+			return nil, fmt.Errorf("starting plugins: %w", err)
+		}
+		if _, err := created.DiscoverPlugins(ctx); err != nil {
+			return nil, fmt.Errorf("discovering plugins: %w", err)
+		}
+		// Nothing else holds this manager, so its plugin processes go with it
+		var loaded []string
+		defer func() {
+			for _, name := range loaded {
+				if err := created.UnloadPlugin(ctx, name); err != nil {
+					m.logProvider.Warn("Failed to unload plugin",
+						interfaces.Field{Key: "plugin", Value: name},
+						interfaces.Field{Key: "error", Value: err})
+				}
+			}
+		}()
+		if err := created.LoadPlugin(ctx, spec.Plugin); err != nil {
+			return nil, fmt.Errorf("loading plugin %s: %w", spec.Plugin, err)
+		}
+		loaded = append(loaded, spec.Plugin)
+		pm = created
+	} else if !pm.IsLoaded(spec.Plugin) {
+		if err := pm.LoadPlugin(ctx, spec.Plugin); err != nil {
+			return nil, fmt.Errorf("loading plugin %s: %w", spec.Plugin, err)
+		}
+	}
+	p, err := pm.GetPlugin(spec.Plugin)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", spec.Plugin, err)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("plugin %s is not loaded", spec.Plugin)
+	}
+
+	// The command runs on the named plugin even when another plugin
+	// registers a command of the same name
+	command := spec.Command
+	if command == "" {
+		command = "catalog"
+	}
+	result, err := p.Execute(ctx, command, spec.Args, interfaces.PluginEnvironment{
+		WorkspacePath: m.workspace,
+		Variables:     map[string]string{},
+		Config:        map[string]interface{}{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", spec.Plugin, err)
+	}
+	if !result.Success {
+		if result.Error != "" {
+			return nil, fmt.Errorf("plugin %s: %s", spec.Plugin, result.Error)
+		}
+		return nil, fmt.Errorf("plugin %s: %s", spec.Plugin, result.Message)
+	}
+
+	// Result data crosses the plugin boundary in whatever shape the RPC
+	// codec produced; a JSON round trip normalises it
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", spec.Plugin, err)
+	}
+	var catalog interface{}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", spec.Plugin, err)
+	}
+	return config.ParseRepoCatalog(catalog)
+}
@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func TestSync_GenerateNodes(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "muno.yaml")
+	writeTestFile(t, configPath, `workspace:
+  name: acme
+  repos_dir: .nodes
+nodes:
+  - name: services
+    generate:
+      list: repos.txt
+      include: ["^svc-"]
+      name: '{{.Name | trimPrefix "svc-"}}'
+      fetch: lazy
+  - name: catalog
+    generate:
+      plugin: github
+      args: [acme]
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "repos.txt"), "git@github.com:acme/svc-api.git\ngit@github.com:acme/svc-web.git\ngit@github.com:acme/tools.git\n")
+	cfg, err := config.LoadTree(configPath)
+	require.NoError(t, err)
+
+	mgr := CreateTestManagerWithConfig(t, tmpDir, cfg)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+	plugins := mocks.NewMockPluginManager()
+	github := &catalogPlugin{result: interfaces.Result{Success: true, Data: []interface{}{
+		map[string]interface{}{"name": "docs", "url": "https://github.com/acme/docs.git"},
+	}}}
+	plugins.SetPlugin("github", github)
+	// Another plugin's catalog command must not answer for github
+	plugins.SetCommandResult("catalog", interfaces.Result{Success: false, Error: "wrong plugin"})
+	mgr.pluginManager = plugins
+
+	require.NoError(t, mgr.Sync())
+	assert.Contains(t, ui.GetMessages(), "SUCCESS:    📦 /services: 2 repositories from repos.txt (2 added, 0 removed)")
+	assert.Contains(t, plugins.GetCalls(), "GetPlugin(github)")
+	assert.NotContains(t, plugins.GetCalls(), "ExecuteCommand(catalog)")
+	assert.Equal(t, []string{"catalog acme"}, github.calls[:1])
+
+	generated, err := config.LoadTree(cfg.Nodes[0].File)
+	require.NoError(t, err)
+	require.Len(t, generated.Nodes, 2)
+	assert.Equal(t, "api", generated.Nodes[0].Name)
+	assert.Equal(t, "lazy", generated.Nodes[0].Fetch)
+	assert.Equal(t, "https://github.com/acme/docs.git", mgr.nodeDefinition("/catalog/docs").URL)
+
+	// Listing changes show up on the next sync
+	writeTestFile(t, filepath.Join(tmpDir, "repos.txt"), "git@github.com:acme/svc-api.git\ngit@github.com:acme/svc-db.git\n")
+	ui.Reset()
+	require.NoError(t, mgr.Sync())
+	assert.Contains(t, ui.GetMessages(), "SUCCESS:    📦 /services: 2 repositories from repos.txt (1 added, 1 removed)")
+	assert.Contains(t, ui.GetMessages(), "INFO:       + db")
+	assert.Contains(t, ui.GetMessages(), "INFO:       - web")
+
+	github.result = interfaces.Result{Success: false, Error: "rate limited"}
+	ui.Reset()
+	assert.ErrorContains(t, mgr.Sync(), "1 of 2 generate: nodes failed")
+	assert.Contains(t, ui.GetMessages(), "ERROR:    ❌ /catalog: plugin github: rate limited")
+}
+
+// catalogPlugin answers every command with a fixed result and records the
+// commands it ran
+type catalogPlugin struct {
+	result interfaces.Result
+	calls  []string
+}
+
+func (p *catalogPlugin) Metadata() interfaces.PluginMetadata {
+	return interfaces.PluginMetadata{Name: "github"}
+}
+
+func (p *catalogPlugin) Commands() []interfaces.CommandDefinition {
+	return []interfaces.CommandDefinition{{Name: "catalog"}}
+}
+
+func (p *catalogPlugin) Execute(ctx context.Context, cmd string, args []string, env interfaces.PluginEnvironment) (interfaces.Result, error) {
+	p.calls = append(p.calls, strings.Join(append([]string{cmd}, args...), " "))
+	return p.result, nil
+}
+
+func (p *catalogPlugin) Initialize(config map[string]interface{}) error { return nil }
+func (p *catalogPlugin) Cleanup() error                                 { return nil }
+func (p *catalogPlugin) HealthCheck(ctx context.Context) error          { return nil }
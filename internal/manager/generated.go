@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// generatedRegistryFile records the files generated from the tree (go.work
+// and IDE workspaces) so they can be regenerated when the tree changes
+const generatedRegistryFile = "generated.json"
+
+// Kinds of generated files besides the IDE names
+const generatedGoWork = "gowork"
+
+// generatedRecord is one generated file entry in .muno/generated.json
+type generatedRecord struct {
+	Kind        string `json:"kind"` // gowork, vscode or jetbrains
+	Path        string `json:"path"` // Tree path the file was generated for
+	Recursive   bool   `json:"recursive,omitempty"`
+	IncludeLazy bool   `json:"include_lazy,omitempty"`
+}
+
+// generatedRegistryPath returns the path of the generated file registry
+func (m *Manager) generatedRegistryPath() string {
+	return filepath.Join(m.workspace, ".muno", generatedRegistryFile)
+}
+
+// loadGeneratedRegistry loads the recorded generated files
+func (m *Manager) loadGeneratedRegistry() []generatedRecord {
+	data, err := os.ReadFile(m.generatedRegistryPath())
+	if err != nil {
+		return nil
+	}
+	var records []generatedRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil
+	}
+	return records
+}
+
+// registerGenerated records a generated file, replacing the entry of the
+// same kind for the same path
+func (m *Manager) registerGenerated(record generatedRecord) error {
+	records := []generatedRecord{}
+	for _, existing := range m.loadGeneratedRegistry() {
+		if existing.Kind != record.Kind || existing.Path != record.Path {
+			records = append(records, existing)
+		}
+	}
+	return m.saveGeneratedRegistry(append(records, record))
+}
+
+// saveGeneratedRegistry writes the registry
+func (m *Manager) saveGeneratedRegistry(records []generatedRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := m.fsProvider.MkdirAll(filepath.Dir(m.generatedRegistryPath()), 0755); err != nil {
+		return err
+	}
+	return m.fsProvider.WriteFile(m.generatedRegistryPath(), data, 0644)
+}
+
+// syncGeneratedFiles regenerates the recorded files (go.work, IDE
+// workspaces) whose subtree overlaps changedPath after nodes under it are
+// added, removed or cloned
+func (m *Manager) syncGeneratedFiles(changedPath string) {
+	if !m.initialized {
+		return
+	}
+	records := m.loadGeneratedRegistry()
+	kept := make([]generatedRecord, 0, len(records))
+	for _, record := range records {
+		if _, err := m.treeProvider.GetNode(record.Path); err != nil {
+			// The node the file was generated for is gone
+			m.logProvider.Debug("Dropping " + record.Kind + " record for removed node " + record.Path)
+			continue
+		}
+		kept = append(kept, record)
+		if !treePathsOverlap(record.Path, changedPath) {
+			continue
+		}
+		if record.IncludeLazy {
+			m.cloneLazySubtree(record.Path)
+		}
+		var err error
+		if record.Kind == generatedGoWork {
+			_, _, err = m.writeGoWork(record.Path, GoWorkOptions{Recursive: record.Recursive})
+		} else {
+			_, _, err = m.writeIDEWorkspace(record.Kind, record.Path)
+		}
+		if err != nil {
+			m.logProvider.Warn("Failed to regenerate " + record.Kind + " for " + record.Path + ": " + err.Error())
+		} else {
+			m.logProvider.Debug("Regenerated " + record.Kind + " for " + record.Path)
+		}
+	}
+	if len(kept) != len(records) {
+		if err := m.saveGeneratedRegistry(kept); err != nil {
+			m.logProvider.Warn("Failed to update generated file registry: " + err.Error())
+		}
+	}
+}
+
+// cloneLazySubtree clones the lazy nodes under targetPath, for generated
+// files recorded with IncludeLazy
+func (m *Manager) cloneLazySubtree(targetPath string) {
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return
+	}
+	for _, child := range node.Children {
+		if err := m.visitNodeForClone(child, true, true); err != nil {
+			m.logProvider.Warn("Failed to clone " + child.Path + ": " + err.Error())
+		}
+	}
+}
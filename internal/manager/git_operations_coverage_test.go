@@ -83,6 +83,54 @@ func (g *GitProviderStub) Fetch(path string, options interfaces.FetchOptions) er
 	return nil
 }
 
+func (g *GitProviderStub) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	return nil
+}
+
+func (g *GitProviderStub) Stash(path string, message string) (bool, error) {
+	return false, nil
+}
+
+func (g *GitProviderStub) StashPop(path string) error {
+	return nil
+}
+
+func (g *GitProviderStub) DiffStat(path string, options interfaces.DiffOptions) ([]interfaces.DiffStat, error) {
+	return nil, nil
+}
+
+func (g *GitProviderStub) CheckoutNew(path string, branch string) error {
+	return nil
+}
+
+func (g *GitProviderStub) DeleteBranch(path string, branch string) error {
+	return nil
+}
+
+func (g *GitProviderStub) Log(path string, options interfaces.LogOptions) ([]interfaces.LogEntry, error) {
+	return nil, nil
+}
+
+func (g *GitProviderStub) RemoteBranch(path string, remote string, branch string) (string, error) {
+	return "", nil
+}
+
+func (g *GitProviderStub) CreateBundle(path string, file string, options interfaces.LogOptions) error {
+	return nil
+}
+
+func (g *GitProviderStub) FetchBundle(path string, file string, branch string) error {
+	return nil
+}
+
+func (g *GitProviderStub) FormatPatch(path string, dir string, options interfaces.LogOptions) ([]string, error) {
+	return nil, nil
+}
+
+func (g *GitProviderStub) ApplyPatches(path string, files []string) error {
+	return nil
+}
+
 func (g *GitProviderStub) Add(path string, files []string) error {
 	return nil
 }
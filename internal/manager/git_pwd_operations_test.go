@@ -198,12 +198,13 @@ func TestCommitNode_EmptyPathFromReposDir(t *testing.T) {
 	}
 }
 
-// TestCommitNode_RecursiveFlag tests commit with recursive flag (though it doesn't seem to use it)
+// TestCommitNode_RecursiveFlag tests commit with recursive flag, which only commits changed repositories
 func TestCommitNode_RecursiveFlag(t *testing.T) {
 	tmpDir := t.TempDir()
 	mgr := CreateTestManager(t, tmpDir)
 
 	gitStub := NewGitProviderStub()
+	gitStub.statusResult = &interfaces.GitStatus{Branch: "main", HasStaged: true}
 	mgr.gitProvider = gitStub
 
 	node := CreateSimpleNode("backend", "https://github.com/test/backend.git")
@@ -212,7 +213,7 @@ func TestCommitNode_RecursiveFlag(t *testing.T) {
 	backendPath := filepath.Join(tmpDir, ".nodes", "backend")
 	os.MkdirAll(backendPath, 0755)
 
-	// Test commit with recursive flag (currently has no effect)
+	// Test commit with recursive flag
 	err := mgr.CommitNode("/backend", "test message", true)
 	if err != nil {
 		t.Errorf("CommitNode with recursive flag failed: %v", err)
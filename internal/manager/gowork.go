@@ -0,0 +1,273 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// goWorkHeader marks go.work files managed by muno. The options they were
+// generated with are kept in the generated file registry for re-syncs.
+const goWorkHeader = "// Code generated by muno gowork. DO NOT EDIT the use block."
+
+// goWorkDefaultVersion is used when neither an existing go.work nor any module declares a version
+const goWorkDefaultVersion = "1.21"
+
+// goWorkSkipDirs are never searched for go.mod files. The nodes directory is
+// skipped too; child nodes are only included as nodes.
+var goWorkSkipDirs = map[string]bool{
+	".git":         true,
+	"vendor":       true,
+	"node_modules": true,
+	"testdata":     true,
+}
+
+// GoWorkOptions controls go.work generation
+type GoWorkOptions struct {
+	Recursive bool // Include modules from the whole subtree, not just direct children
+}
+
+// GenerateGoWork writes a go.work file in the directory of the node at path
+// (workspace root by default) that uses every Go module found in cloned nodes
+func (m *Manager) GenerateGoWork(path string, opts GoWorkOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	targetPath := path
+	if targetPath == "" {
+		targetPath = "/"
+	}
+
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return fmt.Errorf("getting node: %w", err)
+	}
+
+	goWorkPath, modules, err := m.writeGoWork(node.Path, opts)
+	if err != nil {
+		return err
+	}
+	if err := m.registerGenerated(generatedRecord{Kind: generatedGoWork, Path: node.Path, Recursive: opts.Recursive}); err != nil {
+		m.logProvider.Warn(fmt.Sprintf("Failed to record go.work: %v", err))
+	}
+
+	m.uiProvider.Success(fmt.Sprintf("✅ Generated %s", goWorkPath))
+	if len(modules) == 0 {
+		m.uiProvider.Info("   No Go modules found in cloned nodes")
+	}
+	for _, module := range modules {
+		m.uiProvider.Info(fmt.Sprintf("   use %s", module))
+	}
+	m.metricsProvider.Counter("manager.gowork", 1)
+
+	return nil
+}
+
+// writeGoWork collects modules under the node at targetPath and (re)writes its go.work
+func (m *Manager) writeGoWork(targetPath string, opts GoWorkOptions) (string, []string, error) {
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("getting node: %w", err)
+	}
+	baseDir := m.computeFilesystemPath(node.Path)
+	goWorkPath := filepath.Join(baseDir, "go.work")
+
+	modules, goVersion := m.collectGoModules(node, baseDir, opts.Recursive)
+
+	var existing string
+	if data, err := os.ReadFile(goWorkPath); err == nil {
+		existing = string(data)
+	}
+
+	content := renderGoWork(existing, modules, goVersion)
+	if err := m.fsProvider.WriteFile(goWorkPath, []byte(content), 0644); err != nil {
+		return "", nil, fmt.Errorf("writing go.work: %w", err)
+	}
+
+	for _, entry := range []string{"go.work", "go.work.sum"} {
+		if err := m.ensureGitignoreEntry(baseDir, entry); err != nil {
+			m.logProvider.Debug(fmt.Sprintf("Could not add '%s' to .gitignore: %v", entry, err))
+		}
+	}
+
+	return goWorkPath, modules, nil
+}
+
+// collectGoModules finds go.mod files in the node's own directory and in cloned
+// child nodes, returning module directories relative to baseDir and the highest
+// go version declared by any of them
+func (m *Manager) collectGoModules(node interfaces.NodeInfo, baseDir string, recursive bool) ([]string, string) {
+	dirs := []string{baseDir}
+
+	var repos []interfaces.NodeInfo
+	if recursive {
+		for _, child := range node.Children {
+			repos = append(repos, m.collectRepos(child, false)...)
+		}
+	} else {
+		repos = node.Children
+	}
+	for _, repo := range repos {
+		if repo.IsCloned {
+			dirs = append(dirs, m.computeFilesystemPath(repo.Path))
+		}
+	}
+
+	nodesDir := m.getNodesDir()
+	seen := make(map[string]bool)
+	var modules []string
+	goVersion := ""
+
+	for _, dir := range dirs {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				if path != dir && (goWorkSkipDirs[info.Name()] || info.Name() == nodesDir) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.Name() != "go.mod" {
+				return nil
+			}
+
+			rel, err := filepath.Rel(baseDir, filepath.Dir(path))
+			if err != nil {
+				return nil
+			}
+			use := "./" + filepath.ToSlash(rel)
+			if rel == "." {
+				use = "."
+			}
+			if !seen[use] {
+				seen[use] = true
+				modules = append(modules, use)
+			}
+
+			if data, err := os.ReadFile(path); err == nil {
+				if v := goDirectiveVersion(string(data)); compareGoVersions(v, goVersion) > 0 {
+					goVersion = v
+				}
+			}
+			return nil
+		})
+	}
+
+	sort.Strings(modules)
+	return modules, goVersion
+}
+
+// renderGoWork produces go.work content. The go, toolchain, godebug and replace
+// directives of an existing file are preserved; the use block is regenerated.
+func renderGoWork(existing string, modules []string, goVersion string) string {
+	existingVersion := goDirectiveVersion(existing)
+	if existingVersion != "" {
+		goVersion = existingVersion
+	}
+	if goVersion == "" {
+		goVersion = goWorkDefaultVersion
+	}
+
+	var b strings.Builder
+	b.WriteString(goWorkHeader + "\n\n")
+	fmt.Fprintf(&b, "go %s\n", goVersion)
+
+	if toolchain := goWorkDirectiveLines(existing, "toolchain"); len(toolchain) > 0 {
+		b.WriteString(toolchain[0] + "\n")
+	}
+	if godebug := goWorkDirectiveLines(existing, "godebug"); len(godebug) > 0 {
+		b.WriteString("\n")
+		for _, line := range godebug {
+			b.WriteString(line + "\n")
+		}
+	}
+
+	b.WriteString("\nuse (\n")
+	for _, module := range modules {
+		fmt.Fprintf(&b, "\t%s\n", module)
+	}
+	b.WriteString(")\n")
+
+	if replaces := goWorkDirectiveLines(existing, "replace"); len(replaces) > 0 {
+		b.WriteString("\n")
+		for _, line := range replaces {
+			b.WriteString(line + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// goDirectiveVersion returns the version of the go directive in a go.mod or go.work file
+func goDirectiveVersion(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "go" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// goWorkDirectiveLines returns the lines (including blocks) of a directive in a go.work file
+func goWorkDirectiveLines(content string, directive string) []string {
+	var lines []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inBlock {
+			lines = append(lines, line)
+			if trimmed == ")" {
+				inBlock = false
+			}
+			continue
+		}
+		fields := strings.Fields(trimmed)
+		if len(fields) == 0 || fields[0] != directive {
+			continue
+		}
+		lines = append(lines, trimmed)
+		if strings.HasSuffix(trimmed, "(") {
+			inBlock = true
+		}
+	}
+	return lines
+}
+
+// compareGoVersions compares dotted go versions such as 1.21 and 1.22.3
+func compareGoVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if b == "" {
+		return 1
+	}
+	if a == "" {
+		return -1
+	}
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			fmt.Sscanf(as[i], "%d", &x)
+		}
+		if i < len(bs) {
+			fmt.Sscanf(bs[i], "%d", &y)
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
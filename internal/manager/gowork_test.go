@@ -0,0 +1,149 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+)
+
+func setupGoWorkWorkspace(t *testing.T) (*Manager, string) {
+	mgr, tmpDir := CreateTestNodeWorkspace(t, "test",
+		config.NodeDefinition{Name: "api", URL: "https://github.com/acme/api.git"},
+		config.NodeDefinition{Name: "lib", URL: "https://github.com/acme/lib.git"},
+		config.NodeDefinition{Name: "docs", URL: "https://github.com/acme/docs.git"},
+	)
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "api", "go.mod"), "module github.com/acme/api\n\ngo 1.22\n")
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "lib", "go.mod"), "module github.com/acme/lib\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "lib", "tools", "go.mod"), "module github.com/acme/lib/tools\n\ngo 1.23.1\n")
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "lib", "vendor", "x", "go.mod"), "module x\n")
+
+	return mgr, tmpDir
+}
+
+func TestGenerateGoWork(t *testing.T) {
+	mgr, tmpDir := setupGoWorkWorkspace(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755))
+
+	require.NoError(t, mgr.GenerateGoWork("", GoWorkOptions{}))
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "go.work"))
+	require.NoError(t, err)
+	content := string(data)
+
+	assert.Contains(t, content, goWorkHeader)
+	assert.Contains(t, content, "go 1.23.1\n")
+	assert.Contains(t, content, "use (\n\t./.nodes/api\n\t./.nodes/lib\n\t./.nodes/lib/tools\n)\n")
+	assert.NotContains(t, content, "vendor")
+	assert.NotContains(t, content, "docs")
+
+	gitignore, err := os.ReadFile(filepath.Join(tmpDir, ".gitignore"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gitignore), "go.work\n")
+	assert.Contains(t, string(gitignore), "go.work.sum\n")
+}
+
+func TestGenerateGoWork_PreservesDirectives(t *testing.T) {
+	mgr, tmpDir := setupGoWorkWorkspace(t)
+	writeTestFile(t, filepath.Join(tmpDir, "go.work"), `go 1.20
+
+toolchain go1.22.5
+
+use ./old
+
+godebug (
+	default=go1.21
+)
+
+replace example.com/x => ../x
+replace (
+	example.com/y => ../y
+)
+`)
+
+	require.NoError(t, mgr.GenerateGoWork("/", GoWorkOptions{}))
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "go.work"))
+	require.NoError(t, err)
+	content := string(data)
+
+	assert.Contains(t, content, "go 1.20\n")
+	assert.Contains(t, content, "toolchain go1.22.5\n")
+	assert.Contains(t, content, "godebug (\n\tdefault=go1.21\n)\n")
+	assert.Contains(t, content, "replace example.com/x => ../x\n")
+	assert.Contains(t, content, "replace (\n\texample.com/y => ../y\n)\n")
+	assert.NotContains(t, content, "./old")
+}
+
+func TestGenerateGoWork_Recursive(t *testing.T) {
+	mgr, tmpDir := setupGoWorkWorkspace(t)
+	nested := CreateSimpleNode("svc", "https://github.com/acme/svc.git")
+	AddNodeToTree(mgr, "/api/svc", nested)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".nodes", "api", ".git"), 0755))
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "api", ".nodes", "svc", "go.mod"), "module github.com/acme/svc\n")
+
+	require.NoError(t, mgr.GenerateGoWork("", GoWorkOptions{}))
+	data, err := os.ReadFile(filepath.Join(tmpDir, "go.work"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "svc")
+
+	require.NoError(t, mgr.GenerateGoWork("", GoWorkOptions{Recursive: true}))
+	data, err = os.ReadFile(filepath.Join(tmpDir, "go.work"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\t./.nodes/api/.nodes/svc\n")
+	assert.Equal(t, []generatedRecord{{Kind: generatedGoWork, Path: "/", Recursive: true}}, mgr.loadGeneratedRegistry())
+}
+
+func TestGenerateGoWork_CustomNodesDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := CreateTestManagerWithConfig(t, tmpDir, &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "test", ReposDir: "repos"},
+	})
+	AddNodeToTree(mgr, "/api", CreateSimpleNode("api", "https://github.com/acme/api.git"))
+	writeTestFile(t, filepath.Join(tmpDir, "repos", "api", "go.mod"), "module github.com/acme/api\n")
+	writeTestFile(t, filepath.Join(tmpDir, "repos", "api", "repos", "svc", "go.mod"), "module github.com/acme/svc\n")
+
+	require.NoError(t, mgr.GenerateGoWork("", GoWorkOptions{}))
+	data, err := os.ReadFile(filepath.Join(tmpDir, "go.work"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\t./repos/api\n")
+	assert.NotContains(t, string(data), "svc")
+}
+
+func TestSyncGoWork(t *testing.T) {
+	mgr, tmpDir := setupGoWorkWorkspace(t)
+	goWorkPath := filepath.Join(tmpDir, "go.work")
+
+	// Hand-written go.work files are left alone
+	writeTestFile(t, goWorkPath, "go 1.22\n\nuse ./custom\n")
+	mgr.syncGeneratedFiles("/api")
+	data, err := os.ReadFile(goWorkPath)
+	require.NoError(t, err)
+	assert.Equal(t, "go 1.22\n\nuse ./custom\n", string(data))
+
+	// Generated ones pick up new modules
+	require.NoError(t, os.Remove(goWorkPath))
+	require.NoError(t, mgr.GenerateGoWork("", GoWorkOptions{}))
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "docs", "go.mod"), "module github.com/acme/docs\n")
+
+	mgr.syncGeneratedFiles("/docs")
+	data, err = os.ReadFile(goWorkPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\t./.nodes/docs\n")
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	assert.Equal(t, 0, compareGoVersions("1.22", "1.22"))
+	assert.Equal(t, 1, compareGoVersions("1.22.1", "1.22"))
+	assert.Equal(t, -1, compareGoVersions("1.9", "1.21"))
+	assert.Equal(t, 1, compareGoVersions("1.21", ""))
+	assert.Equal(t, -1, compareGoVersions("", "1.21"))
+}
+
+func TestGenerateGoWork_NotInitialized(t *testing.T) {
+	mgr := &Manager{}
+	assert.Error(t, mgr.GenerateGoWork("", GoWorkOptions{}))
+}
@@ -0,0 +1,649 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// Graph output formats
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+)
+
+// GraphEdge is a dependency from one node on another
+type GraphEdge struct {
+	To     string // Tree path of the dependency
+	Source string // How the dependency was found (declared, go.mod, package.json, pom.xml)
+}
+
+// GraphNode is a repository node in the dependency graph
+type GraphNode struct {
+	Name       string
+	Path       string
+	Repository string
+	IsCloned   bool
+	DependsOn  []GraphEdge
+}
+
+// DependencyGraph describes dependencies between repository nodes in the workspace
+type DependencyGraph struct {
+	Nodes map[string]*GraphNode // Keyed by tree path
+}
+
+// NewDependencyGraph creates an empty dependency graph
+func NewDependencyGraph() *DependencyGraph {
+	return &DependencyGraph{Nodes: make(map[string]*GraphNode)}
+}
+
+// AddNode adds a node to the graph, keeping an existing entry for the same path
+func (g *DependencyGraph) AddNode(node *GraphNode) {
+	if existing, ok := g.Nodes[node.Path]; ok {
+		if node.IsCloned {
+			existing.IsCloned = true
+		}
+		return
+	}
+	g.Nodes[node.Path] = node
+}
+
+// AddEdge records that from depends on to. Duplicate and self edges are ignored.
+func (g *DependencyGraph) AddEdge(from, to, source string) {
+	node, ok := g.Nodes[from]
+	if !ok || from == to {
+		return
+	}
+	if _, ok := g.Nodes[to]; !ok {
+		return
+	}
+	for _, edge := range node.DependsOn {
+		if edge.To == to {
+			return
+		}
+	}
+	node.DependsOn = append(node.DependsOn, GraphEdge{To: to, Source: source})
+	sort.Slice(node.DependsOn, func(i, j int) bool { return node.DependsOn[i].To < node.DependsOn[j].To })
+}
+
+// Paths returns all node paths in sorted order
+func (g *DependencyGraph) Paths() []string {
+	paths := make([]string, 0, len(g.Nodes))
+	for path := range g.Nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// TopologicalOrder returns node paths ordered so that every node comes after
+// the nodes it depends on. Ties are broken alphabetically for stable output.
+func (g *DependencyGraph) TopologicalOrder() ([]string, error) {
+	remaining := make(map[string]int, len(g.Nodes))
+	dependents := make(map[string][]string)
+	for path, node := range g.Nodes {
+		remaining[path] = len(node.DependsOn)
+		for _, edge := range node.DependsOn {
+			dependents[edge.To] = append(dependents[edge.To], path)
+		}
+	}
+
+	var ready []string
+	for path, count := range remaining {
+		if count == 0 {
+			ready = append(ready, path)
+		}
+	}
+	sort.Strings(ready)
+
+	order := make([]string, 0, len(g.Nodes))
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+
+		var unlocked []string
+		for _, dependent := range dependents[current] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				unlocked = append(unlocked, dependent)
+			}
+		}
+		ready = append(ready, unlocked...)
+		sort.Strings(ready)
+	}
+
+	if len(order) != len(g.Nodes) {
+		var cyclic []string
+		for path, count := range remaining {
+			if count > 0 {
+				cyclic = append(cyclic, path)
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, fmt.Errorf("dependency cycle detected between: %s", strings.Join(cyclic, ", "))
+	}
+
+	return order, nil
+}
+
+// Subgraph returns the nodes at paths and everything they transitively
+// depend on, so ordering them is not affected by unrelated parts of the
+// workspace
+func (g *DependencyGraph) Subgraph(paths []string) *DependencyGraph {
+	sub := NewDependencyGraph()
+	pending := append([]string(nil), paths...)
+	for len(pending) > 0 {
+		path := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		node, ok := g.Nodes[path]
+		if !ok || sub.Nodes[path] != nil {
+			continue
+		}
+		sub.Nodes[path] = node
+		for _, edge := range node.DependsOn {
+			pending = append(pending, edge.To)
+		}
+	}
+	return sub
+}
+
+// Dependents returns the node at path and every node that transitively depends on it
+func (g *DependencyGraph) Dependents(path string) []string {
+	reverse := make(map[string][]string)
+	for from, node := range g.Nodes {
+		for _, edge := range node.DependsOn {
+			reverse[edge.To] = append(reverse[edge.To], from)
+		}
+	}
+
+	if _, ok := g.Nodes[path]; !ok {
+		return nil
+	}
+
+	visited := map[string]bool{path: true}
+	queue := []string{path}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range reverse[current] {
+			if !visited[dependent] {
+				visited[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	result := make([]string, 0, len(visited))
+	for p := range visited {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Render renders the graph in the given format (dot or mermaid)
+func (g *DependencyGraph) Render(format string) (string, error) {
+	switch format {
+	case GraphFormatDOT, "":
+		return g.RenderDOT(), nil
+	case GraphFormatMermaid:
+		return g.RenderMermaid(), nil
+	default:
+		return "", fmt.Errorf("unsupported graph format: %s (use dot or mermaid)", format)
+	}
+}
+
+// RenderDOT renders the graph in Graphviz DOT format
+func (g *DependencyGraph) RenderDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph muno {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, path := range g.Paths() {
+		node := g.Nodes[path]
+		style := ""
+		if !node.IsCloned {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q%s];\n", path, node.Name, style)
+	}
+	for _, path := range g.Paths() {
+		for _, edge := range g.Nodes[path].DependsOn {
+			fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", path, edge.To, edge.Source)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderMermaid renders the graph as a Mermaid flowchart
+func (g *DependencyGraph) RenderMermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, path := range g.Paths() {
+		ids[path] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, path := range g.Paths() {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[path], g.Nodes[path].Name)
+	}
+	for _, path := range g.Paths() {
+		for _, edge := range g.Nodes[path].DependsOn {
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[path], edge.Source, ids[edge.To])
+		}
+	}
+	return b.String()
+}
+
+// BuildDependencyGraph builds the dependency graph for all repository nodes in the workspace.
+// Dependencies come from depends_on declarations in the configuration and from
+// go.mod, package.json and pom.xml manifests of cloned repositories.
+func (m *Manager) BuildDependencyGraph() (*DependencyGraph, error) {
+	if !m.initialized {
+		return nil, fmt.Errorf("manager not initialized")
+	}
+
+	root, err := m.treeProvider.GetNode("/")
+	if err != nil {
+		return nil, fmt.Errorf("getting root node: %w", err)
+	}
+
+	graph := NewDependencyGraph()
+
+	// Repository nodes known from the tree
+	for _, node := range m.collectRepos(root, false) {
+		graph.AddNode(&GraphNode{
+			Name:       node.Name,
+			Path:       node.Path,
+			Repository: node.Repository,
+			IsCloned:   node.IsCloned,
+		})
+	}
+
+	// Declared dependencies from configuration files
+	declared := make(map[string][]string)
+	if m.config != nil {
+		m.collectDeclaredDependencies(m.config.Nodes, "/", filepath.Dir(m.workspaceConfigPath()), declared, 0)
+	}
+	for from, deps := range declared {
+		for _, dep := range deps {
+			if to := graph.resolveReference(from, dep); to != "" {
+				graph.AddEdge(from, to, DepSourceDeclared)
+			}
+		}
+	}
+
+	// Detected dependencies from build manifests
+	m.detectManifestDependencies(graph)
+
+	m.metricsProvider.Counter("manager.build_graph", 1)
+	return graph, nil
+}
+
+// resolveReference resolves a depends_on entry to a node path. Entries may be
+// absolute tree paths, paths relative to the declaring node's parent, or node names.
+func (g *DependencyGraph) resolveReference(from string, ref string) string {
+	if strings.HasPrefix(ref, "/") {
+		if _, ok := g.Nodes[ref]; ok {
+			return ref
+		}
+		return ""
+	}
+
+	parent := filepath.ToSlash(filepath.Dir(from))
+	sibling := strings.TrimSuffix(parent, "/") + "/" + ref
+	if _, ok := g.Nodes[sibling]; ok {
+		return sibling
+	}
+
+	// Fall back to a unique name match anywhere in the tree
+	match := ""
+	for path, node := range g.Nodes {
+		if node.Name == ref {
+			if match != "" {
+				return ""
+			}
+			match = path
+		}
+	}
+	return match
+}
+
+// collectDeclaredDependencies walks node definitions (and nested configurations)
+// recording depends_on entries by tree path
+func (m *Manager) collectDeclaredDependencies(nodes []config.NodeDefinition, parentPath string, configDir string, declared map[string][]string, depth int) {
+	// Guard against config files that include each other
+	if depth > 32 {
+		return
+	}
+
+	for _, nodeDef := range nodes {
+		childPath := strings.TrimSuffix(parentPath, "/") + "/" + nodeDef.Name
+		if len(nodeDef.DependsOn) > 0 {
+			declared[childPath] = append(declared[childPath], nodeDef.DependsOn...)
+		}
+
+		childConfigPath := m.childConfigPath(nodeDef, childPath, configDir)
+		if childConfigPath == "" {
+			continue
+		}
+		if cfg, err := config.LoadTree(childConfigPath); err == nil {
+			m.collectDeclaredDependencies(cfg.Nodes, childPath, filepath.Dir(childConfigPath), declared, depth+1)
+		}
+	}
+}
+
+// workspaceConfigPath returns the path of the workspace configuration file
+func (m *Manager) workspaceConfigPath() string {
+	if m.config != nil && m.config.File != "" {
+		return m.config.File
+	}
+	return config.ConfigFilePath(m.workspace)
+}
+
+// detectManifestDependencies adds edges found in the build manifests of cloned repositories
+func (m *Manager) detectManifestDependencies(graph *DependencyGraph) {
+	manifests := make(map[string]*repoManifest)
+	provided := make(map[string]string) // coordinate -> node path
+	byURL := make(map[string]string)    // normalized URL -> node path
+	byDir := make(map[string]string)    // filesystem path -> node path
+
+	for _, path := range graph.Paths() {
+		node := graph.Nodes[path]
+		if node.Repository != "" {
+			byURL[normalizeRepoURL(node.Repository)] = path
+		}
+		if !node.IsCloned {
+			continue
+		}
+		fsPath := filepath.Clean(m.computeFilesystemPath(path))
+		byDir[fsPath] = path
+
+		manifest := readRepoManifest(fsPath)
+		manifests[path] = manifest
+		for _, coordinate := range manifest.Provides {
+			provided[coordinate] = path
+		}
+	}
+
+	for _, path := range graph.Paths() {
+		manifest, ok := manifests[path]
+		if !ok {
+			continue
+		}
+
+		for coordinate, source := range manifest.Requires {
+			if to, ok := provided[coordinate]; ok {
+				graph.AddEdge(path, to, source)
+				continue
+			}
+			if to := matchCoordinateToURL(coordinate, byURL); to != "" {
+				graph.AddEdge(path, to, source)
+			}
+		}
+		for spec, source := range manifest.URLs {
+			if to, ok := byURL[normalizeRepoURL(spec)]; ok {
+				graph.AddEdge(path, to, source)
+			}
+		}
+		for dir, source := range manifest.LocalPaths {
+			if to, ok := byDir[dir]; ok {
+				graph.AddEdge(path, to, source)
+			}
+		}
+	}
+}
+
+// matchCoordinateToURL matches a Go module path against repository URLs.
+// A module matches a repository when it equals the repository's host/path
+// or is a sub-module below it.
+func matchCoordinateToURL(coordinate string, byURL map[string]string) string {
+	normalized := strings.ToLower(coordinate)
+	best := ""
+	bestLen := 0
+	for url, path := range byURL {
+		if normalized == url || strings.HasPrefix(normalized, url+"/") {
+			if len(url) > bestLen {
+				best = path
+				bestLen = len(url)
+			}
+		}
+	}
+	return best
+}
+
+// ShowGraph prints the workspace dependency graph in the given format
+func (m *Manager) ShowGraph(format string) error {
+	graph, err := m.BuildDependencyGraph()
+	if err != nil {
+		return err
+	}
+
+	output, err := graph.Render(format)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+// OrderOptions controls which repositories a multi-repo operation runs on and in what order
+type OrderOptions struct {
+	Topological  bool   // Run in dependency order (dependencies first)
+	DependentsOf string // Only run on this node and the nodes that depend on it
+}
+
+// resolveOrderedRepos returns the cloned repositories under path, optionally
+// filtered to dependents of a node and sorted in dependency order
+func (m *Manager) resolveOrderedRepos(path string, opts OrderOptions) ([]interfaces.NodeInfo, error) {
+	targetPath := path
+	if targetPath == "" {
+		var err error
+		targetPath, err = m.getCurrentTreePath()
+		if err != nil {
+			return nil, fmt.Errorf("resolving current tree path: %w", err)
+		}
+	}
+
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("getting node: %w", err)
+	}
+
+	repos := m.collectClonedRepos(node)
+	if !opts.Topological && opts.DependentsOf == "" {
+		return repos, nil
+	}
+
+	graph, err := m.BuildDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed map[string]bool
+	if opts.DependentsOf != "" {
+		start := graph.resolveReference("/", opts.DependentsOf)
+		if start == "" {
+			return nil, fmt.Errorf("node not found in dependency graph: %s", opts.DependentsOf)
+		}
+		allowed = make(map[string]bool)
+		for _, p := range graph.Dependents(start) {
+			allowed[p] = true
+		}
+	}
+
+	byPath := make(map[string]interfaces.NodeInfo, len(repos))
+	selected := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		if allowed != nil && !allowed[repo.Path] {
+			continue
+		}
+		byPath[repo.Path] = repo
+		selected = append(selected, repo.Path)
+	}
+
+	// Only the selected nodes and their dependencies are ordered, so a cycle
+	// elsewhere in the workspace does not block this subtree
+	order, err := graph.Subgraph(selected).TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]interfaces.NodeInfo, 0, len(selected))
+	for _, p := range order {
+		if repo, ok := byPath[p]; ok {
+			ordered = append(ordered, repo)
+		}
+	}
+
+	return ordered, nil
+}
+
+// PullNodeOrdered pulls cloned repositories under a node in dependency order,
+// optionally restricted to dependents of a node
+func (m *Manager) PullNodeOrdered(path string, force bool, opts OrderOptions) (err error) {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.journalOperation("pull", "path", path, "force", force, "ordered", true)(&err)
+	if path != "" {
+		m.refreshRemoteConfigs(path)
+	} else if current, err := m.getCurrentTreePath(); err == nil {
+		m.refreshRemoteConfigs(current)
+	}
+
+	repos, err := m.resolveOrderedRepos(path, opts)
+	if err != nil {
+		return err
+	}
+
+	m.uiProvider.Info("🔄 Pulling repositories in dependency order...")
+	m.uiProvider.Info("─────────────────")
+
+	if len(repos) == 0 {
+		m.uiProvider.Info("📭 No cloned repositories found")
+		return nil
+	}
+
+	successCount := 0
+	failedRepos := []string{}
+	for _, node := range repos {
+		fullPath := m.computeFilesystemPath(node.Path)
+		m.uiProvider.Info(fmt.Sprintf("📦 Pulling: %s", node.Name))
+
+		if err := m.gitProvider.Pull(fullPath, interfaces.PullOptions{Force: force}); err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", err))
+			m.logNodeFailure("pull", node.Path, err)
+			failedRepos = append(failedRepos, node.Name)
+		} else {
+			m.uiProvider.Success("   ✅ Success")
+			successCount++
+		}
+	}
+
+	m.uiProvider.Info("")
+	m.uiProvider.Info("─────────────────")
+	m.uiProvider.Info(fmt.Sprintf("📊 Results: %d succeeded, %d failed", successCount, len(failedRepos)))
+
+	return nil
+}
+
+// ExecOptions controls exec behavior
+type ExecOptions struct {
+	OrderOptions
+	KeepGoing bool // Continue with remaining repositories after a failure
+}
+
+// ShellCommand turns the arguments of muno exec into a shell command line.
+// A single argument is used as written, so pipes and && work; several are
+// quoted one by one so that each reaches the command as one argument.
+func ShellCommand(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r))
+		}) < 0 {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// writeNodeStderr copies a command's error output to stderr, each line
+// prefixed with the node it ran in
+func (m *Manager) writeNodeStderr(nodePath string, output string) {
+	stderr := m.stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		fmt.Fprintf(stderr, "%s: %s\n", nodePath, line)
+	}
+}
+
+// ExecNode runs a shell command in every cloned repository under a node
+func (m *Manager) ExecNode(path string, command string, opts ExecOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("command is required")
+	}
+
+	repos, err := m.resolveOrderedRepos(path, opts.OrderOptions)
+	if err != nil {
+		return err
+	}
+
+	if len(repos) == 0 {
+		m.uiProvider.Info("📭 No cloned repositories found")
+		return nil
+	}
+
+	var failed []string
+	for _, node := range repos {
+		fullPath := m.computeFilesystemPath(node.Path)
+		m.uiProvider.Info(fmt.Sprintf("▶️  %s: %s", node.Path, command))
+
+		result, err := m.processProvider.ExecuteShell(context.Background(), command, interfaces.ProcessOptions{
+			WorkingDir: fullPath,
+		})
+		if err == nil && result.ExitCode != 0 {
+			err = fmt.Errorf("exit code %d", result.ExitCode)
+		}
+		if result != nil && result.Stdout != "" {
+			m.uiProvider.Info(strings.TrimRight(result.Stdout, "\n"))
+		}
+		if result != nil && result.Stderr != "" {
+			m.writeNodeStderr(node.Path, result.Stderr)
+		}
+
+		if err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed at %s: %v", node.Path, err))
+			m.logNodeFailure("exec", node.Path, err)
+			failed = append(failed, node.Path)
+			if !opts.KeepGoing {
+				return fmt.Errorf("command failed in %s: %w", node.Path, err)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("command failed in %d repositories: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
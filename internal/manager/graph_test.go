@@ -0,0 +1,314 @@
+package manager
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// setupGraphWorkspace creates three cloned repos: api depends on lib via go.mod,
+// web depends on api via declared depends_on
+func setupGraphWorkspace(t *testing.T) *Manager {
+	mgr, tmpDir := CreateTestNodeWorkspace(t, "test",
+		config.NodeDefinition{Name: "lib", URL: "https://github.com/acme/lib.git"},
+		config.NodeDefinition{Name: "api", URL: "git@github.com:acme/api.git"},
+		config.NodeDefinition{Name: "web", URL: "https://github.com/acme/web.git", DependsOn: []string{"api"}},
+	)
+
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "lib", "go.mod"), "module github.com/acme/lib\n\ngo 1.22\n")
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "api", "go.mod"), `module github.com/acme/api
+
+go 1.22
+
+require (
+	github.com/acme/lib/pkg v0.1.0 // submodule of lib
+	github.com/stretchr/testify v1.8.4
+)
+`)
+	return mgr
+}
+
+func TestParseGoMod(t *testing.T) {
+	manifest := newRepoManifest()
+	parseGoMod(`module example.com/app
+
+require example.com/single v1.0.0
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.0.0 // indirect
+)
+
+replace example.com/c => ../c
+replace (
+	example.com/d v1.0.0 => example.com/d-fork v1.1.0
+)
+`, "/ws/app", manifest)
+
+	assert.Equal(t, []string{"example.com/app"}, manifest.Provides)
+	for _, dep := range []string{"example.com/single", "example.com/a", "example.com/b", "example.com/c", "example.com/d", "example.com/d-fork"} {
+		assert.Contains(t, manifest.Requires, dep)
+	}
+	assert.Contains(t, manifest.LocalPaths, filepath.Clean("/ws/c"))
+}
+
+func TestParsePackageJSON(t *testing.T) {
+	manifest := newRepoManifest()
+	parsePackageJSON([]byte(`{
+		"name": "@acme/web",
+		"dependencies": {"@acme/ui": "^1.0.0", "sdk": "github:acme/sdk#main"},
+		"devDependencies": {"jest": "^29.0.0"}
+	}`), manifest)
+
+	assert.Equal(t, []string{"@acme/web"}, manifest.Provides)
+	assert.Contains(t, manifest.Requires, "@acme/ui")
+	assert.Contains(t, manifest.Requires, "jest")
+	assert.Contains(t, manifest.URLs, "github:acme/sdk#main")
+}
+
+func TestParsePom(t *testing.T) {
+	manifest := newRepoManifest()
+	parsePom([]byte(`<project>
+  <parent><groupId>com.acme</groupId><artifactId>parent</artifactId></parent>
+  <artifactId>service</artifactId>
+  <dependencies>
+    <dependency><groupId>com.acme</groupId><artifactId>core</artifactId></dependency>
+  </dependencies>
+</project>`), manifest)
+
+	assert.Equal(t, []string{"com.acme:service"}, manifest.Provides)
+	assert.Contains(t, manifest.Requires, "com.acme:parent")
+	assert.Contains(t, manifest.Requires, "com.acme:core")
+}
+
+func TestNormalizeRepoURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://github.com/Acme/Repo.git", "github.com/acme/repo"},
+		{"git@github.com:acme/repo.git", "github.com/acme/repo"},
+		{"ssh://git@github.com/acme/repo", "github.com/acme/repo"},
+		{"git+https://github.com/acme/repo.git#v1.0.0", "github.com/acme/repo"},
+		{"github:acme/repo", "github.com/acme/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeRepoURL(tt.input))
+		})
+	}
+}
+
+func TestDependencyGraph_TopologicalOrder(t *testing.T) {
+	g := NewDependencyGraph()
+	for _, p := range []string{"/a", "/b", "/c", "/d"} {
+		g.AddNode(&GraphNode{Name: p[1:], Path: p, IsCloned: true})
+	}
+	g.AddEdge("/a", "/b", DepSourceDeclared)
+	g.AddEdge("/b", "/c", DepSourceDeclared)
+	g.AddEdge("/d", "/c", DepSourceDeclared)
+
+	order, err := g.TopologicalOrder()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/c", "/b", "/a", "/d"}, order)
+
+	assert.Equal(t, []string{"/a", "/b", "/c", "/d"}, g.Dependents("/c"))
+	assert.Equal(t, []string{"/a"}, g.Dependents("/a"))
+	assert.Nil(t, g.Dependents("/missing"))
+}
+
+func TestDependencyGraph_Cycle(t *testing.T) {
+	g := NewDependencyGraph()
+	g.AddNode(&GraphNode{Name: "a", Path: "/a"})
+	g.AddNode(&GraphNode{Name: "b", Path: "/b"})
+	g.AddEdge("/a", "/b", DepSourceDeclared)
+	g.AddEdge("/b", "/a", DepSourceDeclared)
+
+	_, err := g.TopologicalOrder()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle")
+}
+
+func TestDependencyGraph_SubgraphIgnoresUnrelatedCycle(t *testing.T) {
+	g := NewDependencyGraph()
+	for _, p := range []string{"/a", "/b", "/c", "/x", "/y"} {
+		g.AddNode(&GraphNode{Name: p[1:], Path: p})
+	}
+	g.AddEdge("/a", "/b", DepSourceDeclared)
+	g.AddEdge("/b", "/c", DepSourceDeclared)
+	g.AddEdge("/x", "/y", DepSourceDeclared)
+	g.AddEdge("/y", "/x", DepSourceDeclared)
+
+	// Dependencies outside the selection still order it
+	order, err := g.Subgraph([]string{"/a", "/c"}).TopologicalOrder()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/c", "/b", "/a"}, order)
+
+	_, err = g.Subgraph([]string{"/a", "/x"}).TopologicalOrder()
+	assert.ErrorContains(t, err, "dependency cycle detected between: /x, /y")
+}
+
+func TestShellCommand(t *testing.T) {
+	assert.Equal(t, "make test && make lint", ShellCommand([]string{"make test && make lint"}))
+	assert.Equal(t, "grep -r 'foo bar' ./src", ShellCommand([]string{"grep", "-r", "foo bar", "./src"}))
+	assert.Equal(t, `echo 'it'\''s' ''`, ShellCommand([]string{"echo", "it's", ""}))
+}
+
+func TestDependencyGraph_IgnoresSelfAndUnknownEdges(t *testing.T) {
+	g := NewDependencyGraph()
+	g.AddNode(&GraphNode{Name: "a", Path: "/a"})
+	g.AddEdge("/a", "/a", DepSourceDeclared)
+	g.AddEdge("/a", "/missing", DepSourceDeclared)
+
+	assert.Empty(t, g.Nodes["/a"].DependsOn)
+}
+
+func TestDependencyGraph_Render(t *testing.T) {
+	g := NewDependencyGraph()
+	g.AddNode(&GraphNode{Name: "api", Path: "/api", IsCloned: true})
+	g.AddNode(&GraphNode{Name: "lib", Path: "/lib"})
+	g.AddEdge("/api", "/lib", DepSourceGoMod)
+
+	dot, err := g.Render(GraphFormatDOT)
+	require.NoError(t, err)
+	assert.Contains(t, dot, "digraph muno {")
+	assert.Contains(t, dot, `"/api" -> "/lib" [label="go.mod"];`)
+	assert.Contains(t, dot, `"/lib" [label="lib", style=dashed];`)
+
+	mermaid, err := g.Render(GraphFormatMermaid)
+	require.NoError(t, err)
+	assert.Contains(t, mermaid, "graph LR")
+	assert.Contains(t, mermaid, `n0["api"]`)
+	assert.Contains(t, mermaid, "n0 -->|go.mod| n1")
+
+	_, err = g.Render("svg")
+	assert.Error(t, err)
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	mgr := setupGraphWorkspace(t)
+
+	graph, err := mgr.BuildDependencyGraph()
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 3)
+
+	assert.Equal(t, []GraphEdge{{To: "/lib", Source: DepSourceGoMod}}, graph.Nodes["/api"].DependsOn)
+	assert.Equal(t, []GraphEdge{{To: "/api", Source: DepSourceDeclared}}, graph.Nodes["/web"].DependsOn)
+	assert.Empty(t, graph.Nodes["/lib"].DependsOn)
+
+	order, err := graph.TopologicalOrder()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/lib", "/api", "/web"}, order)
+}
+
+func TestBuildDependencyGraph_NpmAndMaven(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := CreateTestManagerWithConfig(t, tmpDir, &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "test", ReposDir: ".nodes"},
+	})
+	nodes := map[string]string{
+		"ui":      "https://github.com/acme/ui.git",
+		"app":     "https://github.com/acme/app.git",
+		"core":    "https://github.com/acme/core.git",
+		"service": "https://github.com/acme/service.git",
+	}
+	for name, url := range nodes {
+		AddNodeToTree(mgr, "/"+name, CreateSimpleNode(name, url))
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".nodes", name, ".git"), 0755))
+	}
+
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "ui", "package.json"), `{"name": "@acme/ui"}`)
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "app", "package.json"), `{"name": "app", "dependencies": {"@acme/ui": "^1.0.0"}}`)
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "core", "pom.xml"), `<project><groupId>com.acme</groupId><artifactId>core</artifactId></project>`)
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "service", "pom.xml"), `<project><groupId>com.acme</groupId><artifactId>service</artifactId>
+<dependencies><dependency><groupId>com.acme</groupId><artifactId>core</artifactId></dependency></dependencies></project>`)
+
+	graph, err := mgr.BuildDependencyGraph()
+	require.NoError(t, err)
+	assert.Equal(t, []GraphEdge{{To: "/ui", Source: DepSourcePackageJSON}}, graph.Nodes["/app"].DependsOn)
+	assert.Equal(t, []GraphEdge{{To: "/core", Source: DepSourcePom}}, graph.Nodes["/service"].DependsOn)
+}
+
+func TestBuildDependencyGraph_NotInitialized(t *testing.T) {
+	mgr := &Manager{}
+	_, err := mgr.BuildDependencyGraph()
+	assert.Error(t, err)
+}
+
+func TestPullNodeOrdered(t *testing.T) {
+	mgr := setupGraphWorkspace(t)
+	gitMock := mocks.NewMockGitProvider()
+	mgr.gitProvider = gitMock
+
+	err := mgr.PullNodeOrdered("/", false, OrderOptions{Topological: true})
+	require.NoError(t, err)
+
+	nodesDir := filepath.Join(mgr.workspace, ".nodes")
+	assert.Equal(t, []string{
+		"Pull(" + filepath.Join(nodesDir, "lib") + ")",
+		"Pull(" + filepath.Join(nodesDir, "api") + ")",
+		"Pull(" + filepath.Join(nodesDir, "web") + ")",
+	}, gitMock.GetCalls())
+}
+
+func TestPullNodeOrdered_DependentsOf(t *testing.T) {
+	mgr := setupGraphWorkspace(t)
+	gitMock := mocks.NewMockGitProvider()
+	mgr.gitProvider = gitMock
+
+	err := mgr.PullNodeOrdered("/", false, OrderOptions{Topological: true, DependentsOf: "api"})
+	require.NoError(t, err)
+
+	nodesDir := filepath.Join(mgr.workspace, ".nodes")
+	assert.Equal(t, []string{
+		"Pull(" + filepath.Join(nodesDir, "api") + ")",
+		"Pull(" + filepath.Join(nodesDir, "web") + ")",
+	}, gitMock.GetCalls())
+
+	err = mgr.PullNodeOrdered("/", false, OrderOptions{DependentsOf: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestExecNode(t *testing.T) {
+	mgr := setupGraphWorkspace(t)
+	procMock := mocks.NewMockProcessProvider()
+	mgr.processProvider = procMock
+
+	err := mgr.ExecNode("/", "make build", ExecOptions{OrderOptions: OrderOptions{Topological: true}})
+	require.NoError(t, err)
+	assert.Len(t, procMock.GetCalls(), 3)
+
+	err = mgr.ExecNode("/", "  ", ExecOptions{})
+	assert.Error(t, err)
+}
+
+func TestExecNode_LabelsStderr(t *testing.T) {
+	mgr := setupGraphWorkspace(t)
+	procMock := mocks.NewMockProcessProvider()
+	procMock.SetResult("shell:make lint", &interfaces.ProcessResult{Stdout: "ok\n", Stderr: "warning: a\nwarning: b\n"})
+	mgr.processProvider = procMock
+	var stderr bytes.Buffer
+	mgr.stderr = &stderr
+
+	require.NoError(t, mgr.ExecNode("/", "make lint", ExecOptions{}))
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	require.Len(t, lines, 6)
+	for _, line := range lines {
+		assert.Regexp(t, `^/\S+: warning: [ab]$`, line)
+	}
+}
@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// Doctor lists every node whose config could not be loaded: missing or
+// unparsable config files, remote configs that cannot be fetched and
+// generated configs that were never written
+func (m *Manager) Doctor() error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.saveStatusCache()
+
+	root, err := m.treeProvider.GetNode("/")
+	if err != nil {
+		return fmt.Errorf("getting node: %w", err)
+	}
+
+	m.uiProvider.Info("🩺 Checking workspace configs...")
+	broken := collectConfigErrors(root)
+	if len(broken) == 0 {
+		total, _, _ := m.countNodes(root)
+		m.uiProvider.Success(fmt.Sprintf("All configs resolved (%d nodes)", total))
+		return nil
+	}
+
+	for _, node := range broken {
+		m.uiProvider.Error(node.Path)
+		m.uiProvider.Info(fmt.Sprintf("   file:  %s", node.ConfigErrorFile))
+		m.uiProvider.Info(fmt.Sprintf("   error: %s", node.ConfigError))
+		if nodeDef := m.nodeDefinition(node.Path); nodeDef != nil && nodeDef.Generate != nil {
+			m.uiProvider.Info("   hint:  run muno sync to generate it")
+		}
+	}
+	return fmt.Errorf("%d unresolved config references", len(broken))
+}
+
+// checkStrict fails when the tree has error nodes
+func (m *Manager) checkStrict() error {
+	root, err := m.treeProvider.GetNode("/")
+	if err != nil {
+		return err
+	}
+	broken := collectConfigErrors(root)
+	if len(broken) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(broken))
+	for _, node := range broken {
+		lines = append(lines, fmt.Sprintf("  %s: %s: %s", node.Path, node.ConfigErrorFile, node.ConfigError))
+	}
+	return fmt.Errorf("strict mode: %d unresolved config references:\n%s", len(broken), strings.Join(lines, "\n"))
+}
+
+// collectConfigErrors returns the nodes below node whose config could not
+// be loaded
+func collectConfigErrors(node interfaces.NodeInfo) []interfaces.NodeInfo {
+	var nodes []interfaces.NodeInfo
+	if node.ConfigError != "" {
+		nodes = append(nodes, node)
+	}
+	for _, child := range node.Children {
+		nodes = append(nodes, collectConfigErrors(child)...)
+	}
+	return nodes
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/mocks"
+	"github.com/taokim/muno/internal/tree"
+)
+
+func TestDoctor_ReportsBrokenConfigs(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "muno.yaml")
+	writeTestFile(t, configPath, `workspace:
+  name: acme
+  repos_dir: .nodes
+nodes:
+  - name: team
+    file: team.yaml
+  - name: services
+    generate:
+      list: repos.txt
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "team.yaml"), "workspace:\n  name: team\nnodes:\n  - name: a\n    url: [broken\n")
+	cfg, err := config.LoadTree(configPath)
+	require.NoError(t, err)
+
+	treeMgr, err := tree.NewManager(tmpDir, &MockGitInterface{})
+	require.NoError(t, err)
+	mgr := CreateTestManagerWithConfig(t, tmpDir, cfg)
+	mgr.treeProvider = NewTreeAdapter(treeMgr)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+
+	assert.EqualError(t, mgr.Doctor(), "2 unresolved config references")
+	messages := ui.GetMessages()
+	assert.Contains(t, messages, "ERROR: /team")
+	assert.Contains(t, messages, "INFO:    file:  "+filepath.Join(tmpDir, "team.yaml"))
+	assert.Contains(t, messages, "ERROR: /services")
+	assert.Contains(t, messages, "INFO:    hint:  run muno sync to generate it")
+
+	ui.Reset()
+	require.NoError(t, mgr.ShowTreeAtPath("/", 0))
+	assert.Contains(t, ui.GetMessages(), "INFO: ├── team [📄 config ❌ config error]")
+
+	err = mgr.checkStrict()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "strict mode: 2 unresolved config references")
+	assert.Contains(t, err.Error(), "/team: "+filepath.Join(tmpDir, "team.yaml")+": parsing config file")
+
+	// Once the generated config exists and the broken one is fixed, all is well
+	writeTestFile(t, filepath.Join(tmpDir, "repos.txt"), "https://github.com/acme/api.git\n")
+	writeTestFile(t, filepath.Join(tmpDir, "team.yaml"), "workspace:\n  name: team\nnodes: []\n")
+	require.NoError(t, mgr.Sync())
+	ui.Reset()
+	require.NoError(t, mgr.Doctor())
+	assert.NoError(t, mgr.checkStrict())
+}
@@ -8,13 +8,28 @@ import (
 
 	"github.com/taokim/muno/internal/adapters"
 	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/daemon"
 	"github.com/taokim/muno/internal/git"
 	"github.com/taokim/muno/internal/interfaces"
 	"github.com/taokim/muno/internal/tree"
 )
 
+// LoadOptions controls how LoadFromCurrentDirWithOptions loads a workspace
+type LoadOptions struct {
+	// Strict fails the load when any config in the tree cannot be loaded,
+	// instead of showing the broken nodes as error nodes. CI uses it to
+	// catch broken sub-configs.
+	Strict bool
+}
+
 // LoadFromCurrentDir loads a manager from the current directory
 func LoadFromCurrentDir() (*Manager, error) {
+	return LoadFromCurrentDirWithOptions(LoadOptions{})
+}
+
+// LoadFromCurrentDirWithOptions loads a manager from the current directory
+// with the given options
+func LoadFromCurrentDirWithOptions(opts LoadOptions) (*Manager, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("getting current directory: %w", err)
@@ -26,22 +41,10 @@ func LoadFromCurrentDir() (*Manager, error) {
 		return nil, fmt.Errorf("not in a MUNO workspace (no muno.yaml found)")
 	}
 	
-	// Load the config
-	configPath := filepath.Join(workspaceRoot, "muno.yaml")
-	cfg, err := config.LoadTree(configPath)
+	// Load the config in whichever format the workspace uses
+	cfg, err := config.LoadTree(config.ConfigFilePath(workspaceRoot))
 	if err != nil {
-		// Try alternative names
-		for _, name := range []string{"muno.yml", ".muno.yaml", ".muno.yml"} {
-			altPath := filepath.Join(workspaceRoot, name)
-			if altCfg, altErr := config.LoadTree(altPath); altErr == nil {
-				cfg = altCfg
-				configPath = altPath
-				break
-			}
-		}
-		if cfg == nil {
-			return nil, fmt.Errorf("loading config: %w", err)
-		}
+		return nil, fmt.Errorf("loading config: %w", err)
 	}
 	
 	// Create providers using adapters
@@ -59,6 +62,10 @@ func LoadFromCurrentDir() (*Manager, error) {
 		return nil, fmt.Errorf("creating tree manager: %w", err)
 	}
 	
+	// Cache repository states in the workspace state file
+	statusCache := tree.NewStatusCache(workspaceRoot)
+	treeManager.SetStatusCache(statusCache)
+	
 	// Create tree adapter with the actual tree manager
 	treeAdapter := NewTreeAdapter(treeManager)
 	
@@ -69,6 +76,9 @@ func LoadFromCurrentDir() (*Manager, error) {
 		FSProvider:      fsAdapter,
 		UIProvider:      uiAdapter,
 		TreeProvider:    treeAdapter,
+		ProcessProvider: adapters.NewProcessAdapter(),
+		LogProvider:     newWorkspaceLogger(workspaceRoot),
+		MetricsProvider: metricsForWorkspace(workspaceRoot),
 		AutoLoadConfig:  true,
 	})
 	if err != nil {
@@ -78,8 +88,70 @@ func LoadFromCurrentDir() (*Manager, error) {
 	// Set workspace and config
 	mgr.workspace = workspaceRoot
 	mgr.config = cfg
+	mgr.statusCache = statusCache
+	mgr.gitProvider = &instrumentedGit{GitProvider: mgr.gitProvider, m: mgr}
+	if cfg.Overrides != nil {
+		mgr.configResolver.SetWorkspaceConfig(cfg.Overrides)
+	}
 	mgr.initialized = true
 	
+	// Give generate: nodes added since the last sync their children
+	mgr.generateMissing()
+	
+	if opts.Strict {
+		if err := mgr.checkStrict(); err != nil {
+			return nil, err
+		}
+	}
+	
+	return mgr, nil
+}
+
+// ConnectDaemonFromCurrentDir returns a manager for the workspace around the
+// current directory that reads the tree from the daemon serving it. Only the
+// workspace config itself is read, not the configs below it. It fails
+// quickly when no daemon answers, so callers can use LoadFromCurrentDir.
+func ConnectDaemonFromCurrentDir() (*Manager, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	workspaceRoot := findWorkspaceRoot(cwd)
+	if workspaceRoot == "" {
+		return nil, fmt.Errorf("not in a MUNO workspace (no muno.yaml found)")
+	}
+	client, err := daemon.Connect(workspaceRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.LoadTree(config.ConfigFilePath(workspaceRoot))
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	mgr, err := NewManager(ManagerOptions{
+		ConfigProvider:  adapters.NewConfigAdapter(),
+		GitProvider:     adapters.NewGitProvider(),
+		FSProvider:      adapters.NewFileSystemAdapter(),
+		UIProvider:      adapters.NewUIAdapter(),
+		TreeProvider:    newDaemonTree(client),
+		ProcessProvider: adapters.NewProcessAdapter(),
+		LogProvider:     newWorkspaceLogger(workspaceRoot),
+		MetricsProvider: metricsForWorkspace(workspaceRoot),
+	})
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	mgr.workspace = workspaceRoot
+	mgr.config = cfg
+	mgr.gitProvider = &instrumentedGit{GitProvider: mgr.gitProvider, m: mgr}
+	if cfg.Overrides != nil {
+		mgr.configResolver.SetWorkspaceConfig(cfg.Overrides)
+	}
+	mgr.initialized = true
 	return mgr, nil
 }
 
@@ -131,6 +203,7 @@ func NewManagerForInit(projectPath string) (*Manager, error) {
 		FSProvider:      fsAdapter,
 		UIProvider:      uiAdapter,
 		TreeProvider:    treeProvider,
+		LogProvider:     newWorkspaceLogger(absPath),
 		AutoLoadConfig:  false, // Don't auto-load since we're initializing
 	})
 	if err != nil {
@@ -151,19 +224,13 @@ func findWorkspaceRoot(startPath string) string {
 	var candidates []string
 	
 	for {
-		// Check for muno.yaml in current directory (must be a regular file, not a symlink)
-		configPath := filepath.Join(current, "muno.yaml")
-		if info, err := os.Lstat(configPath); err == nil && info.Mode().IsRegular() {
-			// Found a muno.yaml, but we need to check if it's the TRUE workspace root
-			// A true workspace root is one that's not inside any .nodes or repos directory
-			candidates = append(candidates, current)
-		}
-		
-		// Also check for alternative names
-		for _, name := range []string{"muno.yml", ".muno.yaml", ".muno.yml"} {
+		// Check for muno.yaml, muno.json, ... in current directory (must be a
+		// regular file, not a symlink). A found config may still be nested; the
+		// TRUE workspace root is one that's not inside any .nodes or repos directory
+		for _, name := range config.GetConfigFileNames() {
 			configPath := filepath.Join(current, name)
 			if info, err := os.Lstat(configPath); err == nil && info.Mode().IsRegular() {
-				// Don't add duplicate if we already found muno.yaml
+				// Don't add duplicate if we already found another config name
 				if len(candidates) == 0 || candidates[len(candidates)-1] != current {
 					candidates = append(candidates, current)
 				}
@@ -112,6 +112,32 @@ func TestLoadFromCurrentDir(t *testing.T) {
 	}
 }
 
+func TestLoadFromCurrentDirWithOptions_Strict(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "muno.yaml"), "workspace:\n  name: acme\nnodes:\n  - name: team\n    file: team.yaml\n")
+	writeTestFile(t, filepath.Join(tmpDir, "team.yaml"), "workspace:\n  name: team\nnodes:\n  - name: a\n    url: [broken\n")
+
+	if root := findWorkspaceRoot(tmpDir); root != tmpDir {
+		t.Skipf("workspace config in %s hides the test workspace", root)
+	}
+	oldWd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer os.Chdir(oldWd)
+
+	// The broken config shows up as an error node by default
+	m, err := LoadFromCurrentDir()
+	require.NoError(t, err)
+	assert.NotNil(t, m)
+
+	_, err = LoadFromCurrentDirWithOptions(LoadOptions{Strict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "strict mode: 1 unresolved config references")
+
+	// Strict mode is per load; a later plain load is not affected
+	_, err = LoadFromCurrentDir()
+	assert.NoError(t, err)
+}
+
 func TestNewManagerForInit(t *testing.T) {
 	tests := []struct {
 		name      string
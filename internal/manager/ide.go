@@ -0,0 +1,245 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// Supported IDE targets
+const (
+	IDEVSCode    = "vscode"
+	IDEJetBrains = "jetbrains"
+)
+
+// jetbrainsMarker identifies module files generated by muno
+const jetbrainsMarker = "<!-- Generated by muno ide jetbrains -->"
+
+// IDEOptions controls IDE workspace generation
+type IDEOptions struct {
+	IncludeLazy bool // Clone lazy nodes in the subtree and include them
+}
+
+// ideFolder is a cloned node included in an IDE workspace
+type ideFolder struct {
+	Name    string // Tree path without the leading slash, e.g. "team/api"
+	AbsPath string
+}
+
+// GenerateIDEWorkspace generates an IDE workspace listing every cloned node under path
+func (m *Manager) GenerateIDEWorkspace(ide string, path string, opts IDEOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	if ide != IDEVSCode && ide != IDEJetBrains {
+		return fmt.Errorf("unsupported IDE: %s (use vscode or jetbrains)", ide)
+	}
+
+	targetPath := path
+	if targetPath == "" {
+		var err error
+		targetPath, err = m.getCurrentTreePath()
+		if err != nil {
+			return fmt.Errorf("resolving current tree path: %w", err)
+		}
+	}
+
+	if opts.IncludeLazy {
+		if err := m.CloneRepos(targetPath, true, true); err != nil {
+			return fmt.Errorf("cloning lazy nodes: %w", err)
+		}
+	}
+
+	output, folders, err := m.writeIDEWorkspace(ide, targetPath)
+	if err != nil {
+		return err
+	}
+
+	if err := m.registerGenerated(generatedRecord{Kind: ide, Path: targetPath, IncludeLazy: opts.IncludeLazy}); err != nil {
+		m.logProvider.Warn(fmt.Sprintf("Failed to record IDE workspace: %v", err))
+	}
+
+	m.uiProvider.Success(fmt.Sprintf("✅ Generated %s workspace: %s", ide, output))
+	m.uiProvider.Info(fmt.Sprintf("   Folders: %d", len(folders)))
+	for _, folder := range folders {
+		m.uiProvider.Info(fmt.Sprintf("   - %s", folder.Name))
+	}
+	m.metricsProvider.Counter("manager.ide_workspace", 1)
+
+	return nil
+}
+
+// writeIDEWorkspace writes the workspace files for one IDE and returns the main output path
+func (m *Manager) writeIDEWorkspace(ide string, targetPath string) (string, []ideFolder, error) {
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("getting node: %w", err)
+	}
+
+	baseDir := m.computeFilesystemPath(node.Path)
+	folders := m.collectIDEFolders(node)
+
+	name := node.Name
+	if node.Path == "/" && m.config != nil && m.config.Workspace.Name != "" {
+		name = m.config.Workspace.Name
+	}
+	if name == "" {
+		name = "workspace"
+	}
+
+	var output, ignore string
+	switch ide {
+	case IDEVSCode:
+		output = filepath.Join(baseDir, name+".code-workspace")
+		ignore = name + ".code-workspace"
+		err = m.writeVSCodeWorkspace(output, baseDir, folders)
+	default:
+		output = filepath.Join(baseDir, ".idea", "modules.xml")
+		ignore = ".idea/"
+		err = m.writeJetBrainsModules(baseDir, folders)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Generated workspaces list local paths, like go.work
+	if err := m.ensureGitignoreEntry(baseDir, ignore); err != nil {
+		m.logProvider.Debug(fmt.Sprintf("Could not add '%s' to .gitignore: %v", ignore, err))
+	}
+	return output, folders, nil
+}
+
+// collectIDEFolders walks the subtree and returns cloned nodes named by tree path
+func (m *Manager) collectIDEFolders(node interfaces.NodeInfo) []ideFolder {
+	var folders []ideFolder
+	for _, repo := range m.collectRepos(node, false) {
+		if !repo.IsCloned || repo.Path == "/" {
+			continue
+		}
+		absPath := m.computeFilesystemPath(repo.Path)
+		if _, err := os.Stat(absPath); err != nil {
+			continue
+		}
+		folders = append(folders, ideFolder{
+			Name:    strings.TrimPrefix(repo.Path, "/"),
+			AbsPath: absPath,
+		})
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].Name < folders[j].Name })
+	return folders
+}
+
+// writeVSCodeWorkspace writes a multi-root .code-workspace file. Settings and
+// other keys of an existing file are preserved; folders are regenerated.
+func (m *Manager) writeVSCodeWorkspace(output string, baseDir string, folders []ideFolder) error {
+	workspace := make(map[string]interface{})
+	if data, err := os.ReadFile(output); err == nil {
+		if err := json.Unmarshal(data, &workspace); err != nil {
+			return fmt.Errorf("parsing existing %s: %w", filepath.Base(output), err)
+		}
+	}
+
+	entries := make([]map[string]string, 0, len(folders))
+	for _, folder := range folders {
+		rel, err := filepath.Rel(baseDir, folder.AbsPath)
+		if err != nil {
+			rel = folder.AbsPath
+		}
+		entries = append(entries, map[string]string{
+			"name": folder.Name,
+			"path": filepath.ToSlash(rel),
+		})
+	}
+	workspace["folders"] = entries
+	if _, ok := workspace["settings"]; !ok {
+		workspace["settings"] = map[string]interface{}{}
+	}
+
+	data, err := json.MarshalIndent(workspace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding workspace: %w", err)
+	}
+	if err := m.fsProvider.WriteFile(output, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(output), err)
+	}
+	return nil
+}
+
+// writeJetBrainsModules writes .idea/modules.xml and one .iml per cloned node.
+// Stale module files previously generated by muno are removed.
+func (m *Manager) writeJetBrainsModules(baseDir string, folders []ideFolder) error {
+	ideaDir := filepath.Join(baseDir, ".idea")
+	modulesDir := filepath.Join(ideaDir, "modules")
+	if err := m.fsProvider.MkdirAll(modulesDir, 0755); err != nil {
+		return fmt.Errorf("creating .idea directory: %w", err)
+	}
+
+	wanted := make(map[string]bool)
+	var modules strings.Builder
+	for _, folder := range folders {
+		moduleName := strings.ReplaceAll(folder.Name, "/", ".")
+		imlName := moduleName + ".iml"
+		wanted[imlName] = true
+
+		rel, err := filepath.Rel(modulesDir, folder.AbsPath)
+		if err != nil {
+			rel = folder.AbsPath
+		}
+		iml := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+%s
+<module type="WEB_MODULE" version="4">
+  <component name="NewModuleRootManager" inherit-compiler-output="true">
+    <exclude-output />
+    <content url="file://$MODULE_DIR$/%s" />
+    <orderEntry type="inheritedJdk" />
+    <orderEntry type="sourceFolder" forTests="false" />
+  </component>
+</module>
+`, jetbrainsMarker, filepath.ToSlash(rel))
+		if err := m.fsProvider.WriteFile(filepath.Join(modulesDir, imlName), []byte(iml), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", imlName, err)
+		}
+
+		fmt.Fprintf(&modules, "      <module fileurl=\"file://$PROJECT_DIR$/.idea/modules/%s\" filepath=\"$PROJECT_DIR$/.idea/modules/%s\" />\n", imlName, imlName)
+	}
+
+	// Remove modules for nodes that no longer exist
+	if entries, err := os.ReadDir(modulesDir); err == nil {
+		for _, entry := range entries {
+			if wanted[entry.Name()] || !strings.HasSuffix(entry.Name(), ".iml") {
+				continue
+			}
+			imlPath := filepath.Join(modulesDir, entry.Name())
+			if data, err := os.ReadFile(imlPath); err == nil && strings.Contains(string(data), jetbrainsMarker) {
+				m.fsProvider.Remove(imlPath)
+			}
+		}
+	}
+
+	modulesXML := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+%s
+<project version="4">
+  <component name="ProjectModuleManager">
+    <modules>
+%s    </modules>
+  </component>
+</project>
+`, jetbrainsMarker, modules.String())
+	if err := m.fsProvider.WriteFile(filepath.Join(ideaDir, "modules.xml"), []byte(modulesXML), 0644); err != nil {
+		return fmt.Errorf("writing modules.xml: %w", err)
+	}
+	return nil
+}
+
+// treePathsOverlap reports whether one tree path is the other or one of its ancestors
+func treePathsOverlap(a, b string) bool {
+	if a == "/" || b == "/" || a == b {
+		return true
+	}
+	return strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
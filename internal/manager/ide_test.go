@@ -0,0 +1,160 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+)
+
+func setupIDEWorkspace(t *testing.T) (*Manager, string) {
+	mgr, tmpDir := CreateTestNodeWorkspace(t, "acme",
+		config.NodeDefinition{Name: "api", URL: "https://github.com/acme/api.git"},
+		config.NodeDefinition{Name: "web", URL: "https://github.com/acme/web.git"},
+		config.NodeDefinition{Name: "docs", URL: "https://github.com/acme/docs.git", Fetch: config.FetchLazy},
+	)
+	AddNodeToTree(mgr, "/api/svc", CreateSimpleNode("svc", "https://github.com/acme/svc.git"))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".nodes", "api", ".nodes", "svc"), 0755))
+
+	return mgr, tmpDir
+}
+
+func readCodeWorkspace(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var ws map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &ws))
+	return ws
+}
+
+func TestGenerateIDEWorkspace_VSCode(t *testing.T) {
+	mgr, tmpDir := setupIDEWorkspace(t)
+
+	require.NoError(t, mgr.GenerateIDEWorkspace(IDEVSCode, "/", IDEOptions{}))
+
+	ws := readCodeWorkspace(t, filepath.Join(tmpDir, "acme.code-workspace"))
+	folders := ws["folders"].([]interface{})
+	require.Len(t, folders, 3)
+	assert.Equal(t, map[string]interface{}{"name": "api", "path": ".nodes/api"}, folders[0])
+	assert.Equal(t, map[string]interface{}{"name": "api/svc", "path": ".nodes/api/.nodes/svc"}, folders[1])
+	assert.Equal(t, map[string]interface{}{"name": "web", "path": ".nodes/web"}, folders[2])
+	assert.Contains(t, ws, "settings")
+}
+
+func TestGenerateIDEWorkspace_VSCodePreservesSettings(t *testing.T) {
+	mgr, tmpDir := setupIDEWorkspace(t)
+	output := filepath.Join(tmpDir, "acme.code-workspace")
+	writeTestFile(t, output, `{"folders": [{"path": "old"}], "settings": {"editor.tabSize": 2}, "extensions": {"recommendations": ["golang.go"]}}`)
+
+	require.NoError(t, mgr.GenerateIDEWorkspace(IDEVSCode, "/", IDEOptions{}))
+
+	ws := readCodeWorkspace(t, output)
+	assert.Equal(t, map[string]interface{}{"editor.tabSize": float64(2)}, ws["settings"])
+	assert.Contains(t, ws, "extensions")
+	assert.Len(t, ws["folders"], 3)
+}
+
+func TestGenerateIDEWorkspace_Subtree(t *testing.T) {
+	mgr, tmpDir := setupIDEWorkspace(t)
+
+	require.NoError(t, mgr.GenerateIDEWorkspace(IDEVSCode, "/api", IDEOptions{}))
+
+	ws := readCodeWorkspace(t, filepath.Join(tmpDir, ".nodes", "api", "api.code-workspace"))
+	folders := ws["folders"].([]interface{})
+	require.Len(t, folders, 2)
+	assert.Equal(t, map[string]interface{}{"name": "api", "path": "."}, folders[0])
+	assert.Equal(t, map[string]interface{}{"name": "api/svc", "path": ".nodes/svc"}, folders[1])
+}
+
+func TestGenerateIDEWorkspace_JetBrains(t *testing.T) {
+	mgr, tmpDir := setupIDEWorkspace(t)
+	ideaDir := filepath.Join(tmpDir, ".idea")
+	writeTestFile(t, filepath.Join(ideaDir, "modules", "stale.iml"), jetbrainsMarker+"\n")
+	writeTestFile(t, filepath.Join(ideaDir, "modules", "handmade.iml"), "<module/>\n")
+
+	require.NoError(t, mgr.GenerateIDEWorkspace(IDEJetBrains, "/", IDEOptions{}))
+
+	modules, err := os.ReadFile(filepath.Join(ideaDir, "modules.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(modules), "$PROJECT_DIR$/.idea/modules/api.iml")
+	assert.Contains(t, string(modules), "$PROJECT_DIR$/.idea/modules/api.svc.iml")
+	assert.Contains(t, string(modules), "$PROJECT_DIR$/.idea/modules/web.iml")
+
+	iml, err := os.ReadFile(filepath.Join(ideaDir, "modules", "api.svc.iml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(iml), `<content url="file://$MODULE_DIR$/../../.nodes/api/.nodes/svc" />`)
+
+	assert.NoFileExists(t, filepath.Join(ideaDir, "modules", "stale.iml"))
+	assert.FileExists(t, filepath.Join(ideaDir, "modules", "handmade.iml"))
+}
+
+func TestGenerateIDEWorkspace_Errors(t *testing.T) {
+	mgr, _ := setupIDEWorkspace(t)
+	assert.Error(t, mgr.GenerateIDEWorkspace("emacs", "/", IDEOptions{}))
+
+	uninitialized := &Manager{}
+	assert.Error(t, uninitialized.GenerateIDEWorkspace(IDEVSCode, "/", IDEOptions{}))
+}
+
+func TestSyncIDEWorkspaces(t *testing.T) {
+	mgr, tmpDir := setupIDEWorkspace(t)
+	require.NoError(t, mgr.GenerateIDEWorkspace(IDEVSCode, "/", IDEOptions{}))
+
+	// Simulate removing a node
+	require.NoError(t, mgr.treeProvider.RemoveNode("/web"))
+	require.NoError(t, os.RemoveAll(filepath.Join(tmpDir, ".nodes", "web")))
+	mgr.syncGeneratedFiles("/")
+
+	ws := readCodeWorkspace(t, filepath.Join(tmpDir, "acme.code-workspace"))
+	assert.Len(t, ws["folders"], 2)
+
+	records := mgr.loadGeneratedRegistry()
+	require.Len(t, records, 1)
+	assert.Equal(t, generatedRecord{Kind: IDEVSCode, Path: "/"}, records[0])
+}
+
+func TestSyncGeneratedFiles_PrunesAndClonesLazy(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	mgr, tmpDir := setupIDEWorkspace(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755))
+	require.NoError(t, mgr.GenerateIDEWorkspace(IDEJetBrains, "/web", IDEOptions{}))
+	require.NoError(t, mgr.registerGenerated(generatedRecord{Kind: IDEVSCode, Path: "/", IncludeLazy: true}))
+
+	gitignore, err := os.ReadFile(filepath.Join(tmpDir, ".nodes", "web", ".gitignore"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gitignore), ".idea/\n")
+
+	// A lazy node added later is cloned for the include-lazy workspace
+	bare := filepath.Join(t.TempDir(), "tools.git")
+	runTestGit(t, tmpDir, "init", "--quiet", "--bare", bare)
+	AddNodeToTree(mgr, "/tools", CreateLazyNode("tools", bare))
+
+	// Records of removed nodes are dropped
+	require.NoError(t, mgr.treeProvider.RemoveNode("/web"))
+	mgr.syncGeneratedFiles("/tools")
+
+	assert.DirExists(t, filepath.Join(tmpDir, ".nodes", "tools", ".git"))
+	ws := readCodeWorkspace(t, filepath.Join(tmpDir, "acme.code-workspace"))
+	assert.Contains(t, ws["folders"], map[string]interface{}{"name": "tools", "path": ".nodes/tools"})
+	assert.Equal(t, []generatedRecord{{Kind: IDEVSCode, Path: "/", IncludeLazy: true}}, mgr.loadGeneratedRegistry())
+
+	gitignore, err = os.ReadFile(filepath.Join(tmpDir, ".gitignore"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gitignore), "acme.code-workspace\n")
+}
+
+func TestTreePathsOverlap(t *testing.T) {
+	assert.True(t, treePathsOverlap("/", "/a"))
+	assert.True(t, treePathsOverlap("/a", "/a/b"))
+	assert.True(t, treePathsOverlap("/a/b", "/a"))
+	assert.False(t, treePathsOverlap("/a", "/ab"))
+	assert.False(t, treePathsOverlap("/a/b", "/a/c"))
+}
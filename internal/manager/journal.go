@@ -0,0 +1,320 @@
+package manager
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taokim/muno/internal/tree"
+)
+
+// JournalFile is the append-only operation journal, relative to the workspace root
+const JournalFile = ".muno/journal.jsonl"
+
+// JournalEntry records one mutating operation and what it did to each node
+type JournalEntry struct {
+	ID        string            `json:"id"`
+	Operation string            `json:"operation"`
+	Command   string            `json:"command,omitempty"`
+	Args      map[string]string `json:"args,omitempty"`
+	User      string            `json:"user,omitempty"`
+	Started   time.Time         `json:"started"`
+	Finished  time.Time         `json:"finished"`
+	Error     string            `json:"error,omitempty"`
+	Nodes     []JournalNode     `json:"nodes,omitempty"`
+}
+
+// JournalNode is the outcome of one step on one node
+type JournalNode struct {
+	Path   string `json:"path"`
+	Action string `json:"action"` // clone, pull, push, commit, checkout, reset, add or remove
+	Result string `json:"result"` // ok or error
+	Error  string `json:"error,omitempty"`
+	Before string `json:"before,omitempty"` // HEAD before the step
+	After  string `json:"after,omitempty"`  // HEAD after the step
+	Branch string `json:"branch,omitempty"` // Branch checked out before the step
+}
+
+// JournalOptions filters muno journal
+type JournalOptions struct {
+	Node  string    // Only entries touching this node or its descendants
+	Since time.Time // Only entries started at or after this time
+}
+
+// journalOperation starts a journal entry for a mutating entry point and
+// returns the function that finishes and appends it:
+//
+//	defer m.journalOperation("pull", "path", path)(&err)
+//
+// Entry points called by another one record into the outer entry.
+func (m *Manager) journalOperation(operation string, args ...interface{}) func(*error) {
+	m.journalMu.Lock()
+	defer m.journalMu.Unlock()
+	if m.journal != nil || m.workspace == "" {
+		return func(*error) {}
+	}
+
+	started := time.Now()
+	entry := &JournalEntry{
+		ID:        journalID(started),
+		Operation: operation,
+		Command:   journalCommand(),
+		Args:      make(map[string]string),
+		User:      journalUser(),
+		Started:   started,
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.Args[fmt.Sprint(args[i])] = fmt.Sprint(args[i+1])
+	}
+	m.journal = entry
+
+	return func(errp *error) {
+		m.journalMu.Lock()
+		m.journal = nil
+		m.journalMu.Unlock()
+
+		entry.Finished = time.Now()
+		if errp != nil && *errp != nil {
+			entry.Error = (*errp).Error()
+		}
+		if err := appendJournal(filepath.Join(m.workspace, JournalFile), entry); err != nil {
+			m.logProvider.Warn(fmt.Sprintf("Failed to write journal: %v", err))
+		}
+	}
+}
+
+// journalArg adds an argument to the active journal entry, for entry points
+// that learn it only after they started
+func (m *Manager) journalArg(key string, value interface{}) {
+	m.journalMu.Lock()
+	defer m.journalMu.Unlock()
+	if m.journal != nil {
+		m.journal.Args[key] = fmt.Sprint(value)
+	}
+}
+
+// journalStep records a step on the node at fsPath into the active journal
+// entry. Call it before the step and the returned function after it.
+func (m *Manager) journalStep(action string, fsPath string) func(error) {
+	m.journalMu.Lock()
+	entry := m.journal
+	m.journalMu.Unlock()
+	if entry == nil {
+		return func(error) {}
+	}
+
+	before := tree.HeadCommit(fsPath)
+	branch := tree.HeadBranch(fsPath)
+	return func(err error) {
+		node := JournalNode{
+			Path:   m.nodeLabel(fsPath),
+			Action: action,
+			Result: "ok",
+			Before: before,
+			After:  tree.HeadCommit(fsPath),
+			Branch: branch,
+		}
+		if err != nil {
+			node.Result = "error"
+			node.Error = err.Error()
+		}
+		m.journalMu.Lock()
+		entry.Nodes = append(entry.Nodes, node)
+		m.journalMu.Unlock()
+	}
+}
+
+// journalID returns a sortable, unique entry id
+func journalID(started time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return started.UTC().Format("20060102T150405.000") + "-" + hex.EncodeToString(suffix)
+}
+
+// journalCommand returns the command line that started this process
+func journalCommand() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")
+}
+
+// journalUser names the user running muno
+func journalUser() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// appendJournal adds entry as one JSON line
+func appendJournal(path string, entry *JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readJournal returns the entries in path, oldest first. Lines that do not
+// parse, such as one cut short by a crash, are skipped.
+func readJournal(path string) ([]JournalEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// filter returns the entry restricted to opts, or false when nothing matches
+func (e JournalEntry) filter(opts JournalOptions) (JournalEntry, bool) {
+	if !opts.Since.IsZero() && e.Started.Before(opts.Since) {
+		return e, false
+	}
+	if opts.Node == "" || opts.Node == "/" {
+		return e, true
+	}
+
+	node := "/" + strings.Trim(opts.Node, "/")
+	var nodes []JournalNode
+	for _, n := range e.Nodes {
+		if n.Path == node || strings.HasPrefix(n.Path, node+"/") {
+			nodes = append(nodes, n)
+		}
+	}
+	e.Nodes = nodes
+	return e, len(nodes) > 0
+}
+
+// ParseSince parses a --since value: a duration such as 90m, 2h or 3d, a
+// date (2006-01-02) or an RFC 3339 timestamp
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (use a duration like 2h or 3d, or a date like 2006-01-02)", value)
+}
+
+// ShowJournal prints the recorded operations, oldest first
+func (m *Manager) ShowJournal(opts JournalOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	entries, err := readJournal(filepath.Join(m.workspace, JournalFile))
+	if err != nil {
+		return fmt.Errorf("reading journal: %w", err)
+	}
+
+	shown := 0
+	for _, entry := range entries {
+		entry, ok := entry.filter(opts)
+		if !ok {
+			continue
+		}
+		if shown > 0 {
+			m.uiProvider.Info("")
+		}
+		shown++
+		m.showJournalEntry(entry)
+	}
+
+	if shown == 0 {
+		m.uiProvider.Info("📭 No journal entries found")
+	}
+	return nil
+}
+
+// showJournalEntry prints one operation and its per-node results
+func (m *Manager) showJournalEntry(entry JournalEntry) {
+	status := "✅"
+	if entry.Error != "" {
+		status = "❌"
+	}
+	header := fmt.Sprintf("%s %s  %s", status, entry.Started.Local().Format("2006-01-02 15:04:05"), entry.Operation)
+	if entry.User != "" {
+		header += "  by " + entry.User
+	}
+	if !entry.Finished.IsZero() {
+		header += "  (" + formatSeconds(entry.Finished.Sub(entry.Started).Seconds()) + ")"
+	}
+	m.uiProvider.Info(header)
+	if entry.Command != "" {
+		m.uiProvider.Info("   $ " + entry.Command)
+	}
+	if entry.Error != "" {
+		m.uiProvider.Info("   Error: " + entry.Error)
+	}
+
+	for _, node := range entry.Nodes {
+		icon := "✅"
+		if node.Result != "ok" {
+			icon = "❌"
+		}
+		line := fmt.Sprintf("   %s %-7s %s", icon, node.Action, node.Path)
+		switch {
+		case node.Before != "" && node.After != "" && node.Before != node.After:
+			line += fmt.Sprintf("  %s → %s", shortSHA(node.Before), shortSHA(node.After))
+		case node.After != "":
+			line += "  @ " + shortSHA(node.After)
+		case node.Before != "":
+			line += "  was " + shortSHA(node.Before)
+		}
+		if node.Error != "" {
+			line += "  " + node.Error
+		}
+		m.uiProvider.Info(line)
+	}
+}
+
+// shortSHA abbreviates a commit id for display
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
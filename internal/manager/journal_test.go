@@ -0,0 +1,181 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+// advancingGit moves HEAD of a repository to the commit in heads on pull and
+// commit, and to the given commit on reset
+type advancingGit struct {
+	*mocks.MockGitProvider
+	heads map[string]string
+}
+
+func (g *advancingGit) Pull(path string, options interfaces.PullOptions) error {
+	if err := g.MockGitProvider.Pull(path, options); err != nil {
+		return err
+	}
+	return g.advance(path)
+}
+
+func (g *advancingGit) Commit(path string, message string, options interfaces.CommitOptions) error {
+	if err := g.MockGitProvider.Commit(path, message, options); err != nil {
+		return err
+	}
+	return g.advance(path)
+}
+
+func (g *advancingGit) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	if err := g.MockGitProvider.ResetTo(path, commit, options); err != nil {
+		return err
+	}
+	return writeHead(path, commit)
+}
+
+func (g *advancingGit) advance(path string) error {
+	if head, ok := g.heads[path]; ok {
+		return writeHead(path, head)
+	}
+	return nil
+}
+
+func writeHead(repoPath string, commit string) error {
+	return os.WriteFile(filepath.Join(repoPath, ".git", "HEAD"), []byte(commit+"\n"), 0644)
+}
+
+func setupJournalWorkspace(t *testing.T) (*Manager, string, *mocks.MockUIProvider, *advancingGit) {
+	mgr, tmpDir, ui := setupStatsWorkspace(t)
+	instrumented := mgr.gitProvider.(*instrumentedGit)
+	git := &advancingGit{MockGitProvider: instrumented.GitProvider.(*mocks.MockGitProvider), heads: map[string]string{}}
+	instrumented.GitProvider = git
+
+	for name, head := range map[string]string{"api": "1111111111aaaa", "web": "2222222222bbbb"} {
+		writeTestFile(t, filepath.Join(tmpDir, ".nodes", name, ".git", "HEAD"), head+"\n")
+	}
+	return mgr, tmpDir, ui, git
+}
+
+func readTestJournal(t *testing.T, workspace string) []JournalEntry {
+	entries, err := readJournal(filepath.Join(workspace, JournalFile))
+	require.NoError(t, err)
+	return entries
+}
+
+func TestJournal_PullRecordsNodes(t *testing.T) {
+	mgr, tmpDir, _, git := setupJournalWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.heads[apiPath] = "3333333333cccc"
+	git.SetError("pull", webPath, errors.New("merge conflict"))
+
+	require.NoError(t, mgr.PullNode("", true, true))
+
+	entries := readTestJournal(t, tmpDir)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "pull", entry.Operation)
+	assert.Equal(t, "true", entry.Args["force"])
+	assert.NotEmpty(t, entry.ID)
+	assert.NotEmpty(t, entry.Command)
+	assert.False(t, entry.Finished.Before(entry.Started))
+	assert.Empty(t, entry.Error)
+
+	assert.ElementsMatch(t, []JournalNode{
+		{Path: "/api", Action: "pull", Result: "ok", Before: "1111111111aaaa", After: "3333333333cccc"},
+		{Path: "/web", Action: "pull", Result: "error", Error: "merge conflict", Before: "2222222222bbbb", After: "2222222222bbbb"},
+	}, entry.Nodes)
+}
+
+func TestJournal_OneEntryPerOperation(t *testing.T) {
+	mgr, tmpDir, ui, _ := setupJournalWorkspace(t)
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+
+	require.NoError(t, mgr.CommitNode("/api", "fix", false))
+	mgr.gitProvider.(*instrumentedGit).GitProvider.(*advancingGit).SetError("push", webPath, errors.New("rejected"))
+	assert.Error(t, mgr.PushNode("/web", false))
+
+	ui.SetConfirmResponse(true)
+	require.NoError(t, mgr.Remove(context.Background(), "web"))
+
+	entries := readTestJournal(t, tmpDir)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "commit", entries[0].Operation)
+	assert.Equal(t, "fix", entries[0].Args["message"])
+	assert.Equal(t, "push", entries[1].Operation)
+	assert.Equal(t, "rejected", entries[1].Error)
+	assert.Equal(t, "remove", entries[2].Operation)
+	require.Len(t, entries[2].Nodes, 1)
+	assert.Equal(t, JournalNode{Path: "/web", Action: "remove", Result: "ok", Before: "2222222222bbbb"}, entries[2].Nodes[0])
+	assert.NoDirExists(t, webPath)
+
+	// Nothing outside an entry point is journaled
+	_, err := mgr.gitProvider.Status(filepath.Join(tmpDir, ".nodes", "api"))
+	require.NoError(t, err)
+	assert.Len(t, readTestJournal(t, tmpDir), 3)
+}
+
+func TestShowJournal(t *testing.T) {
+	mgr, tmpDir, ui, git := setupJournalWorkspace(t)
+
+	require.NoError(t, mgr.ShowJournal(JournalOptions{}))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📭 No journal entries found")
+
+	git.heads[filepath.Join(tmpDir, ".nodes", "api")] = "3333333333cccc"
+	require.NoError(t, mgr.PullNode("", true, false))
+	ui.Reset()
+
+	require.NoError(t, mgr.ShowJournal(JournalOptions{Node: "api"}))
+	output := strings.Join(ui.GetMessages(), "\n")
+	assert.Contains(t, output, "pull")
+	assert.Contains(t, output, "✅ pull    /api  11111111 → 33333333")
+	assert.NotContains(t, output, "/web")
+
+	ui.Reset()
+	require.NoError(t, mgr.ShowJournal(JournalOptions{Since: time.Now().Add(time.Hour)}))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📭 No journal entries found")
+}
+
+func TestReadJournal_SkipsTruncatedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	require.NoError(t, appendJournal(path, &JournalEntry{ID: "a", Operation: "pull"}))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"id":"b","oper`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	entries, err := readJournal(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "a", entries[0].ID)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]time.Time{
+		"90m":                  now.Add(-90 * time.Minute),
+		"3d":                   now.AddDate(0, 0, -3),
+		"2026-03-01":           time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2026-03-09T08:00:00Z": time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC),
+	}
+	for value, want := range cases {
+		got, err := ParseSince(value, now)
+		require.NoError(t, err, value)
+		assert.True(t, want.Equal(got), value)
+	}
+
+	_, err := ParseSince("yesterday", now)
+	assert.ErrorContains(t, err, "invalid --since")
+}
@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/taokim/muno/internal/adapters"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+const (
+	// LogDir holds the workspace log files, relative to the workspace root
+	LogDir = ".muno/logs"
+	// LogFileName is the current log file in LogDir
+	LogFileName = "muno.log"
+
+	logFileMaxSize    = 5 * 1024 * 1024
+	logFileMaxBackups = 3
+)
+
+// LogOptions configures the logger of managers loaded from disk
+type LogOptions struct {
+	Level  string // Console threshold: debug, info, warn or error
+	Format string // logfmt or json
+}
+
+var (
+	logOptionsMu sync.Mutex
+	logOptions   = LogOptions{Level: "warn", Format: string(adapters.LogFormatLogfmt)}
+)
+
+// SetLogOptions validates and sets the logging options used by
+// LoadFromCurrentDir and NewManagerForInit
+func SetLogOptions(opts LogOptions) error {
+	if _, err := adapters.ParseLogLevel(opts.Level); err != nil {
+		return err
+	}
+	if _, err := adapters.ParseLogFormat(opts.Format); err != nil {
+		return err
+	}
+
+	logOptionsMu.Lock()
+	defer logOptionsMu.Unlock()
+	logOptions = opts
+	return nil
+}
+
+// newWorkspaceLogger logs to stderr at the configured level and to a rotating
+// file in the workspace at info level or below, so failed bulk operations can
+// be diagnosed afterwards without rerunning them with more output
+func newWorkspaceLogger(workspace string) *adapters.Logger {
+	logOptionsMu.Lock()
+	opts := logOptions
+	logOptionsMu.Unlock()
+
+	level, _ := adapters.ParseLogLevel(opts.Level)
+	format, _ := adapters.ParseLogFormat(opts.Format)
+
+	sinks := []adapters.LogSink{{Writer: os.Stderr, Level: level, Format: format, Console: true}}
+	if file, err := adapters.OpenRotatingFile(filepath.Join(workspace, LogDir, LogFileName), logFileMaxSize, logFileMaxBackups); err == nil {
+		fileLevel := level
+		if fileLevel > interfaces.LogLevelInfo {
+			fileLevel = interfaces.LogLevelInfo
+		}
+		sinks = append(sinks, adapters.LogSink{Writer: file, Level: fileLevel, Format: format})
+	}
+	return adapters.NewLogger(sinks...)
+}
+
+// logNodeFailure records a failed per-node operation with its node and error.
+// It is logged at info level so it always reaches the log file while the
+// console, at warn by default, leaves reporting the failure to the UI.
+func (m *Manager) logNodeFailure(operation string, nodePath string, err error) {
+	m.logProvider.Info(operation+" failed",
+		interfaces.Field{Key: "op", Value: operation},
+		interfaces.Field{Key: "node", Value: nodePath},
+		interfaces.Field{Key: "error", Value: err})
+}
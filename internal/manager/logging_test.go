@@ -0,0 +1,38 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLogOptions(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, SetLogOptions(LogOptions{Level: "warn", Format: "logfmt"}))
+	})
+
+	assert.Error(t, SetLogOptions(LogOptions{Level: "loud", Format: "logfmt"}))
+	assert.Error(t, SetLogOptions(LogOptions{Level: "info", Format: "xml"}))
+	require.NoError(t, SetLogOptions(LogOptions{Level: "debug", Format: "json"}))
+	assert.Equal(t, LogOptions{Level: "debug", Format: "json"}, logOptions)
+}
+
+func TestWorkspaceLogger_WritesNodeFailures(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	logger := newWorkspaceLogger(tmpDir)
+	mgr.logProvider = logger
+
+	mgr.logProvider.Debug("not in the file")
+	mgr.logNodeFailure("pull", "/api", errors.New("merge conflict"))
+	require.NoError(t, mgr.Close())
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, LogDir, LogFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `level=info msg="pull failed" op=pull node=/api error="merge conflict"`)
+	assert.Contains(t, string(data), `msg="Closing manager"`)
+	assert.NotContains(t, string(data), "not in the file")
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"time"
 	
@@ -16,6 +17,7 @@ import (
 	"github.com/taokim/muno/internal/interfaces"
 	"github.com/taokim/muno/internal/plugin"
 	"github.com/taokim/muno/internal/tree"
+	"github.com/taokim/muno/internal/tree/navigator"
 )
 
 // Embed the AI Agent Context documentation as a file system
@@ -38,9 +40,28 @@ type Manager struct {
 	config       *config.ConfigTree
 	initialized  bool
 	
+	// Live agent sessions keyed by node path, refreshed for tree display
+	agentSessions map[string]config.Session
+	
+	// Repository state cache shared with the tree manager (nil when not loaded from disk)
+	statusCache *tree.StatusCache
+	
+	// Last successful fetch by node path, refreshed for status display
+	fetchTimes map[string]time.Time
+	
+	// Journal entry of the mutating operation in progress, if any
+	journal   *JournalEntry
+	journalMu sync.Mutex
+	
+	// Serializes config reloads with scheduled fetches in long-running commands
+	reloadMu sync.Mutex
+	
 	// Configuration resolver
 	configResolver *config.ConfigResolver
 	
+	// Where error output of commands run in nodes goes; os.Stderr when nil
+	stderr io.Writer
+	
 	// Options
 	opts         ManagerOptions
 }
@@ -146,7 +167,7 @@ func (m *Manager) Initialize(ctx context.Context, workspace string) error {
 	
 	// Load configuration if auto-load is enabled
 	if m.opts.AutoLoadConfig {
-		configPath := filepath.Join(workspace, "muno.yaml")
+		configPath := config.ConfigFilePath(workspace)
 		if m.configProvider.Exists(configPath) {
 			m.logProvider.Debug("Loading configuration", 
 				interfaces.Field{Key: "path", Value: configPath})
@@ -234,10 +255,11 @@ func (m *Manager) GetConfigResolver() *config.ConfigResolver {
 
 
 // Add adds a new repository to the tree
-func (m *Manager) Add(ctx context.Context, repoURL string, options AddOptions) error {
+func (m *Manager) Add(ctx context.Context, repoURL string, options AddOptions) (err error) {
 	if !m.initialized {
 		return fmt.Errorf("manager not initialized")
 	}
+	defer m.journalOperation("add", "url", repoURL, "name", options.Name, "fetch", options.Fetch)(&err)
 	
 	m.logProvider.Info("Adding repository", 
 		interfaces.Field{Key: "url", Value: repoURL},
@@ -305,6 +327,7 @@ func (m *Manager) Add(ctx context.Context, repoURL string, options AddOptions) e
 	if err := m.treeProvider.AddNode(current.Path, newNode); err != nil {
 		return fmt.Errorf("failed to add node: %w", err)
 	}
+	m.journalStep("add", m.computeFilesystemPath(filepath.Join(current.Path, repoName)))(nil)
 	
 	// Update config to persist the change
 	if m.config != nil && current.Path == "/" {
@@ -361,14 +384,20 @@ func (m *Manager) Add(ctx context.Context, repoURL string, options AddOptions) e
 	m.uiProvider.Info(fmt.Sprintf("   Location: %s", filepath.Join(current.Path, repoName)))
 	m.metricsProvider.Counter("manager.add_repo", 1)
 	
+	// Keep generated go.work and IDE files in sync with the tree
+	if !isLazy {
+		m.syncGeneratedFiles(current.Path)
+	}
+	
 	return nil
 }
 
 // Remove removes a repository from the tree
-func (m *Manager) Remove(ctx context.Context, name string) error {
+func (m *Manager) Remove(ctx context.Context, name string) (err error) {
 	if !m.initialized {
 		return fmt.Errorf("manager not initialized")
 	}
+	defer m.journalOperation("remove", "name", name)(&err)
 	
 	m.logProvider.Info("Removing repository", 
 		interfaces.Field{Key: "name", Value: name})
@@ -422,18 +451,21 @@ func (m *Manager) Remove(ctx context.Context, name string) error {
 	}
 	
 	// Remove from filesystem if cloned
+	repoPath := m.computeFilesystemPath(nodePath)
+	done := m.journalStep("remove", repoPath)
+	var removeErr error
 	if node.IsCloned {
-		repoPath := m.computeFilesystemPath(nodePath)
 		if m.fsProvider.Exists(repoPath) {
 			m.logProvider.Debug("Removing repository files", 
 				interfaces.Field{Key: "path", Value: repoPath})
 			
-			if err := m.fsProvider.RemoveAll(repoPath); err != nil {
+			if removeErr = m.fsProvider.RemoveAll(repoPath); removeErr != nil {
 				m.logProvider.Warn("Failed to remove files", 
-					interfaces.Field{Key: "error", Value: err})
+					interfaces.Field{Key: "error", Value: removeErr})
 			}
 		}
 	}
+	done(removeErr)
 	
 	// Remove from tree
 	if err := m.treeProvider.RemoveNode(nodePath); err != nil {
@@ -467,6 +499,9 @@ func (m *Manager) Remove(ctx context.Context, name string) error {
 	m.uiProvider.Info("   File: Updated")
 	m.metricsProvider.Counter("manager.remove_repo", 1)
 	
+	// Keep generated go.work and IDE files in sync with the tree
+	m.syncGeneratedFiles(current.Path)
+	
 	return nil
 }
 
@@ -549,8 +584,7 @@ func (m *Manager) saveConfig() error {
 		return nil
 	}
 	
-	configPath := filepath.Join(m.workspace, "muno.yaml")
-	return m.configProvider.Save(configPath, m.config)
+	return m.configProvider.Save(m.workspaceConfigPath(), m.config)
 }
 
 // getSSHPreference returns the SSH preference setting from configuration
@@ -595,6 +629,18 @@ func (m *Manager) Close() error {
 		}
 	}
 	
+	// Stop config watchers
+	if closer, ok := m.configProvider.(io.Closer); ok {
+		closer.Close()
+	}
+	
+	m.saveStatusCache()
+	
+	// Close log files
+	if closer, ok := m.logProvider.(io.Closer); ok {
+		closer.Close()
+	}
+	
 	return nil
 }
 
@@ -958,7 +1004,7 @@ func (m *Manager) buildTreePathFromFilesystem(physicalPath string) string {
 		configFound = false
 		
 		// Check parent's muno.yaml
-		parentMunoYaml := filepath.Join(parentPath, "muno.yaml")
+		parentMunoYaml := config.ConfigFilePath(parentPath)
 		if m.fsProvider != nil && m.fsProvider.Exists(parentMunoYaml) {
 			if cfg, err := config.LoadTree(parentMunoYaml); err == nil && cfg != nil {
 				for _, node := range cfg.Nodes {
@@ -975,7 +1021,7 @@ func (m *Manager) buildTreePathFromFilesystem(physicalPath string) string {
 		// If parent is a repos directory (like .nodes), check grandparent's muno.yaml
 		if !configFound {
 			grandparentPath := filepath.Dir(parentPath)
-			grandparentMunoYaml := filepath.Join(grandparentPath, "muno.yaml")
+			grandparentMunoYaml := config.ConfigFilePath(grandparentPath)
 			if m.fsProvider != nil && m.fsProvider.Exists(grandparentMunoYaml) {
 				if cfg, err := config.LoadTree(grandparentMunoYaml); err == nil && cfg != nil {
 					reposDir := cfg.Workspace.ReposDir
@@ -1000,8 +1046,8 @@ func (m *Manager) buildTreePathFromFilesystem(physicalPath string) string {
 		
 		// Check for special directories to skip
 		shouldSkip = false
-		if m.fsProvider != nil && m.fsProvider.Exists(filepath.Join(parentPath, "muno.yaml")) {
-			if cfg, err := config.LoadTree(filepath.Join(parentPath, "muno.yaml")); err == nil && cfg != nil {
+		if m.fsProvider != nil && m.fsProvider.Exists(config.ConfigFilePath(parentPath)) {
+			if cfg, err := config.LoadTree(config.ConfigFilePath(parentPath)); err == nil && cfg != nil {
 				reposDir := cfg.Workspace.ReposDir
 				if reposDir == "" {
 					reposDir = ".nodes"
@@ -1122,6 +1168,54 @@ func (g *StubGitProvider) Fetch(path string, options interfaces.FetchOptions) er
 	return nil
 }
 
+func (g *StubGitProvider) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	return nil
+}
+
+func (g *StubGitProvider) Stash(path string, message string) (bool, error) {
+	return false, nil
+}
+
+func (g *StubGitProvider) StashPop(path string) error {
+	return nil
+}
+
+func (g *StubGitProvider) DiffStat(path string, options interfaces.DiffOptions) ([]interfaces.DiffStat, error) {
+	return nil, nil
+}
+
+func (g *StubGitProvider) CheckoutNew(path string, branch string) error {
+	return nil
+}
+
+func (g *StubGitProvider) DeleteBranch(path string, branch string) error {
+	return nil
+}
+
+func (g *StubGitProvider) Log(path string, options interfaces.LogOptions) ([]interfaces.LogEntry, error) {
+	return nil, nil
+}
+
+func (g *StubGitProvider) RemoteBranch(path string, remote string, branch string) (string, error) {
+	return "", nil
+}
+
+func (g *StubGitProvider) CreateBundle(path string, file string, options interfaces.LogOptions) error {
+	return nil
+}
+
+func (g *StubGitProvider) FetchBundle(path string, file string, branch string) error {
+	return nil
+}
+
+func (g *StubGitProvider) FormatPatch(path string, dir string, options interfaces.LogOptions) ([]string, error) {
+	return nil, nil
+}
+
+func (g *StubGitProvider) ApplyPatches(path string, files []string) error {
+	return nil
+}
+
 func (g *StubGitProvider) Add(path string, files []string) error {
 	return nil
 }
@@ -1154,7 +1248,9 @@ func NewStubConfigProvider() interfaces.ConfigProvider {
 }
 
 // StubConfigProvider is a stub implementation of ConfigProvider for testing
-type StubConfigProvider struct{}
+type StubConfigProvider struct {
+	watches adapters.ConfigWatchSet
+}
 
 func (c *StubConfigProvider) Load(path string) (interface{}, error) {
 	return &config.ConfigTree{}, nil
@@ -1170,9 +1266,12 @@ func (c *StubConfigProvider) Exists(path string) bool {
 }
 
 func (c *StubConfigProvider) Watch(path string) (<-chan interfaces.ConfigEvent, error) {
-	ch := make(chan interfaces.ConfigEvent)
-	close(ch)
-	return ch, nil
+	return c.watches.Watch(path)
+}
+
+// Close stops watchers started by Watch
+func (c *StubConfigProvider) Close() error {
+	return c.watches.Close()
 }
 
 // StubFileSystemProvider is a stub implementation of FileSystemProvider for testing
@@ -1588,7 +1687,7 @@ func (m *Manager) computeFilesystemPath(logicalPath string) string {
 					}
 				} else if err == nil && parentNode.Repository != "" {
 					// Parent is a git repository, check if it has a muno.yaml
-					parentMunoYaml := filepath.Join(currentPath, "muno.yaml")
+					parentMunoYaml := config.ConfigFilePath(currentPath)
 					if m.fsProvider.Exists(parentMunoYaml) {
 						// Parent has muno.yaml, use its repos_dir
 						childReposDir = constants.DefaultReposDir // default from constants
@@ -1606,7 +1705,7 @@ func (m *Manager) computeFilesystemPath(logicalPath string) string {
 
 			// If we haven't processed a config node yet, check for muno.yaml at the current path
 			if !processedConfig {
-				parentMunoYaml := filepath.Join(currentPath, "muno.yaml")
+				parentMunoYaml := config.ConfigFilePath(currentPath)
 				if m.fsProvider.Exists(parentMunoYaml) {
 					// Parent has muno.yaml, use its repos_dir without defaults
 					if reposDir, err := config.LoadTreeReposDir(parentMunoYaml); err == nil {
@@ -1633,6 +1732,7 @@ func (m *Manager) computeFilesystemPath(logicalPath string) string {
 
 // Helper function to display tree recursively
 func (m *Manager) displayTreeRecursive(node interfaces.NodeInfo, indent int) {
+	m.agentSessions = m.activeAgentSessions()
 	m.displayTreeRecursiveWithPrefix(node, "", true, true)
 }
 
@@ -1710,23 +1810,34 @@ func (m *Manager) displayTreeRecursiveWithPrefix(node interfaces.NodeInfo, prefi
 		}
 	}
 	
+	if _, ok := m.agentSessions[node.Path]; ok {
+		status = append(status, "🤖 agent")
+	}
+	if node.ConfigError != "" {
+		status = append(status, "❌ config error")
+	}
+	
 	if len(status) > 0 {
 		output += " [" + strings.Join(status, " ") + "]"
 	}
 	
 	m.uiProvider.Info(output)
 	
+	childPrefix := prefix
+	if !isRoot {
+		if isLast {
+			childPrefix += "    "
+		} else {
+			childPrefix += "│   "
+		}
+	}
+	if node.ConfigError != "" {
+		m.uiProvider.Info(fmt.Sprintf("%s⚠️  %s: %s", childPrefix, node.ConfigErrorFile, node.ConfigError))
+	}
+	
 	// Process children
 	childCount := len(node.Children)
 	for i, child := range node.Children {
-		childPrefix := prefix
-		if !isRoot {
-			if isLast {
-				childPrefix += "    "
-			} else {
-				childPrefix += "│   "
-			}
-		}
 		isLastChild := (i == childCount - 1)
 		m.displayTreeRecursiveWithPrefix(child, childPrefix, false, isLastChild)
 	}
@@ -1906,6 +2017,7 @@ func (m *Manager) ShowTreeAtPath(path string, depth int) error {
 	if !m.initialized {
 		return fmt.Errorf("manager not initialized")
 	}
+	defer m.saveStatusCache()
 	
 	// Use pwd-based resolution if path is empty
 	if path == "" {
@@ -1931,8 +2043,12 @@ func (m *Manager) ShowTreeAtPath(path string, depth int) error {
 	totalNodes, clonedNodes, lazyNodes := m.countNodes(node)
 	m.uiProvider.Info("")
 	m.uiProvider.Info("─────────────────")
-	m.uiProvider.Info(fmt.Sprintf("📊 Summary: %d total • %d cloned • %d lazy", 
-		totalNodes, clonedNodes, lazyNodes))
+	summary := fmt.Sprintf("📊 Summary: %d total • %d cloned • %d lazy", 
+		totalNodes, clonedNodes, lazyNodes)
+	if broken := collectConfigErrors(node); len(broken) > 0 {
+		summary += fmt.Sprintf(" • %d config errors (run muno doctor)", len(broken))
+	}
+	m.uiProvider.Info(summary)
 		
 	return nil
 }
@@ -2025,11 +2141,14 @@ func (m *Manager) CloneRepos(path string, recursive bool, includeLazy bool) erro
 	}
 	
 	if clonedCount == 0 {
-		m.logProvider.Info("No repositories to clone")
+		m.uiProvider.Info("No repositories to clone")
 	} else {
-		m.logProvider.Info(fmt.Sprintf("Successfully cloned %d repositories", clonedCount))
+		m.uiProvider.Success(fmt.Sprintf("Successfully cloned %d repositories", clonedCount))
 	}
 	
+	// Keep generated go.work and IDE files in sync with newly cloned repositories
+	m.syncGeneratedFiles(targetPath)
+	
 	return m.saveConfig()
 }
 
@@ -2104,6 +2223,8 @@ func (m *Manager) StatusNode(path string, recursive bool) error {
 	if !m.initialized {
 		return fmt.Errorf("manager not initialized")
 	}
+	defer m.saveStatusCache()
+	m.fetchTimes = m.loadFetchTimes()
 	
 	targetPath := path
 	if targetPath == "" {
@@ -2122,10 +2243,15 @@ func (m *Manager) StatusNode(path string, recursive bool) error {
 	// Show status
 	m.uiProvider.Info("Tree Status")
 	if recursive {
-		return m.showStatusRecursive(node)
+		nav := m.statusNavigator()
+		if closer, ok := nav.(io.Closer); ok {
+			defer closer.Close()
+		}
+		return m.showStatusRecursive(node, nav)
 	}
 	
-	status, err := m.gitProvider.Status(m.computeFilesystemPath(node.Path))
+	fsPath := m.computeFilesystemPath(node.Path)
+	status, err := m.gitProvider.Status(fsPath)
 	if err != nil {
 		return fmt.Errorf("getting status: %w", err)
 	}
@@ -2193,15 +2319,28 @@ func (m *Manager) StatusNode(path string, recursive bool) error {
 	} else {
 		statusMsg += " (clean)"
 	}
+	statusMsg += m.freshnessSuffix(node.Path, fsPath, status.Behind)
 	
 	m.uiProvider.Info(statusMsg)
+	if node.ConfigError != "" {
+		m.uiProvider.Info(fmt.Sprintf("%s: config error - %s: %s", node.Name, node.ConfigErrorFile, node.ConfigError))
+	}
 	return nil
 }
 
-func (m *Manager) showStatusRecursive(node interfaces.NodeInfo) error {
+func (m *Manager) showStatusRecursive(node interfaces.NodeInfo, nav navigator.TreeNavigator) error {
 	// Only check status for cloned repositories
-	if node.IsCloned && !node.IsLazy {
-		status, err := m.gitProvider.Status(m.computeFilesystemPath(node.Path))
+	if node.IsCloned && !node.IsLazy && nav != nil && node.Path != "/" {
+		// Status from the configured navigator, served from its cache when fresh
+		if status, err := nav.GetNodeStatus(node.Path); err == nil {
+			fsPath := m.computeFilesystemPath(node.Path)
+			m.uiProvider.Info(formatNavigatorStatus(node.Name, status) + m.freshnessSuffix(node.Path, fsPath, status.Behind))
+		} else {
+			m.uiProvider.Info(fmt.Sprintf("%s: error - %v", node.Name, err))
+		}
+	} else if node.IsCloned && !node.IsLazy {
+		fsPath := m.computeFilesystemPath(node.Path)
+		status, err := m.gitProvider.Status(fsPath)
 		if err != nil {
 			m.uiProvider.Info(fmt.Sprintf("%s: error - %v", node.Name, err))
 		} else {
@@ -2251,6 +2390,7 @@ func (m *Manager) showStatusRecursive(node interfaces.NodeInfo) error {
 			} else {
 				statusMsg += " (clean)"
 			}
+			statusMsg += m.freshnessSuffix(node.Path, fsPath, status.Behind)
 			
 			m.uiProvider.Info(statusMsg)
 		}
@@ -2258,9 +2398,12 @@ func (m *Manager) showStatusRecursive(node interfaces.NodeInfo) error {
 		// Show lazy repositories that haven't been cloned
 		m.uiProvider.Info(fmt.Sprintf("%s: (lazy - not cloned)", node.Name))
 	}
+	if node.ConfigError != "" {
+		m.uiProvider.Info(fmt.Sprintf("%s: config error - %s: %s", node.Name, node.ConfigErrorFile, node.ConfigError))
+	}
 	
 	for _, child := range node.Children {
-		if err := m.showStatusRecursive(child); err != nil {
+		if err := m.showStatusRecursive(child, nav); err != nil {
 			return err
 		}
 	}
@@ -2269,13 +2412,15 @@ func (m *Manager) showStatusRecursive(node interfaces.NodeInfo) error {
 }
 
 // PullNodeWithOptions pulls changes for a node with additional options
-func (m *Manager) PullNodeWithOptions(path string, recursive bool, force bool, includeLazy bool) error {
+func (m *Manager) PullNodeWithOptions(path string, recursive bool, force bool, includeLazy bool) (err error) {
 	if !m.initialized {
 		return fmt.Errorf("manager not initialized")
 	}
+	defer m.journalOperation("pull", "path", path, "recursive", recursive, "force", force, "include_lazy", includeLazy)(&err)
 	
 	// Handle --all case (empty path with recursive flag)
 	if path == "" && recursive {
+		m.refreshRemoteConfigs("/")
 		return m.pullAllRepositories(force)
 	}
 	
@@ -2287,6 +2432,7 @@ func (m *Manager) PullNodeWithOptions(path string, recursive bool, force bool, i
 			return fmt.Errorf("resolving current tree path: %w", err)
 		}
 	}
+	m.refreshRemoteConfigs(targetPath)
 	
 	node, err := m.treeProvider.GetNode(targetPath)
 	if err != nil {
@@ -2305,6 +2451,7 @@ func (m *Manager) PullNodeWithOptions(path string, recursive bool, force bool, i
 	pullOpts := interfaces.PullOptions{Force: force}
 	if err := m.gitProvider.Pull(fullPath, pullOpts); err != nil {
 		m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", err))
+		m.logNodeFailure("pull", node.Path, err)
 		return err
 	}
 	
@@ -2313,13 +2460,15 @@ func (m *Manager) PullNodeWithOptions(path string, recursive bool, force bool, i
 }
 
 // PullNode pulls changes for a node (or all nodes if path is empty and recursive is true)
-func (m *Manager) PullNode(path string, recursive bool, force bool) error {
+func (m *Manager) PullNode(path string, recursive bool, force bool) (err error) {
 	if !m.initialized {
 		return fmt.Errorf("manager not initialized")
 	}
+	defer m.journalOperation("pull", "path", path, "recursive", recursive, "force", force)(&err)
 	
 	// Handle --all case (empty path with recursive flag)
 	if path == "" && recursive {
+		m.refreshRemoteConfigs("/")
 		return m.pullAllRepositories(force)
 	}
 	
@@ -2331,6 +2480,7 @@ func (m *Manager) PullNode(path string, recursive bool, force bool) error {
 			return fmt.Errorf("resolving current tree path: %w", err)
 		}
 	}
+	m.refreshRemoteConfigs(targetPath)
 	
 	node, err := m.treeProvider.GetNode(targetPath)
 	if err != nil {
@@ -2349,6 +2499,7 @@ func (m *Manager) PullNode(path string, recursive bool, force bool) error {
 	pullOpts := interfaces.PullOptions{Force: force}
 	if err := m.gitProvider.Pull(fullPath, pullOpts); err != nil {
 		m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", err))
+		m.logNodeFailure("pull", node.Path, err)
 		return err
 	}
 	
@@ -2388,6 +2539,7 @@ func (m *Manager) pullAllRepositories(force bool) error {
 		
 		if err := m.gitProvider.Pull(fullPath, pullOpts); err != nil {
 			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", err))
+			m.logNodeFailure("pull", node.Path, err)
 			failedRepos = append(failedRepos, node.Name)
 		} else {
 			m.uiProvider.Success("   ✅ Success")
@@ -2418,6 +2570,13 @@ func (m *Manager) pullAllRepositories(force bool) error {
 
 // collectClonedRepos collects all cloned repositories recursively
 func (m *Manager) collectClonedRepos(node interfaces.NodeInfo) []interfaces.NodeInfo {
+	return m.collectRepos(node, true)
+}
+
+// collectRepos collects the repositories in a subtree, expanding config
+// nodes. With clonedOnly unset, repositories that are not cloned and git
+// parents with children are included too.
+func (m *Manager) collectRepos(node interfaces.NodeInfo, clonedOnly bool) []interfaces.NodeInfo {
 	var repos []interfaces.NodeInfo
 	
 	// Handle config nodes - expand them to find repositories
@@ -2439,19 +2598,20 @@ func (m *Manager) collectClonedRepos(node interfaces.NodeInfo) []interfaces.Node
 				if nodeDef.URL != "" {
 					// It's a repository - check if it's cloned
 					childFsPath := m.computeFilesystemPath(childPath)
-					if _, err := os.Stat(filepath.Join(childFsPath, ".git")); err == nil {
-						// Repository is cloned, add it
+					_, statErr := os.Stat(filepath.Join(childFsPath, ".git"))
+					if statErr == nil || !clonedOnly {
 						repos = append(repos, interfaces.NodeInfo{
 							Name:       nodeDef.Name,
 							Path:       childPath,
 							Repository: nodeDef.URL,
-							IsCloned:   true,
+							IsLazy:     nodeDef.IsLazy(),
+							IsCloned:   statErr == nil,
 						})
 					}
 				} else if nodeDef.File != "" {
 					// It's another config node - recurse into it
 					childConfigFile := nodeDef.File
-					if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") {
+					if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") && !config.IsRemoteRef(childConfigFile) {
 						currentConfigDir := filepath.Dir(configFilePath)
 						childConfigFile = filepath.Join(currentConfigDir, nodeDef.File)
 					}
@@ -2462,18 +2622,20 @@ func (m *Manager) collectClonedRepos(node interfaces.NodeInfo) []interfaces.Node
 						ConfigFile: childConfigFile,
 						IsConfig:   true,
 					}
-					repos = append(repos, m.collectClonedRepos(childNode)...)
+					repos = append(repos, m.collectRepos(childNode, clonedOnly)...)
 				}
 			}
 		}
-	} else if len(node.Children) == 0 && node.IsCloned {
+	} else if clonedOnly && len(node.Children) == 0 && node.IsCloned {
 		// Terminal node that is cloned
 		repos = append(repos, node)
+	} else if !clonedOnly && node.Repository != "" {
+		repos = append(repos, node)
 	}
 	
 	// Recurse into children (for non-config nodes)
 	for _, child := range node.Children {
-		repos = append(repos, m.collectClonedRepos(child)...)
+		repos = append(repos, m.collectRepos(child, clonedOnly)...)
 	}
 	
 	return repos
@@ -2492,6 +2654,7 @@ func (m *Manager) pullRecursiveWithOptions(node interfaces.NodeInfo, force bool,
 		}
 		if err := m.gitProvider.Clone(node.Repository, fullPath, cloneOptions); err != nil {
 			m.uiProvider.Error(fmt.Sprintf("   ❌ Clone failed: %v", err))
+			m.logNodeFailure("clone", node.Path, err)
 			return fmt.Errorf("cloning %s: %w", node.Name, err)
 		}
 		
@@ -2510,6 +2673,7 @@ func (m *Manager) pullRecursiveWithOptions(node interfaces.NodeInfo, force bool,
 		}
 		
 		m.uiProvider.Success(fmt.Sprintf("   ✅ Cloned successfully: %s", node.Name))
+		m.syncGeneratedFiles(node.Path)
 	}
 	
 	// Handle config nodes - expand them to find repositories
@@ -2547,7 +2711,7 @@ func (m *Manager) pullRecursiveWithOptions(node interfaces.NodeInfo, force bool,
 				} else if nodeDef.File != "" {
 					// Nested config node
 					childConfigFile := nodeDef.File
-					if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") {
+					if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") && !config.IsRemoteRef(childConfigFile) {
 						currentConfigDir := filepath.Dir(configFilePath)
 						childConfigFile = filepath.Join(currentConfigDir, nodeDef.File)
 					}
@@ -2573,6 +2737,7 @@ func (m *Manager) pullRecursiveWithOptions(node interfaces.NodeInfo, force bool,
 		pullOpts := interfaces.PullOptions{Force: force}
 		if err := m.gitProvider.Pull(fullPath, pullOpts); err != nil {
 			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed at %s: %v", node.Path, err))
+			m.logNodeFailure("pull", node.Path, err)
 			// Don't stop on error, continue with other repos
 		} else {
 			m.uiProvider.Success(fmt.Sprintf("   ✅ Success: %s", node.Name))
@@ -2618,6 +2783,7 @@ func (m *Manager) pullRecursive(node interfaces.NodeInfo, force bool) error {
 						pullOpts := interfaces.PullOptions{Force: force}
 						if err := m.gitProvider.Pull(childFsPath, pullOpts); err != nil {
 							m.uiProvider.Error(fmt.Sprintf("   ❌ Failed at %s: %v", childPath, err))
+							m.logNodeFailure("pull", childPath, err)
 						} else {
 							m.uiProvider.Success(fmt.Sprintf("   ✅ Success: %s", nodeDef.Name))
 						}
@@ -2625,7 +2791,7 @@ func (m *Manager) pullRecursive(node interfaces.NodeInfo, force bool) error {
 				} else if nodeDef.File != "" {
 					// It's another config node - recurse into it
 					childConfigFile := nodeDef.File
-					if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") {
+					if !filepath.IsAbs(childConfigFile) && !strings.HasPrefix(childConfigFile, "http") && !config.IsRemoteRef(childConfigFile) {
 						currentConfigDir := filepath.Dir(configFilePath)
 						childConfigFile = filepath.Join(currentConfigDir, nodeDef.File)
 					}
@@ -2650,6 +2816,7 @@ func (m *Manager) pullRecursive(node interfaces.NodeInfo, force bool) error {
 		pullOpts := interfaces.PullOptions{Force: force}
 		if err := m.gitProvider.Pull(fullPath, pullOpts); err != nil {
 			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed at %s: %v", node.Path, err))
+			m.logNodeFailure("pull", node.Path, err)
 			// Don't stop on error, continue with other repos
 		} else {
 			m.uiProvider.Success(fmt.Sprintf("   ✅ Success: %s", node.Name))
@@ -2666,112 +2833,6 @@ func (m *Manager) pullRecursive(node interfaces.NodeInfo, force bool) error {
 	return nil
 }
 
-// PushNode pushes changes for a node
-func (m *Manager) PushNode(path string, recursive bool) error {
-	if !m.initialized {
-		return fmt.Errorf("manager not initialized")
-	}
-	
-	targetPath := path
-	if targetPath == "" {
-		var err error
-		targetPath, err = m.getCurrentTreePath()
-		if err != nil {
-			return fmt.Errorf("resolving current tree path: %w", err)
-		}
-	}
-	
-	node, err := m.treeProvider.GetNode(targetPath)
-	if err != nil {
-		return fmt.Errorf("getting node: %w", err)
-	}
-	
-	if recursive {
-		return m.pushRecursive(node)
-	}
-	
-	fullPath := m.computeFilesystemPath(node.Path)
-	m.logProvider.Info("Pushing changes")
-	m.logProvider.Info(fmt.Sprintf("  Tree path: %s", node.Path))
-	m.logProvider.Info(fmt.Sprintf("  Directory: %s", fullPath))
-	return m.gitProvider.Push(fullPath, interfaces.PushOptions{})
-}
-
-func (m *Manager) pushRecursive(node interfaces.NodeInfo) error {
-	if node.IsCloned {
-		m.logProvider.Info(fmt.Sprintf("Pushing changes at %s", node.Path))
-		if err := m.gitProvider.Push(m.computeFilesystemPath(node.Path), interfaces.PushOptions{}); err != nil {
-			m.logProvider.Error(fmt.Sprintf("Push failed at %s: %v", node.Path, err))
-		}
-	}
-	
-	for _, child := range node.Children {
-		if err := m.pushRecursive(child); err != nil {
-			return err
-		}
-	}
-	
-	return nil
-}
-
-// CommitNode commits changes for a node
-func (m *Manager) CommitNode(path string, message string, recursive bool) error {
-	if !m.initialized {
-		return fmt.Errorf("manager not initialized")
-	}
-	
-	targetPath := path
-	if targetPath == "" {
-		var err error
-		targetPath, err = m.getCurrentTreePath()
-		if err != nil {
-			return fmt.Errorf("resolving current tree path: %w", err)
-		}
-	}
-	
-	node, err := m.treeProvider.GetNode(targetPath)
-	if err != nil {
-		return fmt.Errorf("getting node: %w", err)
-	}
-	
-	if recursive {
-		return m.commitRecursive(node, message)
-	}
-	
-	// Single node commit
-	fullPath := m.computeFilesystemPath(node.Path)
-	m.logProvider.Info(fmt.Sprintf("Committing changes: %s", message))
-	m.logProvider.Info(fmt.Sprintf("  Tree path: %s", node.Path))
-	m.logProvider.Info(fmt.Sprintf("  Directory: %s", fullPath))
-	return m.gitProvider.Commit(fullPath, message, interfaces.CommitOptions{})
-}
-
-// commitRecursive commits changes in the node and all its children
-func (m *Manager) commitRecursive(node interfaces.NodeInfo, message string) error {
-	// Commit current node if it's a cloned git repo
-	if node.IsCloned {
-		fullPath := m.computeFilesystemPath(node.Path)
-		m.logProvider.Info(fmt.Sprintf("Committing %s: %s", node.Path, message))
-		if err := m.gitProvider.Commit(fullPath, message, interfaces.CommitOptions{}); err != nil {
-			m.logProvider.Error(fmt.Sprintf("Commit failed at %s: %v", node.Path, err))
-			// Continue with other repos even if one fails
-		}
-	}
-	
-	// Recurse to children
-	for _, child := range node.Children {
-		if err := m.commitRecursive(child, message); err != nil {
-			return err
-		}
-	}
-	
-	return nil
-}
-
-
-
-
-
 // ensureGitignoreEntry adds an entry to .gitignore if not already present
 func (m *Manager) ensureGitignoreEntry(workDir string, entry string) error {
 	// Check if this is a git repository
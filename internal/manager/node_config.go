@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/taokim/muno/internal/config"
+)
+
+// nodeDefinition finds the config definition of a tree node, following
+// config references and repository muno.yaml files, or nil
+func (m *Manager) nodeDefinition(treePath string) *config.NodeDefinition {
+	if m.config == nil {
+		return nil
+	}
+	nodes := m.config.Nodes
+	configDir := filepath.Dir(m.workspaceConfigPath())
+	parentPath := "/"
+	for _, name := range strings.Split(strings.Trim(treePath, "/"), "/") {
+		var found *config.NodeDefinition
+		for i := range nodes {
+			if nodes[i].Name == name {
+				found = &nodes[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		childPath := strings.TrimSuffix(parentPath, "/") + "/" + name
+		if childPath == "/"+strings.Trim(treePath, "/") {
+			return found
+		}
+
+		childConfigPath := m.childConfigPath(*found, childPath, configDir)
+		if childConfigPath == "" {
+			return nil
+		}
+		cfg, err := config.LoadTree(childConfigPath)
+		if err != nil {
+			return nil
+		}
+		nodes, configDir, parentPath = cfg.Nodes, filepath.Dir(childConfigPath), childPath
+	}
+	return nil
+}
+
+// childConfigPath returns the config file declaring the children of a node:
+// its file reference, or the muno.yaml of a cloned repository
+func (m *Manager) childConfigPath(nodeDef config.NodeDefinition, childPath string, configDir string) string {
+	if nodeDef.File != "" {
+		if config.IsRemoteRef(nodeDef.File) {
+			return m.resolveConfigPath(nodeDef.File, childPath)
+		}
+		if filepath.IsAbs(nodeDef.File) || strings.HasPrefix(nodeDef.File, "http") {
+			return nodeDef.File
+		}
+		childConfigPath := filepath.Join(configDir, nodeDef.File)
+		if _, err := os.Stat(childConfigPath); err != nil {
+			childConfigPath = m.resolveConfigPath(nodeDef.File, childPath)
+		}
+		return childConfigPath
+	}
+	if nodeDef.URL != "" {
+		// Repositories may carry their own muno.yaml with further nodes
+		return config.ConfigFilePath(m.computeFilesystemPath(childPath))
+	}
+	return ""
+}
+
+// nodeSetting is setting resolved for a tree node, with the overrides of its
+// definition applied
+func (m *Manager) nodeSetting(treePath string, key string, fallback string) string {
+	if m.configResolver == nil {
+		return fallback
+	}
+	value := m.configResolver.GetValue(key, m.nodeDefinition(treePath))
+	if value == nil {
+		return fallback
+	}
+	if s := fmt.Sprint(value); s != "" {
+		return s
+	}
+	return fallback
+}
+
+// nodeSettingList returns a list setting resolved for a tree node. A single
+// string is treated as a comma-separated list.
+func (m *Manager) nodeSettingList(treePath string, key string) []string {
+	if m.configResolver == nil {
+		return nil
+	}
+	var items []string
+	switch value := m.configResolver.GetValue(key, m.nodeDefinition(treePath)).(type) {
+	case []string:
+		items = value
+	case []interface{}:
+		for _, item := range value {
+			items = append(items, fmt.Sprint(item))
+		}
+	case string:
+		items = strings.Split(value, ",")
+	}
+	var list []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -137,6 +137,54 @@ func (g *EnhancedGitProviderStub) Fetch(path string, options interfaces.FetchOpt
 	return nil
 }
 
+func (g *EnhancedGitProviderStub) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	return nil
+}
+
+func (g *EnhancedGitProviderStub) Stash(path string, message string) (bool, error) {
+	return false, nil
+}
+
+func (g *EnhancedGitProviderStub) StashPop(path string) error {
+	return nil
+}
+
+func (g *EnhancedGitProviderStub) DiffStat(path string, options interfaces.DiffOptions) ([]interfaces.DiffStat, error) {
+	return nil, nil
+}
+
+func (g *EnhancedGitProviderStub) CheckoutNew(path string, branch string) error {
+	return nil
+}
+
+func (g *EnhancedGitProviderStub) DeleteBranch(path string, branch string) error {
+	return nil
+}
+
+func (g *EnhancedGitProviderStub) Log(path string, options interfaces.LogOptions) ([]interfaces.LogEntry, error) {
+	return nil, nil
+}
+
+func (g *EnhancedGitProviderStub) RemoteBranch(path string, remote string, branch string) (string, error) {
+	return "", nil
+}
+
+func (g *EnhancedGitProviderStub) CreateBundle(path string, file string, options interfaces.LogOptions) error {
+	return nil
+}
+
+func (g *EnhancedGitProviderStub) FetchBundle(path string, file string, branch string) error {
+	return nil
+}
+
+func (g *EnhancedGitProviderStub) FormatPatch(path string, dir string, options interfaces.LogOptions) ([]string, error) {
+	return nil, nil
+}
+
+func (g *EnhancedGitProviderStub) ApplyPatches(path string, files []string) error {
+	return nil
+}
+
 func (g *EnhancedGitProviderStub) Add(path string, files []string) error {
 	return nil
 }
@@ -0,0 +1,195 @@
+package manager
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// PushOptions controls muno push
+type PushOptions struct {
+	Recursive      bool // Push every repository below the node
+	ForceWithLease bool // Overwrite remote history unless it moved since the last fetch
+}
+
+// pushResult is the outcome of pushing one repository
+type pushResult struct {
+	path    string
+	branch  string
+	outcome string // Shown in the summary table
+	skipped bool
+	err     error // Set when the push failed or was refused
+}
+
+// PushNode pushes changes for a node, or for every repository below it when
+// recursive
+func (m *Manager) PushNode(path string, recursive bool) error {
+	return m.PushNodeWithOptions(path, PushOptions{Recursive: recursive})
+}
+
+// PushNodeWithOptions pushes changes for a node. Each repository is checked
+// first: branches without an upstream get one on the default remote, branches
+// with nothing to push and detached HEADs are skipped, and pushes to branches
+// matching git.protected_branches are refused.
+func (m *Manager) PushNodeWithOptions(path string, opts PushOptions) (err error) {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	defer m.journalOperation("push", "path", path, "recursive", opts.Recursive)(&err)
+
+	targetPath := path
+	if targetPath == "" {
+		var err error
+		targetPath, err = m.getCurrentTreePath()
+		if err != nil {
+			return fmt.Errorf("resolving current tree path: %w", err)
+		}
+	}
+
+	node, err := m.treeProvider.GetNode(targetPath)
+	if err != nil {
+		return fmt.Errorf("getting node: %w", err)
+	}
+
+	if opts.Recursive {
+		return m.pushRecursive(node, opts)
+	}
+
+	// Single node push
+	m.uiProvider.Info("Pushing changes")
+	m.uiProvider.Info(fmt.Sprintf("  Tree path: %s", node.Path))
+	m.uiProvider.Info(fmt.Sprintf("  Directory: %s", m.computeFilesystemPath(node.Path)))
+	result := m.pushRepo(node, opts)
+	if result.err != nil {
+		return result.err
+	}
+	m.uiProvider.Info(fmt.Sprintf("  %s", result.outcome))
+	return nil
+}
+
+// pushRecursive pushes the repositories in the node's subtree and prints a
+// summary table
+func (m *Manager) pushRecursive(node interfaces.NodeInfo, opts PushOptions) error {
+	repos := m.subtreeRepos(node)
+	if len(repos) == 0 {
+		m.uiProvider.Info("📭 No cloned repositories to push")
+		return nil
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("🚀 Pushing %d repositories...", len(repos)))
+	m.uiProvider.Info("─────────────────")
+
+	results := make([]pushResult, 0, len(repos))
+	pushed, skipped, failed := 0, 0, 0
+	for _, repo := range repos {
+		m.uiProvider.Info(fmt.Sprintf("📦 Pushing: %s", repo.Path))
+		result := m.pushRepo(repo, opts)
+		switch {
+		case result.err != nil:
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed: %v", result.err))
+			m.logNodeFailure("push", repo.Path, result.err)
+			failed++
+		case result.skipped:
+			m.uiProvider.Info(fmt.Sprintf("   %s", result.outcome))
+			skipped++
+		default:
+			m.uiProvider.Success(fmt.Sprintf("   %s", result.outcome))
+			pushed++
+		}
+		results = append(results, result)
+	}
+
+	m.metricsProvider.Counter("manager.push", int64(pushed))
+	m.uiProvider.Info("")
+	m.showPushSummary(results)
+	m.uiProvider.Info(fmt.Sprintf("📊 Results: %d pushed, %d skipped, %d failed", pushed, skipped, failed))
+	if failed > 0 {
+		return fmt.Errorf("push failed for %d repositories", failed)
+	}
+	return nil
+}
+
+// pushRepo runs the pre-flight checks for one repository and pushes it
+func (m *Manager) pushRepo(node interfaces.NodeInfo, opts PushOptions) pushResult {
+	result := pushResult{path: node.Path}
+	fullPath := m.computeFilesystemPath(node.Path)
+	status, err := m.gitProvider.Status(fullPath)
+	if err != nil {
+		result.err = fmt.Errorf("reading status: %w", err)
+		result.outcome = "❌ failed: cannot read status"
+		return result
+	}
+	result.branch = status.Branch
+
+	if status.Branch == "" || status.Branch == "HEAD" {
+		result.skipped = true
+		result.outcome = "⏭️  skipped: detached HEAD"
+		return result
+	}
+	// A branch only behind its upstream is never pushed: rewinding the remote
+	// would drop commits fetched but not pulled, which the lease allows
+	if status.Upstream != "" && status.Ahead == 0 {
+		result.skipped = true
+		result.outcome = "⏭️  nothing to push"
+		return result
+	}
+	if pattern, ok := m.protectedBranch(node.Path, status.Branch); ok {
+		result.err = fmt.Errorf("branch %s is protected (git.protected_branches: %s)", status.Branch, pattern)
+		result.outcome = "⛔ refused: protected branch"
+		return result
+	}
+
+	// The lease is only needed, and only used, where history diverged
+	force := opts.ForceWithLease && status.Upstream != "" && status.Ahead > 0 && status.Behind > 0
+	pushOpts := interfaces.PushOptions{ForceWithLease: force}
+	if status.Upstream == "" {
+		remote := m.nodeSetting(node.Path, "git.default_remote", "origin")
+		pushOpts.SetUpstream = true
+		pushOpts.Remote = remote
+		pushOpts.Branch = status.Branch
+		result.outcome = fmt.Sprintf("✅ pushed, upstream set to %s/%s", remote, status.Branch)
+	} else if force {
+		result.outcome = fmt.Sprintf("✅ replaced %s of %s with %s", pluralize(status.Behind, "commit"), status.Upstream, pluralize(status.Ahead, "commit"))
+	} else {
+		result.outcome = fmt.Sprintf("✅ pushed %s to %s", pluralize(status.Ahead, "commit"), status.Upstream)
+	}
+	if force {
+		result.outcome += " (force-with-lease)"
+	}
+
+	if err := m.gitProvider.Push(fullPath, pushOpts); err != nil {
+		result.err = err
+		result.outcome = "❌ failed: " + strings.TrimSpace(strings.SplitN(err.Error(), "\n", 2)[0])
+	}
+	return result
+}
+
+// protectedBranch returns the git.protected_branches pattern the branch
+// matches for the node, if any
+func (m *Manager) protectedBranch(treePath string, branch string) (string, bool) {
+	for _, pattern := range m.nodeSettingList(treePath, "git.protected_branches") {
+		if pattern == branch {
+			return pattern, true
+		}
+		if ok, _ := path.Match(pattern, branch); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// showPushSummary prints one row per repository
+func (m *Manager) showPushSummary(results []pushResult) {
+	repoWidth, branchWidth := len("REPOSITORY"), len("BRANCH")
+	for _, result := range results {
+		repoWidth = max(repoWidth, len(result.path))
+		branchWidth = max(branchWidth, len(result.branch))
+	}
+	m.uiProvider.Info(fmt.Sprintf("%-*s  %-*s  %s", repoWidth, "REPOSITORY", branchWidth, "BRANCH", "RESULT"))
+	for _, result := range results {
+		m.uiProvider.Info(fmt.Sprintf("%-*s  %-*s  %s", repoWidth, result.path, branchWidth, result.branch, result.outcome))
+	}
+	m.uiProvider.Info("")
+}
@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+func TestPushRecursive_PreflightAndSummary(t *testing.T) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "feature/login"})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", Ahead: 2})
+
+	require.NoError(t, mgr.PushNodeWithOptions("/", PushOptions{Recursive: true, ForceWithLease: true}))
+
+	// A new branch is pushed with its upstream set on the default remote
+	options, ok := git.GetPushOptions(apiPath)
+	require.True(t, ok)
+	assert.Equal(t, interfaces.PushOptions{SetUpstream: true, Remote: "origin", Branch: "feature/login"}, options)
+	options, ok = git.GetPushOptions(webPath)
+	require.True(t, ok)
+	assert.Equal(t, interfaces.PushOptions{}, options, "a branch only ahead needs no lease")
+
+	messages := ui.GetMessages()
+	assert.Contains(t, messages, "INFO: REPOSITORY  BRANCH         RESULT")
+	assert.Contains(t, messages, "INFO: /api        feature/login  ✅ pushed, upstream set to origin/feature/login")
+	assert.Contains(t, messages, "INFO: /web        main           ✅ pushed 2 commits to origin/main")
+	assert.Contains(t, messages, "INFO: 📊 Results: 2 pushed, 0 skipped, 0 failed")
+
+	// Up to date branches and detached HEADs are skipped
+	git.Reset()
+	ui.Reset()
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "HEAD"})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", Behind: 3})
+	require.NoError(t, mgr.PushNode("/", true))
+	assert.NotContains(t, git.GetCalls(), "Push("+apiPath+")")
+	assert.NotContains(t, git.GetCalls(), "Push("+webPath+")")
+	assert.Contains(t, ui.GetMessages(), "INFO: 📊 Results: 0 pushed, 2 skipped, 0 failed")
+
+	// With a lease, only diverged history is overwritten; a branch behind
+	// its upstream keeps the fetched commits on the remote
+	git.Reset()
+	ui.Reset()
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", Behind: 3})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", Ahead: 1, Behind: 3})
+	require.NoError(t, mgr.PushNodeWithOptions("/", PushOptions{Recursive: true, ForceWithLease: true}))
+	_, ok = git.GetPushOptions(apiPath)
+	assert.False(t, ok, "a branch behind its upstream is not rewound even with a lease")
+	options, ok = git.GetPushOptions(webPath)
+	require.True(t, ok)
+	assert.Equal(t, interfaces.PushOptions{ForceWithLease: true}, options)
+	messages = ui.GetMessages()
+	assert.Contains(t, messages, "INFO: /web        main    ✅ replaced 3 commits of origin/main with 1 commit (force-with-lease)")
+	assert.Contains(t, messages, "INFO: 📊 Results: 1 pushed, 1 skipped, 0 failed")
+}
+
+func TestPushRecursive_ProtectedAndFailed(t *testing.T) {
+	mgr, tmpDir, ui, git := setupCommitWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	mgr.config.Nodes[0].Overrides = map[string]interface{}{
+		"git": map[string]interface{}{"protected_branches": []interface{}{"main", "release/*"}},
+	}
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "release/1.2", Upstream: "origin/release/1.2", Ahead: 1})
+	git.SetStatus(webPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", Ahead: 1})
+	git.SetError("push", webPath, errors.New("rejected (fetch first)"))
+
+	err := mgr.PushNode("/", true)
+	assert.EqualError(t, err, "push failed for 2 repositories")
+	assert.NotContains(t, git.GetCalls(), "Push("+apiPath+")")
+	assert.Contains(t, git.GetCalls(), "Push("+webPath+")", "protected branches are configured per node")
+
+	messages := ui.GetMessages()
+	assert.Contains(t, messages, "INFO: /api        release/1.2  ⛔ refused: protected branch")
+	assert.Contains(t, messages, "INFO: /web        main         ❌ failed: rejected (fetch first)")
+	assert.Contains(t, messages, "INFO: 📊 Results: 0 pushed, 0 skipped, 2 failed")
+
+	err = mgr.PushNode("/api", false)
+	assert.EqualError(t, err, "branch release/1.2 is protected (git.protected_branches: release/*)")
+}
+
+func TestNodeSettingList(t *testing.T) {
+	mgr, _ := setupContextWorkspace(t)
+	assert.Empty(t, mgr.nodeSettingList("/api", "git.protected_branches"))
+
+	mgr.configResolver.SetWorkspaceConfig(map[string]interface{}{
+		"git": map[string]interface{}{"protected_branches": "main, production"},
+	})
+	assert.Equal(t, []string{"main", "production"}, mgr.nodeSettingList("/web", "git.protected_branches"))
+	assert.Equal(t, "origin", mgr.nodeSetting("/web", "git.default_remote", "upstream"))
+	assert.Nil(t, mgr.nodeDefinition("/missing"))
+}
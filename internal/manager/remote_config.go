@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/tree"
+)
+
+// refreshRemoteConfigs moves the remote configs referenced by the node at
+// treePath and the nodes below it to the commit their ref names now, and
+// reloads the tree when one moved. Pins of configs outside the subtree or no
+// longer referenced are not fetched. Failures are reported but do not stop
+// the pull; the old pin stays in use.
+func (m *Manager) refreshRemoteConfigs(treePath string) {
+	cache := tree.RemoteConfigCache(m.workspace)
+	pins, err := cache.Pins()
+	if err != nil {
+		m.uiProvider.Warning(fmt.Sprintf("⚠️  Reading remote config cache: %v", err))
+		return
+	}
+	if len(pins) == 0 {
+		return
+	}
+	referenced := m.remoteConfigRefs(treePath)
+
+	moved := false
+	header := false
+	for _, pin := range pins {
+		if !referenced[pin.Repo+"@"+pin.Ref] {
+			continue
+		}
+		label := pin.Repo
+		if pin.Ref != "" {
+			label += "@" + pin.Ref
+		}
+		if !header {
+			m.uiProvider.Info("🔄 Refreshing remote configs...")
+			header = true
+		}
+		from, to, err := cache.Refresh(pin.Repo, pin.Ref)
+		switch {
+		case err != nil:
+			m.uiProvider.Warning(fmt.Sprintf("   ⚠️  %s: %v", label, err))
+			m.logProvider.Warn(fmt.Sprintf("Failed to refresh remote config %s: %v", label, err))
+		case from != to:
+			m.uiProvider.Success(fmt.Sprintf("   📌 %s: %s → %s", label, shortSHA(from), shortSHA(to)))
+			moved = true
+		default:
+			m.uiProvider.Info(fmt.Sprintf("   ✅ %s is up to date", label))
+		}
+	}
+	if !header {
+		return
+	}
+
+	// Nodes declared by a moved config appear or disappear only on reload
+	if moved {
+		if err := m.ReloadConfig(); err != nil {
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  Reloading the tree with the refreshed configs: %v", err))
+		}
+	}
+	m.uiProvider.Info("")
+}
+
+// remoteConfigRefs returns the repository@ref of every remote config the
+// node at treePath and the nodes below it reference
+func (m *Manager) remoteConfigRefs(treePath string) map[string]bool {
+	refs := make(map[string]bool)
+	if m.config != nil {
+		m.collectRemoteConfigRefs(m.config.Nodes, "/", filepath.Dir(m.workspaceConfigPath()), treePath, refs, 0)
+	}
+	return refs
+}
+
+func (m *Manager) collectRemoteConfigRefs(nodes []config.NodeDefinition, parentPath string, configDir string, treePath string, refs map[string]bool, depth int) {
+	// Guard against config files that include each other
+	if depth > 32 {
+		return
+	}
+
+	for _, nodeDef := range nodes {
+		childPath := strings.TrimSuffix(parentPath, "/") + "/" + nodeDef.Name
+		inSubtree := isTreePathWithin(childPath, treePath)
+		if !inSubtree && !isTreePathWithin(treePath, childPath) {
+			continue
+		}
+
+		if inSubtree && config.IsRemoteRef(nodeDef.File) {
+			if ref, err := config.ParseRemoteRef(nodeDef.File); err == nil {
+				refs[ref.Repo+"@"+ref.Ref] = true
+			}
+		}
+
+		childConfigPath := m.childConfigPath(nodeDef, childPath, configDir)
+		if childConfigPath == "" {
+			continue
+		}
+		if cfg, err := config.LoadTree(childConfigPath); err == nil {
+			m.collectRemoteConfigRefs(cfg.Nodes, childPath, filepath.Dir(childConfigPath), treePath, refs, depth+1)
+		}
+	}
+}
+
+// isTreePathWithin reports whether treePath is root or a node below it
+func isTreePathWithin(treePath, root string) bool {
+	root = strings.TrimSuffix(root, "/")
+	return root == "" || treePath == root || strings.HasPrefix(treePath, root+"/")
+}
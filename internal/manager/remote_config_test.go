@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/mocks"
+	"github.com/taokim/muno/internal/tree"
+)
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestRemoteConfigReference(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repoDir := t.TempDir()
+	bare, work := filepath.Join(repoDir, "platform.git"), filepath.Join(repoDir, "work")
+	runTestGit(t, repoDir, "init", "--quiet", "--bare", bare)
+	runTestGit(t, repoDir, "clone", "--quiet", bare, work)
+	publish := func(branch string) {
+		writeTestFile(t, filepath.Join(work, "muno.yaml"), "workspace:\n  name: platform\nnodes:\n  - name: svc\n    url: https://github.com/acme/svc.git\n    overrides:\n      git:\n        default_branch: "+branch+"\n")
+		runTestGit(t, work, "add", "muno.yaml")
+		runTestGit(t, work, "commit", "--quiet", "-m", "publish")
+		runTestGit(t, work, "tag", "-f", "v2")
+		runTestGit(t, work, "push", "--quiet", "--force", "origin", "HEAD", "v2")
+	}
+	publish("trunk")
+
+	tmpDir := t.TempDir()
+	mgr := CreateTestManagerWithConfig(t, tmpDir, &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "acme", ReposDir: ".nodes"},
+		Nodes:     []config.NodeDefinition{{Name: "platform", File: "git+file://" + bare + "//muno.yaml@v2"}},
+	})
+	mgr.config.File = filepath.Join(tmpDir, "muno.yaml")
+	require.NoError(t, mgr.config.Save(mgr.config.File))
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+
+	assert.Equal(t, "trunk", mgr.nodeSetting("/platform/svc", "git.default_branch", "main"))
+	assert.DirExists(t, filepath.Join(tmpDir, ".muno", "cache", "configs"))
+
+	// A pin the tree no longer references is left alone
+	_, err := tree.RemoteConfigCache(tmpDir).Snapshot("file://"+bare, "")
+	require.NoError(t, err)
+
+	// The ref stays pinned until a pull of a node whose subtree references
+	// it; svc is declared by the config, it does not reference it
+	publish("develop")
+	assert.Equal(t, "trunk", mgr.nodeSetting("/platform/svc", "git.default_branch", "main"))
+	_ = mgr.PullNode("/platform/svc", false, false)
+	assert.Equal(t, "trunk", mgr.nodeSetting("/platform/svc", "git.default_branch", "main"))
+	assert.NotContains(t, ui.GetMessages(), "INFO: 🔄 Refreshing remote configs...")
+
+	loaded := mgr.config
+	_ = mgr.PullNode("/platform", false, false)
+	assert.Equal(t, "develop", mgr.nodeSetting("/platform/svc", "git.default_branch", "main"))
+	messages := ui.GetMessages()
+	assert.Contains(t, messages, "INFO: 🔄 Refreshing remote configs...")
+	assert.NotContains(t, strings.Join(messages, "\n"), "file://"+bare+":")
+	assert.NotContains(t, messages, "INFO:    ✅ file://"+bare+" is up to date")
+
+	assert.NotSame(t, loaded, mgr.config, "the tree is reloaded after a pin moved")
+
+	loaded = mgr.config
+	ui.Reset()
+	mgr.refreshRemoteConfigs("/")
+	assert.Contains(t, ui.GetMessages(), "INFO:    ✅ file://"+bare+"@v2 is up to date")
+	assert.Same(t, loaded, mgr.config)
+}
@@ -0,0 +1,456 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/taokim/muno/internal/adapters"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// MetricsFile holds accumulated metrics, relative to the workspace root
+const MetricsFile = ".muno/metrics.json"
+
+// Stats output formats
+const (
+	StatsFormatTable      = "table"
+	StatsFormatPrometheus = "prometheus"
+	StatsFormatOTLP       = "otlp"
+)
+
+// StatsOptions controls muno stats
+type StatsOptions struct {
+	Format       string // table, prometheus or otlp
+	Output       string // Write exports to this file instead of stdout
+	OTLPEndpoint string // Push to an OTLP/HTTP collector instead of printing
+	Top          int    // Number of repositories in the slowest list (0 = all)
+	Reset        bool   // Clear recorded metrics
+}
+
+var (
+	workspaceMetricsMu sync.Mutex
+	workspaceMetrics   = make(map[string]*adapters.Metrics)
+)
+
+// metricsForWorkspace returns the process-wide metrics provider of a workspace
+func metricsForWorkspace(workspace string) *adapters.Metrics {
+	workspaceMetricsMu.Lock()
+	defer workspaceMetricsMu.Unlock()
+
+	metrics, ok := workspaceMetrics[workspace]
+	if !ok {
+		metrics = adapters.NewMetrics(filepath.Join(workspace, MetricsFile))
+		workspaceMetrics[workspace] = metrics
+	}
+	return metrics
+}
+
+// FlushMetrics saves the metrics recorded by every workspace loaded in this
+// process. The CLI calls it once a command finishes, whether or not it failed.
+func FlushMetrics() error {
+	workspaceMetricsMu.Lock()
+	defer workspaceMetricsMu.Unlock()
+
+	var firstErr error
+	for _, metrics := range workspaceMetrics {
+		if err := metrics.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// instrumentedGit records the duration and outcome of git operations per node,
+// and adds the ones that change a repository to the active journal entry.
+// It implements every method itself, so new GitProvider methods do not
+// compile until they are timed here too.
+type instrumentedGit struct {
+	GitProvider interfaces.GitProvider
+	m           *Manager
+}
+
+var _ interfaces.GitProvider = (*instrumentedGit)(nil)
+
+func (g *instrumentedGit) Clone(url, path string, options interfaces.CloneOptions) error {
+	done := g.m.journalStep("clone", path)
+	start := time.Now()
+	err := g.GitProvider.Clone(url, path, options)
+	g.m.recordGitOperation("clone", path, start, err)
+	done(err)
+	return err
+}
+
+func (g *instrumentedGit) Pull(path string, options interfaces.PullOptions) error {
+	done := g.m.journalStep("pull", path)
+	start := time.Now()
+	err := g.GitProvider.Pull(path, options)
+	g.m.recordGitOperation("pull", path, start, err)
+	done(err)
+	return err
+}
+
+func (g *instrumentedGit) Push(path string, options interfaces.PushOptions) error {
+	done := g.m.journalStep("push", path)
+	start := time.Now()
+	err := g.GitProvider.Push(path, options)
+	g.m.recordGitOperation("push", path, start, err)
+	done(err)
+	return err
+}
+
+func (g *instrumentedGit) Fetch(path string, options interfaces.FetchOptions) error {
+	start := time.Now()
+	err := g.GitProvider.Fetch(path, options)
+	g.m.recordGitOperation("fetch", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) Commit(path string, message string, options interfaces.CommitOptions) error {
+	done := g.m.journalStep("commit", path)
+	start := time.Now()
+	err := g.GitProvider.Commit(path, message, options)
+	g.m.recordGitOperation("commit", path, start, err)
+	done(err)
+	return err
+}
+
+func (g *instrumentedGit) Checkout(path string, branch string) error {
+	done := g.m.journalStep("checkout", path)
+	start := time.Now()
+	err := g.GitProvider.Checkout(path, branch)
+	g.m.recordGitOperation("checkout", path, start, err)
+	done(err)
+	return err
+}
+
+func (g *instrumentedGit) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	done := g.m.journalStep("reset", path)
+	start := time.Now()
+	err := g.GitProvider.ResetTo(path, commit, options)
+	g.m.recordGitOperation("reset", path, start, err)
+	done(err)
+	return err
+}
+
+func (g *instrumentedGit) Status(path string) (*interfaces.GitStatus, error) {
+	start := time.Now()
+	status, err := g.GitProvider.Status(path)
+	g.m.recordGitOperation("status", path, start, err)
+	return status, err
+}
+
+func (g *instrumentedGit) Branch(path string) (string, error) {
+	start := time.Now()
+	result, err := g.GitProvider.Branch(path)
+	g.m.recordGitOperation("branch", path, start, err)
+	return result, err
+}
+
+func (g *instrumentedGit) CheckoutNew(path string, branch string) error {
+	start := time.Now()
+	err := g.GitProvider.CheckoutNew(path, branch)
+	g.m.recordGitOperation("checkout_new", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) DeleteBranch(path string, branch string) error {
+	start := time.Now()
+	err := g.GitProvider.DeleteBranch(path, branch)
+	g.m.recordGitOperation("delete_branch", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) Stash(path string, message string) (bool, error) {
+	start := time.Now()
+	result, err := g.GitProvider.Stash(path, message)
+	g.m.recordGitOperation("stash", path, start, err)
+	return result, err
+}
+
+func (g *instrumentedGit) StashPop(path string) error {
+	start := time.Now()
+	err := g.GitProvider.StashPop(path)
+	g.m.recordGitOperation("stash_pop", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) DiffStat(path string, options interfaces.DiffOptions) ([]interfaces.DiffStat, error) {
+	start := time.Now()
+	result, err := g.GitProvider.DiffStat(path, options)
+	g.m.recordGitOperation("diff_stat", path, start, err)
+	return result, err
+}
+
+func (g *instrumentedGit) Log(path string, options interfaces.LogOptions) ([]interfaces.LogEntry, error) {
+	start := time.Now()
+	result, err := g.GitProvider.Log(path, options)
+	g.m.recordGitOperation("log", path, start, err)
+	return result, err
+}
+
+func (g *instrumentedGit) RemoteBranch(path string, remote string, branch string) (string, error) {
+	start := time.Now()
+	result, err := g.GitProvider.RemoteBranch(path, remote, branch)
+	g.m.recordGitOperation("remote_branch", path, start, err)
+	return result, err
+}
+
+func (g *instrumentedGit) CreateBundle(path string, file string, options interfaces.LogOptions) error {
+	start := time.Now()
+	err := g.GitProvider.CreateBundle(path, file, options)
+	g.m.recordGitOperation("create_bundle", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) FetchBundle(path string, file string, branch string) error {
+	start := time.Now()
+	err := g.GitProvider.FetchBundle(path, file, branch)
+	g.m.recordGitOperation("fetch_bundle", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) FormatPatch(path string, dir string, options interfaces.LogOptions) ([]string, error) {
+	start := time.Now()
+	result, err := g.GitProvider.FormatPatch(path, dir, options)
+	g.m.recordGitOperation("format_patch", path, start, err)
+	return result, err
+}
+
+func (g *instrumentedGit) ApplyPatches(path string, files []string) error {
+	start := time.Now()
+	err := g.GitProvider.ApplyPatches(path, files)
+	g.m.recordGitOperation("apply_patches", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) Add(path string, files []string) error {
+	start := time.Now()
+	err := g.GitProvider.Add(path, files)
+	g.m.recordGitOperation("add", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) Remove(path string, files []string) error {
+	start := time.Now()
+	err := g.GitProvider.Remove(path, files)
+	g.m.recordGitOperation("remove", path, start, err)
+	return err
+}
+
+func (g *instrumentedGit) GetRemoteURL(path string) (string, error) {
+	start := time.Now()
+	result, err := g.GitProvider.GetRemoteURL(path)
+	g.m.recordGitOperation("get_remote_url", path, start, err)
+	return result, err
+}
+
+func (g *instrumentedGit) SetRemoteURL(path string, url string) error {
+	start := time.Now()
+	err := g.GitProvider.SetRemoteURL(path, url)
+	g.m.recordGitOperation("set_remote_url", path, start, err)
+	return err
+}
+
+// recordGitOperation records git.<op>.duration_seconds and git.<op> tagged
+// with the node so slow repositories stand out
+func (m *Manager) recordGitOperation(operation string, fsPath string, start time.Time, err error) {
+	node := m.nodeLabel(fsPath)
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.metricsProvider.Histogram("git."+operation+".duration_seconds", time.Since(start).Seconds(), "node:"+node)
+	m.metricsProvider.Counter("git."+operation, 1, "node:"+node, "result:"+result)
+}
+
+// nodeLabel names the node at fsPath for metric tags and journal entries
+func (m *Manager) nodeLabel(fsPath string) string {
+	if treePath, err := m.GetTreePath(fsPath); err == nil && treePath != "" {
+		return treePath
+	}
+	if rel, err := filepath.Rel(m.workspace, fsPath); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return fsPath
+}
+
+// ShowStats prints or exports the metrics recorded in this workspace
+func (m *Manager) ShowStats(opts StatsOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	path := filepath.Join(m.workspace, MetricsFile)
+	if opts.Reset {
+		if err := adapters.RemoveMetrics(path); err != nil {
+			return fmt.Errorf("resetting metrics: %w", err)
+		}
+		m.uiProvider.Success("Metrics reset")
+		return nil
+	}
+
+	// Include anything this process recorded but has not saved yet
+	if err := m.metricsProvider.Flush(); err != nil {
+		m.logProvider.Warn(fmt.Sprintf("Failed to flush metrics: %v", err))
+	}
+	snapshot, err := adapters.LoadMetrics(path)
+	if err != nil {
+		return fmt.Errorf("loading metrics: %w", err)
+	}
+
+	if opts.OTLPEndpoint != "" {
+		if err := adapters.PushOTLP(opts.OTLPEndpoint, snapshot, time.Now()); err != nil {
+			return err
+		}
+		m.uiProvider.Success(fmt.Sprintf("Sent %d series to %s", len(snapshot.Series), opts.OTLPEndpoint))
+		return nil
+	}
+
+	var out io.Writer = os.Stdout
+	if opts.Output != "" {
+		file, err := os.Create(opts.Output)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch opts.Format {
+	case StatsFormatPrometheus:
+		err = adapters.WritePrometheus(out, snapshot)
+	case StatsFormatOTLP:
+		err = adapters.WriteOTLP(out, snapshot, time.Now())
+	case StatsFormatTable, "":
+		if opts.Output != "" {
+			return fmt.Errorf("--output requires --format prometheus or otlp")
+		}
+		m.showStatsTable(snapshot, opts.Top)
+		return nil
+	default:
+		return fmt.Errorf("unknown stats format: %s (use table, prometheus or otlp)", opts.Format)
+	}
+	if err != nil {
+		return fmt.Errorf("writing metrics: %w", err)
+	}
+	if opts.Output != "" {
+		m.uiProvider.Success(fmt.Sprintf("Wrote %d series to %s", len(snapshot.Series), opts.Output))
+	}
+	return nil
+}
+
+// timingSummary sums recorded durations of one node or operation
+type timingSummary struct {
+	name  string
+	ops   int64
+	total float64
+	max   float64
+}
+
+// showStatsTable prints the slowest repositories and operation totals
+func (m *Manager) showStatsTable(snapshot *adapters.MetricsSnapshot, top int) {
+	if len(snapshot.Series) == 0 {
+		m.uiProvider.Info("📭 No metrics recorded yet")
+		return
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("📊 Metrics since %s", snapshot.Since.Local().Format("2006-01-02 15:04")))
+	m.uiProvider.Info("─────────────────")
+
+	timings := make(map[string]*timingSummary)
+	var counters []*adapters.Series
+	for _, series := range snapshot.Sorted() {
+		switch {
+		case series.Kind == adapters.MetricHistogram && series.Tag("node") != "" && series.Count > 0:
+			node := series.Tag("node")
+			timing, ok := timings[node]
+			if !ok {
+				timing = &timingSummary{name: node}
+				timings[node] = timing
+			}
+			timing.ops += series.Count
+			timing.total += series.Sum
+			if series.Max > timing.max {
+				timing.max = series.Max
+			}
+		case series.Kind == adapters.MetricCounter && len(series.Tags) == 0:
+			counters = append(counters, series)
+		}
+	}
+
+	if len(timings) > 0 {
+		ranked := make([]*timingSummary, 0, len(timings))
+		for _, timing := range timings {
+			ranked = append(ranked, timing)
+		}
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].total != ranked[j].total {
+				return ranked[i].total > ranked[j].total
+			}
+			return ranked[i].name < ranked[j].name
+		})
+		if top > 0 && len(ranked) > top {
+			ranked = ranked[:top]
+		}
+
+		m.uiProvider.Info("🐢 Slowest repositories (git time)")
+		m.uiProvider.Info(fmt.Sprintf("   %-32s %6s %10s %10s %10s", "NODE", "OPS", "TOTAL", "AVG", "MAX"))
+		for _, timing := range ranked {
+			m.uiProvider.Info(fmt.Sprintf("   %-32s %6d %10s %10s %10s", timing.name, timing.ops,
+				formatSeconds(timing.total), formatSeconds(timing.total/float64(timing.ops)), formatSeconds(timing.max)))
+		}
+		m.uiProvider.Info("")
+	}
+
+	// Per-operation totals across all repositories
+	byOperation := make(map[string]*timingSummary)
+	var names []string
+	for _, series := range snapshot.Sorted() {
+		if series.Kind != adapters.MetricHistogram || series.Count == 0 {
+			continue
+		}
+		timing, ok := byOperation[series.Name]
+		if !ok {
+			timing = &timingSummary{name: series.Name}
+			byOperation[series.Name] = timing
+			names = append(names, series.Name)
+		}
+		timing.ops += series.Count
+		timing.total += series.Sum
+		if series.Max > timing.max {
+			timing.max = series.Max
+		}
+	}
+	if len(names) > 0 {
+		m.uiProvider.Info("⏱️  Operations")
+		for _, name := range names {
+			timing := byOperation[name]
+			m.uiProvider.Info(fmt.Sprintf("   %-32s %6d %10s %10s %10s", name, timing.ops,
+				formatSeconds(timing.total), formatSeconds(timing.total/float64(timing.ops)), formatSeconds(timing.max)))
+		}
+	}
+
+	if len(counters) > 0 {
+		m.uiProvider.Info("")
+		m.uiProvider.Info("🔢 Counters")
+		for _, series := range counters {
+			m.uiProvider.Info(fmt.Sprintf("   %-32s %6.0f", series.Name, series.Value))
+		}
+	}
+}
+
+// formatSeconds renders seconds as a rounded duration
+func formatSeconds(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	default:
+		return d.Round(time.Millisecond).String()
+	}
+}
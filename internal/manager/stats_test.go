@@ -0,0 +1,122 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/adapters"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+func setupStatsWorkspace(t *testing.T) (*Manager, string, *mocks.MockUIProvider) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+	mgr.metricsProvider = adapters.NewMetrics(filepath.Join(tmpDir, MetricsFile))
+	mgr.gitProvider = &instrumentedGit{GitProvider: mgr.gitProvider, m: mgr}
+	return mgr, tmpDir, ui
+}
+
+func TestInstrumentedGit_RecordsPerNode(t *testing.T) {
+	mgr, tmpDir, _ := setupStatsWorkspace(t)
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	mgr.gitProvider.(*instrumentedGit).GitProvider.(*mocks.MockGitProvider).SetError("pull", webPath, errors.New("conflict"))
+
+	require.NoError(t, mgr.PullNode("", true, false))
+	_, err := mgr.gitProvider.Status(filepath.Join(tmpDir, ".nodes", "api"))
+	require.NoError(t, err)
+
+	snapshot := mgr.metricsProvider.(*adapters.Metrics).Snapshot()
+	assert.Equal(t, int64(1), snapshot.Series["git.pull.duration_seconds{node:/api}"].Count)
+	assert.Equal(t, int64(1), snapshot.Series["git.status.duration_seconds{node:/api}"].Count)
+	assert.Equal(t, float64(1), snapshot.Series["git.pull{node:/api,result:ok}"].Value)
+	assert.Equal(t, float64(1), snapshot.Series["git.pull{node:/web,result:error}"].Value)
+}
+
+func TestInstrumentedGit_TimesEveryMethod(t *testing.T) {
+	mgr, tmpDir, _ := setupStatsWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	instrumented := reflect.ValueOf(mgr.gitProvider)
+	methods := reflect.TypeOf((*interfaces.GitProvider)(nil)).Elem()
+
+	for i := 0; i < methods.NumMethod(); i++ {
+		method := instrumented.MethodByName(methods.Method(i).Name)
+		args := make([]reflect.Value, method.Type().NumIn())
+		for j := range args {
+			args[j] = reflect.Zero(method.Type().In(j))
+		}
+		// Every method takes the repository path first, apart from the URL of Clone
+		if methods.Method(i).Name == "Clone" {
+			args[1] = reflect.ValueOf(apiPath)
+		} else {
+			args[0] = reflect.ValueOf(apiPath)
+		}
+		method.Call(args)
+	}
+
+	operations := map[string]bool{}
+	for name := range mgr.metricsProvider.(*adapters.Metrics).Snapshot().Series {
+		if strings.HasSuffix(strings.SplitN(name, "{", 2)[0], ".duration_seconds") {
+			operations[name] = true
+		}
+	}
+	assert.Len(t, operations, methods.NumMethod(), "every GitProvider method records its duration")
+}
+
+func TestShowStats_Table(t *testing.T) {
+	mgr, _, ui := setupStatsWorkspace(t)
+
+	require.NoError(t, mgr.ShowStats(StatsOptions{}))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📭 No metrics recorded yet")
+
+	mgr.metricsProvider.Histogram("git.pull.duration_seconds", 4, "node:/web")
+	mgr.metricsProvider.Histogram("git.pull.duration_seconds", 0.5, "node:/api")
+	mgr.metricsProvider.Histogram("git.status.duration_seconds", 0.1, "node:/api")
+	mgr.metricsProvider.Counter("manager.fetch", 2)
+
+	require.NoError(t, mgr.ShowStats(StatsOptions{Top: 1}))
+	output := strings.Join(ui.GetMessages(), "\n")
+	assert.Contains(t, output, "🐢 Slowest repositories (git time)")
+	assert.Regexp(t, `/web\s+1\s+4s\s+4s\s+4s`, output)
+	assert.NotRegexp(t, `/api\s+2`, output)
+	assert.Regexp(t, `git\.pull\.duration_seconds\s+2\s+4\.5s`, output)
+	assert.Regexp(t, `manager\.fetch\s+2`, output)
+}
+
+func TestShowStats_ExportAndReset(t *testing.T) {
+	mgr, tmpDir, ui := setupStatsWorkspace(t)
+	mgr.metricsProvider.Counter("git.clone", 1, "node:/api", "result:ok")
+
+	out := filepath.Join(tmpDir, "metrics.prom")
+	require.NoError(t, mgr.ShowStats(StatsOptions{Format: StatsFormatPrometheus, Output: out}))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `muno_git_clone_total{node="/api",result="ok"} 1`)
+	assert.Contains(t, ui.GetMessages(), "SUCCESS: Wrote 1 series to "+out)
+
+	assert.ErrorContains(t, mgr.ShowStats(StatsOptions{Format: "csv"}), "unknown stats format")
+	assert.ErrorContains(t, mgr.ShowStats(StatsOptions{Output: out}), "--output requires")
+
+	require.NoError(t, mgr.ShowStats(StatsOptions{Reset: true}))
+	assert.NoFileExists(t, filepath.Join(tmpDir, MetricsFile))
+}
+
+func TestFlushMetrics(t *testing.T) {
+	workspace := t.TempDir()
+	metrics := metricsForWorkspace(workspace)
+	assert.Same(t, metrics, metricsForWorkspace(workspace))
+
+	metrics.Counter("git.fetch", 1)
+	require.NoError(t, FlushMetrics())
+
+	stored, err := adapters.LoadMetrics(filepath.Join(workspace, MetricsFile))
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), stored.Series["git.fetch"].Value)
+}
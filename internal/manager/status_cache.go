@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/taokim/muno/internal/config"
+)
+
+// SetStatusRefresh makes the next status lookups ignore cached repository
+// states and run git in every repository
+func (m *Manager) SetStatusRefresh(refresh bool) {
+	if m.statusCache != nil {
+		m.statusCache.SetRefresh(refresh)
+	}
+}
+
+// ShowStatusCacheStats displays how many repository states were served from the status cache
+func (m *Manager) ShowStatusCacheStats() error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	if m.statusCache == nil {
+		m.uiProvider.Info("Status cache: disabled")
+		return nil
+	}
+
+	stats := m.statusCache.Stats()
+	hitRate := 0.0
+	if total := stats.Hits + stats.Misses; total > 0 {
+		hitRate = float64(stats.Hits) * 100 / float64(total)
+	}
+	m.uiProvider.Info(fmt.Sprintf("Status cache: %d entries, %d hits, %d misses (%.0f%% hit rate)",
+		stats.Size, stats.Hits, stats.Misses, hitRate))
+	return nil
+}
+
+// saveStatusCache persists repository states gathered during this command
+func (m *Manager) saveStatusCache() {
+	if m.statusCache == nil {
+		return
+	}
+	if err := m.statusCache.Save(); err != nil {
+		if m.logProvider != nil {
+			m.logProvider.Warn(fmt.Sprintf("Failed to save status cache: %v", err))
+		}
+		return
+	}
+	if m.statusCache.Stats().Size > 0 {
+		if err := m.ensureGitignoreEntry(m.workspace, config.GetStateFileName()); err != nil && m.logProvider != nil {
+			m.logProvider.Debug(fmt.Sprintf("Could not add state file to .gitignore: %v", err))
+		}
+	}
+}
+
+// statePath returns the path of the workspace state file
+func (m *Manager) statePath() string {
+	return filepath.Join(m.workspace, config.GetStateFileName())
+}
+
+// loadState loads the workspace state file
+func (m *Manager) loadState() (*config.State, error) {
+	state, err := config.LoadState(m.statePath())
+	if err != nil {
+		return nil, err
+	}
+	if state.Sessions == nil {
+		state.Sessions = make(map[string]config.Session)
+	}
+	return state, nil
+}
+
+// saveState writes the workspace state file and keeps it out of git
+func (m *Manager) saveState(state *config.State) error {
+	if err := state.SaveState(m.statePath()); err != nil {
+		return err
+	}
+	if err := m.ensureGitignoreEntry(m.workspace, config.GetStateFileName()); err != nil {
+		m.logProvider.Debug(fmt.Sprintf("Could not add state file to .gitignore: %v", err))
+	}
+	return nil
+}
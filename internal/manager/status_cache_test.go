@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/mocks"
+	"github.com/taokim/muno/internal/tree"
+)
+
+func TestShowStatusCacheStats(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+
+	// Managers built without a status cache report it as disabled
+	mgr.SetStatusRefresh(true)
+	require.NoError(t, mgr.ShowStatusCacheStats())
+	assert.Contains(t, ui.GetMessages(), "INFO: Status cache: disabled")
+
+	mgr.statusCache = tree.NewStatusCache(tmpDir)
+	mgr.statusCache.RepoState(filepath.Join(tmpDir, ".nodes", "docs"))
+	require.NoError(t, mgr.ShowStatusCacheStats())
+	assert.Contains(t, strings.Join(ui.GetMessages(), "\n"), "Status cache: 0 entries, 0 hits, 0 misses (0% hit rate)")
+
+	// Nothing changed, so nothing is written
+	mgr.saveStatusCache()
+	assert.NoFileExists(t, filepath.Join(tmpDir, config.GetStateFileName()))
+
+	uninitialized := &Manager{}
+	assert.Error(t, uninitialized.ShowStatusCacheStats())
+}
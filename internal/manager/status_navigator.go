@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// statusNavigator returns the navigator for recursive status: the workspace
+// daemon when one is running, otherwise the navigator configured in the
+// navigator: section of muno.yaml, or nil when caching is not enabled so status
+// is read directly through the git provider
+func (m *Manager) statusNavigator() navigator.TreeNavigator {
+	if nav := m.daemonNavigator(); nav != nil {
+		return nav
+	}
+	if m.config == nil || m.config.Navigator == nil || !m.config.Navigator.Cache.Enabled {
+		return nil
+	}
+
+	nav, err := m.navigatorFactory().CreateFromConfig()
+	if err != nil {
+		m.logProvider.Warn(fmt.Sprintf("Failed to create navigator, using git directly: %v", err))
+		return nil
+	}
+	return nav
+}
+
+// navigatorFactory returns a navigator factory whose cached navigators keep
+// node status in the workspace status cache
+func (m *Manager) navigatorFactory() *navigator.Factory {
+	factory := navigator.NewFactory(m.workspace, m.config, NewRealGit())
+	if m.statusCache != nil {
+		factory.WithStatusStore(m.statusCache.NodeStatusStore(m.computeFilesystemPath))
+	}
+	return factory
+}
+
+// formatNavigatorStatus renders a navigator status in the same form as git status output
+func formatNavigatorStatus(name string, status *navigator.NodeStatus) string {
+	statusMsg := fmt.Sprintf("%s: branch=%s", name, status.Branch)
+
+	details := []string{}
+	if status.Untracked > 0 {
+		details = append(details, fmt.Sprintf("%d untracked", status.Untracked))
+	}
+	if status.Unstaged > 0 {
+		details = append(details, fmt.Sprintf("%d modified", status.Unstaged))
+	}
+	if status.Staged > 0 {
+		details = append(details, fmt.Sprintf("%d staged", status.Staged))
+	}
+
+	if len(details) > 0 {
+		statusMsg += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+	} else if !status.Modified {
+		statusMsg += " (clean)"
+	}
+	return statusMsg
+}
@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/mocks"
+	"github.com/taokim/muno/internal/tree"
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+func TestStatusNode_RecursiveUsesNavigatorCache(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+
+	// Without a navigator section status goes straight to git
+	assert.Nil(t, mgr.statusNavigator())
+
+	mgr.config.Navigator = &config.NavigatorConfig{
+		Cache: config.NavigatorCacheConfig{Enabled: true, TTL: "1h"},
+	}
+
+	// Seed the status cache as a previous run would have left it
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	mgr.statusCache = tree.NewStatusCache(tmpDir)
+	store := mgr.statusCache.NodeStatusStore(mgr.computeFilesystemPath)
+	for _, name := range []string{"api", "web"} {
+		repo := filepath.Join(tmpDir, ".nodes", name)
+		require.NoError(t, os.RemoveAll(filepath.Join(repo, ".git")))
+		runTestGit(t, repo, "init", "--quiet")
+	}
+	store.StoreStatus("/api", &navigator.NodeStatus{Exists: true, Cloned: true, State: navigator.RepoStateModified,
+		Branch: "feature", Modified: true, Unstaged: 2, Untracked: 1, Behind: 3, LastCheck: time.Now()})
+	store.StoreStatus("/web", &navigator.NodeStatus{Exists: true, Cloned: true, State: navigator.RepoStateCloned,
+		Branch: "main", LastCheck: time.Now()})
+	require.NoError(t, store.Save())
+
+	require.NoError(t, mgr.StatusNode("/", true))
+
+	messages := strings.Join(ui.GetMessages(), "\n")
+	assert.Contains(t, messages, "api: branch=feature (1 untracked, 2 modified) ⬇️ 3 behind [never fetched]")
+	assert.Contains(t, messages, "web: branch=main (clean)")
+	assert.Contains(t, messages, "docs: (lazy - not cloned)")
+
+	// A repository that changed since is read from git again
+	writeTestFile(t, filepath.Join(tmpDir, ".nodes", "web", "new.txt"), "x")
+	ui.Reset()
+	require.NoError(t, mgr.StatusNode("/", true))
+	messages = strings.Join(ui.GetMessages(), "\n")
+	assert.Contains(t, messages, "api: branch=feature (1 untracked, 2 modified)")
+	assert.NotContains(t, messages, "web: branch=main (clean)")
+	assert.Contains(t, messages, "(1 untracked)")
+}
+
+func TestFormatNavigatorStatus(t *testing.T) {
+	assert.Equal(t, "api: branch=main (clean)", formatNavigatorStatus("api", &navigator.NodeStatus{Branch: "main"}))
+	assert.Equal(t, "api: branch=main (3 staged)", formatNavigatorStatus("api", &navigator.NodeStatus{Branch: "main", Modified: true, Staged: 3}))
+	assert.Equal(t, "api: branch=main", formatNavigatorStatus("api", &navigator.NodeStatus{Branch: "main", Modified: true}))
+}
@@ -164,6 +164,27 @@ func CreateLazyNode(name string, repository string) interfaces.NodeInfo {
 	}
 }
 
+// CreateTestNodeWorkspace creates a manager for a workspace with the given top-level
+// repository nodes. Lazy nodes are only added to the tree; the others also get a
+// .nodes/<name>/.git directory so they count as cloned.
+func CreateTestNodeWorkspace(t *testing.T, name string, nodes ...config.NodeDefinition) (*Manager, string) {
+	tmpDir := t.TempDir()
+	mgr := CreateTestManagerWithConfig(t, tmpDir, &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: name, ReposDir: ".nodes"},
+		Nodes:     nodes,
+	})
+
+	for _, def := range nodes {
+		if def.Fetch == config.FetchLazy {
+			AddNodeToTree(mgr, "/"+def.Name, CreateLazyNode(def.Name, def.URL))
+			continue
+		}
+		AddNodeToTree(mgr, "/"+def.Name, CreateSimpleNode(def.Name, def.URL))
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".nodes", def.Name, ".git"), 0755))
+	}
+	return mgr, tmpDir
+}
+
 // AssertPathResolution checks that a path resolves correctly
 func AssertPathResolution(t *testing.T, m *Manager, target string, expected string, ensure bool) {
 	result, err := m.ResolvePath(target, ensure)
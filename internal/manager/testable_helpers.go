@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	
+	"github.com/taokim/muno/internal/config"
 	"github.com/taokim/muno/internal/constants"
 )
 
@@ -21,9 +22,10 @@ func (h *TestableHelpers) FindWorkspaceRoot(startPath string) string {
 
 	current := startPath
 	for {
-		configPath := filepath.Join(current, "muno.yaml")
-		if _, err := os.Stat(configPath); err == nil {
-			return current
+		for _, name := range config.GetConfigFileNames() {
+			if _, err := os.Stat(filepath.Join(current, name)); err == nil {
+				return current
+			}
 		}
 
 		parent := filepath.Dir(current)
@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/taokim/muno/internal/adapters"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tui"
+)
+
+// UIOptions controls muno ui
+type UIOptions struct {
+	Start string // Tree path to select first, relative to the current node
+}
+
+// RunUI opens the interactive tree browser and returns the directory of the
+// node the user picked, or "" when they quit without picking one
+func (m *Manager) RunUI(opts UIOptions) (string, error) {
+	if !m.initialized {
+		return "", fmt.Errorf("manager not initialized")
+	}
+
+	start, err := m.getCurrentTreePath()
+	if err != nil {
+		return "", fmt.Errorf("resolving current tree path: %w", err)
+	}
+	if strings.HasPrefix(opts.Start, "/") {
+		start = opts.Start
+	} else if opts.Start != "" {
+		start = path.Join(start, opts.Start)
+	}
+
+	// Actions report through the browser; their regular output and console
+	// log entries would draw over it
+	ui := m.uiProvider
+	m.uiProvider = &StubUIProvider{}
+	defer func() { m.uiProvider = ui }()
+	if logger, ok := m.logProvider.(*adapters.Logger); ok {
+		defer logger.MuteConsole()()
+	}
+
+	return tui.Run(&uiBackend{m: m}, start)
+}
+
+// uiBackend gives the tree browser access to the workspace
+type uiBackend struct {
+	m *Manager
+}
+
+func (b *uiBackend) Root() tui.Entry {
+	name := "workspace"
+	if b.m.config != nil && b.m.config.Workspace.Name != "" {
+		name = b.m.config.Workspace.Name
+	}
+	return tui.Entry{Path: "/", Name: name, HasChildren: true}
+}
+
+func (b *uiBackend) Children(path string) ([]tui.Entry, error) {
+	node, err := b.m.treeProvider.GetNode(path)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", path, err)
+	}
+	entries := make([]tui.Entry, 0, len(node.Children))
+	for _, child := range node.Children {
+		entries = append(entries, b.entry(child))
+	}
+	return entries, nil
+}
+
+// entry describes a node, checking the filesystem for whether it is cloned
+func (b *uiBackend) entry(node interfaces.NodeInfo) tui.Entry {
+	cloned := node.IsCloned
+	if node.Repository != "" {
+		_, err := os.Stat(filepath.Join(b.m.computeFilesystemPath(node.Path), ".git"))
+		cloned = err == nil
+	}
+	return tui.Entry{
+		Path:        node.Path,
+		Name:        node.Name,
+		HasChildren: len(node.Children) > 0,
+		Repo:        node.Repository != "",
+		Cloned:      cloned,
+		Lazy:        node.IsLazy,
+	}
+}
+
+func (b *uiBackend) Status(path string) (tui.Status, error) {
+	status, err := b.m.gitProvider.Status(b.m.computeFilesystemPath(path))
+	if err != nil {
+		return tui.Status{}, err
+	}
+	changes := len(status.Files)
+	if changes == 0 && !status.IsClean {
+		changes = 1
+	}
+	return tui.Status{
+		Branch:  status.Branch,
+		Changes: changes,
+		Ahead:   status.Ahead,
+		Behind:  status.Behind,
+	}, nil
+}
+
+func (b *uiBackend) Clone(path string) error {
+	_, err := b.m.ResolvePath(path, true)
+	return err
+}
+
+func (b *uiBackend) Pull(path string) error {
+	return b.m.PullNode(path, false, false)
+}
+
+func (b *uiBackend) Resolve(path string) (string, error) {
+	return b.m.ResolvePath(path, true)
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+	"github.com/taokim/muno/internal/tui"
+)
+
+func TestUIBackend(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	git := mgr.gitProvider.(*mocks.MockGitProvider)
+	backend := &uiBackend{m: mgr}
+
+	assert.Equal(t, tui.Entry{Path: "/", Name: "acme", HasChildren: true}, backend.Root())
+
+	children, err := backend.Children("/")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []tui.Entry{
+		{Path: "/api", Name: "api", Repo: true, Cloned: true},
+		{Path: "/web", Name: "web", Repo: true, Cloned: true},
+		{Path: "/docs", Name: "docs", Repo: true, Lazy: true},
+	}, children)
+
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.SetStatus(webPath, &interfaces.GitStatus{
+		Branch: "feature",
+		Files:  []interfaces.GitFileStatus{{Path: "a.go"}, {Path: "b.go"}},
+		Ahead:  1,
+	})
+	status, err := backend.Status("/web")
+	require.NoError(t, err)
+	assert.Equal(t, tui.Status{Branch: "feature", Changes: 2, Ahead: 1}, status)
+
+	// Selecting a lazy node clones it
+	t.Chdir(tmpDir)
+	dir, err := backend.Resolve("/docs")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, ".nodes", "docs"), dir)
+	assert.Contains(t, git.GetCalls(), "Clone(https://github.com/acme/docs.git, "+dir+")")
+
+	git.SetError("pull", webPath, errors.New("merge conflict"))
+	assert.EqualError(t, backend.Pull("/web"), "merge conflict")
+}
+
+func TestRunUI_NotInitialized(t *testing.T) {
+	mgr := &Manager{}
+	_, err := mgr.RunUI(UIOptions{})
+	assert.EqualError(t, err, "manager not initialized")
+}
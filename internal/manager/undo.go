@@ -0,0 +1,271 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/tree"
+)
+
+// undoableOperations are the journaled operations muno undo can revert
+var undoableOperations = map[string]bool{"pull": true, "commit": true, "change-start": true, "change-import": true}
+
+// undoableActions are the journaled steps an undo reverts; change-start and
+// change-import journal a checkout step for each repository they switch
+var undoableActions = map[string]bool{"pull": true, "commit": true, "checkout": true}
+
+// UndoOptions controls muno undo
+type UndoOptions struct {
+	DryRun bool // Show what would be reverted without changing anything
+	Yes    bool // Do not ask for confirmation
+}
+
+// undoStep reverts one repository to where it was before an operation
+type undoStep struct {
+	path   string // Tree path
+	action string // Journaled action: pull, commit or checkout
+	from   string // HEAD the operation left behind
+	to     string // HEAD before the operation
+	branch string // Branch checked out before the operation
+}
+
+// Undo reverts the last pull, commit, change start or change import recorded
+// in the journal in every repository it moved. It refuses when any of them has
+// moved on since, so later work is never lost, and when a commit to undo has
+// already been pushed.
+func (m *Manager) Undo(opts UndoOptions) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	entries, err := readJournal(filepath.Join(m.workspace, JournalFile))
+	if err != nil {
+		return fmt.Errorf("reading journal: %w", err)
+	}
+	target, ok := lastUndoable(entries)
+	if !ok {
+		m.uiProvider.Info("📭 Nothing to undo")
+		return nil
+	}
+
+	steps := undoSteps(target)
+	when := target.Started.Local().Format("2006-01-02 15:04:05")
+	if len(steps) == 0 {
+		m.uiProvider.Info(fmt.Sprintf("📭 Nothing to undo: the %s at %s did not move any repository", target.Operation, when))
+		return nil
+	}
+
+	// Check every repository before touching any of them
+	var pending []undoStep
+	var moved []string
+	for _, step := range steps {
+		fsPath := m.computeFilesystemPath(step.path)
+		head := tree.HeadCommit(fsPath)
+		reverted := head == step.to && (step.action != "checkout" || step.branch == "" || tree.HeadBranch(fsPath) == step.branch)
+		switch {
+		case reverted:
+			m.uiProvider.Info(fmt.Sprintf("⏭️  %s is already at %s", step.path, shortSHA(step.to)))
+		case head == step.from:
+			pending = append(pending, step)
+		default:
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  %s moved since the %s: HEAD is %s, expected %s", step.path, target.Operation, shortSHA(head), shortSHA(step.from)))
+			moved = append(moved, step.path)
+		}
+	}
+	if len(moved) > 0 {
+		return fmt.Errorf("refusing to undo %s: %s changed since and would lose work", target.Operation, strings.Join(moved, ", "))
+	}
+
+	var pushed []string
+	for _, step := range pending {
+		if step.action != "commit" {
+			continue
+		}
+		// HEAD is the commit to undo; an upstream that is not behind it contains it
+		status, err := m.gitProvider.Status(m.computeFilesystemPath(step.path))
+		if err != nil {
+			return fmt.Errorf("reading status of %s: %w", step.path, err)
+		}
+		if status.Upstream != "" && status.Ahead == 0 {
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  %s: %s is already on %s", step.path, shortSHA(step.from), status.Upstream))
+			pushed = append(pushed, step.path)
+		}
+	}
+	if len(pushed) > 0 {
+		return fmt.Errorf("refusing to undo %s: already pushed from %s; revert it with a new commit instead", target.Operation, strings.Join(pushed, ", "))
+	}
+	if len(pending) == 0 {
+		m.uiProvider.Info("📭 Nothing to undo")
+		return nil
+	}
+
+	m.uiProvider.Info(fmt.Sprintf("↩️  Undoing %s from %s", target.Operation, when))
+	if target.Command != "" {
+		m.uiProvider.Info("   $ " + target.Command)
+	}
+	m.uiProvider.Info("─────────────────")
+	for _, step := range pending {
+		m.uiProvider.Info(fmt.Sprintf("📦 %s: %s → %s", step.path, shortSHA(step.from), shortSHA(step.to)))
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+	if !opts.Yes {
+		confirm, err := m.uiProvider.Confirm(fmt.Sprintf("Undo %s in %d repositories?", target.Operation, len(pending)))
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			m.uiProvider.Info("Undo cancelled")
+			return nil
+		}
+	}
+
+	return m.applyUndo(target, pending)
+}
+
+// applyUndo reverts each repository and journals the undo itself
+func (m *Manager) applyUndo(target JournalEntry, steps []undoStep) (err error) {
+	defer m.journalOperation("undo", "entry", target.ID, "operation", target.Operation)(&err)
+
+	successCount := 0
+	failedRepos := []string{}
+	for _, step := range steps {
+		if err := m.revertStep(step, "muno undo "+target.ID); err != nil {
+			m.uiProvider.Error(fmt.Sprintf("   ❌ Failed at %s: %v", step.path, err))
+			m.logNodeFailure("undo", step.path, err)
+			failedRepos = append(failedRepos, step.path)
+		} else {
+			m.uiProvider.Success(fmt.Sprintf("   ✅ Reverted %s", step.path))
+			successCount++
+		}
+	}
+
+	m.uiProvider.Info("")
+	m.uiProvider.Info(fmt.Sprintf("📊 Results: %d succeeded, %d failed", successCount, len(failedRepos)))
+	m.metricsProvider.Counter("manager.undo", int64(successCount))
+	if len(failedRepos) > 0 {
+		return fmt.Errorf("undo failed for %d repositories", len(failedRepos))
+	}
+
+	if target.Operation == "change-start" || target.Operation == "change-import" {
+		return m.forgetUndoneChange(target.Args["name"], steps)
+	}
+	return nil
+}
+
+// forgetUndoneChange drops the change an undone change start or import
+// recorded and deletes its branch from the repositories switched back
+func (m *Manager) forgetUndoneChange(name string, steps []undoStep) error {
+	state, err := m.loadState()
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	change, ok := state.Changes[name]
+	if !ok || change.Landed != "" {
+		return nil
+	}
+
+	for _, step := range steps {
+		if err := m.gitProvider.DeleteBranch(m.computeFilesystemPath(step.path), change.Branch); err != nil {
+			m.uiProvider.Warning(fmt.Sprintf("⚠️  Could not delete branch %s in %s: %v", change.Branch, step.path, err))
+		}
+	}
+	delete(state.Changes, name)
+	if state.CurrentChange == name {
+		state.CurrentChange = ""
+	}
+	if err := m.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	m.uiProvider.Info(fmt.Sprintf("🗑️  Forgot change %s", name))
+	return nil
+}
+
+// revertStep moves one repository back. Commits are undone with a soft reset
+// so their changes stay staged; pulls and checkouts set local changes aside
+// in a stash and restore them afterwards.
+func (m *Manager) revertStep(step undoStep, stashMessage string) error {
+	fsPath := m.computeFilesystemPath(step.path)
+	if step.action == "commit" {
+		return m.gitProvider.ResetTo(fsPath, step.to, interfaces.ResetOptions{Mode: interfaces.ResetSoft})
+	}
+
+	stashed, err := m.gitProvider.Stash(fsPath, stashMessage)
+	if err != nil {
+		return fmt.Errorf("stashing local changes: %w", err)
+	}
+
+	if step.action == "checkout" && step.branch != "" {
+		err = m.gitProvider.Checkout(fsPath, step.branch)
+	} else if step.action == "checkout" {
+		err = m.gitProvider.Checkout(fsPath, step.to)
+	} else {
+		err = m.gitProvider.ResetTo(fsPath, step.to, interfaces.ResetOptions{Mode: interfaces.ResetHard})
+	}
+	if err != nil {
+		if stashed {
+			m.uiProvider.Warning(fmt.Sprintf("   Local changes of %s are in the stash: %s", step.path, stashMessage))
+		}
+		return err
+	}
+
+	if stashed {
+		if err := m.gitProvider.StashPop(fsPath); err != nil {
+			return fmt.Errorf("restoring local changes (still in the stash): %w", err)
+		}
+	}
+	return nil
+}
+
+// lastUndoable returns the most recent undoable operation that has not been
+// undone yet
+func lastUndoable(entries []JournalEntry) (JournalEntry, bool) {
+	undone := make(map[string]bool)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Operation == "undo" && entry.Error == "" {
+			undone[entry.Args["entry"]] = true
+			continue
+		}
+		if undoableOperations[entry.Operation] && !undone[entry.ID] {
+			return entry, true
+		}
+	}
+	return JournalEntry{}, false
+}
+
+// undoSteps lists the repositories an entry moved, one step per repository,
+// from the HEAD before its first step to the HEAD after its last
+func undoSteps(entry JournalEntry) []undoStep {
+	var steps []undoStep
+	index := make(map[string]int)
+	for _, node := range entry.Nodes {
+		if !undoableActions[node.Action] || node.Before == "" {
+			continue
+		}
+		if i, ok := index[node.Path]; ok {
+			steps[i].from = node.After
+			continue
+		}
+		index[node.Path] = len(steps)
+		steps = append(steps, undoStep{
+			path:   node.Path,
+			action: node.Action,
+			from:   node.After,
+			to:     node.Before,
+			branch: node.Branch,
+		})
+	}
+
+	moved := steps[:0]
+	for _, step := range steps {
+		if step.from != step.to || step.action == "checkout" {
+			moved = append(moved, step)
+		}
+	}
+	return moved
+}
@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+	"github.com/taokim/muno/internal/tree"
+)
+
+func TestUndo_RevertsPullAndRestoresChanges(t *testing.T) {
+	mgr, tmpDir, ui, git := setupJournalWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	git.heads[apiPath] = "3333333333cccc"
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", HasModified: true})
+
+	require.NoError(t, mgr.PullNode("", true, true))
+	require.Equal(t, "3333333333cccc", tree.HeadCommit(apiPath))
+
+	require.NoError(t, mgr.Undo(UndoOptions{Yes: true}))
+	assert.Equal(t, "1111111111aaaa", tree.HeadCommit(apiPath))
+
+	var undoCalls []string
+	for _, call := range git.GetCalls() {
+		if strings.HasPrefix(call, "Stash") || strings.HasPrefix(call, "ResetTo") {
+			undoCalls = append(undoCalls, call)
+		}
+	}
+	require.Len(t, undoCalls, 3)
+	assert.True(t, strings.HasPrefix(undoCalls[0], "Stash("+apiPath+", muno undo "))
+	assert.Equal(t, "ResetTo("+apiPath+", 1111111111aaaa, hard)", undoCalls[1])
+	assert.Equal(t, "StashPop("+apiPath+")", undoCalls[2])
+	assert.Contains(t, ui.GetMessages(), "INFO: 📊 Results: 1 succeeded, 0 failed")
+
+	// The undo is journaled and the pull is not undone twice
+	entries := readTestJournal(t, tmpDir)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "undo", entries[1].Operation)
+	assert.Equal(t, entries[0].ID, entries[1].Args["entry"])
+
+	ui.Reset()
+	require.NoError(t, mgr.Undo(UndoOptions{Yes: true}))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📭 Nothing to undo")
+}
+
+func TestUndo_StepsBackThroughCommitAndPull(t *testing.T) {
+	mgr, tmpDir, _, git := setupJournalWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+
+	git.heads[apiPath] = "3333333333cccc"
+	require.NoError(t, mgr.PullNode("/api", false, false))
+	git.heads[apiPath] = "5555555555eeee"
+	require.NoError(t, mgr.CommitNode("/api", "wip", false))
+
+	// The commit is undone first, keeping its changes staged
+	require.NoError(t, mgr.Undo(UndoOptions{Yes: true}))
+	assert.Equal(t, "3333333333cccc", tree.HeadCommit(apiPath))
+	assert.Contains(t, git.GetCalls(), "ResetTo("+apiPath+", 3333333333cccc, soft)")
+
+	require.NoError(t, mgr.Undo(UndoOptions{Yes: true}))
+	assert.Equal(t, "1111111111aaaa", tree.HeadCommit(apiPath))
+}
+
+func TestUndo_RefusesWhenRepositoryMovedOn(t *testing.T) {
+	mgr, tmpDir, _, git := setupJournalWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	webPath := filepath.Join(tmpDir, ".nodes", "web")
+	git.heads[apiPath] = "3333333333cccc"
+	git.heads[webPath] = "4444444444dddd"
+	require.NoError(t, mgr.PullNode("", true, false))
+
+	// New work in /web after the pull
+	require.NoError(t, writeHead(webPath, "6666666666ffff"))
+
+	err := mgr.Undo(UndoOptions{Yes: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to undo pull: /web changed since")
+	assert.Equal(t, "3333333333cccc", tree.HeadCommit(apiPath), "nothing is reverted")
+	assert.Len(t, readTestJournal(t, tmpDir), 1)
+}
+
+func TestUndo_DryRunAndCancel(t *testing.T) {
+	mgr, tmpDir, ui, git := setupJournalWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+
+	require.NoError(t, mgr.Undo(UndoOptions{}))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📭 Nothing to undo")
+
+	git.heads[apiPath] = "3333333333cccc"
+	require.NoError(t, mgr.PullNode("", true, false))
+
+	ui.Reset()
+	require.NoError(t, mgr.Undo(UndoOptions{DryRun: true}))
+	assert.Contains(t, ui.GetMessages(), "INFO: 📦 /api: 33333333 → 11111111")
+
+	ui.SetConfirmResponse(false)
+	require.NoError(t, mgr.Undo(UndoOptions{}))
+	assert.Contains(t, ui.GetMessages(), "INFO: Undo cancelled")
+	assert.Equal(t, "3333333333cccc", tree.HeadCommit(apiPath))
+}
+
+func TestUndoSteps(t *testing.T) {
+	steps := undoSteps(JournalEntry{Operation: "pull", Nodes: []JournalNode{
+		{Path: "/api", Action: "pull", Before: "a1", After: "a2"},
+		{Path: "/web", Action: "pull", Before: "w1", After: "w1"},
+		{Path: "/docs", Action: "clone", After: "d1"},
+		{Path: "/api", Action: "pull", Before: "a2", After: "a3"},
+	}})
+	require.Len(t, steps, 1)
+	assert.Equal(t, undoStep{path: "/api", action: "pull", from: "a3", to: "a1"}, steps[0])
+}
+
+func TestUndo_RevertsChangeStart(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	mgr, tmpDir := CreateTestNodeWorkspace(t, "acme",
+		config.NodeDefinition{Name: "api", URL: "https://github.com/acme/api.git"},
+	)
+	ui := mocks.NewMockUIProvider()
+	mgr.uiProvider = ui
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+	runTestGit(t, apiPath, "init", "--quiet", "--initial-branch=main")
+	writeTestFile(t, filepath.Join(apiPath, "README.md"), "api\n")
+	runTestGit(t, apiPath, "add", "README.md")
+	runTestGit(t, apiPath, "commit", "--quiet", "-m", "initial")
+	base := tree.HeadCommit(apiPath)
+
+	require.NoError(t, mgr.StartChange("login", ChangeStartOptions{Paths: []string{"/api"}}))
+	require.Equal(t, "login", tree.HeadBranch(apiPath))
+
+	require.NoError(t, mgr.Undo(UndoOptions{Yes: true}))
+	assert.Equal(t, "main", tree.HeadBranch(apiPath))
+	assert.Equal(t, base, tree.HeadCommit(apiPath))
+	assert.Contains(t, ui.GetMessages(), "INFO: 🗑️  Forgot change login")
+
+	// The change branch and record are gone, so the change can start again
+	assert.Error(t, exec.Command("git", "-C", apiPath, "rev-parse", "--verify", "--quiet", "refs/heads/login").Run())
+	state, err := mgr.loadState()
+	require.NoError(t, err)
+	assert.NotContains(t, state.Changes, "login")
+	assert.Empty(t, state.CurrentChange)
+	require.NoError(t, mgr.StartChange("login", ChangeStartOptions{Paths: []string{"/api"}}))
+}
+
+func TestUndo_RefusesPushedCommit(t *testing.T) {
+	mgr, tmpDir, _, git := setupJournalWorkspace(t)
+	apiPath := filepath.Join(tmpDir, ".nodes", "api")
+
+	git.heads[apiPath] = "5555555555eeee"
+	require.NoError(t, mgr.CommitNode("/api", "wip", false))
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main"})
+
+	err := mgr.Undo(UndoOptions{Yes: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to undo commit: already pushed from /api")
+	assert.Equal(t, "5555555555eeee", tree.HeadCommit(apiPath), "nothing is reverted")
+
+	// A commit the upstream does not contain yet can be undone
+	git.SetStatus(apiPath, &interfaces.GitStatus{Branch: "main", Upstream: "origin/main", Ahead: 1})
+	require.NoError(t, mgr.Undo(UndoOptions{Yes: true}))
+	assert.Equal(t, "1111111111aaaa", tree.HeadCommit(apiPath))
+}
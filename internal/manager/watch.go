@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+)
+
+// ReloadConfig re-reads the workspace config and reloads the tree. It waits
+// for a scheduled fetch in progress, which reads both.
+func (m *Manager) ReloadConfig() error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	cfg, err := config.LoadTree(m.workspaceConfigPath())
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	m.config = cfg
+	if m.configResolver != nil && cfg.Overrides != nil {
+		m.configResolver.SetWorkspaceConfig(cfg.Overrides)
+	}
+
+	if err := m.treeProvider.Load(cfg); err != nil {
+		return fmt.Errorf("loading tree: %w", err)
+	}
+
+	m.metricsProvider.Counter("manager.config_reload", 1)
+	return nil
+}
+
+// WatchConfig watches every config file in the workspace tree and reloads the
+// configuration whenever one changes. onChange, if set, is called after each
+// reload with the event and the tree path of the node owning the changed file,
+// so callers can invalidate caches. It blocks until ctx is done.
+func (m *Manager) WatchConfig(ctx context.Context, onChange func(event interfaces.ConfigEvent, treePath string)) error {
+	if !m.initialized {
+		return fmt.Errorf("manager not initialized")
+	}
+
+	events, err := m.configProvider.Watch(m.workspace)
+	if err != nil {
+		return fmt.Errorf("watching config: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			m.logProvider.Debug(fmt.Sprintf("Config %s: %s", event.Type, event.Path))
+			if err := m.ReloadConfig(); err != nil {
+				m.logProvider.Warn(fmt.Sprintf("Failed to reload config after change to %s: %v", event.Path, err))
+				event.Error = err
+			}
+
+			treePath, err := m.GetTreePath(filepath.Dir(event.Path))
+			if err != nil {
+				treePath = "/"
+			}
+			if onChange != nil {
+				onChange(event, treePath)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/interfaces"
+	"github.com/taokim/muno/internal/mocks"
+)
+
+// eventConfigProvider delivers config events from a test-controlled channel
+type eventConfigProvider struct {
+	interfaces.ConfigProvider
+	events chan interfaces.ConfigEvent
+}
+
+func (p *eventConfigProvider) Watch(path string) (<-chan interfaces.ConfigEvent, error) {
+	return p.events, nil
+}
+
+func TestReloadConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "acme", ReposDir: ".nodes"},
+		Nodes:     []config.NodeDefinition{{Name: "api", URL: "https://github.com/acme/api.git"}},
+	}
+	require.NoError(t, cfg.Save(filepath.Join(tmpDir, "muno.yaml")))
+	mgr := CreateTestManagerWithConfig(t, tmpDir, cfg)
+
+	updated := &config.ConfigTree{
+		Workspace: cfg.Workspace,
+		Nodes: append(cfg.Nodes, config.NodeDefinition{Name: "web", URL: "https://github.com/acme/web.git"}),
+		Overrides: map[string]interface{}{
+			"agent": map[string]interface{}{"command": "codex"},
+		},
+	}
+	require.NoError(t, updated.Save(filepath.Join(tmpDir, "muno.yaml")))
+
+	require.NoError(t, mgr.ReloadConfig())
+	assert.Len(t, mgr.config.Nodes, 2)
+	_, err := mgr.treeProvider.GetNode("/web")
+	assert.NoError(t, err)
+	assert.Equal(t, "codex", mgr.agentSetting("command", ""))
+
+	uninitialized := &Manager{}
+	assert.Error(t, uninitialized.ReloadConfig())
+	assert.Error(t, uninitialized.WatchConfig(context.Background(), nil))
+}
+
+func TestReloadConfig_DuringScheduledFetch(t *testing.T) {
+	mgr, tmpDir := setupContextWorkspace(t)
+	mgr.uiProvider = mocks.NewMockUIProvider()
+	require.NoError(t, mgr.config.Save(filepath.Join(tmpDir, "muno.yaml")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- mgr.fetchPeriodically(ctx, "/", time.Millisecond, FetchOptions{Recursive: true}, nil)
+	}()
+
+	for i := 0; i < 20; i++ {
+		require.NoError(t, mgr.ReloadConfig())
+	}
+	cancel()
+	require.NoError(t, <-done)
+
+	_, err := mgr.treeProvider.GetNode("/web")
+	assert.NoError(t, err)
+}
+
+func TestWatchConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "acme", ReposDir: ".nodes"},
+		Nodes:     []config.NodeDefinition{{Name: "api", URL: "https://github.com/acme/api.git"}},
+	}
+	configPath := filepath.Join(tmpDir, "muno.yaml")
+	require.NoError(t, cfg.Save(configPath))
+	mgr := CreateTestManagerWithConfig(t, tmpDir, cfg)
+
+	provider := &eventConfigProvider{ConfigProvider: mgr.configProvider, events: make(chan interfaces.ConfigEvent, 1)}
+	mgr.configProvider = provider
+
+	cfg.Nodes = append(cfg.Nodes, config.NodeDefinition{Name: "web", URL: "https://github.com/acme/web.git"})
+	require.NoError(t, cfg.Save(configPath))
+	provider.events <- interfaces.ConfigEvent{Type: interfaces.ConfigEventModified, Path: configPath, Timestamp: time.Now()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []string
+	err := mgr.WatchConfig(ctx, func(event interfaces.ConfigEvent, treePath string) {
+		got = append(got, event.Type+" "+treePath)
+		assert.NoError(t, event.Error)
+		close(provider.events)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"modified /"}, got)
+	_, err = mgr.treeProvider.GetNode("/web")
+	assert.NoError(t, err)
+}
+
+func TestStubConfigProviderWatch(t *testing.T) {
+	provider := NewStubConfigProvider()
+	events, err := provider.Watch(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, provider.(*StubConfigProvider).Close())
+	for range events {
+	}
+}
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	
 	"github.com/taokim/muno/internal/interfaces"
@@ -17,6 +19,10 @@ type MockGitProvider struct {
 	calls       []string
 	pullResults map[string]interfaces.GitPullResult
 	pushResults map[string]interfaces.GitPushResult
+	diffStats   map[string][]interfaces.DiffStat
+	pushOptions map[string]interfaces.PushOptions
+	logs        map[string][]interfaces.LogEntry
+	remoteBranches map[string]string
 }
 
 // NewMockGitProvider creates a new mock git provider
@@ -27,6 +33,10 @@ func NewMockGitProvider() *MockGitProvider {
 		remoteURLs: make(map[string]string),
 		errors:     make(map[string]error),
 		calls:      []string{},
+		diffStats:  make(map[string][]interfaces.DiffStat),
+		pushOptions: make(map[string]interfaces.PushOptions),
+		logs:       make(map[string][]interfaces.LogEntry),
+		remoteBranches: make(map[string]string),
 	}
 }
 
@@ -81,7 +91,20 @@ func (m *MockGitProvider) Push(path string, options interfaces.PushOptions) erro
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
-	m.calls = append(m.calls, fmt.Sprintf("Push(%s)", path))
+	switch {
+	case options.DryRun:
+		m.calls = append(m.calls, fmt.Sprintf("Push(%s, --dry-run)", path))
+		if err, ok := m.errors["push-dry-run:"+path]; ok && err != nil {
+			return err
+		}
+		return nil
+	case options.Refspec != "":
+		m.calls = append(m.calls, fmt.Sprintf("Push(%s, %s)", path, options.Refspec))
+		m.pushOptions[path] = options
+	default:
+		m.calls = append(m.calls, fmt.Sprintf("Push(%s)", path))
+		m.pushOptions[path] = options
+	}
 	
 	if err, ok := m.errors["push:"+path]; ok && err != nil {
 		return err
@@ -189,6 +212,205 @@ func (m *MockGitProvider) Fetch(path string, options interfaces.FetchOptions) er
 	return nil
 }
 
+// ResetTo moves the branch of a repository to commit
+func (m *MockGitProvider) ResetTo(path string, commit string, options interfaces.ResetOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("ResetTo(%s, %s, %s)", path, commit, options.Mode))
+	
+	if err, ok := m.errors["reset:"+path]; ok && err != nil {
+		return err
+	}
+	
+	if status, ok := m.statuses[path]; ok && options.Mode == interfaces.ResetHard {
+		status.IsClean = true
+		status.HasChanges = false
+	}
+	
+	return nil
+}
+
+// Stash stashes local changes, reporting whether the status had any
+func (m *MockGitProvider) Stash(path string, message string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("Stash(%s, %s)", path, message))
+	
+	if err, ok := m.errors["stash:"+path]; ok && err != nil {
+		return false, err
+	}
+	
+	status, ok := m.statuses[path]
+	return ok && !status.IsClean, nil
+}
+
+// StashPop restores stashed changes
+func (m *MockGitProvider) StashPop(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("StashPop(%s)", path))
+	
+	if err, ok := m.errors["stashpop:"+path]; ok && err != nil {
+		return err
+	}
+	
+	return nil
+}
+
+// CheckoutNew creates and checks out a branch
+func (m *MockGitProvider) CheckoutNew(path string, branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("CheckoutNew(%s, %s)", path, branch))
+	
+	if err, ok := m.errors["checkout:"+path]; ok && err != nil {
+		return err
+	}
+	
+	m.branches[path] = branch
+	if status, ok := m.statuses[path]; ok {
+		status.Branch = branch
+	}
+	
+	return nil
+}
+
+// DeleteBranch deletes a branch
+func (m *MockGitProvider) DeleteBranch(path string, branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("DeleteBranch(%s, %s)", path, branch))
+	
+	if err, ok := m.errors["delete-branch:"+path]; ok && err != nil {
+		return err
+	}
+	
+	return nil
+}
+
+// Log returns the commits set with SetLog
+func (m *MockGitProvider) Log(path string, options interfaces.LogOptions) ([]interfaces.LogEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	to := options.To
+	if to == "" {
+		to = "HEAD"
+	}
+	revRange := options.From + ".." + to
+	m.calls = append(m.calls, fmt.Sprintf("Log(%s, %s)", path, revRange))
+	
+	if err, ok := m.errors["log:"+path]; ok && err != nil {
+		return nil, err
+	}
+	
+	return m.logs[path+" "+revRange], nil
+}
+
+// RemoteBranch returns the commit set with SetRemoteBranch
+func (m *MockGitProvider) RemoteBranch(path string, remote string, branch string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("RemoteBranch(%s, %s, %s)", path, remote, branch))
+	
+	if err, ok := m.errors["ls-remote:"+path]; ok && err != nil {
+		return "", err
+	}
+	
+	return m.remoteBranches[path], nil
+}
+
+// CreateBundle writes a placeholder bundle file
+func (m *MockGitProvider) CreateBundle(path string, file string, options interfaces.LogOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("CreateBundle(%s, %s..%s)", path, options.From, options.To))
+	
+	if err, ok := m.errors["bundle:"+path]; ok && err != nil {
+		return err
+	}
+	
+	return os.WriteFile(file, []byte("# v2 git bundle\n"), 0644)
+}
+
+// FetchBundle fetches a branch from a bundle file
+func (m *MockGitProvider) FetchBundle(path string, file string, branch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("FetchBundle(%s, %s, %s)", path, filepath.Base(file), branch))
+	
+	if err, ok := m.errors["fetch-bundle:"+path]; ok && err != nil {
+		return err
+	}
+	
+	return nil
+}
+
+// FormatPatch writes one placeholder patch per commit set with SetLog
+func (m *MockGitProvider) FormatPatch(path string, dir string, options interfaces.LogOptions) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("FormatPatch(%s, %s..%s)", path, options.From, options.To))
+	
+	if err, ok := m.errors["format-patch:"+path]; ok && err != nil {
+		return nil, err
+	}
+	
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var files []string
+	for i, entry := range m.logs[path+" "+options.From+".."+options.To] {
+		file := filepath.Join(dir, fmt.Sprintf("%04d-%s.patch", i+1, entry.SHA))
+		if err := os.WriteFile(file, []byte("Subject: "+entry.Subject+"\n"), 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// ApplyPatches applies patch files
+func (m *MockGitProvider) ApplyPatches(path string, files []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = filepath.Base(file)
+	}
+	m.calls = append(m.calls, fmt.Sprintf("ApplyPatches(%s, %v)", path, names))
+	
+	if err, ok := m.errors["am:"+path]; ok && err != nil {
+		return err
+	}
+	
+	return nil
+}
+
+// DiffStat returns the diff stat set with SetDiffStat
+func (m *MockGitProvider) DiffStat(path string, options interfaces.DiffOptions) ([]interfaces.DiffStat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.calls = append(m.calls, fmt.Sprintf("DiffStat(%s, staged=%t, %v)", path, options.Staged, options.Paths))
+	
+	if err, ok := m.errors["diff:"+path]; ok && err != nil {
+		return nil, err
+	}
+	
+	return m.diffStats[path], nil
+}
+
 // Add stages files
 func (m *MockGitProvider) Add(path string, files []string) error {
 	m.mu.Lock()
@@ -288,6 +510,40 @@ func (m *MockGitProvider) SetStatus(path string, status *interfaces.GitStatus) {
 	}
 }
 
+// SetDiffStat sets the diff stat returned for a path
+func (m *MockGitProvider) SetDiffStat(path string, stats []interfaces.DiffStat) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.diffStats[path] = stats
+}
+
+// GetPushOptions returns the options of the last push at a path
+func (m *MockGitProvider) GetPushOptions(path string) (interfaces.PushOptions, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	
+	options, ok := m.pushOptions[path]
+	return options, ok
+}
+
+// SetLog sets the commits Log returns for a path and range such as
+// "main..topic"
+func (m *MockGitProvider) SetLog(path string, revRange string, entries []interfaces.LogEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.logs[path+" "+revRange] = entries
+}
+
+// SetRemoteBranch sets the commit RemoteBranch returns for a path
+func (m *MockGitProvider) SetRemoteBranch(path string, sha string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	m.remoteBranches[path] = sha
+}
+
 // SetError sets an error for a specific operation and path
 func (m *MockGitProvider) SetError(operation, path string, err error) {
 	m.mu.Lock()
@@ -316,4 +572,8 @@ func (m *MockGitProvider) Reset() {
 	m.remoteURLs = make(map[string]string)
 	m.errors = make(map[string]error)
 	m.calls = []string{}
+	m.diffStats = make(map[string][]interfaces.DiffStat)
+	m.pushOptions = make(map[string]interfaces.PushOptions)
+	m.logs = make(map[string][]interfaces.LogEntry)
+	m.remoteBranches = make(map[string]string)
 }
\ No newline at end of file
@@ -59,6 +59,10 @@ func (m *MockProcessProvider) ExecuteShell(ctx context.Context, command string,
 		return nil, err
 	}
 	
+	if result, ok := m.results["shell:"+command]; ok {
+		return result, nil
+	}
+	
 	return &interfaces.ProcessResult{
 		Stdout:   "mock shell output",
 		ExitCode: 0,
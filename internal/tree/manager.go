@@ -10,7 +10,6 @@ import (
 	"github.com/taokim/muno/internal/config"
 	"github.com/taokim/muno/internal/constants"
 	"github.com/taokim/muno/internal/git"
-	"gopkg.in/yaml.v3"
 )
 
 // Manager manages tree without persistent state file
@@ -21,6 +20,7 @@ type Manager struct {
 	resolver      *ConfigResolver
 	gitCmd        git.Interface
 	currentPath   string  // Current logical path (session only)
+	statusCache   *StatusCache // Optional cache for repository states
 }
 
 // NewManager creates a manager that derives state from filesystem
@@ -59,6 +59,19 @@ func NewManager(workspacePath string, gitCmd git.Interface) (*Manager, error) {
 	}, nil
 }
 
+// SetStatusCache makes repository state lookups go through cache
+func (m *Manager) SetStatusCache(cache *StatusCache) {
+	m.statusCache = cache
+}
+
+// RepoState returns the repository state at fsPath, using the status cache if set
+func (m *Manager) RepoState(fsPath string) RepoState {
+	if m.statusCache != nil {
+		return m.statusCache.RepoState(fsPath)
+	}
+	return GetRepoState(fsPath)
+}
+
 // ComputeFilesystemPath converts logical path to filesystem path
 func (m *Manager) ComputeFilesystemPath(logicalPath string) string {
 	reposDir := m.config.GetReposDir()
@@ -88,7 +101,7 @@ func (m *Manager) ComputeFilesystemPath(logicalPath string) string {
 			parentFsPath := m.ComputeFilesystemPath(parentPath)
 			
 			// Check if parent has muno.yaml with custom repos_dir
-			parentMunoYaml := filepath.Join(parentFsPath, "muno.yaml")
+			parentMunoYaml := config.ConfigFilePath(parentFsPath)
 			if _, err := os.Stat(parentMunoYaml); err == nil {
 				// Parent has muno.yaml, check its repos_dir
 				childReposDir := constants.DefaultReposDir // default from constants
@@ -115,7 +128,7 @@ func (m *Manager) ComputeFilesystemPath(logicalPath string) string {
 			currentPath = filepath.Join(currentPath, part)
 		} else {
 			// For nested levels, check if parent has muno.yaml
-			parentMunoYaml := filepath.Join(currentPath, "muno.yaml")
+			parentMunoYaml := config.ConfigFilePath(currentPath)
 			if _, err := os.Stat(parentMunoYaml); err == nil {
 				// Parent has muno.yaml, use its repos_dir
 				childReposDir := constants.DefaultReposDir // default from constants
@@ -204,8 +217,7 @@ func (m *Manager) AddRepo(parentPath, name, url string, lazy bool) error {
 	
 	// Save config (only for top-level repos)
 	if parentPath == "/" {
-		configPath := filepath.Join(m.workspacePath, "muno.yaml")
-		if err := m.config.Save(configPath); err != nil {
+		if err := m.config.Save(m.configPath()); err != nil {
 			return fmt.Errorf("saving config: %w", err)
 		}
 	}
@@ -223,6 +235,15 @@ func (m *Manager) AddRepo(parentPath, name, url string, lazy bool) error {
 	return nil
 }
 
+// configPath returns the workspace config file, keeping the format it was
+// loaded from
+func (m *Manager) configPath() string {
+	if m.config != nil && m.config.File != "" {
+		return m.config.File
+	}
+	return config.ConfigFilePath(m.workspacePath)
+}
+
 // RemoveNode removes a node from config
 func (m *Manager) RemoveNode(targetPath string) error {
 	// Handle relative paths
@@ -261,8 +282,7 @@ func (m *Manager) RemoveNode(targetPath string) error {
 			m.config.Nodes = newNodes
 			
 			// Save config
-			configPath := filepath.Join(m.workspacePath, "muno.yaml")
-			if err := m.config.Save(configPath); err != nil {
+			if err := m.config.Save(m.configPath()); err != nil {
 				return fmt.Errorf("saving config: %w", err)
 			}
 		}
@@ -341,8 +361,11 @@ func (m *Manager) GetNode(logicalPath string) *TreeNode {
 		fileExists = false
 	}
 	
-	// Try to find it in config for URL and lazy status
+	// Try to find it in config for URL and lazy status. defDir is the
+	// directory of the config declaring nodeDef, which its file: is
+	// relative to.
 	nodeDef, err := m.GetNodeByPath(logicalPath)
+	defDir := m.workspacePath
 	
 	// For nested paths, check if parent has a muno.yaml first
 	if len(parts) > 1 {
@@ -350,7 +373,7 @@ func (m *Manager) GetNode(logicalPath string) *TreeNode {
 		// First, check if parent has a muno.yaml
 		parentPath := "/" + strings.Join(parts[:len(parts)-1], "/")
 		parentFsPath := m.ComputeFilesystemPath(parentPath)
-		munoYamlPath := filepath.Join(parentFsPath, "muno.yaml")
+		munoYamlPath := config.ConfigFilePath(parentFsPath)
 		
 		if _, statErr := os.Stat(munoYamlPath); statErr == nil {
 			// Parent has muno.yaml, load it to find child definition
@@ -358,6 +381,7 @@ func (m *Manager) GetNode(logicalPath string) *TreeNode {
 				for _, child := range cfg.Nodes {
 					if child.Name == nodeName {
 						nodeDef = &child
+						defDir = config.ConfigDir(munoYamlPath)
 						err = nil
 						break
 					}
@@ -371,13 +395,14 @@ func (m *Manager) GetNode(logicalPath string) *TreeNode {
 			
 			// If parent is a config reference, try to find the child in that config
 			if parentDef.File != "" {
-				refConfig, loadErr := m.loadExternalConfig(parentDef.File)
+				refConfig, refPath, loadErr := m.loadExternalConfig(parentDef.File, m.workspacePath)
 				if loadErr == nil && refConfig != nil {
 					// Look for the child node in the referenced config
 					for i := range refConfig.Nodes {
 						if refConfig.Nodes[i].Name == nodeName {
 							// Found the child node definition
 							nodeDef = &refConfig.Nodes[i]
+							defDir = config.ConfigDir(refPath)
 							err = nil
 							break
 						}
@@ -446,8 +471,10 @@ func (m *Manager) GetNode(logicalPath string) *TreeNode {
 		for _, configNode := range m.config.Nodes {
 			if configNode.Name == nodeName && configNode.File != "" {
 				// This is a config reference node, load its children
-				refConfig, err := m.loadExternalConfig(configNode.File)
-				if err == nil && refConfig != nil {
+				refConfig, refPath, err := m.loadExternalConfig(configNode.File, m.workspacePath)
+				if err != nil {
+					node.ConfigError = &ConfigError{File: refPath, Message: err.Error()}
+				} else if refConfig != nil {
 					for _, childNode := range refConfig.Nodes {
 						if !childMap[childNode.Name] {
 							node.Children = append(node.Children, childNode.Name)
@@ -463,8 +490,10 @@ func (m *Manager) GetNode(logicalPath string) *TreeNode {
 	// For config reference nodes found via GetNodeByPath, also load the referenced config
 	if nodeDef != nil && nodeDef.File != "" {
 		// Try to load the referenced config file
-		refConfig, err := m.loadExternalConfig(nodeDef.File)
-		if err == nil && refConfig != nil {
+		refConfig, refPath, err := m.loadExternalConfig(nodeDef.File, defDir)
+		if err != nil {
+			node.ConfigError = &ConfigError{File: refPath, Message: err.Error()}
+		} else if refConfig != nil {
 			for _, childNode := range refConfig.Nodes {
 				if !childMap[childNode.Name] {
 					node.Children = append(node.Children, childNode.Name)
@@ -477,11 +506,13 @@ func (m *Manager) GetNode(logicalPath string) *TreeNode {
 	// Then, add children from filesystem (if they're not already in the list)
 	if fileExists {
 		// Check if this node has a muno.yaml - if so, load children from it
-		munoYamlPath := filepath.Join(fsPath, "muno.yaml")
+		munoYamlPath := config.ConfigFilePath(fsPath)
 		if _, err := os.Stat(munoYamlPath); err == nil {
 			// Load the muno.yaml to get child definitions and repos_dir
 			reposDir := ".nodes" // default
-			if cfg, err := config.LoadTree(munoYamlPath); err == nil && cfg != nil {
+			if cfg, err := config.LoadTree(munoYamlPath); err != nil {
+				node.ConfigError = &ConfigError{File: munoYamlPath, Message: err.Error()}
+			} else if cfg != nil {
 				for _, childNode := range cfg.Nodes {
 					if !childMap[childNode.Name] {
 						node.Children = append(node.Children, childNode.Name)
@@ -544,7 +575,7 @@ func (m *Manager) ListChildren(targetPath string) ([]*TreeNode, error) {
 		children := make([]*TreeNode, 0, len(m.config.Nodes))
 		for _, node := range m.config.Nodes {
 			fsPath := m.ComputeFilesystemPath("/" + node.Name)
-			state := GetRepoState(fsPath)
+			state := m.RepoState(fsPath)
 			
 			children = append(children, &TreeNode{
 				Name:  node.Name,
@@ -629,7 +660,7 @@ func (m *Manager) DisplayTree() string {
 		
 		icon := "📦"
 		fsPath := m.ComputeFilesystemPath("/" + node.Name)
-		state := GetRepoState(fsPath)
+		state := m.RepoState(fsPath)
 		
 		if state == RepoStateMissing {
 			icon = "💤"
@@ -665,7 +696,7 @@ func (m *Manager) DisplayStatus() string {
 	for _, node := range m.config.Nodes {
 		if node.URL != "" {
 			fsPath := m.ComputeFilesystemPath("/" + node.Name)
-			state := GetRepoState(fsPath)
+			state := m.RepoState(fsPath)
 			switch state {
 			case RepoStateCloned:
 				cloned++
@@ -735,40 +766,55 @@ func (m *Manager) GetState() *TreeState {
 	return state
 }
 
-// loadExternalConfig loads an external config file
-func (m *Manager) loadExternalConfig(filePath string) (*config.ConfigTree, error) {
+// loadExternalConfig loads the config a file: reference names. Relative
+// references resolve from baseDir, the directory of the config declaring
+// them. It also returns the resolved path, or the reference itself when it
+// could not be resolved.
+func (m *Manager) loadExternalConfig(filePath string, baseDir string) (*config.ConfigTree, string, error) {
+	// Remote git references load from the workspace cache
+	if config.IsRemoteRef(filePath) {
+		cached, err := ResolveRemoteConfig(m.workspacePath, filePath)
+		if err != nil {
+			return nil, filePath, err
+		}
+		filePath = cached
+	}
+	
 	// Check if it's a remote URL
 	if strings.HasPrefix(filePath, "http://") || strings.HasPrefix(filePath, "https://") {
 		// For now, we don't support remote configs in tree display
 		// This could be implemented in the future
-		return nil, fmt.Errorf("remote config not supported")
+		return nil, filePath, fmt.Errorf("remote config not supported")
 	}
 	
 	// Load the local config file
 	configPath := filePath
 	if !filepath.IsAbs(filePath) {
-		// Make it relative to the workspace path
-		configPath = filepath.Join(m.workspacePath, filePath)
+		configPath = filepath.Join(baseDir, filePath)
 	}
 	
 	// Check if file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file not found: %s", configPath)
+		return nil, configPath, fmt.Errorf("config file not found: %s", configPath)
 	}
 	
 	// Read the config file
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("reading config file: %w", err)
+		return nil, configPath, fmt.Errorf("reading config file: %w", err)
 	}
 	
-	// Parse the YAML
-	var extConfig config.ConfigTree
-	if err := yaml.Unmarshal(data, &extConfig); err != nil {
-		return nil, fmt.Errorf("parsing config file: %w", err)
+	// Parse the config in the format given by its extension
+	format, err := config.FormatForPath(configPath)
+	if err != nil {
+		return nil, configPath, err
+	}
+	extConfig, err := config.DecodeTree(format, data, config.ConfigDir(configPath))
+	if err != nil {
+		return nil, configPath, fmt.Errorf("parsing config file: %w", err)
 	}
 	
-	return &extConfig, nil
+	return extConfig, configPath, nil
 }
 
 // SaveState does nothing as this is a stateless manager
@@ -26,6 +26,10 @@ type CachedNavigator struct {
 	mu       sync.RWMutex
 	ttl      time.Duration
 	maxSize  int
+	
+	// Persisted status and its background refresh (see status_cache.go)
+	store    StatusStore
+	refresh  refresher
 }
 
 // NewCachedNavigator creates a new caching wrapper around a base navigator
@@ -144,6 +148,19 @@ func (c *CachedNavigator) GetNodeStatus(path string) (*NodeStatus, error) {
 		}
 	}
 
+	// Serve status persisted by a previous run while its repository is
+	// unchanged; entries older than the TTL are refreshed in the background
+	if c.store != nil {
+		if status := c.store.LoadStatus(path, staleStatusAge); status != nil {
+			if time.Since(status.LastCheck) > c.ttl {
+				c.queueRefresh(path)
+			} else {
+				c.putInCache(cacheKey, status)
+			}
+			return status, nil
+		}
+	}
+
 	// Get from base navigator
 	status, err := c.base.GetNodeStatus(path)
 	if err != nil {
@@ -153,6 +170,9 @@ func (c *CachedNavigator) GetNodeStatus(path string) (*NodeStatus, error) {
 	// Cache the result
 	if status != nil {
 		c.putInCache(cacheKey, status)
+		if c.store != nil {
+			c.store.StoreStatus(path, status)
+		}
 	}
 
 	return status, nil
@@ -163,6 +183,9 @@ func (c *CachedNavigator) RefreshStatus(path string) error {
 	// Clear cache for this path and its status
 	c.invalidatePath(path)
 	c.invalidateStatus(path)
+	if c.store != nil {
+		c.store.ForgetStatus(path)
+	}
 	
 	// Refresh in base navigator
 	return c.base.RefreshStatus(path)
@@ -211,6 +234,46 @@ func (c *CachedNavigator) ClearCache() {
 	c.cache = make(map[string]*CacheEntry)
 }
 
+// ReloadConfig reloads the base navigator's configuration, if it holds any,
+// and drops all cached entries
+func (c *CachedNavigator) ReloadConfig() error {
+	var err error
+	if reloader, ok := c.base.(ConfigReloader); ok {
+		err = reloader.ReloadConfig()
+	}
+	c.ClearCache()
+	return err
+}
+
+// InvalidateSubtree removes cached entries for path and every node below it,
+// along with tree views and the parent's children listing
+func (c *CachedNavigator) InvalidateSubtree(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.cache {
+		kind, keyPath, found := strings.Cut(key, ":")
+		if !found {
+			continue
+		}
+		if kind == "tree" {
+			delete(c.cache, key)
+			continue
+		}
+		if path == "/" || keyPath == path || strings.HasPrefix(keyPath, path+"/") {
+			delete(c.cache, key)
+		}
+	}
+
+	if path != "/" && path != "" {
+		parentPath := path[:strings.LastIndex(path, "/")]
+		if parentPath == "" {
+			parentPath = "/"
+		}
+		delete(c.cache, fmt.Sprintf("children:%s", parentPath))
+	}
+}
+
 // ClearExpired removes expired cache entries
 func (c *CachedNavigator) ClearExpired() {
 	c.mu.Lock()
@@ -24,9 +24,10 @@ const (
 
 // Factory creates TreeNavigator instances based on configuration
 type Factory struct {
-	workspace string
-	config    *config.ConfigTree
-	gitCmd    interfaces.GitInterface
+	workspace   string
+	config      *config.ConfigTree
+	gitCmd      interfaces.GitInterface
+	statusStore StatusStore
 }
 
 // NewFactory creates a new navigator factory
@@ -38,6 +39,13 @@ func NewFactory(workspace string, cfg *config.ConfigTree, gitCmd interfaces.GitI
 	}
 }
 
+// WithStatusStore makes cached navigators created by default or from config
+// persist node status in store
+func (f *Factory) WithStatusStore(store StatusStore) *Factory {
+	f.statusStore = store
+	return f
+}
+
 // Create creates a navigator based on the specified type and options
 func (f *Factory) Create(navType NavigatorType, opts *NavigatorOptions) (TreeNavigator, error) {
 	if opts == nil {
@@ -59,44 +67,36 @@ func (f *Factory) Create(navType NavigatorType, opts *NavigatorOptions) (TreeNav
 	}
 }
 
-// CreateFromConfig creates a navigator based on configuration
+// CreateFromConfig creates a navigator based on the navigator section of the config.
+// Enabling the cache selects the cached navigator unless a type is set explicitly.
 func (f *Factory) CreateFromConfig() (TreeNavigator, error) {
-	opts := f.parseConfigOptions()
-	
-	// Determine navigator type from config
-	navType := TypeFilesystem // Default
-	
-	if f.config != nil {
-		// Check for navigator configuration in the config tree
-		// Note: ConfigTree doesn't have Metadata field currently
-		// This would need to be added to support navigator config
-		if false {
-			navConfig := map[string]interface{}{}
-			if typeStr, ok := navConfig["type"].(string); ok {
-				navType = NavigatorType(typeStr)
-			}
-			
-			// Override cache settings if specified
-			if cacheConfig, ok := navConfig["cache"].(map[string]interface{}); ok {
-				if enabled, ok := cacheConfig["enabled"].(bool); ok && enabled {
-					navType = TypeCached
-					opts.CacheEnabled = true
-				}
-				if ttlStr, ok := cacheConfig["ttl"].(string); ok {
-					if ttl, err := time.ParseDuration(ttlStr); err == nil {
-						opts.CacheTTL = ttl
-					}
-				}
-				if maxSize, ok := cacheConfig["max_size"].(int); ok {
-					opts.MaxCacheSize = maxSize
-				}
-			}
-		}
+	opts, err := f.parseConfigOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	navType := TypeFilesystem
+	if opts.CacheEnabled {
+		navType = TypeCached
+	}
+	if f.config != nil && f.config.Navigator != nil && f.config.Navigator.Type != "" {
+		navType = NavigatorType(f.config.Navigator.Type)
 	}
 
 	return f.Create(navType, opts)
 }
 
+// CreateCachedFromConfig creates a cached navigator from the navigator section
+// of the config, enabling the cache even if the config leaves it off
+func (f *Factory) CreateCachedFromConfig() (TreeNavigator, error) {
+	opts, err := f.parseConfigOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts.CacheEnabled = true
+	return f.Create(TypeCached, opts)
+}
+
 // CreateDefault creates the default navigator (filesystem)
 func (f *Factory) CreateDefault() (TreeNavigator, error) {
 	return f.Create(TypeFilesystem, f.defaultOptions())
@@ -123,7 +123,12 @@ func (f *Factory) createFilesystemNavigator(opts *NavigatorOptions) (TreeNavigat
 		workspace = f.workspace
 	}
 
-	return NewFilesystemNavigator(workspace, f.config, f.gitCmd)
+	nav, err := NewFilesystemNavigator(workspace, f.config, f.gitCmd)
+	if err != nil {
+		return nil, err
+	}
+	nav.lazyLoadTimeout = opts.LazyLoadTimeout
+	return nav, nil
 }
 
 func (f *Factory) createCachedNavigator(opts *NavigatorOptions) (TreeNavigator, error) {
@@ -145,6 +150,10 @@ func (f *Factory) createCachedNavigator(opts *NavigatorOptions) (TreeNavigator,
 		cached.WithMaxSize(opts.MaxCacheSize)
 	}
 
+	if opts.StatusStore != nil {
+		cached.WithStatusStore(opts.StatusStore)
+	}
+
 	// Start cleanup routine if refresh interval is set
 	if opts.RefreshInterval > 0 {
 		cached.StartCleanupRoutine(opts.RefreshInterval)
@@ -166,51 +175,53 @@ func (f *Factory) defaultOptions() *NavigatorOptions {
 		MaxCacheSize:    1000,
 		LazyLoadTimeout: 5 * time.Minute,
 		RefreshInterval: 5 * time.Minute,
+		StatusStore:     f.statusStore,
 	}
 }
 
-func (f *Factory) parseConfigOptions() *NavigatorOptions {
+// parseConfigOptions reads the navigator section of the config over the
+// defaults. Empty durations keep the default; invalid ones are an error.
+func (f *Factory) parseConfigOptions() (*NavigatorOptions, error) {
 	opts := f.defaultOptions()
 
-	if f.config == nil {
-		return opts
-	}
-
-	// Parse navigator configuration from config metadata
-	// Note: ConfigTree doesn't have Metadata field currently
-	if false {
-		navConfig := map[string]interface{}{}
-		// Parse cache settings
-		if cacheConfig, ok := navConfig["cache"].(map[string]interface{}); ok {
-			if enabled, ok := cacheConfig["enabled"].(bool); ok {
-				opts.CacheEnabled = enabled
-			}
-			if ttlStr, ok := cacheConfig["ttl"].(string); ok {
-				if ttl, err := time.ParseDuration(ttlStr); err == nil {
-					opts.CacheTTL = ttl
-				}
-			}
-			if maxSize, ok := cacheConfig["max_size"].(int); ok {
-				opts.MaxCacheSize = maxSize
-			}
-		}
-
-		// Parse timeout settings
-		if timeoutStr, ok := navConfig["lazy_load_timeout"].(string); ok {
-			if timeout, err := time.ParseDuration(timeoutStr); err == nil {
-				opts.LazyLoadTimeout = timeout
-			}
-		}
-
-		// Parse refresh interval
-		if intervalStr, ok := navConfig["refresh_interval"].(string); ok {
-			if interval, err := time.ParseDuration(intervalStr); err == nil {
-				opts.RefreshInterval = interval
-			}
-		}
-	}
-
-	return opts
+	if f.config == nil || f.config.Navigator == nil {
+		return opts, nil
+	}
+	navConfig := f.config.Navigator
+
+	// Parse cache settings
+	opts.CacheEnabled = navConfig.Cache.Enabled
+	if err := parseConfigDuration("navigator.cache.ttl", navConfig.Cache.TTL, &opts.CacheTTL); err != nil {
+		return nil, err
+	}
+	if navConfig.Cache.MaxSize > 0 {
+		opts.MaxCacheSize = navConfig.Cache.MaxSize
+	}
+
+	// Parse timeout settings
+	if err := parseConfigDuration("navigator.lazy_load_timeout", navConfig.LazyLoadTimeout, &opts.LazyLoadTimeout); err != nil {
+		return nil, err
+	}
+
+	// Parse refresh interval
+	if err := parseConfigDuration("navigator.refresh_interval", navConfig.RefreshInterval, &opts.RefreshInterval); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// parseConfigDuration sets *d from value unless value is empty
+func parseConfigDuration(key, value string, d *time.Duration) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return fmt.Errorf("invalid %s %q: use a positive duration such as \"30s\" or \"5m\"", key, value)
+	}
+	*d = parsed
+	return nil
 }
 
 // Quick creation functions for common use cases
@@ -24,6 +24,8 @@ type FilesystemNavigator struct {
 	gitCmd       interfaces.GitInterface
 	currentPath  string
 	currentFile  string // Path to .muno/current file
+	
+	lazyLoadTimeout time.Duration // Maximum time to wait for a lazy clone (0 = no limit)
 }
 
 // NewFilesystemNavigator creates a new filesystem-based navigator
@@ -38,15 +40,7 @@ func NewFilesystemNavigator(workspace string, cfg *config.ConfigTree, gitCmd int
 	}
 
 	if cfg == nil {
-		// Try to load config from workspace
-		var err error
-		for _, configName := range config.GetConfigFileNames() {
-			configPath := filepath.Join(workspace, configName)
-			cfg, err = config.LoadTree(configPath)
-			if err == nil {
-				break
-			}
-		}
+		cfg = loadWorkspaceConfig(workspace)
 		if cfg == nil {
 			cfg = config.DefaultConfigTree("workspace")
 		}
@@ -70,6 +64,28 @@ func NewFilesystemNavigator(workspace string, cfg *config.ConfigTree, gitCmd int
 	return nav, nil
 }
 
+// loadWorkspaceConfig loads the first config file found in the workspace root
+func loadWorkspaceConfig(workspace string) *config.ConfigTree {
+	for _, configName := range config.GetConfigFileNames() {
+		configPath := filepath.Join(workspace, configName)
+		if cfg, err := config.LoadTree(configPath); err == nil {
+			return cfg
+		}
+	}
+	return nil
+}
+
+// ReloadConfig re-reads the workspace config and drops cached node configs
+func (n *FilesystemNavigator) ReloadConfig() error {
+	cfg := loadWorkspaceConfig(n.workspace)
+	if cfg == nil {
+		return fmt.Errorf("no config file found in %s", n.workspace)
+	}
+	n.config = cfg
+	n.resolver.ClearCache()
+	return nil
+}
+
 // GetCurrentPath returns the current position in the tree
 func (n *FilesystemNavigator) GetCurrentPath() (string, error) {
 	return n.currentPath, nil
@@ -235,9 +251,10 @@ func (n *FilesystemNavigator) GetNodeStatus(nodePath string) (*NodeStatus, error
 			if url, err := n.gitCmd.RemoteURL(fsPath); err == nil {
 				status.RemoteURL = url
 			}
-			if modified, err := n.gitCmd.HasChanges(fsPath); err == nil {
-				status.Modified = modified
-				if modified {
+			if output, err := n.gitCmd.StatusWithOptions(fsPath, "--short", "--branch"); err == nil {
+				countStatusChanges(status, output)
+				status.Modified = status.Staged+status.Unstaged+status.Untracked > 0
+				if status.Modified {
 					status.State = RepoStateModified
 				}
 			}
@@ -247,6 +264,32 @@ func (n *FilesystemNavigator) GetNodeStatus(nodePath string) (*NodeStatus, error
 	return status, nil
 }
 
+// countStatusChanges counts staged, unstaged and untracked files in `git status --short`
+// output, and upstream commits from the "## branch...upstream [behind N]" line of --branch
+func countStatusChanges(status *NodeStatus, output string) {
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		if line[:2] == "##" {
+			if i := strings.Index(line, "behind "); i >= 0 {
+				fmt.Sscanf(line[i+len("behind "):], "%d", &status.Behind)
+			}
+			continue
+		}
+		if line[:2] == "??" {
+			status.Untracked++
+			continue
+		}
+		if line[0] != ' ' {
+			status.Staged++
+		}
+		if line[1] != ' ' {
+			status.Unstaged++
+		}
+	}
+}
+
 // RefreshStatus forces a status refresh for a node and its children
 func (n *FilesystemNavigator) RefreshStatus(nodePath string) error {
 	// In filesystem navigator, status is always fresh
@@ -295,8 +338,24 @@ func (n *FilesystemNavigator) TriggerLazyLoad(nodePath string) error {
 		return fmt.Errorf("git command not configured")
 	}
 	fmt.Printf("Cloning %s to %s\n", node.URL, fsPath)
-	if err := n.cloneWithSSHPreference(node.URL, fsPath); err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
+	if n.lazyLoadTimeout <= 0 {
+		if err := n.cloneWithSSHPreference(node.URL, fsPath); err != nil {
+			return fmt.Errorf("failed to clone repository: %w", err)
+		}
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- n.cloneWithSSHPreference(node.URL, fsPath)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to clone repository: %w", err)
+		}
+	case <-time.After(n.lazyLoadTimeout):
+		return fmt.Errorf("cloning %s timed out after %s", nodePath, n.lazyLoadTimeout)
 	}
 
 	return nil
@@ -340,7 +399,7 @@ func (n *FilesystemNavigator) computeFilesystemPath(logicalPath string) string {
 	// If parent has muno.yaml or .git, children go in configured repos subdirectory
 	parentPath := filepath.Join(n.workspace, reposDir, parts[0])
 	gitPath := filepath.Join(parentPath, ".git")
-	configPath := filepath.Join(parentPath, "muno.yaml")
+	configPath := config.ConfigFilePath(parentPath)
 	
 	if n.pathExists(gitPath) || n.pathExists(configPath) {
 		// Determine the repos directory to use
@@ -478,7 +537,7 @@ func (n *FilesystemNavigator) getNodeChildren(nodePath string) []string {
 	
 	// For nodes that have muno.yaml, check their configured repos subdirectory for children
 	checkPath := fsPath
-	munoYamlPath := filepath.Join(fsPath, "muno.yaml")
+	munoYamlPath := config.ConfigFilePath(fsPath)
 	if n.pathExists(munoYamlPath) {
 		// Default to .nodes
 		reposDir := ".nodes"
@@ -465,4 +465,24 @@ func TestFilesystemNavigatorHelpers(t *testing.T) {
 		os.WriteFile(testFile, []byte("test"), 0644)
 		assert.True(t, nav.pathExists(testFile))
 	})
-}
\ No newline at end of file
+}
+func TestFilesystemNavigator_ReloadConfig(t *testing.T) {
+	workspace := t.TempDir()
+	cfg := config.DefaultConfigTree("test")
+	cfg.Nodes = []config.NodeDefinition{{Name: "api", URL: "https://github.com/acme/api.git"}}
+	require.NoError(t, cfg.Save(filepath.Join(workspace, "muno.yaml")))
+
+	nav, err := NewFilesystemNavigator(workspace, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, nav.config.Nodes, 1)
+
+	cfg.Nodes = append(cfg.Nodes, config.NodeDefinition{Name: "web", URL: "https://github.com/acme/web.git"})
+	require.NoError(t, cfg.Save(filepath.Join(workspace, "muno.yaml")))
+
+	require.NoError(t, nav.ReloadConfig())
+	assert.Len(t, nav.config.Nodes, 2)
+
+	require.NoError(t, os.Remove(filepath.Join(workspace, "muno.yaml")))
+	assert.Error(t, nav.ReloadConfig())
+	assert.Len(t, nav.config.Nodes, 2, "failed reload keeps the previous config")
+}
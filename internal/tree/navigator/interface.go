@@ -48,6 +48,12 @@ type TreeNavigator interface {
 	TriggerLazyLoad(path string) error
 }
 
+// ConfigReloader is implemented by navigators that hold parsed configuration.
+// ReloadConfig re-reads it after config files change on disk.
+type ConfigReloader interface {
+	ReloadConfig() error
+}
+
 // NodeVisitor is a callback for tree traversal operations
 type NodeVisitor func(node *Node, depth int) error
 
@@ -440,4 +440,43 @@ func setupTestTree(nav *InMemoryNavigator) {
 		}
 		nav.AddNode(n.path, node)
 	}
-}
\ No newline at end of file
+}
+// TestCachedNavigatorInvalidateSubtree tests invalidation after config changes
+func TestCachedNavigatorInvalidateSubtree(t *testing.T) {
+	base := NewInMemoryNavigator()
+	base.AddNode("/backend", &Node{Path: "/backend", Name: "backend", Type: NodeTypeRepo, Children: []string{"services"}})
+	base.AddNode("/backend/services", &Node{Path: "/backend/services", Name: "services", Type: NodeTypeRepo})
+	base.AddNode("/frontend", &Node{Path: "/frontend", Name: "frontend", Type: NodeTypeRepo})
+
+	cached := NewCachedNavigator(base, time.Hour)
+	for _, path := range []string{"/backend", "/backend/services", "/frontend"} {
+		if _, err := cached.GetNode(path); err != nil {
+			t.Fatalf("GetNode(%s) failed: %v", path, err)
+		}
+	}
+	if _, err := cached.ListChildren("/"); err != nil {
+		t.Fatalf("ListChildren failed: %v", err)
+	}
+	if _, err := cached.GetTree("/", 2); err != nil {
+		t.Fatalf("GetTree failed: %v", err)
+	}
+
+	cached.InvalidateSubtree("/backend")
+
+	for _, key := range []string{"node:/backend", "node:/backend/services", "children:/", "tree:/:2"} {
+		if cached.getFromCache(key) != nil {
+			t.Errorf("expected %s to be invalidated", key)
+		}
+	}
+	if cached.getFromCache("node:/frontend") == nil {
+		t.Error("expected node:/frontend to stay cached")
+	}
+
+	// Reloading drops everything; the in-memory base has no config to reload
+	if err := cached.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	if stats := cached.GetCacheStats(); stats.Size != 0 {
+		t.Errorf("expected empty cache after reload, got %d entries", stats.Size)
+	}
+}
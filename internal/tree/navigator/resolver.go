@@ -3,12 +3,14 @@ package navigator
 import (
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/taokim/muno/internal/config"
 )
 
 // ConfigResolver resolves configuration references
 type ConfigResolver struct {
+	mu    sync.Mutex // Guards cache; status refreshes resolve nodes concurrently
 	cache map[string]*config.ConfigTree
 	root  string
 }
@@ -21,8 +23,18 @@ func NewConfigResolver(root string) *ConfigResolver {
 	}
 }
 
+// ClearCache drops all cached configurations
+func (r *ConfigResolver) ClearCache() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = make(map[string]*config.ConfigTree)
+}
+
 // LoadNodeFile loads a configuration file for a node
 func (r *ConfigResolver) LoadNodeFile(configPath string, nodeDef *config.NodeDefinition) (*config.ConfigTree, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	// Check cache first
 	if cached, exists := r.cache[configPath]; exists {
 		return cached, nil
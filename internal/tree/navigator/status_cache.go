@@ -0,0 +1,112 @@
+package navigator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// staleStatusAge bounds how old a stored status may be to be served
+	// while it is refreshed; older entries are read again synchronously
+	staleStatusAge = 24 * time.Hour
+
+	// refreshQueueSize bounds the stale entries waiting for a refresh. Reads
+	// beyond it still serve the stale entry and queue it on a later read.
+	refreshQueueSize = 64
+)
+
+// StatusStore persists node status between runs. The workspace status cache
+// implements it, so navigator status is kept in the workspace state file next
+// to the repository states muno status uses.
+type StatusStore interface {
+	// LoadStatus returns the stored status of path, or nil when there is none,
+	// the repository changed since, or it is older than maxAge
+	LoadStatus(path string, maxAge time.Duration) *NodeStatus
+
+	// StoreStatus records a status read from git
+	StoreStatus(path string, status *NodeStatus)
+
+	// ForgetStatus drops stored status for path and every node below it
+	ForgetStatus(path string)
+
+	// Save writes changed entries
+	Save() error
+}
+
+// refresher re-reads stale stored status on one background worker
+type refresher struct {
+	mu      sync.Mutex
+	queue   chan string
+	pending map[string]bool
+	done    chan struct{}
+	closed  bool
+}
+
+// WithStatusStore makes the navigator answer status from store when the
+// stored entry's repository is unchanged. Entries younger than the cache TTL
+// are served as they are; older ones are served while a background worker
+// reads them again from the base navigator. Call Close to finish queued
+// refreshes and save the store.
+func (c *CachedNavigator) WithStatusStore(store StatusStore) *CachedNavigator {
+	c.store = store
+	return c
+}
+
+// queueRefresh schedules a background read of the status of path, starting
+// the worker on first use. Paths already queued and reads past a full queue
+// are dropped.
+func (c *CachedNavigator) queueRefresh(path string) {
+	r := &c.refresh
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed || r.pending[path] {
+		return
+	}
+	if r.queue == nil {
+		r.queue = make(chan string, refreshQueueSize)
+		r.pending = make(map[string]bool)
+		r.done = make(chan struct{})
+		go c.refreshWorker(r.queue, r.done)
+	}
+	select {
+	case r.queue <- path:
+		r.pending[path] = true
+	default:
+	}
+}
+
+// refreshWorker reads queued paths from the base navigator and stores them
+func (c *CachedNavigator) refreshWorker(queue <-chan string, done chan<- struct{}) {
+	defer close(done)
+	for path := range queue {
+		if status, err := c.base.GetNodeStatus(path); err == nil && status != nil {
+			c.putInCache(fmt.Sprintf("status:%s", path), status)
+			c.store.StoreStatus(path, status)
+		}
+
+		c.refresh.mu.Lock()
+		delete(c.refresh.pending, path)
+		c.refresh.mu.Unlock()
+	}
+}
+
+// Close finishes queued refreshes and saves the status store
+func (c *CachedNavigator) Close() error {
+	r := &c.refresh
+	r.mu.Lock()
+	r.closed = true
+	queue, done := r.queue, r.done
+	r.queue = nil
+	r.mu.Unlock()
+	if queue != nil {
+		close(queue)
+		<-done
+	}
+
+	if c.store == nil {
+		return nil
+	}
+	return c.store.Save()
+}
@@ -0,0 +1,175 @@
+package navigator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+)
+
+// TestFactoryParseNavigatorConfig tests the navigator section of muno.yaml
+func TestFactoryParseNavigatorConfig(t *testing.T) {
+	workspace := t.TempDir()
+	cfg := config.DefaultConfigTree("test")
+	cfg.Navigator = &config.NavigatorConfig{
+		Cache: config.NavigatorCacheConfig{
+			Enabled: true,
+			TTL:     "5m",
+			MaxSize: 500,
+		},
+		LazyLoadTimeout: "2m",
+	}
+	factory := NewFactory(workspace, cfg, nil)
+
+	opts, err := factory.parseConfigOptions()
+	require.NoError(t, err)
+	assert.True(t, opts.CacheEnabled)
+	assert.Equal(t, 5*time.Minute, opts.CacheTTL)
+	assert.Equal(t, 500, opts.MaxCacheSize)
+	assert.Equal(t, 2*time.Minute, opts.LazyLoadTimeout)
+	assert.Equal(t, factory.defaultOptions().RefreshInterval, opts.RefreshInterval)
+	assert.Nil(t, opts.StatusStore)
+
+	// Invalid durations are reported instead of falling back to the default
+	cfg.Navigator.RefreshInterval = "not-a-duration"
+	_, err = factory.CreateFromConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "navigator.refresh_interval")
+	cfg.Navigator.RefreshInterval = ""
+
+	store := newMemoryStatusStore()
+	nav, err := factory.WithStatusStore(store).CreateFromConfig()
+	require.NoError(t, err)
+	cached, ok := nav.(*CachedNavigator)
+	require.True(t, ok)
+	assert.Equal(t, store, cached.store)
+	assert.Equal(t, 500, cached.maxSize)
+	require.NoError(t, cached.Close())
+
+	// An explicit type wins over the cache setting
+	cfg.Navigator.Type = string(TypeFilesystem)
+	nav, err = factory.CreateFromConfig()
+	require.NoError(t, err)
+	fsNav, ok := nav.(*FilesystemNavigator)
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Minute, fsNav.lazyLoadTimeout)
+}
+
+// memoryStatusStore is a StatusStore that keeps entries in a map
+type memoryStatusStore struct {
+	mu       sync.Mutex
+	statuses map[string]*NodeStatus
+	maxAge   time.Duration
+	saves    int
+}
+
+func newMemoryStatusStore() *memoryStatusStore {
+	return &memoryStatusStore{statuses: make(map[string]*NodeStatus)}
+}
+
+func (s *memoryStatusStore) LoadStatus(path string, maxAge time.Duration) *NodeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxAge = maxAge
+	status := s.statuses[path]
+	if status == nil || time.Since(status.LastCheck) > maxAge {
+		return nil
+	}
+	return status
+}
+
+func (s *memoryStatusStore) StoreStatus(path string, status *NodeStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[path] = status
+}
+
+func (s *memoryStatusStore) ForgetStatus(path string) {
+	delete(s.statuses, path)
+}
+
+func (s *memoryStatusStore) Save() error {
+	s.saves++
+	return nil
+}
+
+// TestCachedNavigatorStatusStore tests status persisted between runs
+func TestCachedNavigatorStatusStore(t *testing.T) {
+	store := newMemoryStatusStore()
+	base := NewInMemoryNavigator()
+	base.AddNode("/api", &Node{Path: "/api", Name: "api", Type: NodeTypeRepo})
+	base.SetNodeStatus("/api", &NodeStatus{Exists: true, Cloned: true, Branch: "main", LastCheck: time.Now()})
+
+	// First run reads from the base navigator and saves on close
+	first := NewCachedNavigator(base, time.Hour).WithStatusStore(store)
+	status, err := first.GetNodeStatus("/api")
+	require.NoError(t, err)
+	assert.Equal(t, "main", status.Branch)
+	require.NoError(t, first.Close())
+	assert.Equal(t, 1, store.saves)
+	assert.Contains(t, store.statuses, "/api")
+
+	// Second run answers from the store without asking the base navigator
+	base.SetNodeStatus("/api", &NodeStatus{Exists: true, Cloned: true, Branch: "develop", LastCheck: time.Now()})
+	second := NewCachedNavigator(base, time.Hour).WithStatusStore(store)
+	status, err = second.GetNodeStatus("/api")
+	require.NoError(t, err)
+	assert.Equal(t, "main", status.Branch)
+	assert.Equal(t, staleStatusAge, store.maxAge)
+
+	// RefreshStatus drops the stored entry
+	require.NoError(t, second.RefreshStatus("/api"))
+	status, err = second.GetNodeStatus("/api")
+	require.NoError(t, err)
+	assert.Equal(t, "develop", status.Branch)
+}
+
+// TestCachedNavigatorStatusStoreStale tests that entries older than the TTL
+// are served at once and refreshed in the background
+func TestCachedNavigatorStatusStoreStale(t *testing.T) {
+	store := newMemoryStatusStore()
+	store.statuses["/api"] = &NodeStatus{Exists: true, Cloned: true, Branch: "old", LastCheck: time.Now().Add(-time.Hour)}
+
+	base := NewInMemoryNavigator()
+	base.AddNode("/api", &Node{Path: "/api", Name: "api", Type: NodeTypeRepo})
+	base.SetNodeStatus("/api", &NodeStatus{Exists: true, Cloned: true, Branch: "new", LastCheck: time.Now()})
+
+	cached := NewCachedNavigator(base, time.Minute).WithStatusStore(store)
+	status, err := cached.GetNodeStatus("/api")
+	require.NoError(t, err)
+	assert.Equal(t, "old", status.Branch)
+
+	// Close waits for the queued refresh before saving
+	require.NoError(t, cached.Close())
+	assert.Equal(t, "new", store.statuses["/api"].Branch)
+	assert.Equal(t, 1, store.saves)
+
+	status, err = cached.GetNodeStatus("/api")
+	require.NoError(t, err)
+	assert.Equal(t, "new", status.Branch)
+
+	// Entries past the stale window are read synchronously
+	store.statuses["/api"] = &NodeStatus{Exists: true, Cloned: true, Branch: "ancient", LastCheck: time.Now().Add(-2 * staleStatusAge)}
+	base.SetNodeStatus("/api", &NodeStatus{Exists: true, Cloned: true, Branch: "latest", LastCheck: time.Now()})
+	status, err = NewCachedNavigator(base, time.Minute).WithStatusStore(store).GetNodeStatus("/api")
+	require.NoError(t, err)
+	assert.Equal(t, "latest", status.Branch)
+}
+
+// TestCountStatusChanges tests parsing of git status --short output
+func TestCountStatusChanges(t *testing.T) {
+	status := &NodeStatus{}
+	countStatusChanges(status, "## main...origin/main [ahead 1, behind 3]\nM  staged.go\n M unstaged.go\nMM both.go\n?? new.go\n?? other.go\n")
+
+	assert.Equal(t, 2, status.Staged)
+	assert.Equal(t, 2, status.Unstaged)
+	assert.Equal(t, 2, status.Untracked)
+	assert.Equal(t, 3, status.Behind)
+
+	clean := &NodeStatus{}
+	countStatusChanges(clean, "## main...origin/main\n")
+	assert.Equal(t, NodeStatus{}, *clean)
+}
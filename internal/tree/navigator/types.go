@@ -91,6 +91,14 @@ type NodeStatus struct {
 	// RemoteURL is the configured remote URL (for repositories)
 	RemoteURL string `json:"remote_url,omitempty"`
 	
+	// Staged, Unstaged and Untracked count changed files by kind
+	Staged    int `json:"staged,omitempty"`
+	Unstaged  int `json:"unstaged,omitempty"`
+	Untracked int `json:"untracked,omitempty"`
+	
+	// Behind counts upstream commits not yet merged, as of the last fetch
+	Behind int `json:"behind,omitempty"`
+	
 	// LastCheck is when this status was last updated
 	LastCheck time.Time `json:"last_check"`
 	
@@ -138,6 +146,10 @@ type NavigatorOptions struct {
 	
 	// RefreshInterval is how often to refresh status automatically
 	RefreshInterval time.Duration
+	
+	// StatusStore persists node status of cached navigators between runs.
+	// Nil keeps status in memory only.
+	StatusStore StatusStore
 }
 
 // NewNode creates a new node with the given parameters
@@ -0,0 +1,34 @@
+package tree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/git"
+)
+
+// RemoteConfigCache returns the cache of remote configs of a workspace
+func RemoteConfigCache(workspace string) *git.ConfigCache {
+	return git.NewConfigCache(filepath.Join(workspace, filepath.FromSlash(config.RemoteConfigDir)))
+}
+
+// ResolveRemoteConfig returns the local path of a remote config reference,
+// fetching its repository into the workspace cache on first use. Later
+// calls read the pinned snapshot until muno pull refreshes it.
+func ResolveRemoteConfig(workspace, file string) (string, error) {
+	ref, err := config.ParseRemoteRef(file)
+	if err != nil {
+		return "", err
+	}
+	snapshot, err := RemoteConfigCache(workspace).Snapshot(ref.Repo, ref.Ref)
+	if err != nil {
+		return "", fmt.Errorf("fetching %s: %w", file, err)
+	}
+	configPath := filepath.Join(snapshot, filepath.FromSlash(ref.Path))
+	if _, err := os.Stat(configPath); err != nil {
+		return "", fmt.Errorf("%s not found in %s", ref.Path, ref.Repo)
+	}
+	return configPath, nil
+}
@@ -27,7 +27,10 @@ func (r *ConfigResolver) LoadNodeFile(basePath string, node *config.NodeDefiniti
 		return nil, nil // No config to load
 	}
 	
-	configPath := r.resolveFilePath(basePath, node)
+	configPath, err := r.configPath(basePath, node)
+	if err != nil {
+		return nil, err
+	}
 	
 	// Check cache first
 	if cached, ok := r.cache[configPath]; ok {
@@ -94,11 +97,11 @@ func (r *ConfigResolver) buildNode(basePath string, nodeDef *config.NodeDefiniti
 		// If repo is cloned, auto-discover its config
 		if node.State != RepoStateMissing {
 			if configPath, found := AutoDiscoverConfig(nodePath); found {
-				// Load the auto-discovered config
+				// A broken config keeps the repository but marks it as an
+				// error node instead of dropping its subtree
 				cfg, err := config.LoadTree(configPath)
 				if err != nil {
-					// Log warning but don't fail
-					fmt.Fprintf(os.Stderr, "Warning: failed to load auto-discovered config in %s: %v\n", nodeDef.Name, err)
+					node.ConfigError = &ConfigError{File: configPath, Message: err.Error()}
 				} else if cfg != nil {
 					// Cache the discovered config
 					r.cache[configPath] = cfg
@@ -106,7 +109,7 @@ func (r *ConfigResolver) buildNode(basePath string, nodeDef *config.NodeDefiniti
 					// Build sub-tree from discovered config
 					subNodes, err := r.BuildDistributedTree(cfg, nodePath)
 					if err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: failed to build tree for %s: %v\n", nodeDef.Name, err)
+						node.ConfigError = &ConfigError{File: configPath, Message: err.Error()}
 					} else if rootNode, ok := subNodes[""]; ok {
 						// Add children names from the sub-tree root
 						node.Children = rootNode.Children
@@ -118,11 +121,16 @@ func (r *ConfigResolver) buildNode(basePath string, nodeDef *config.NodeDefiniti
 	case NodeKindFile:
 		node.Type = "config"  // Config-only node type
 		
-		// Load the referenced config
-		configPath := r.resolveFilePath(basePath, nodeDef)
+		// Load the referenced config; failures become an error node
+		configPath, err := r.configPath(basePath, nodeDef)
+		if err != nil {
+			node.ConfigError = &ConfigError{File: nodeDef.File, Message: err.Error()}
+			return node, nil
+		}
 		cfg, err := config.LoadTree(configPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load config reference %s: %w", configPath, err)
+			node.ConfigError = &ConfigError{File: configPath, Message: err.Error()}
+			return node, nil
 		}
 		
 		// Cache it
@@ -131,7 +139,8 @@ func (r *ConfigResolver) buildNode(basePath string, nodeDef *config.NodeDefiniti
 		// Build sub-tree from referenced config
 		subNodes, err := r.BuildDistributedTree(cfg, nodePath)
 		if err != nil {
-			return nil, err
+			node.ConfigError = &ConfigError{File: configPath, Message: err.Error()}
+			return node, nil
 		}
 		if rootNode, ok := subNodes[""]; ok {
 			node.Children = rootNode.Children
@@ -148,6 +157,15 @@ func (r *ConfigResolver) buildNode(basePath string, nodeDef *config.NodeDefiniti
 	return node, nil
 }
 
+// configPath returns the local path of a node's config, fetching remote
+// references into the workspace cache
+func (r *ConfigResolver) configPath(basePath string, node *config.NodeDefinition) (string, error) {
+	if config.IsRemoteRef(node.File) {
+		return ResolveRemoteConfig(r.root, node.File)
+	}
+	return r.resolveFilePath(basePath, node), nil
+}
+
 func (r *ConfigResolver) resolveFilePath(basePath string, node *config.NodeDefinition) string {
 	if filepath.IsAbs(node.File) {
 		return node.File
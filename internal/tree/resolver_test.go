@@ -0,0 +1,92 @@
+package tree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+)
+
+const brokenConfig = "workspace:\n  name: team\nnodes:\n  - name: a\n    url: [broken\n"
+
+func TestConfigResolver_ErrorNodes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	repoPath := filepath.Join(tmpDir, "backend")
+	require.NoError(t, exec.Command("git", "init", "--quiet", repoPath).Run())
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "muno.yaml"), []byte(brokenConfig), 0644))
+
+	cfg := &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "ws"},
+		Nodes: []config.NodeDefinition{
+			{Name: "backend", URL: "https://github.com/acme/backend.git"},
+			{Name: "team", File: filepath.Join(tmpDir, "missing.yaml")},
+		},
+	}
+	nodes, err := NewConfigResolver(tmpDir).BuildDistributedTree(cfg, tmpDir)
+	require.NoError(t, err)
+
+	// The subtrees are missing but the nodes stay, carrying the error
+	backend := nodes["backend"]
+	require.NotNil(t, backend.ConfigError)
+	assert.Equal(t, filepath.Join(repoPath, "muno.yaml"), backend.ConfigError.File)
+	assert.Contains(t, backend.ConfigError.Message, "did not find expected")
+	assert.Empty(t, backend.Children)
+
+	team := nodes["team"]
+	require.NotNil(t, team.ConfigError)
+	assert.Equal(t, filepath.Join(tmpDir, "missing.yaml"), team.ConfigError.File)
+	assert.Equal(t, []string{"backend", "team"}, nodes[""].Children)
+}
+
+func TestManager_GetNodeConfigError(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "team.yaml"), []byte(brokenConfig), 0644))
+	cfg := &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "ws", ReposDir: ".nodes"},
+		Nodes: []config.NodeDefinition{
+			{Name: "team", File: "team.yaml"},
+			{Name: "gone", File: "gone.yaml"},
+		},
+	}
+	require.NoError(t, cfg.Save(filepath.Join(tmpDir, "muno.yaml")))
+	mgr, err := NewManager(tmpDir, &MockGitInterface{})
+	require.NoError(t, err)
+
+	team := mgr.GetNode("/team")
+	require.NotNil(t, team)
+	require.NotNil(t, team.ConfigError)
+	assert.Equal(t, filepath.Join(tmpDir, "team.yaml"), team.ConfigError.File)
+	assert.Contains(t, team.ConfigError.Error(), "team.yaml: parsing config file")
+
+	gone := mgr.GetNode("/gone")
+	require.NotNil(t, gone)
+	require.NotNil(t, gone.ConfigError)
+	assert.Contains(t, gone.ConfigError.Message, "config file not found")
+}
+
+func TestManager_GetNodeResolvesFilesFromDeclaringConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "team"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "team", "muno.yaml"), []byte("workspace:\n  name: team\nnodes:\n  - name: shared\n    file: ../ok.yaml\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "ok.yaml"), []byte("workspace:\n  name: ok\nnodes:\n  - name: lib\n    url: https://github.com/acme/lib.git\n"), 0644))
+	cfg := &config.ConfigTree{
+		Workspace: config.WorkspaceTree{Name: "ws", ReposDir: ".nodes"},
+		Nodes:     []config.NodeDefinition{{Name: "team", File: "team/muno.yaml"}},
+	}
+	require.NoError(t, cfg.Save(filepath.Join(tmpDir, "muno.yaml")))
+	mgr, err := NewManager(tmpDir, &MockGitInterface{})
+	require.NoError(t, err)
+
+	// ../ok.yaml is next to the workspace config, not above the workspace
+	shared := mgr.GetNode("/team/shared")
+	require.NotNil(t, shared)
+	assert.Nil(t, shared.ConfigError)
+	assert.Equal(t, []string{"lib"}, shared.Children)
+}
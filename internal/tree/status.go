@@ -18,6 +18,8 @@ func GetRepoState(repoPath string) RepoState {
 	// Check for modifications using git status
 	cmd := exec.Command("git", "status", "--porcelain")
 	cmd.Dir = repoPath
+	// Keep git from refreshing the index, which would change the status cache signature
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
 	output, err := cmd.Output()
 	if err != nil {
 		// If git command fails, assume missing
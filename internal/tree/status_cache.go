@@ -0,0 +1,592 @@
+package tree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+// StatusCache remembers repository states between runs so git status only
+// runs in repositories whose HEAD, index or worktree changed. Entries are
+// stored in the workspace state file, together with the node status details
+// the navigator records through NodeStatusStore.
+type StatusCache struct {
+	workspacePath string
+	statePath     string
+
+	mu        sync.Mutex
+	entries   map[string]config.RepoStatus
+	refresh   bool
+	refreshed map[string]bool
+	dirty     bool
+	hits      int64
+	misses    int64
+}
+
+// StatusCacheStats provides status cache statistics
+type StatusCacheStats struct {
+	Size   int
+	Hits   int64
+	Misses int64
+}
+
+// NewStatusCache creates a status cache backed by the workspace state file
+func NewStatusCache(workspacePath string) *StatusCache {
+	c := &StatusCache{
+		workspacePath: workspacePath,
+		statePath:     filepath.Join(workspacePath, config.GetStateFileName()),
+		entries:       make(map[string]config.RepoStatus),
+		refreshed:     make(map[string]bool),
+	}
+
+	// A missing or unreadable state file just means an empty cache
+	if state, err := config.LoadState(c.statePath); err == nil && state.StatusCache != nil {
+		c.entries = state.StatusCache
+	}
+	return c
+}
+
+// SetRefresh forces every repository to be checked with git once, ignoring cached entries
+func (c *StatusCache) SetRefresh(refresh bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh = refresh
+	c.refreshed = make(map[string]bool)
+}
+
+// RepoState returns the state of the repository at repoPath, running git
+// status only if the repository changed since the cached entry was recorded
+func (c *StatusCache) RepoState(repoPath string) RepoState {
+	key := c.key(repoPath)
+
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+		c.forget(key)
+		return RepoStateMissing
+	}
+
+	sig, err := repoSignature(repoPath)
+	if err != nil {
+		c.countMiss()
+		return GetRepoState(repoPath)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	forced := c.refresh && !c.refreshed[key]
+	if ok && !forced && sameSignature(entry, sig) {
+		c.hits++
+		c.mu.Unlock()
+		return RepoState(entry.State)
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	state := GetRepoState(repoPath)
+	if state == RepoStateMissing {
+		// git failed; do not remember a state it did not report
+		c.forget(key)
+		return state
+	}
+
+	sig.State = string(state)
+	sig.CheckedAt = time.Now().Format(time.RFC3339)
+
+	c.mu.Lock()
+	c.entries[key] = sig
+	c.refreshed[key] = true
+	c.dirty = true
+	c.mu.Unlock()
+
+	return state
+}
+
+// Stats returns cache statistics for the current run
+func (c *StatusCache) Stats() StatusCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return StatusCacheStats{
+		Size:   len(c.entries),
+		Hits:   c.hits,
+		Misses: c.misses,
+	}
+}
+
+// Save writes changed entries to the workspace state file
+func (c *StatusCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	// Re-read the state so sessions written by other commands are kept
+	state, err := config.LoadState(c.statePath)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	if state.Sessions == nil {
+		state.Sessions = make(map[string]config.Session)
+	}
+	state.StatusCache = c.entries
+	if err := state.SaveState(c.statePath); err != nil {
+		return err
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// NodeStatusStore returns the cache as the navigator's persisted status, so
+// navigator status shares entries and signatures with repository states.
+// resolve maps a tree path to the repository directory.
+func (c *StatusCache) NodeStatusStore(resolve func(treePath string) string) navigator.StatusStore {
+	return &nodeStatusStore{cache: c, resolve: resolve}
+}
+
+// nodeStatusStore stores navigator status in status cache entries
+type nodeStatusStore struct {
+	cache   *StatusCache
+	resolve func(treePath string) string
+}
+
+// LoadStatus returns the status recorded for a node when its repository is
+// unchanged since and the entry is not older than maxAge
+func (s *nodeStatusStore) LoadStatus(path string, maxAge time.Duration) *navigator.NodeStatus {
+	repoPath := s.resolve(path)
+	sig, err := repoSignature(repoPath)
+	if err != nil {
+		return nil
+	}
+
+	c := s.cache
+	key := c.key(repoPath)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.Node == nil || (c.refresh && !c.refreshed[key]) || !sameSignature(entry, sig) {
+		return nil
+	}
+	checked, err := time.Parse(time.RFC3339Nano, entry.CheckedAt)
+	if err != nil || time.Since(checked) > maxAge {
+		return nil
+	}
+	// A fetch since may have changed how far behind upstream the repository is
+	if gitDir, err := resolveGitDir(repoPath); err == nil {
+		if info, err := os.Stat(filepath.Join(gitDir, "FETCH_HEAD")); err == nil && info.ModTime().After(checked) {
+			return nil
+		}
+	}
+
+	state := navigator.RepoState(entry.State)
+	return &navigator.NodeStatus{
+		Exists:    true,
+		Cloned:    true,
+		State:     state,
+		Modified:  state == navigator.RepoStateModified,
+		Lazy:      entry.Node.Lazy,
+		Branch:    entry.Node.Branch,
+		RemoteURL: entry.Node.RemoteURL,
+		Staged:    entry.Node.Staged,
+		Unstaged:  entry.Node.Unstaged,
+		Untracked: entry.Node.Untracked,
+		Behind:    entry.Node.Behind,
+		LastCheck: checked,
+	}
+}
+
+// StoreStatus records the status of a cloned repository node
+func (s *nodeStatusStore) StoreStatus(path string, status *navigator.NodeStatus) {
+	if status == nil || !status.Cloned || status.Error != "" {
+		return
+	}
+	repoPath := s.resolve(path)
+	sig, err := repoSignature(repoPath)
+	if err != nil {
+		return
+	}
+
+	sig.State = string(status.State)
+	sig.CheckedAt = status.LastCheck.Format(time.RFC3339Nano)
+	sig.Node = &config.RepoNodeStatus{
+		Branch:    status.Branch,
+		RemoteURL: status.RemoteURL,
+		Staged:    status.Staged,
+		Unstaged:  status.Unstaged,
+		Untracked: status.Untracked,
+		Behind:    status.Behind,
+		Lazy:      status.Lazy,
+	}
+
+	c := s.cache
+	key := c.key(repoPath)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = sig
+	c.refreshed[key] = true
+	c.dirty = true
+}
+
+// ForgetStatus drops the entries of the node at path and every repository below it
+func (s *nodeStatusStore) ForgetStatus(path string) {
+	c := s.cache
+	key := c.key(s.resolve(path))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for entryKey := range c.entries {
+		if key == "." || entryKey == key || strings.HasPrefix(entryKey, key+"/") {
+			delete(c.entries, entryKey)
+			c.dirty = true
+		}
+	}
+}
+
+// Save writes the entries to the workspace state file
+func (s *nodeStatusStore) Save() error {
+	return s.cache.Save()
+}
+
+// key returns the workspace-relative cache key for a repository path
+func (c *StatusCache) key(repoPath string) string {
+	if rel, err := filepath.Rel(c.workspacePath, repoPath); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(repoPath)
+}
+
+func (c *StatusCache) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		delete(c.entries, key)
+		c.dirty = true
+	}
+}
+
+func (c *StatusCache) countMiss() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.misses++
+}
+
+// sameSignature reports whether a cached entry was recorded for the repository state sig
+func sameSignature(entry, sig config.RepoStatus) bool {
+	return entry.Head == sig.Head && entry.IndexTime == sig.IndexTime &&
+		entry.IndexSize == sig.IndexSize && entry.Worktree == sig.Worktree
+}
+
+// repoSignature reads HEAD, the index stat and a worktree hash without running git
+func repoSignature(repoPath string) (config.RepoStatus, error) {
+	gitDir, err := resolveGitDir(repoPath)
+	if err != nil {
+		return config.RepoStatus{}, err
+	}
+
+	head, err := readHead(gitDir)
+	if err != nil {
+		return config.RepoStatus{}, err
+	}
+	sig := config.RepoStatus{Head: head}
+
+	if info, err := os.Stat(filepath.Join(gitDir, "index")); err == nil {
+		sig.IndexTime = info.ModTime().UnixNano()
+		sig.IndexSize = info.Size()
+	}
+
+	sig.Worktree, err = worktreeSignature(repoPath, gitDir)
+	if err != nil {
+		return config.RepoStatus{}, err
+	}
+	return sig, nil
+}
+
+// resolveGitDir returns the git directory of a repository, following .git files of worktrees and submodules
+func resolveGitDir(repoPath string) (string, error) {
+	gitPath := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return gitPath, nil
+	}
+
+	data, err := os.ReadFile(gitPath)
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", fmt.Errorf("invalid .git file in %s", repoPath)
+	}
+	dir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return dir, nil
+}
+
+// GitDir returns the git directory of repoPath, following .git files of
+// worktrees and submodules, or "" when it is not a repository
+func GitDir(repoPath string) string {
+	gitDir, err := resolveGitDir(repoPath)
+	if err != nil {
+		return ""
+	}
+	return gitDir
+}
+
+// HeadCommit returns the commit checked out in repoPath without running git,
+// or "" when it is not a repository or the branch has no commits yet
+func HeadCommit(repoPath string) string {
+	gitDir, err := resolveGitDir(repoPath)
+	if err != nil {
+		return ""
+	}
+	head, err := readHead(gitDir)
+	if err != nil || strings.HasPrefix(head, "ref:") {
+		return ""
+	}
+	return head
+}
+
+// HeadBranch returns the branch checked out in repoPath, or "" for a
+// detached HEAD or when it is not a repository
+func HeadBranch(repoPath string) string {
+	gitDir, err := resolveGitDir(repoPath)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	branch, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "ref: refs/heads/")
+	if !ok {
+		return ""
+	}
+	return branch
+}
+
+// readHead returns the commit HEAD points to, or the symbolic ref for an unborn branch
+func readHead(gitDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	head := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(head, "ref:") {
+		return head, nil
+	}
+	if commit := readRef(gitDir, strings.TrimSpace(strings.TrimPrefix(head, "ref:"))); commit != "" {
+		return commit, nil
+	}
+	return head, nil
+}
+
+// BranchCommit returns the commit a local branch of repoPath points to without
+// running git, or "" when there is no such branch
+func BranchCommit(repoPath string, branch string) string {
+	gitDir, err := resolveGitDir(repoPath)
+	if err != nil {
+		return ""
+	}
+	return readRef(gitDir, "refs/heads/"+branch)
+}
+
+// readRef returns the commit ref points to, loose or packed, or ""
+func readRef(gitDir string, ref string) string {
+	// Linked worktrees keep shared refs in the common directory
+	dirs := []string{gitDir}
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		dirs = append(dirs, commonDir)
+	}
+
+	for _, dir := range dirs {
+		if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	for _, dir := range dirs {
+		if commit := packedRef(filepath.Join(dir, "packed-refs"), ref); commit != "" {
+			return commit
+		}
+	}
+	return ""
+}
+
+// packedRef looks up ref in a packed-refs file
+func packedRef(path, ref string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == ref {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// worktreeSignature hashes the size and modification time of every tracked
+// file, the entry names of the directories holding them and the ignore rules,
+// which covers what git status reports without walking untracked trees such
+// as node_modules or nested repositories. Files added inside a directory that
+// only holds ignored files go unnoticed until --refresh.
+func worktreeSignature(repoPath, gitDir string) (string, error) {
+	tracked, err := indexPaths(filepath.Join(gitDir, "index"))
+	if err != nil {
+		return "", err
+	}
+
+	hash := fnv.New64a()
+	dirs := map[string]bool{".": true}
+	for _, file := range tracked {
+		if info, err := os.Lstat(filepath.Join(repoPath, filepath.FromSlash(file))); err == nil {
+			fmt.Fprintf(hash, "%s\x00%d\x00%d\x00%o\n", file, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		} else {
+			fmt.Fprintf(hash, "%s\x00missing\n", file)
+		}
+		for dir := path.Dir(file); dir != "." && !dirs[dir]; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+
+	// New untracked files show up as new entries next to tracked ones
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+	for _, dir := range sorted {
+		entries, err := os.ReadDir(filepath.Join(repoPath, filepath.FromSlash(dir)))
+		if err != nil {
+			fmt.Fprintf(hash, "%s/\x00missing\n", dir)
+			continue
+		}
+		fmt.Fprintf(hash, "%s/", dir)
+		for _, entry := range entries {
+			switch entry.Name() {
+			case ".git", ".muno", config.GetStateFileName():
+				// Git metadata and muno's own files, which change on every run
+				continue
+			}
+			fmt.Fprintf(hash, "\x00%s", entry.Name())
+		}
+		fmt.Fprintln(hash)
+	}
+
+	for _, rules := range []string{filepath.Join(repoPath, ".gitignore"), filepath.Join(gitDir, "info", "exclude")} {
+		if info, err := os.Stat(rules); err == nil {
+			fmt.Fprintf(hash, "%s\x00%d\x00%d\n", rules, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return fmt.Sprintf("%016x", hash.Sum64()), nil
+}
+
+// indexPaths lists the paths tracked in a git index file (versions 2 to 4).
+// A missing index, as in a repository without commits, tracks nothing.
+func indexPaths(indexPath string) ([]string, error) {
+	data, err := os.ReadFile(indexPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil, fmt.Errorf("invalid index %s", indexPath)
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported index version %d", version)
+	}
+	count := int(binary.BigEndian.Uint32(data[8:12]))
+
+	paths := make([]string, 0, count)
+	pos, prev := 12, ""
+	for i := 0; i < count; i++ {
+		// ctime, mtime, dev, ino, mode, uid, gid, size, object id and flags
+		start := pos
+		if pos+62 > len(data) {
+			return nil, fmt.Errorf("truncated index %s", indexPath)
+		}
+		flags := binary.BigEndian.Uint16(data[pos+60 : pos+62])
+		pos += 62
+		if version >= 3 && flags&0x4000 != 0 {
+			pos += 2
+		}
+
+		prefix := ""
+		if version == 4 {
+			// Version 4 stores how much of the previous path to drop before the suffix
+			strip, n := indexVarint(data[pos:])
+			if n == 0 || strip > len(prev) {
+				return nil, fmt.Errorf("invalid index %s", indexPath)
+			}
+			prefix = prev[:len(prev)-strip]
+			pos += n
+		}
+		end := bytes.IndexByte(data[pos:], 0)
+		if end < 0 {
+			return nil, fmt.Errorf("truncated index %s", indexPath)
+		}
+		name := prefix + string(data[pos:pos+end])
+		pos += end + 1
+		if version < 4 {
+			// Entries are padded with NULs to a multiple of eight bytes
+			pos = start + (pos-1-start+8)&^7
+		}
+
+		paths = append(paths, name)
+		prev = name
+	}
+
+	// A split index keeps most entries in a shared file this does not read
+	if pos+4 <= len(data) && string(data[pos:pos+4]) == "link" {
+		return nil, fmt.Errorf("split index %s", indexPath)
+	}
+	return paths, nil
+}
+
+// indexVarint decodes the offset varint used by index version 4, returning
+// the value and the number of bytes read, or 0 bytes if data ends early
+func indexVarint(data []byte) (int, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	c := data[0]
+	value, n := int(c&127), 1
+	for c&128 != 0 {
+		if n >= len(data) {
+			return 0, 0
+		}
+		c = data[n]
+		n++
+		value = (value+1)<<7 | int(c&127)
+	}
+	return value, n
+}
@@ -0,0 +1,276 @@
+package tree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taokim/muno/internal/config"
+	"github.com/taokim/muno/internal/tree/navigator"
+)
+
+func initStatusCacheRepo(t *testing.T, dir string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		t.Skipf("Skipping test: git init failed: %v", err)
+	}
+}
+
+func TestStatusCache(t *testing.T) {
+	workspace := t.TempDir()
+	repo := filepath.Join(workspace, ".nodes", "api")
+	initStatusCacheRepo(t, repo)
+
+	cache := NewStatusCache(workspace)
+	assert.Equal(t, RepoStateCloned, cache.RepoState(repo))
+	assert.Equal(t, StatusCacheStats{Size: 1, Hits: 0, Misses: 1}, cache.Stats())
+	require.NoError(t, cache.Save())
+
+	// A new run answers from the state file
+	cache = NewStatusCache(workspace)
+	assert.Equal(t, RepoStateCloned, cache.RepoState(repo))
+	assert.Equal(t, int64(1), cache.Stats().Hits)
+
+	// Changing the worktree invalidates the entry
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "new.txt"), []byte("test"), 0644))
+	assert.Equal(t, RepoStateModified, cache.RepoState(repo))
+	assert.Equal(t, RepoStateModified, cache.RepoState(repo))
+	assert.Equal(t, StatusCacheStats{Size: 1, Hits: 2, Misses: 1}, cache.Stats())
+
+	// Refresh runs git once per repository
+	cache.SetRefresh(true)
+	assert.Equal(t, RepoStateModified, cache.RepoState(repo))
+	assert.Equal(t, RepoStateModified, cache.RepoState(repo))
+	assert.Equal(t, StatusCacheStats{Size: 1, Hits: 3, Misses: 2}, cache.Stats())
+
+	// Removed repositories are dropped from the cache
+	require.NoError(t, os.RemoveAll(filepath.Join(repo, ".git")))
+	assert.Equal(t, RepoStateMissing, cache.RepoState(repo))
+	assert.Equal(t, 0, cache.Stats().Size)
+}
+
+func TestStatusCacheSaveKeepsSessions(t *testing.T) {
+	workspace := t.TempDir()
+	repo := filepath.Join(workspace, "repo")
+	initStatusCacheRepo(t, repo)
+
+	statePath := filepath.Join(workspace, config.GetStateFileName())
+	state := &config.State{Sessions: map[string]config.Session{"/api": {NodePath: "/api", PID: 42}}}
+	require.NoError(t, state.SaveState(statePath))
+
+	cache := NewStatusCache(workspace)
+	cache.RepoState(repo)
+	require.NoError(t, cache.Save())
+
+	state, err := config.LoadState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, 42, state.Sessions["/api"].PID)
+	require.Contains(t, state.StatusCache, "repo")
+	assert.Equal(t, string(RepoStateCloned), state.StatusCache["repo"].State)
+}
+
+func runStatusCacheGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestWorktreeSignature(t *testing.T) {
+	repo := t.TempDir()
+	initStatusCacheRepo(t, repo)
+	gitDir := filepath.Join(repo, ".git")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".gitignore"), []byte("node_modules/\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "cmd"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "cmd", "main.go"), []byte("package main"), 0644))
+	runStatusCacheGit(t, repo, "add", ".gitignore", "cmd/main.go")
+
+	child := filepath.Join(repo, ".nodes", "child")
+	require.NoError(t, os.MkdirAll(filepath.Join(child, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "node_modules", "pkg"), 0755))
+
+	before, err := worktreeSignature(repo, gitDir)
+	require.NoError(t, err)
+
+	// Git metadata, muno files, ignored trees and nested repositories do not affect the signature
+	require.NoError(t, os.WriteFile(filepath.Join(child, "file.txt"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "node_modules", "pkg", "index.js"), []byte("x"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".muno", "cache"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, config.GetStateFileName()), []byte("{}"), 0644))
+
+	unchanged, err := worktreeSignature(repo, gitDir)
+	require.NoError(t, err)
+	assert.Equal(t, before, unchanged)
+
+	// Edits to tracked files and new files next to them do
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "cmd", "main.go"), []byte("package main\n"), 0644))
+	edited, err := worktreeSignature(repo, gitDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, unchanged, edited)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "cmd", "util.go"), []byte("package main"), 0644))
+	added, err := worktreeSignature(repo, gitDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, edited, added)
+}
+
+func TestIndexPaths(t *testing.T) {
+	repo := t.TempDir()
+	initStatusCacheRepo(t, repo)
+	indexPath := filepath.Join(repo, ".git", "index")
+
+	paths, err := indexPaths(indexPath)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	files := []string{"README.md", "cmd/muno/main.go", "cmd/muno/main_test.go", "internal/a-very-long-directory-name/file.go"}
+	for _, file := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, filepath.Dir(file)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, file), []byte(file), 0644))
+	}
+	runStatusCacheGit(t, repo, "add", ".")
+
+	for _, version := range []string{"2", "3", "4"} {
+		runStatusCacheGit(t, repo, "update-index", "--index-version", version)
+		paths, err := indexPaths(indexPath)
+		require.NoError(t, err, "version %s", version)
+		assert.Equal(t, files, paths, "version %s", version)
+	}
+
+	require.NoError(t, os.WriteFile(indexPath, []byte("not an index"), 0644))
+	_, err = indexPaths(indexPath)
+	assert.Error(t, err)
+}
+
+func TestNodeStatusStore(t *testing.T) {
+	workspace := t.TempDir()
+	repo := filepath.Join(workspace, ".nodes", "api")
+	initStatusCacheRepo(t, repo)
+	resolve := func(treePath string) string {
+		if treePath == "/" {
+			return workspace
+		}
+		return filepath.Join(workspace, ".nodes", strings.TrimPrefix(treePath, "/"))
+	}
+
+	cache := NewStatusCache(workspace)
+	store := cache.NodeStatusStore(resolve)
+	assert.Nil(t, store.LoadStatus("/api", time.Hour))
+
+	store.StoreStatus("/api", &navigator.NodeStatus{Exists: true, Cloned: true, State: navigator.RepoStateModified,
+		Branch: "main", Untracked: 1, LastCheck: time.Now()})
+	require.NoError(t, store.Save())
+
+	// The entry is shared with repository states and survives a new run
+	cache = NewStatusCache(workspace)
+	assert.Equal(t, RepoStateModified, cache.RepoState(repo))
+	assert.Equal(t, int64(1), cache.Stats().Hits)
+	store = cache.NodeStatusStore(resolve)
+	status := store.LoadStatus("/api", time.Hour)
+	require.NotNil(t, status)
+	assert.Equal(t, "main", status.Branch)
+	assert.Equal(t, 1, status.Untracked)
+	assert.True(t, status.Modified)
+
+	// Entries older than the TTL are not served
+	assert.Nil(t, store.LoadStatus("/api", time.Nanosecond))
+
+	// Nor are entries recorded before the repository changed
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x"), 0644))
+	assert.Nil(t, store.LoadStatus("/api", time.Hour))
+
+	store.StoreStatus("/api", &navigator.NodeStatus{Exists: true, Cloned: true, State: navigator.RepoStateModified, LastCheck: time.Now()})
+	require.NotNil(t, store.LoadStatus("/api", time.Hour))
+
+	// A fetch since may have moved the upstream branch
+	fetchHead := filepath.Join(repo, ".git", "FETCH_HEAD")
+	require.NoError(t, os.WriteFile(fetchHead, nil, 0644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(fetchHead, later, later))
+	assert.Nil(t, store.LoadStatus("/api", time.Hour))
+
+	store.ForgetStatus("/")
+	assert.Equal(t, 0, cache.Stats().Size)
+}
+
+func TestReadHead(t *testing.T) {
+	gitDir := t.TempDir()
+
+	// Unborn branch resolves to the symbolic ref
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+	head, err := readHead(gitDir)
+	require.NoError(t, err)
+	assert.Equal(t, "ref: refs/heads/main", head)
+
+	// Packed refs
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "packed-refs"),
+		[]byte("# pack-refs with: peeled fully-peeled sorted\nabc123 refs/heads/main\n"), 0644))
+	head, err = readHead(gitDir)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", head)
+
+	// Loose refs win over packed refs
+	require.NoError(t, os.MkdirAll(filepath.Join(gitDir, "refs", "heads"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "refs", "heads", "main"), []byte("def456\n"), 0644))
+	head, err = readHead(gitDir)
+	require.NoError(t, err)
+	assert.Equal(t, "def456", head)
+
+	// Detached HEAD
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("0123abcd\n"), 0644))
+	head, err = readHead(gitDir)
+	require.NoError(t, err)
+	assert.Equal(t, "0123abcd", head)
+}
+
+func TestResolveGitDir(t *testing.T) {
+	repo := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".git"), []byte("gitdir: ../main/.git/worktrees/feature\n"), 0644))
+
+	dir, err := resolveGitDir(repo)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, "..", "main", ".git", "worktrees", "feature"), dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".git"), []byte("garbage"), 0644))
+	_, err = resolveGitDir(repo)
+	assert.Error(t, err)
+}
+
+func TestHeadCommit(t *testing.T) {
+	repo := t.TempDir()
+	assert.Equal(t, "", HeadCommit(repo))
+
+	gitDir := filepath.Join(repo, ".git")
+	require.NoError(t, os.MkdirAll(filepath.Join(gitDir, "refs", "heads"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+	assert.Equal(t, "", HeadCommit(repo), "unborn branch has no commit")
+
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "refs", "heads", "main"), []byte("def456\n"), 0644))
+	assert.Equal(t, "def456", HeadCommit(repo))
+	assert.Equal(t, "main", HeadBranch(repo))
+
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("def456\n"), 0644))
+	assert.Equal(t, "def456", HeadCommit(repo))
+	assert.Equal(t, "", HeadBranch(repo), "detached HEAD has no branch")
+}
+
+func TestBranchCommit(t *testing.T) {
+	repo := t.TempDir()
+	gitDir := filepath.Join(repo, ".git")
+	require.NoError(t, os.MkdirAll(filepath.Join(gitDir, "refs", "heads", "topic"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "refs", "heads", "topic", "login"), []byte("abc123\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "packed-refs"), []byte("# pack-refs with: peeled\ndef456 refs/heads/main\n"), 0644))
+
+	assert.Equal(t, "abc123", BranchCommit(repo, "topic/login"))
+	assert.Equal(t, "def456", BranchCommit(repo, "main"), "packed refs are read too")
+	assert.Equal(t, "", BranchCommit(repo, "missing"))
+}
@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -39,6 +40,19 @@ type TreeNode struct {
 	// Config reference (only for type="config")
 	FilePath string `json:"config_path,omitempty"`
 	Cloned     bool   `json:"cloned,omitempty"` // Whether the repository has been cloned
+	
+	// Set when the node's config could not be loaded; its children are missing
+	ConfigError *ConfigError `json:"config_error,omitempty"`
+}
+
+// ConfigError describes a config that could not be loaded into the tree
+type ConfigError struct {
+	File    string `json:"file"`    // Config file or reference that failed
+	Message string `json:"message"` // Why it failed
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
 }
 
 // TreeState is deprecated and no longer used
@@ -0,0 +1,94 @@
+package tui
+
+import "unicode/utf8"
+
+// KeyCode identifies a key press
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota // A printable character in Key.Rune
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDown
+	KeyCtrlC
+	KeyUnknown
+)
+
+// Key is one decoded key press
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// Is reports whether the key is the printable character r
+func (k Key) Is(r rune) bool {
+	return k.Code == KeyRune && k.Rune == r
+}
+
+// escapeSequences maps the CSI and SS3 sequences terminals send for special keys
+var escapeSequences = map[string]KeyCode{
+	"[A": KeyUp, "[B": KeyDown, "[C": KeyRight, "[D": KeyLeft,
+	"OA": KeyUp, "OB": KeyDown, "OC": KeyRight, "OD": KeyLeft,
+	"[H": KeyHome, "[F": KeyEnd, "OH": KeyHome, "OF": KeyEnd,
+	"[1~": KeyHome, "[4~": KeyEnd, "[7~": KeyHome, "[8~": KeyEnd,
+	"[5~": KeyPgUp, "[6~": KeyPgDown,
+}
+
+// parseKeys decodes the bytes of one terminal read into key presses
+func parseKeys(data []byte) []Key {
+	var keys []Key
+	for len(data) > 0 {
+		switch b := data[0]; {
+		case b == 0x1b:
+			key, n := parseEscape(data)
+			keys = append(keys, key)
+			data = data[n:]
+			continue
+		case b == '\r' || b == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+		case b == 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+		case b == 0x0e: // Ctrl-N
+			keys = append(keys, Key{Code: KeyDown})
+		case b == 0x10: // Ctrl-P
+			keys = append(keys, Key{Code: KeyUp})
+		case b < 0x20:
+			keys = append(keys, Key{Code: KeyUnknown})
+		default:
+			r, n := utf8.DecodeRune(data)
+			keys = append(keys, Key{Code: KeyRune, Rune: r})
+			data = data[n:]
+			continue
+		}
+		data = data[1:]
+	}
+	return keys
+}
+
+// parseEscape decodes an escape sequence at the start of data and returns the
+// key and the number of bytes used. A lone ESC is the Escape key.
+func parseEscape(data []byte) (Key, int) {
+	if len(data) == 1 || (data[1] != '[' && data[1] != 'O') {
+		return Key{Code: KeyEsc}, 1
+	}
+	// The sequence ends with its first byte in the range @ to ~
+	for i := 2; i < len(data); i++ {
+		if data[i] >= 0x40 && data[i] <= 0x7e {
+			if code, ok := escapeSequences[string(data[1:i+1])]; ok {
+				return Key{Code: code}, i + 1
+			}
+			return Key{Code: KeyUnknown}, i + 1
+		}
+	}
+	return Key{Code: KeyUnknown}, len(data)
+}
@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	cases := map[string][]Key{
+		"jk":         {{Code: KeyRune, Rune: 'j'}, {Code: KeyRune, Rune: 'k'}},
+		"\x1b[A":     {{Code: KeyUp}},
+		"\x1bOB":     {{Code: KeyDown}},
+		"\x1b[5~":    {{Code: KeyPgUp}},
+		"\x1b[C\x1b": {{Code: KeyRight}, {Code: KeyEsc}},
+		"\x1b":       {{Code: KeyEsc}},
+		"\x1b[1;5A":  {{Code: KeyUnknown}},
+		"\r\x7f\x03": {{Code: KeyEnter}, {Code: KeyBackspace}, {Code: KeyCtrlC}},
+		"é":          {{Code: KeyRune, Rune: 'é'}},
+	}
+	for input, want := range cases {
+		assert.Equal(t, want, parseKeys([]byte(input)), "%q", input)
+	}
+}
@@ -0,0 +1,511 @@
+// Package tui implements muno ui, a full-screen browser for the workspace tree
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Entry is a tree node as reported by a Backend
+type Entry struct {
+	Path        string
+	Name        string
+	HasChildren bool
+	Repo        bool // Backed by a git repository
+	Cloned      bool
+	Lazy        bool
+}
+
+// Status is the live git status of a cloned node
+type Status struct {
+	Branch  string
+	Changes int
+	Ahead   int
+	Behind  int
+	Err     string
+}
+
+// Backend provides the tree and runs actions on its nodes. Actions are called
+// from background goroutines while the tree keeps responding; the runner
+// never calls Clone, Pull or Resolve while any other call is in progress.
+type Backend interface {
+	Root() Entry
+	Children(path string) ([]Entry, error)
+	Status(path string) (Status, error)
+	Clone(path string) error
+	Pull(path string) error
+	// Resolve returns the directory of a node, cloning it when lazy
+	Resolve(path string) (string, error)
+}
+
+// ActionKind is something the model asks the runner to do
+type ActionKind int
+
+const (
+	ActionNone ActionKind = iota
+	ActionQuit
+	ActionSelect
+	ActionClone
+	ActionPull
+	ActionStatus
+)
+
+// Action is a request from the model; Path is the node it applies to
+type Action struct {
+	Kind ActionKind
+	Path string
+}
+
+// row is one visible line of the tree
+type row struct {
+	entry Entry
+	depth int
+}
+
+// Model holds the browser state. It is not safe for concurrent use; the
+// runner feeds it keys and action results from a single goroutine.
+type Model struct {
+	backend   Backend
+	root      Entry
+	children  map[string][]Entry
+	expanded  map[string]bool
+	statuses  map[string]Status
+	busy      map[string]string // Action in progress by path
+	rows      []row
+	cursor    int
+	offset    int
+	filter    string
+	filtering bool
+	message   string
+}
+
+// NewModel creates a model showing the root and its children, with the
+// cursor on start when it is in the tree
+func NewModel(backend Backend, start string) *Model {
+	m := &Model{
+		backend:  backend,
+		root:     backend.Root(),
+		children: make(map[string][]Entry),
+		expanded: make(map[string]bool),
+		statuses: make(map[string]Status),
+		busy:     make(map[string]string),
+	}
+	m.expanded[m.root.Path] = true
+
+	// Expand the ancestors of start so it is visible
+	start = cleanPath(start)
+	for p := parentPath(start); p != "" && p != m.root.Path; p = parentPath(p) {
+		m.expanded[p] = true
+	}
+	m.rebuild()
+	m.moveTo(start)
+	return m
+}
+
+// Selected returns the entry under the cursor
+func (m *Model) Selected() (Entry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return Entry{}, false
+	}
+	return m.rows[m.cursor].entry, true
+}
+
+// SetMessage sets the footer message
+func (m *Model) SetMessage(message string) {
+	m.message = message
+}
+
+// SetStatus records the status of a node
+func (m *Model) SetStatus(path string, status Status) {
+	m.statuses[path] = status
+}
+
+// SetBusy marks an action in progress on a node, or clears it when action is ""
+func (m *Model) SetBusy(path string, action string) {
+	if action == "" {
+		delete(m.busy, path)
+		return
+	}
+	m.busy[path] = action
+}
+
+// Reload forgets the loaded children of path so they are read again, e.g.
+// after cloning it
+func (m *Model) Reload(path string) {
+	for p := range m.children {
+		if p == path || strings.HasPrefix(p, strings.TrimSuffix(path, "/")+"/") {
+			delete(m.children, p)
+		}
+	}
+	if parent := parentPath(path); parent != "" {
+		delete(m.children, parent)
+	}
+	m.rebuild()
+}
+
+// VisibleRepos returns the cloned repositories currently listed, for status
+// refreshes
+func (m *Model) VisibleRepos() []string {
+	var paths []string
+	for _, r := range m.rows {
+		if r.entry.Repo && r.entry.Cloned {
+			paths = append(paths, r.entry.Path)
+		}
+	}
+	return paths
+}
+
+// HasStatus reports whether a status was recorded for path
+func (m *Model) HasStatus(path string) bool {
+	_, ok := m.statuses[path]
+	return ok
+}
+
+// HandleKey updates the model for a key press and returns what the runner
+// should do next
+func (m *Model) HandleKey(key Key) Action {
+	if m.filtering {
+		return m.handleFilterKey(key)
+	}
+
+	switch {
+	case key.Code == KeyCtrlC, key.Is('q'):
+		return Action{Kind: ActionQuit}
+	case key.Code == KeyUp, key.Is('k'):
+		m.moveCursor(-1)
+	case key.Code == KeyDown, key.Is('j'):
+		m.moveCursor(1)
+	case key.Code == KeyPgUp:
+		m.moveCursor(-10)
+	case key.Code == KeyPgDown:
+		m.moveCursor(10)
+	case key.Code == KeyHome, key.Is('g'):
+		m.cursor = 0
+	case key.Code == KeyEnd, key.Is('G'):
+		m.cursor = len(m.rows) - 1
+	case key.Code == KeyRight, key.Is('l'):
+		m.expand(true)
+	case key.Code == KeyLeft, key.Is('h'):
+		m.collapse()
+	case key.Is(' '):
+		m.toggle()
+	case key.Is('/'):
+		m.filtering = true
+		m.message = ""
+	case key.Code == KeyEsc:
+		if m.filter != "" {
+			m.setFilter("")
+		}
+	case key.Code == KeyEnter:
+		if entry, ok := m.Selected(); ok {
+			return Action{Kind: ActionSelect, Path: entry.Path}
+		}
+	case key.Is('c'):
+		return m.repoAction(ActionClone)
+	case key.Is('p'):
+		return m.repoAction(ActionPull)
+	case key.Is('s'):
+		return m.repoAction(ActionStatus)
+	}
+	return Action{}
+}
+
+func (m *Model) handleFilterKey(key Key) Action {
+	switch key.Code {
+	case KeyCtrlC:
+		return Action{Kind: ActionQuit}
+	case KeyEnter:
+		m.filtering = false
+	case KeyEsc:
+		m.filtering = false
+		m.setFilter("")
+	case KeyBackspace:
+		if m.filter != "" {
+			runes := []rune(m.filter)
+			m.setFilter(string(runes[:len(runes)-1]))
+		}
+	case KeyUp:
+		m.moveCursor(-1)
+	case KeyDown:
+		m.moveCursor(1)
+	case KeyRune:
+		m.setFilter(m.filter + string(key.Rune))
+	}
+	return Action{}
+}
+
+// repoAction returns an action on the selected node
+func (m *Model) repoAction(kind ActionKind) Action {
+	entry, ok := m.Selected()
+	if !ok {
+		return Action{}
+	}
+	if !entry.Repo {
+		m.message = fmt.Sprintf("%s is not a repository", entry.Path)
+		return Action{}
+	}
+	if kind == ActionClone && entry.Cloned {
+		m.message = fmt.Sprintf("%s is already cloned", entry.Path)
+		return Action{}
+	}
+	if kind != ActionClone && !entry.Cloned {
+		m.message = fmt.Sprintf("%s is not cloned yet (press c)", entry.Path)
+		return Action{}
+	}
+	return Action{Kind: kind, Path: entry.Path}
+}
+
+func (m *Model) moveCursor(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// moveTo puts the cursor on path if it is visible
+func (m *Model) moveTo(path string) {
+	for i, r := range m.rows {
+		if r.entry.Path == path {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m *Model) expand(descend bool) {
+	entry, ok := m.Selected()
+	if !ok || !entry.HasChildren {
+		return
+	}
+	if m.expanded[entry.Path] && descend {
+		m.moveCursor(1)
+		return
+	}
+	m.expanded[entry.Path] = true
+	m.rebuild()
+}
+
+// collapse folds the selected node, or moves to its parent when it is folded
+func (m *Model) collapse() {
+	entry, ok := m.Selected()
+	if !ok {
+		return
+	}
+	if m.expanded[entry.Path] && entry.HasChildren && entry.Path != m.root.Path && m.filter == "" {
+		m.expanded[entry.Path] = false
+		m.rebuild()
+		return
+	}
+	m.moveTo(parentPath(entry.Path))
+}
+
+func (m *Model) toggle() {
+	entry, ok := m.Selected()
+	if !ok || !entry.HasChildren || entry.Path == m.root.Path {
+		return
+	}
+	m.expanded[entry.Path] = !m.expanded[entry.Path]
+	m.rebuild()
+}
+
+func (m *Model) setFilter(filter string) {
+	selected, _ := m.Selected()
+	m.filter = filter
+	if filter == "" {
+		// Keep the node picked while filtering visible
+		for p := parentPath(selected.Path); p != ""; p = parentPath(p) {
+			m.expanded[p] = true
+		}
+	}
+	m.rebuild()
+	m.moveTo(selected.Path)
+	if m.filter != "" && len(m.rows) > 1 {
+		// Jump to the first match
+		for i, r := range m.rows {
+			if m.matches(r.entry) {
+				m.cursor = i
+				break
+			}
+		}
+	}
+}
+
+// loadChildren returns the children of path, reading them on first use
+func (m *Model) loadChildren(path string) []Entry {
+	if children, ok := m.children[path]; ok {
+		return children
+	}
+	children, err := m.backend.Children(path)
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+	}
+	sort.SliceStable(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	m.children[path] = children
+	return children
+}
+
+func (m *Model) matches(entry Entry) bool {
+	return strings.Contains(strings.ToLower(entry.Name), strings.ToLower(m.filter))
+}
+
+// rebuild recomputes the visible rows. With a filter every matching node is
+// listed together with its ancestors, whatever is expanded.
+func (m *Model) rebuild() {
+	selected, _ := m.Selected()
+	m.rows = m.rows[:0]
+	m.rows = append(m.rows, row{entry: m.root})
+
+	if m.filter == "" {
+		m.appendExpanded(m.root.Path, 1)
+	} else {
+		m.appendMatching(m.root.Path, 1)
+	}
+
+	m.cursor = 0
+	m.moveTo(selected.Path)
+}
+
+func (m *Model) appendExpanded(path string, depth int) {
+	for _, child := range m.loadChildren(path) {
+		m.rows = append(m.rows, row{entry: child, depth: depth})
+		if child.HasChildren && m.expanded[child.Path] {
+			m.appendExpanded(child.Path, depth+1)
+		}
+	}
+}
+
+// appendMatching adds the matches below path and reports whether there were any
+func (m *Model) appendMatching(path string, depth int) bool {
+	found := false
+	for _, child := range m.loadChildren(path) {
+		at := len(m.rows)
+		m.rows = append(m.rows, row{entry: child, depth: depth})
+		below := child.HasChildren && m.appendMatching(child.Path, depth+1)
+		if !below && !m.matches(child) {
+			m.rows = m.rows[:at]
+			continue
+		}
+		found = true
+	}
+	return found
+}
+
+// View renders the browser into exactly height lines of at most width cells
+func (m *Model) View(width, height int) []string {
+	if height < 4 {
+		height = 4
+	}
+	lines := []string{truncate("🌳 muno — "+m.root.Name, width)}
+
+	listHeight := height - 3
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+listHeight {
+		m.offset = m.cursor - listHeight + 1
+	}
+	if m.offset > len(m.rows)-listHeight {
+		m.offset = max(0, len(m.rows)-listHeight)
+	}
+
+	for i := m.offset; i < len(m.rows) && i < m.offset+listHeight; i++ {
+		line := truncate(m.renderRow(m.rows[i]), width)
+		if i == m.cursor {
+			line = reverse + pad(line, width) + reset
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+
+	if m.filtering || m.filter != "" {
+		cursor := ""
+		if m.filtering {
+			cursor = "▏"
+		}
+		lines = append(lines, truncate(fmt.Sprintf("🔍 /%s%s  (%d shown)", m.filter, cursor, len(m.rows)), width))
+	} else {
+		lines = append(lines, truncate(m.message, width))
+	}
+	lines = append(lines, dim+truncate("↑↓ move  ←→ fold  ⏎ cd  / filter  c clone  p pull  s status  q quit", width)+reset)
+	return lines
+}
+
+// renderRow formats one tree line: fold marker, icon, name and status
+func (m *Model) renderRow(r row) string {
+	e := r.entry
+	marker := "  "
+	if e.HasChildren && e.Path != m.root.Path {
+		marker = "▸ "
+		if m.expanded[e.Path] || m.filter != "" {
+			marker = "▾ "
+		}
+	}
+
+	icon := "📁"
+	switch {
+	case e.Path == m.root.Path:
+		icon = "🌳"
+	case e.Repo && e.Lazy && !e.Cloned:
+		icon = "💤"
+	case e.Repo && !e.Cloned:
+		icon = "⏳"
+	case e.Repo:
+		icon = "📦"
+	}
+
+	line := strings.Repeat("  ", r.depth) + marker + icon + " " + e.Name
+	if action, ok := m.busy[e.Path]; ok {
+		return line + "  ⏳ " + action + "…"
+	}
+	if status, ok := m.statuses[e.Path]; ok {
+		line += "  " + formatStatus(status)
+	}
+	return line
+}
+
+// formatStatus renders a status as "main ✓", "main ●3 ↑1 ↓2" or an error
+func formatStatus(s Status) string {
+	if s.Err != "" {
+		return "❌ " + s.Err
+	}
+	parts := []string{s.Branch}
+	if s.Changes > 0 {
+		parts = append(parts, fmt.Sprintf("●%d", s.Changes))
+	} else {
+		parts = append(parts, "✓")
+	}
+	if s.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", s.Ahead))
+	}
+	if s.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", s.Behind))
+	}
+	return strings.Join(parts, " ")
+}
+
+// cleanPath normalizes a tree path to "/a/b" form
+func cleanPath(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" || path == "." {
+		return "/"
+	}
+	return "/" + path
+}
+
+// parentPath returns the parent of a tree path, or "" for the root
+func parentPath(path string) string {
+	if path == "/" || path == "" {
+		return ""
+	}
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return path[:i]
+}
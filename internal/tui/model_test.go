@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend serves a fixed tree
+type fakeBackend struct {
+	children map[string][]Entry
+	listed   []string
+}
+
+func (b *fakeBackend) Root() Entry {
+	return Entry{Path: "/", Name: "acme", HasChildren: true}
+}
+
+func (b *fakeBackend) Children(path string) ([]Entry, error) {
+	b.listed = append(b.listed, path)
+	return b.children[path], nil
+}
+
+func (b *fakeBackend) Status(path string) (Status, error)  { return Status{Branch: "main"}, nil }
+func (b *fakeBackend) Clone(path string) error             { return nil }
+func (b *fakeBackend) Pull(path string) error              { return nil }
+func (b *fakeBackend) Resolve(path string) (string, error) { return "/ws" + path, nil }
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{children: map[string][]Entry{
+		"/": {
+			{Path: "/web", Name: "web", Repo: true, Cloned: true},
+			{Path: "/platform", Name: "platform", HasChildren: true},
+			{Path: "/docs", Name: "docs", Repo: true, Lazy: true},
+		},
+		"/platform": {
+			{Path: "/platform/auth", Name: "auth", Repo: true, Cloned: true},
+			{Path: "/platform/billing", Name: "billing", Repo: true, Cloned: true, HasChildren: true},
+		},
+		"/platform/billing": {
+			{Path: "/platform/billing/invoices", Name: "invoices", Repo: true},
+		},
+	}}
+}
+
+func rowPaths(m *Model) []string {
+	var paths []string
+	for _, r := range m.rows {
+		paths = append(paths, r.entry.Path)
+	}
+	return paths
+}
+
+func selected(t *testing.T, m *Model) string {
+	entry, ok := m.Selected()
+	require.True(t, ok)
+	return entry.Path
+}
+
+func press(m *Model, keys string) Action {
+	var action Action
+	for _, key := range parseKeys([]byte(keys)) {
+		action = m.HandleKey(key)
+	}
+	return action
+}
+
+func TestModel_ExpandAndCollapse(t *testing.T) {
+	backend := newFakeBackend()
+	m := NewModel(backend, "/")
+
+	assert.Equal(t, []string{"/", "/docs", "/platform", "/web"}, rowPaths(m))
+	assert.Equal(t, []string{"/"}, backend.listed, "children load on demand")
+
+	press(m, "jj")
+	assert.Equal(t, "/platform", selected(t, m))
+
+	press(m, "l")
+	assert.Equal(t, []string{"/", "/docs", "/platform", "/platform/auth", "/platform/billing", "/web"}, rowPaths(m))
+	assert.Equal(t, "/platform", selected(t, m))
+
+	// Expanding an expanded node moves into it
+	press(m, "l")
+	assert.Equal(t, "/platform/auth", selected(t, m))
+
+	// Collapsing a leaf moves to its parent, then folds it
+	press(m, "h")
+	assert.Equal(t, "/platform", selected(t, m))
+	press(m, "h")
+	assert.Equal(t, []string{"/", "/docs", "/platform", "/web"}, rowPaths(m))
+
+	press(m, " ")
+	assert.Len(t, rowPaths(m), 6)
+
+	press(m, "G")
+	assert.Equal(t, "/web", selected(t, m))
+	press(m, "g")
+	assert.Equal(t, "/", selected(t, m))
+}
+
+func TestModel_StartsAtNode(t *testing.T) {
+	m := NewModel(newFakeBackend(), "/platform/billing/invoices")
+	assert.Equal(t, "/platform/billing/invoices", selected(t, m))
+}
+
+func TestModel_Filter(t *testing.T) {
+	m := NewModel(newFakeBackend(), "/")
+
+	press(m, "/INV")
+	assert.Equal(t, []string{"/", "/platform", "/platform/billing", "/platform/billing/invoices"}, rowPaths(m))
+	assert.Equal(t, "/platform/billing/invoices", selected(t, m))
+
+	// Typed keys go to the filter, not to the tree
+	assert.Equal(t, Action{}, press(m, "q"))
+	assert.Len(t, rowPaths(m), 1)
+
+	press(m, "\x7f\r")
+	assert.Equal(t, ActionSelect, press(m, "\r").Kind)
+	assert.Equal(t, "/platform/billing/invoices", selected(t, m))
+
+	press(m, "\x1b")
+	assert.Equal(t, []string{"/", "/docs", "/platform", "/platform/auth", "/platform/billing", "/platform/billing/invoices", "/web"}, rowPaths(m))
+	assert.Equal(t, "/platform/billing/invoices", selected(t, m))
+}
+
+func TestModel_Actions(t *testing.T) {
+	m := NewModel(newFakeBackend(), "/docs")
+
+	assert.Equal(t, Action{Kind: ActionSelect, Path: "/docs"}, press(m, "\r"))
+	assert.Equal(t, Action{Kind: ActionClone, Path: "/docs"}, press(m, "c"))
+	assert.Equal(t, Action{}, press(m, "p"))
+	assert.Contains(t, m.message, "not cloned yet")
+
+	press(m, "G")
+	assert.Equal(t, Action{Kind: ActionPull, Path: "/web"}, press(m, "p"))
+	assert.Equal(t, Action{Kind: ActionStatus, Path: "/web"}, press(m, "s"))
+	assert.Equal(t, Action{}, press(m, "c"))
+	assert.Contains(t, m.message, "already cloned")
+
+	press(m, "g")
+	assert.Equal(t, Action{}, press(m, "p"))
+	assert.Contains(t, m.message, "not a repository")
+
+	assert.Equal(t, ActionQuit, press(m, "q").Kind)
+	assert.Equal(t, ActionQuit, press(m, "\x03").Kind)
+}
+
+func TestModel_View(t *testing.T) {
+	m := NewModel(newFakeBackend(), "/web")
+	m.SetStatus("/web", Status{Branch: "main", Changes: 2, Behind: 1})
+	m.SetBusy("/docs", "cloning")
+
+	lines := m.View(60, 8)
+	require.Len(t, lines, 8)
+	assert.Equal(t, "🌳 muno — acme", lines[0])
+	assert.Equal(t, "  🌳 acme", lines[1])
+	assert.Equal(t, "    💤 docs  ⏳ cloning…", lines[2])
+	assert.Equal(t, "  ▸ 📁 platform", lines[3])
+	assert.Equal(t, reverse+pad("    📦 web  main ●2 ↓1", 60)+reset, lines[4])
+	assert.Contains(t, lines[7], "⏎ cd")
+
+	for _, line := range m.View(12, 8) {
+		plain := strings.NewReplacer(reverse, "", dim, "", reset, "").Replace(line)
+		assert.LessOrEqual(t, textWidth(plain), 12, line)
+	}
+}
+
+func TestModel_ViewScrollsToCursor(t *testing.T) {
+	m := NewModel(newFakeBackend(), "/platform/billing/invoices")
+
+	lines := m.View(60, 5)
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[2], "invoices")
+	assert.Contains(t, lines[2], reverse)
+}
+
+func TestFormatStatus(t *testing.T) {
+	assert.Equal(t, "main ✓", formatStatus(Status{Branch: "main"}))
+	assert.Equal(t, "dev ●3 ↑1 ↓2", formatStatus(Status{Branch: "dev", Changes: 3, Ahead: 1, Behind: 2}))
+	assert.Equal(t, "❌ not a git repository", formatStatus(Status{Err: "not a git repository"}))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 3))
+	assert.Equal(t, "ab…", truncate("abcd", 3))
+	assert.Equal(t, "📦…", truncate("📦 web", 3))
+	assert.Equal(t, 4, textWidth("📦 w"))
+}
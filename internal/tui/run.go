@@ -0,0 +1,261 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Refresh intervals of the runner
+const (
+	tickInterval    = time.Second // Checks for terminal resizes
+	refreshInterval = 10          // Ticks between status refreshes
+	statusWorkers   = 4           // Status reads running at the same time
+)
+
+// result is the outcome of a backend call run in the background
+type result struct {
+	kind   ActionKind
+	path   string
+	status Status
+	dir    string
+	err    error
+}
+
+// Run shows the browser on the controlling terminal until the user quits or
+// selects a node. It returns the directory of the selected node, or "" when
+// the user quit.
+func Run(backend Backend, start string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("muno ui needs an interactive terminal: %w", err)
+	}
+	defer tty.Close()
+
+	fd := int(tty.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("setting up terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	// Alternate screen with a hidden cursor, restored on exit
+	fmt.Fprint(tty, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(tty, "\x1b[2J\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []Key)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := tty.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- parseKeys(buf[:n])
+		}
+	}()
+
+	r := newRunner(backend, start)
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	width, height := 0, 0
+	ticks := 0
+	for {
+		r.loadStatuses(false)
+		if w, h, err := term.GetSize(fd); err == nil && (w != width || h != height) {
+			width, height = w, h
+			fmt.Fprint(tty, "\x1b[2J")
+		}
+		render(tty, r.model.View(width, height))
+
+		select {
+		case pressed, ok := <-keys:
+			if !ok {
+				return "", nil
+			}
+			for _, key := range pressed {
+				switch action := r.model.HandleKey(key); action.Kind {
+				case ActionQuit:
+					return "", nil
+				case ActionSelect, ActionClone, ActionPull, ActionStatus:
+					r.start(action)
+				}
+			}
+		case res := <-r.results:
+			if dir, done := r.finish(res); done {
+				return dir, nil
+			}
+		case <-ticker.C:
+			ticks++
+			if ticks%refreshInterval == 0 {
+				r.loadStatuses(true)
+			}
+		}
+	}
+}
+
+// runner runs backend calls in the background and applies their results to
+// the model on the main loop
+type runner struct {
+	backend  Backend
+	model    *Model
+	results  chan result
+	inflight map[string]bool // Status reads in progress
+	statuses chan struct{}   // Bounds the status reads running at once
+}
+
+func newRunner(backend Backend, start string) *runner {
+	locked := &lockedBackend{Backend: backend}
+	return &runner{
+		backend:  locked,
+		model:    NewModel(locked, start),
+		results:  make(chan result, 16),
+		inflight: make(map[string]bool),
+		statuses: make(chan struct{}, statusWorkers),
+	}
+}
+
+// lockedBackend lets reads of the tree and status run side by side but gives
+// clones, pulls and resolves the backend to themselves, as they change the
+// workspace the reads look at
+type lockedBackend struct {
+	Backend
+	mu sync.RWMutex
+}
+
+func (b *lockedBackend) Root() Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Backend.Root()
+}
+
+func (b *lockedBackend) Children(path string) ([]Entry, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Backend.Children(path)
+}
+
+func (b *lockedBackend) Status(path string) (Status, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Backend.Status(path)
+}
+
+func (b *lockedBackend) Clone(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Backend.Clone(path)
+}
+
+func (b *lockedBackend) Pull(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Backend.Pull(path)
+}
+
+func (b *lockedBackend) Resolve(path string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Backend.Resolve(path)
+}
+
+// start runs an action in the background
+func (r *runner) start(action Action) {
+	path := action.Path
+	switch action.Kind {
+	case ActionSelect:
+		r.model.SetBusy(path, "opening")
+		go func() {
+			dir, err := r.backend.Resolve(path)
+			r.results <- result{kind: ActionSelect, path: path, dir: dir, err: err}
+		}()
+	case ActionClone:
+		r.model.SetBusy(path, "cloning")
+		go func() {
+			r.results <- result{kind: ActionClone, path: path, err: r.backend.Clone(path)}
+		}()
+	case ActionPull:
+		r.model.SetBusy(path, "pulling")
+		go func() {
+			r.results <- result{kind: ActionPull, path: path, err: r.backend.Pull(path)}
+		}()
+	case ActionStatus:
+		r.readStatus(path)
+	}
+}
+
+// finish applies a result to the model and reports whether the browser is done
+func (r *runner) finish(res result) (string, bool) {
+	switch res.kind {
+	case ActionStatus:
+		delete(r.inflight, res.path)
+		if res.err != nil {
+			res.status = Status{Err: res.err.Error()}
+		}
+		r.model.SetStatus(res.path, res.status)
+		return "", false
+	case ActionSelect:
+		r.model.SetBusy(res.path, "")
+		if res.err != nil {
+			r.model.SetMessage(fmt.Sprintf("❌ %s: %v", res.path, res.err))
+			return "", false
+		}
+		return res.dir, true
+	}
+
+	r.model.SetBusy(res.path, "")
+	verb := map[ActionKind]string{ActionClone: "Cloned", ActionPull: "Pulled"}[res.kind]
+	if res.err != nil {
+		r.model.SetMessage(fmt.Sprintf("❌ %s: %v", res.path, res.err))
+	} else {
+		r.model.SetMessage(fmt.Sprintf("✅ %s %s", verb, res.path))
+	}
+	if res.kind == ActionClone {
+		r.model.Reload(res.path)
+	}
+	r.readStatus(res.path)
+	return "", false
+}
+
+// loadStatuses reads the status of visible repositories, all of them when
+// refresh is set, otherwise only those not read yet
+func (r *runner) loadStatuses(refresh bool) {
+	for _, path := range r.model.VisibleRepos() {
+		if refresh || !r.model.HasStatus(path) {
+			r.readStatus(path)
+		}
+	}
+}
+
+func (r *runner) readStatus(path string) {
+	if r.inflight[path] {
+		return
+	}
+	r.inflight[path] = true
+	go func() {
+		r.statuses <- struct{}{}
+		status, err := r.backend.Status(path)
+		<-r.statuses
+		r.results <- result{kind: ActionStatus, path: path, status: status, err: err}
+	}()
+}
+
+// render draws lines from the top left corner, clearing what is left of each
+func render(tty *os.File, lines []string) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	tty.WriteString(b.String())
+}
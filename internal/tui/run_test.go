@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sharedBackend mimics a backend whose calls share unsynchronized state, like
+// the manager behind muno ui; run with -race to catch overlapping writes
+type sharedBackend struct {
+	*fakeBackend
+	pulls   map[string]int
+	reading int32 // Status reads in progress
+	peak    int32 // Most status reads seen at once
+}
+
+func (b *sharedBackend) Status(path string) (Status, error) {
+	n := atomic.AddInt32(&b.reading, 1)
+	defer atomic.AddInt32(&b.reading, -1)
+	for {
+		peak := atomic.LoadInt32(&b.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&b.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return Status{Branch: "main", Ahead: b.pulls[path]}, nil
+}
+
+func (b *sharedBackend) Clone(path string) error {
+	b.pulls[path] = 0
+	return nil
+}
+
+func (b *sharedBackend) Pull(path string) error {
+	b.pulls[path]++
+	return nil
+}
+
+func (b *sharedBackend) Resolve(path string) (string, error) {
+	b.pulls[path] += 0
+	return "/ws" + path, nil
+}
+
+func TestRunner_SerializesActionsWithStatusReads(t *testing.T) {
+	backend := &sharedBackend{fakeBackend: newFakeBackend(), pulls: map[string]int{}}
+	children := backend.children["/"]
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		children = append(children, Entry{Path: "/" + name, Name: name, Repo: true, Cloned: true})
+	}
+	backend.children["/"] = children
+
+	r := newRunner(backend, "/")
+	r.loadStatuses(true)
+	r.start(Action{Kind: ActionPull, Path: "/web"})
+	r.start(Action{Kind: ActionClone, Path: "/docs"})
+	r.start(Action{Kind: ActionPull, Path: "/web"})
+	r.start(Action{Kind: ActionSelect, Path: "/a"})
+
+	// Wait for the actions and the status reads they start afterwards
+	var dir string
+	for actions := 0; actions < 4 || len(r.inflight) > 0; {
+		select {
+		case res := <-r.results:
+			if res.kind != ActionStatus {
+				actions++
+			}
+			if selected, done := r.finish(res); done {
+				dir = selected
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out with %d status reads in progress", len(r.inflight))
+		}
+	}
+
+	assert.Equal(t, "/ws/a", dir)
+	assert.Equal(t, 2, backend.pulls["/web"])
+	assert.LessOrEqual(t, atomic.LoadInt32(&backend.peak), int32(statusWorkers))
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ANSI attributes used by the view
+const (
+	reverse = "\x1b[7m"
+	dim     = "\x1b[2m"
+	reset   = "\x1b[0m"
+)
+
+// runeWidth returns the number of terminal cells r occupies: 2 for emoji and
+// East Asian wide characters, 0 for combining marks and joiners
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200d || r == 0xfe0f || unicode.Is(unicode.Mn, r):
+		return 0
+	case r >= 0x1f000,
+		r == 0x23f3, r == 0x2705, r == 0x274c, r == 0x2728,
+		r >= 0x1100 && r <= 0x115f,
+		r >= 0x2e80 && r <= 0xa4cf,
+		r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff,
+		r >= 0xff00 && r <= 0xff60:
+		return 2
+	}
+	return 1
+}
+
+// textWidth returns the number of cells s occupies
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// truncate shortens s to at most width cells, marking the cut with …
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if textWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	b.WriteString("…")
+	return b.String()
+}
+
+// pad fills s with spaces up to width cells
+func pad(s string, width int) string {
+	if n := width - textWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}